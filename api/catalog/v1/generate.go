@@ -0,0 +1,4 @@
+// Package catalogv1 - gRPC API каталога, сгенерированный из catalog.proto
+package catalogv1
+
+//go:generate protoc -I../../.. --go_out=../../.. --go_opt=paths=source_relative --go-grpc_out=../../.. --go-grpc_opt=paths=source_relative api/catalog/v1/catalog.proto
@@ -0,0 +1,233 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"goapi/internal/app"
+	"goapi/internal/config"
+	"goapi/internal/lib/catalog"
+	"goapi/internal/model"
+	"io"
+	"os"
+	"time"
+)
+
+const usage = `usage: api [--config=path] <command> [flags]
+
+commands:
+  serve                                           run http and grpc servers (default)
+  config check                                    print the effective config with secrets redacted
+  migrate up | down [N|all] | status              apply or roll back schema migrations
+  collect [--source=petstore] [--once]            collect products from open apis
+  user create --email=E --password=P [--role=R]   create a user, role is user or admin
+  catalog export [--format=csv|jsonl] [--out=F]   export the catalog to a file or stdout
+  catalog import --file=F [--format=F] [--dry-run] import the catalog from a file
+  token issue --email=E [--ttl=D]                 issue a user token without a password`
+
+var ErrUsage = errors.New(usage)
+
+// commands - действия приложения, которые вызывает командная строка
+type commands interface {
+	Run(cfg *config.Config) error
+	Migrate(cfg *config.Config, args []string, out io.Writer) error
+	Collect(cfg *config.Config, source string, once bool) error
+	CreateUser(cfg *config.Config, email, password string, role model.Role, out io.Writer) error
+	IssueToken(cfg *config.Config, email string, ttl time.Duration, out io.Writer) error
+	ExportCatalog(cfg *config.Config, format catalog.Format, w io.Writer) error
+	ImportCatalog(cfg *config.Config, format catalog.Format, r io.Reader, dryRun bool, out io.Writer) error
+}
+
+var _ commands = (*app.App)(nil)
+
+// run разбирает подкоманду из args (аргументы после флагов конфига) и выполняет ее
+func run(a commands, cfg *config.Config, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return a.Run(cfg)
+	}
+
+	switch args[0] {
+	case "serve":
+		if len(args) > 1 {
+			return ErrUsage
+		}
+		return a.Run(cfg)
+	case "migrate":
+		return a.Migrate(cfg, args[1:], out)
+	case "collect":
+		return runCollect(a, cfg, args[1:])
+	case "user":
+		if len(args) < 2 || args[1] != "create" {
+			return ErrUsage
+		}
+		return runUserCreate(a, cfg, args[2:], out)
+	case "catalog":
+		if len(args) < 2 {
+			return ErrUsage
+		}
+		switch args[1] {
+		case "export":
+			return runCatalogExport(a, cfg, args[2:], out)
+		case "import":
+			return runCatalogImport(a, cfg, args[2:], out)
+		}
+		return ErrUsage
+	case "token":
+		if len(args) < 2 || args[1] != "issue" {
+			return ErrUsage
+		}
+		return runTokenIssue(a, cfg, args[2:], out)
+	default:
+		return ErrUsage
+	}
+}
+
+// checkConfig выводит итоговый конфиг и возвращает ошибки его загрузки
+func checkConfig(cfg *config.Config, loadErr error, args []string, out io.Writer) error {
+	if len(args) != 1 || args[0] != "check" {
+		return ErrUsage
+	}
+
+	if cfg == nil {
+		return loadErr
+	}
+
+	if err := cfg.PrintRedacted(out); err != nil {
+		return err
+	}
+
+	if loadErr == nil {
+		fmt.Fprintln(out, "\nconfig is valid")
+	}
+
+	return loadErr
+}
+
+func runCollect(a commands, cfg *config.Config, args []string) error {
+	fs := newFlagSet("collect")
+	source := fs.String("source", "", "name of the products api, collector.sources from config by default")
+	once := fs.Bool("once", false, "collect once and exit")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	return a.Collect(cfg, *source, *once)
+}
+
+func runUserCreate(a commands, cfg *config.Config, args []string, out io.Writer) error {
+	fs := newFlagSet("user create")
+	email := fs.String("email", "", "user email")
+	password := fs.String("password", "", "user password")
+	role := fs.String("role", string(model.RoleUser), "user role: user or admin")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	if *email == "" || *password == "" {
+		return ErrUsage
+	}
+
+	return a.CreateUser(cfg, *email, *password, model.Role(*role), out)
+}
+
+func runTokenIssue(a commands, cfg *config.Config, args []string, out io.Writer) error {
+	fs := newFlagSet("token issue")
+	email := fs.String("email", "", "user email")
+	ttl := fs.Duration("ttl", 0, "token lifetime, token_ttl from config by default")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	if *email == "" {
+		return ErrUsage
+	}
+
+	return a.IssueToken(cfg, *email, *ttl, out)
+}
+
+func runCatalogExport(a commands, cfg *config.Config, args []string, out io.Writer) (err error) {
+	fs := newFlagSet("catalog export")
+	formatName := fs.String("format", "", "csv or jsonl, by extension of --out or csv by default")
+	path := fs.String("out", "", "output file, stdout by default")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	format, err := fileFormat(*formatName, *path)
+	if err != nil {
+		return err
+	}
+
+	if *path == "" {
+		return a.ExportCatalog(cfg, format, out)
+	}
+
+	file, err := os.Create(*path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+
+	return a.ExportCatalog(cfg, format, file)
+}
+
+func runCatalogImport(a commands, cfg *config.Config, args []string, out io.Writer) error {
+	fs := newFlagSet("catalog import")
+	formatName := fs.String("format", "", "csv or jsonl, by extension of --file by default")
+	path := fs.String("file", "", "catalog file")
+	dryRun := fs.Bool("dry-run", false, "check rows without saving")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	if *path == "" {
+		return ErrUsage
+	}
+
+	format, err := fileFormat(*formatName, *path)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Open(*path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return a.ImportCatalog(cfg, format, file, *dryRun, out)
+}
+
+// fileFormat определяет формат каталога: явно заданный, по расширению файла, иначе csv
+func fileFormat(name, path string) (catalog.Format, error) {
+	switch {
+	case name != "":
+		return catalog.ParseFormat(name)
+	case path != "":
+		return catalog.FormatFromFilename(path)
+	default:
+		return catalog.FormatCSV, nil
+	}
+}
+
+func newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	return fs
+}
+
+// parseFlags разбирает флаги подкоманды, лишние позиционные аргументы - ошибка использования
+func parseFlags(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		return fmt.Errorf("%s: %w\n%w", fs.Name(), err, ErrUsage)
+	}
+	if fs.NArg() > 0 {
+		return ErrUsage
+	}
+
+	return nil
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"goapi/internal/config"
+	"goapi/internal/lib/catalog"
+	"goapi/internal/model"
+)
+
+// recorder запоминает вызванную команду вместо работы с базой
+type recorder struct {
+	call string
+}
+
+func (r *recorder) Run(cfg *config.Config) error {
+	r.call = "serve"
+	return nil
+}
+
+func (r *recorder) Migrate(cfg *config.Config, args []string, out io.Writer) error {
+	r.call = fmt.Sprint("migrate ", args)
+	return nil
+}
+
+func (r *recorder) Collect(cfg *config.Config, source string, once bool) error {
+	r.call = fmt.Sprint("collect ", source, " ", once)
+	return nil
+}
+
+func (r *recorder) CreateUser(cfg *config.Config, email, password string, role model.Role, out io.Writer) error {
+	r.call = fmt.Sprint("user ", email, " ", password, " ", role)
+	return nil
+}
+
+func (r *recorder) IssueToken(cfg *config.Config, email string, ttl time.Duration, out io.Writer) error {
+	r.call = fmt.Sprint("token ", email, " ", ttl)
+	return nil
+}
+
+func (r *recorder) ExportCatalog(cfg *config.Config, format catalog.Format, w io.Writer) error {
+	r.call = fmt.Sprint("export ", format)
+	return nil
+}
+
+func (r *recorder) ImportCatalog(cfg *config.Config, format catalog.Format, rd io.Reader, dryRun bool, out io.Writer) error {
+	data, _ := io.ReadAll(rd)
+	r.call = fmt.Sprint("import ", format, " ", dryRun, " ", string(data))
+	return nil
+}
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	importFile := filepath.Join(dir, "catalog.jsonl")
+	require.NoError(t, os.WriteFile(importFile, []byte("rows"), 0o600))
+	exportFile := filepath.Join(dir, "out.jsonl")
+
+	tests := []struct {
+		name         string
+		args         []string
+		expectedCall string
+		expectedErr  error
+	}{
+		{name: "Default Serve", expectedCall: "serve"},
+		{name: "Serve", args: []string{"serve"}, expectedCall: "serve"},
+		{name: "Migrate", args: []string{"migrate", "down", "2"}, expectedCall: "migrate [down 2]"},
+		{name: "Collect Default", args: []string{"collect"}, expectedCall: "collect  false"},
+		{name: "Collect Once", args: []string{"collect", "--source=petstore", "--once"}, expectedCall: "collect petstore true"},
+		{
+			name:         "User Create",
+			args:         []string{"user", "create", "--email=admin@example.com", "--password=secret", "--role=admin"},
+			expectedCall: "user admin@example.com secret admin",
+		},
+		{
+			name:         "User Create Default Role",
+			args:         []string{"user", "create", "--email=u@example.com", "--password=secret"},
+			expectedCall: "user u@example.com secret user",
+		},
+		{name: "User Create Without Email", args: []string{"user", "create", "--password=secret"}, expectedErr: ErrUsage},
+		{name: "Token Issue", args: []string{"token", "issue", "--email=a@example.com", "--ttl=1h"}, expectedCall: "token a@example.com 1h0m0s"},
+		{name: "Token Issue Bad TTL", args: []string{"token", "issue", "--email=a@example.com", "--ttl=soon"}, expectedErr: ErrUsage},
+		{name: "Catalog Export Stdout", args: []string{"catalog", "export"}, expectedCall: "export csv"},
+		{name: "Catalog Export File", args: []string{"catalog", "export", "--out=" + exportFile}, expectedCall: "export jsonl"},
+		{name: "Catalog Export Unknown Format", args: []string{"catalog", "export", "--format=xml"}, expectedErr: catalog.ErrUnknownFormat},
+		{
+			name:         "Catalog Import",
+			args:         []string{"catalog", "import", "--file=" + importFile, "--dry-run"},
+			expectedCall: "import jsonl true rows",
+		},
+		{name: "Catalog Import Without File", args: []string{"catalog", "import"}, expectedErr: ErrUsage},
+		{name: "Unknown Command", args: []string{"deploy"}, expectedErr: ErrUsage},
+		{name: "Unknown Subcommand", args: []string{"user", "delete"}, expectedErr: ErrUsage},
+		{name: "Extra Args", args: []string{"collect", "now"}, expectedErr: ErrUsage},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := &recorder{}
+			err := run(r, &config.Config{}, test.args, &bytes.Buffer{})
+			assert.ErrorIs(t, err, test.expectedErr)
+			assert.Equal(t, test.expectedCall, r.call)
+		})
+	}
+}
+
+func TestCheckConfig(t *testing.T) {
+	cfg := &config.Config{DBConfig: config.DataBaseConfig{Password: "qwerty"}}
+	loadErr := errors.New("invalid config")
+
+	var out bytes.Buffer
+	assert.ErrorIs(t, checkConfig(cfg, loadErr, []string{"check"}, &out), loadErr)
+	assert.Contains(t, out.String(), "db.password")
+	assert.NotContains(t, out.String(), "qwerty")
+
+	out.Reset()
+	require.NoError(t, checkConfig(cfg, nil, []string{"check"}, &out))
+	assert.Contains(t, out.String(), "config is valid")
+
+	assert.ErrorIs(t, checkConfig(nil, loadErr, []string{"check"}, &out), loadErr)
+	assert.ErrorIs(t, checkConfig(cfg, nil, nil, &out), ErrUsage)
+}
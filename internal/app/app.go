@@ -2,26 +2,58 @@ package app
 
 import (
 	"context"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"goapi/internal/app/grpcserver"
+	"goapi/internal/app/lifecycle"
 	"goapi/internal/app/logger"
-	"goapi/internal/app/productcollector"
+	"goapi/internal/app/outbox"
 	"goapi/internal/app/server"
+	"goapi/internal/app/webhookdispatcher"
 	"goapi/internal/config"
+	"goapi/internal/graphqlapi"
+	"goapi/internal/grpchandler"
 	"goapi/internal/handler"
-	"goapi/internal/repository/postgres"
-	"goapi/internal/service"
+	"goapi/internal/lib/eventstream"
+	"goapi/internal/lib/health"
+	"goapi/internal/lib/leader"
+	"goapi/internal/lib/logger/sl"
+	"goapi/internal/lib/tracing"
+	"goapi/internal/lib/webhook"
+	"goapi/internal/repository/sqlstore"
 	"log/slog"
+	"net/http"
 	"os"
 	"os/signal"
+	"slices"
+	"strings"
 	"syscall"
+	"time"
+)
+
+const (
+	// tracingShutdownTimeout - сколько ждать отправки оставшихся спанов при остановке
+	tracingShutdownTimeout = 5 * time.Second
+
+	// collectorsLease - имя аренды, владелец которой запускает сборщики товаров
+	collectorsLease = "product-collectors"
+	// outboxLease - имя аренды, владелец которой доставляет события из outbox
+	outboxLease = "outbox-relay"
 )
 
 type App struct {
-	log *slog.Logger
+	log   *slog.Logger
+	level *slog.LevelVar
 }
 
-func New(env string) *App {
+// New создает приложение с логгером для окружения env и уровнем логирования level
+func New(env, level string) *App {
+	logLevel := new(slog.LevelVar)
+	logLevel.Set(logger.Level(env, level))
+
 	return &App{
-		log: logger.SetupLogger(env),
+		log:   logger.SetupLogger(env, logLevel),
+		level: logLevel,
 	}
 }
 
@@ -38,59 +70,265 @@ func (a *App) Run(cfg *config.Config) error {
 		slog.String("op", op),
 	)
 
-	db, err := postgres.NewPostgresDB(postgres.Config{
-		Host:     cfg.DBConfig.Host,
-		Port:     cfg.DBConfig.Port,
-		Username: cfg.DBConfig.Username,
-		Password: cfg.DBConfig.Password,
-		DBName:   cfg.DBConfig.DBName,
-		SSLMode:  cfg.DBConfig.SSLMode,
-	})
+	sinks, err := outboxSinks(cfg.Outbox, a.log)
 	if err != nil {
-		log.Error("failed to initialize db: %s", err)
+		log.Error("failed to configure outbox sinks", sl.Err(err))
 		return err
 	}
 
-	authRep := postgres.NewAuthPostgres(db, a.log)
-	productRep := postgres.NewProductRepository(db, a.log)
-	categoryRep := postgres.NewCategoryRepository(db, a.log)
-
-	authServ := service.NewAuthService(authRep, authRep, a.log, cfg.TokenTTL)
-	productServ := service.NewProductService(productRep, productRep, productRep, productRep, a.log)
-	categoryServ := service.NewCategoryService(categoryRep, categoryRep, categoryRep, categoryRep, a.log)
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Config{
+		ServiceName: cfg.Tracing.ServiceName,
+		Exporter:    cfg.Tracing.Exporter,
+		Endpoint:    cfg.Tracing.Endpoint,
+		Insecure:    cfg.Tracing.Insecure,
+		SampleRatio: cfg.Tracing.SampleRatio,
+	}, os.Stdout)
+	if err != nil {
+		log.Error("failed to configure tracing", sl.Err(err))
+		return err
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), tracingShutdownTimeout)
+		defer cancel()
 
-	handlers := handler.NewHandler(authServ, productServ, categoryServ, a.log)
+		if err := shutdownTracing(ctx); err != nil {
+			log.Error("failed to flush traces", sl.Err(err))
+		}
+	}()
 
-	srv := new(server.Server)
-	go func() {
-		if err := srv.Run(cfg.SConfig.Port, handlers.Init()); err != nil {
-			log.Error("error occured while running http server: %s", err.Error())
+	svc, err := newServices(cfg, a.log)
+	if err != nil {
+		log.Error("failed to initialize db", sl.Err(err))
+		return err
+	}
+	// пул закрывается и при раннем выходе, и после остановки серверов и задач в m.Run
+	defer func() {
+		if err := svc.Close(); err != nil {
+			log.Error("failed to close db", sl.Err(err))
 		}
 	}()
 
-	ctx, cancel := context.WithCancel(context.Background())
-	collector := productcollector.NewProductCollector(productServ, a.log)
-	go collector.Collect(ctx)
+	if err := sqlstore.RegisterPoolMetrics(svc.db, cfg.DBConfig.Driver); err != nil {
+		log.Warn("failed to register db pool metrics", sl.Err(err))
+	}
 
-	log.Info("Application started")
+	migrator := sqlstore.NewMigrator(svc.db, a.log)
+	if cfg.DBConfig.MigrateOnStart {
+		if err := migrator.Up(context.Background()); err != nil {
+			log.Error("failed to migrate db", sl.Err(err))
+			return err
+		}
+	}
+
+	hub := eventstream.NewHub(svc.outboxRep, cfg.Stream.BufferSize, cfg.Stream.Interval, cfg.Stream.Heartbeat, a.log)
 
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGTERM, syscall.SIGINT)
-	<-quit
+	graphqlSchema, err := graphqlapi.NewSchema(svc.product, svc.category, graphqlapi.Limits{
+		MaxDepth:      cfg.GraphQL.MaxDepth,
+		MaxComplexity: cfg.GraphQL.MaxComplexity,
+		MaxListSize:   cfg.GraphQL.MaxListSize,
+	}, a.log)
+	if err != nil {
+		log.Error("failed to build graphql schema", sl.Err(err))
+		return err
+	}
+
+	checker := health.NewChecker(cfg.Health.CheckTimeout)
+	checker.Add("db", svc.db.PingContext)
+	checker.Add("migrations", migrator.CheckVersion)
+
+	handlers := handler.NewHandler(handler.Deps{
+		Auth:        svc.auth,
+		Tokens:      svc.tokens,
+		Product:     svc.product,
+		Category:    svc.category,
+		Catalog:     svc.catalog,
+		Idempotency: svc.idempotency,
+		Webhook:     svc.webhook,
+		Stream:      hub,
+		GraphQL:     graphqlSchema,
+		Health:      checker,
+	}, a.log)
+	grpcHandlers := grpchandler.NewHandler(svc.auth, svc.tokens, svc.product, svc.category, a.log)
+
+	m := lifecycle.New(lifecycle.Config{
+		Delay:         cfg.Shutdown.Delay,
+		WorkerTimeout: cfg.Shutdown.WorkerTimeout,
+	}, a.log)
+
+	// /readyz начинает отвечать 503 до остановки серверов, и балансировщик снимает приложение с трафика
+	m.OnShutdown(func() {
+		log.Info("Application Shutting Down")
+		checker.Shutdown()
+	})
 
-	log.Info("Application Shutting Down")
+	srv := server.New(cfg.SConfig.Host, cfg.SConfig.Port, handlers.Init())
+	srv.RegisterOnShutdown(hub.Close)
+	m.AddServer("http", cfg.SConfig.ShutdownTimeout, srv.Run, srv.Shutdown)
 
-	cancel()
+	grpcSrv := grpcserver.New(grpcHandlers.Init())
+	m.AddServer("grpc", cfg.GRPC.ShutdownTimeout, func() error { return grpcSrv.Run(cfg.GRPC.Host, cfg.GRPC.Port) }, grpcSrv.Shutdown)
 
-	if err := srv.Shutdown(context.Background()); err != nil {
-		log.Info("error occured on server shutting down: %s", err.Error())
+	collectors := newCollectors(svc.product, a.log)
+	if err := collectors.Apply(cfg.Collector); err != nil {
+		log.Error("failed to configure product collectors", sl.Err(err))
 		return err
 	}
+	checker.Add("collectors", collectors.Check)
+
+	replicaID := leader.NewID()
+	m.AddWorker("collectors", untilDone(leading(cfg.Leader, collectorsLease, replicaID, svc.leaseRep, a.log, collectors.Run)))
+
+	m.AddWorker("reload", func(ctx context.Context) error {
+		hup := make(chan os.Signal, 1)
+		signal.Notify(hup, syscall.SIGHUP)
+		defer signal.Stop(hup)
+
+		current := cfg
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-hup:
+				current = a.reload(current, collectors)
+			}
+		}
+	})
+
+	m.AddWorker("idempotency purge", untilDone(func(ctx context.Context) {
+		svc.idempotency.Purge(ctx, cfg.Idempotency.PurgeInterval)
+	}))
+
+	// поток событий читает outbox на каждой реплике: подписчики есть у всех реплик, а не только у лидера
+	m.AddWorker("event stream", untilDone(hub.Run))
+
+	sinks = append(sinks, webhookdispatcher.NewSubscriptionSink(svc.webhookRep))
+	relay := outbox.NewRelay(svc.outboxRep, sinks, a.log, cfg.Outbox.Interval, cfg.Outbox.BatchSize)
+	m.AddWorker("outbox relay", untilDone(leading(cfg.Leader, outboxLease, replicaID, svc.leaseRep, a.log, relay.Run)))
+	m.AddWorker("outbox purge", untilDone(func(ctx context.Context) {
+		outbox.Purge(ctx, svc.outboxRep, a.log, cfg.Outbox.PurgeInterval, cfg.Outbox.Retention)
+	}))
+
+	sender := webhook.NewClient(webhook.NewHTTPClient(cfg.Webhooks.Timeout))
+	dispatcher := webhookdispatcher.NewDispatcher(svc.webhookRep, sender, a.log, cfg.Webhooks.Interval, cfg.Webhooks.BatchSize, cfg.Webhooks.Timeout)
+	m.AddWorker("webhook dispatcher", untilDone(dispatcher.Run))
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, syscall.SIGINT)
+	defer stop()
+
+	log.Info("Application started")
 
-	if err := db.Close(); err != nil {
-		log.Info("error occured on db connection close: %s", err.Error())
+	if err := m.Run(ctx); err != nil {
+		log.Error("application stopped with error", sl.Err(err))
 		return err
 	}
 
+	log.Info("Application stopped")
+
 	return nil
 }
+
+// untilDone приводит задачу, работающую до отмены контекста, к виду задачи lifecycle
+func untilDone(run func(ctx context.Context)) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		run(ctx)
+		return nil
+	}
+}
+
+// leading возвращает задачу, которая с выборами лидера выполняет run только на реплике,
+// владеющей арендой lease, а без них - на каждой реплике
+func leading(
+	cfg config.LeaderConfig,
+	lease, replicaID string,
+	store leader.LeaseStore,
+	log *slog.Logger,
+	run func(ctx context.Context),
+) func(ctx context.Context) {
+	if !cfg.Enabled {
+		return run
+	}
+
+	elector := leader.NewElector(leader.Config{
+		Name:          lease,
+		LeaseTTL:      cfg.LeaseTTL,
+		RenewInterval: cfg.RenewInterval,
+	}, replicaID, store, log)
+
+	return func(ctx context.Context) {
+		elector.Run(ctx, run)
+	}
+}
+
+// reload перечитывает конфиг по SIGHUP и применяет настройки, которые меняются без перезапуска.
+// Возвращает конфиг, с которым приложение работает дальше: при любой ошибке - прежний.
+func (a *App) reload(current *config.Config, collectors *collectors) *config.Config {
+	const op = "app.reload"
+
+	log := a.log.With(
+		slog.String("op", op),
+	)
+
+	log.Info("reloading config")
+
+	next, err := current.Reload()
+	if err != nil {
+		log.Error("config is not reloaded", sl.Err(err))
+		return current
+	}
+
+	reloadable, restart := config.Changes(current, next)
+	if len(restart) > 0 {
+		log.Warn("changed settings require restart", slog.Any("settings", restart))
+	}
+	if len(reloadable) == 0 {
+		log.Info("nothing to reload")
+		return current
+	}
+
+	if slices.ContainsFunc(reloadable, func(key string) bool { return strings.HasPrefix(key, "collector.") }) {
+		if err := collectors.Apply(next.Collector); err != nil {
+			log.Error("config is not reloaded", sl.Err(err))
+			return current
+		}
+	}
+	a.level.Set(logger.Level(current.Env, next.LogLevel))
+
+	log.Info("config reloaded", slog.Any("settings", reloadable))
+
+	return current.WithReloadable(next)
+}
+
+// openDB подключается к базе из конфига
+func openDB(cfg *config.Config) (*sqlx.DB, error) {
+	return sqlstore.Open(sqlstore.Config{
+		Driver:   cfg.DBConfig.Driver,
+		Host:     cfg.DBConfig.Host,
+		Port:     cfg.DBConfig.Port,
+		Username: cfg.DBConfig.Username,
+		Password: cfg.DBConfig.Password,
+		DBName:   cfg.DBConfig.DBName,
+		SSLMode:  cfg.DBConfig.SSLMode,
+		Path:     cfg.StoragePaths,
+	})
+}
+
+// outboxSinks создает получателей событий каталога по конфигу
+func outboxSinks(cfg config.OutboxConfig, log *slog.Logger) ([]outbox.Sink, error) {
+	sinks := make([]outbox.Sink, 0, len(cfg.Sinks))
+
+	for _, name := range cfg.Sinks {
+		switch name {
+		case "log":
+			sinks = append(sinks, outbox.NewLogSink(log))
+		case "webhook":
+			if cfg.WebhookURL == "" {
+				return nil, fmt.Errorf("outbox webhook sink requires webhook_url")
+			}
+			sinks = append(sinks, outbox.NewWebhookSink(cfg.WebhookURL, &http.Client{Timeout: cfg.WebhookTimeout}))
+		default:
+			return nil, fmt.Errorf("unknown outbox sink %q", name)
+		}
+	}
+
+	return sinks, nil
+}
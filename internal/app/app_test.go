@@ -0,0 +1,107 @@
+package app
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"goapi/internal/config"
+	"goapi/internal/model"
+	"goapi/internal/repository/sqlstore"
+)
+
+type discardSaver struct{}
+
+func (discardSaver) AddProducts(ctx context.Context, products []model.Product) error {
+	return nil
+}
+
+func TestReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	write := func(logLevel, source, port string) {
+		data := "env: \"local\"\nlog_level: \"" + logLevel + "\"\ntoken_ttl: \"1h\"\ntoken_secret: \"test-token-secret-0123456789abcdef\"\nstorage_paths: \"sso.db\"\n" +
+			"collector:\n  sources: [\"" + source + "\"]\nserver:\n  port: \"" + port + "\"\ndb:\n  driver: \"sqlite\"\n"
+		require.NoError(t, os.WriteFile(path, []byte(data), 0o600))
+	}
+
+	write("debug", "petstore", "8000")
+	cfg, err := config.Load(path)
+	require.NoError(t, err)
+
+	a := New(cfg.Env, cfg.LogLevel)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	collectors := newCollectors(discardSaver{}, a.log)
+	require.NoError(t, collectors.Apply(cfg.Collector))
+	go collectors.Run(ctx)
+
+	// уровень логирования применяется, смена порта ждет перезапуска
+	write("error", "petstore", "8001")
+	current := a.reload(cfg, collectors)
+	assert.Equal(t, slog.LevelError, a.level.Level())
+	assert.Equal(t, "error", current.LogLevel)
+	assert.Equal(t, "8000", current.SConfig.Port)
+
+	// неизвестный источник отменяет перезагрузку целиком
+	write("info", "nowhere", "8000")
+	assert.Same(t, current, a.reload(current, collectors))
+	assert.Equal(t, slog.LevelError, a.level.Level())
+
+	// некорректный конфиг не применяется
+	write("loud", "petstore", "8000")
+	assert.Same(t, current, a.reload(current, collectors))
+}
+
+func TestLeading(t *testing.T) {
+	db, err := sqlstore.NewSQLiteDB(filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	defer db.Close()
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	require.NoError(t, sqlstore.NewMigrator(db, logger).Up(context.Background()))
+	leases := sqlstore.NewLeaseRepository(db, logger)
+
+	cfg := config.LeaderConfig{Enabled: true, LeaseTTL: time.Minute, RenewInterval: 10 * time.Millisecond}
+
+	var running atomic.Int32
+	started := make(chan string, 2)
+	run := func(id string) func(ctx context.Context) {
+		return func(ctx context.Context) {
+			running.Add(1)
+			defer running.Add(-1)
+			started <- id
+			<-ctx.Done()
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	for _, id := range []string{"a", "b"} {
+		wg.Add(1)
+		go func(id string) {
+			defer wg.Done()
+			leading(cfg, outboxLease, id, leases, logger, run(id))(ctx)
+		}(id)
+	}
+
+	// задача выполняется только на одной реплике
+	<-started
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, int32(1), running.Load())
+
+	cancel()
+	wg.Wait()
+
+	// без выборов задача выполняется на каждой реплике
+	var called bool
+	leading(config.LeaderConfig{}, outboxLease, "c", leases, logger, func(ctx context.Context) { called = true })(context.Background())
+	assert.True(t, called)
+}
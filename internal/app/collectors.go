@@ -0,0 +1,136 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"goapi/internal/app/productcollector"
+	"goapi/internal/config"
+	"goapi/internal/lib/schedule"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// collectors - сборщики товаров, по одному на источник. Набор задается Apply и работает
+// только внутри Run: при выборах лидера Run выполняется лишь на реплике-лидере.
+// При перезагрузке конфига набор сборщиков заменяется целиком.
+type collectors struct {
+	saver productcollector.ProductSaver
+	log   *slog.Logger
+
+	mu   sync.Mutex
+	next []*productcollector.ProductCollector
+	// ctx - контекст Run, nil, пока сборщики не запущены
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+	running []*productcollector.ProductCollector
+}
+
+func newCollectors(saver productcollector.ProductSaver, log *slog.Logger) *collectors {
+	return &collectors{
+		saver: saver,
+		log:   log,
+	}
+}
+
+// Apply задает набор сборщиков по cfg и, если они запущены, перезапускает их.
+// Если источник неизвестен, ничего не меняется.
+func (c *collectors) Apply(cfg config.CollectorConfig) error {
+	next, err := buildCollectors(c.saver, cfg, c.log)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.next = next
+	if c.ctx != nil {
+		ctx := c.ctx
+		c.stop()
+		c.start(ctx)
+	}
+
+	return nil
+}
+
+// Run запускает сборщики и ждет их завершения после отмены ctx
+func (c *collectors) Run(ctx context.Context) {
+	c.mu.Lock()
+	c.start(ctx)
+	c.mu.Unlock()
+
+	<-ctx.Done()
+	c.Stop()
+}
+
+// Stop останавливает сборщики и ждет их завершения
+func (c *collectors) Stop() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.stop()
+}
+
+// Check - проверка готовности: ни один из запущенных сборщиков не завис
+func (c *collectors) Check(context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var errs []error
+	for _, collector := range c.running {
+		errs = append(errs, collector.Alive(time.Now()))
+	}
+
+	return errors.Join(errs...)
+}
+
+func (c *collectors) start(ctx context.Context) {
+	c.ctx = ctx
+	ctx, c.cancel = context.WithCancel(ctx)
+	c.running = c.next
+	for _, collector := range c.running {
+		c.wg.Add(1)
+		go func(collector *productcollector.ProductCollector) {
+			defer c.wg.Done()
+			collector.Collect(ctx)
+		}(collector)
+	}
+}
+
+func (c *collectors) stop() {
+	if c.cancel != nil {
+		c.cancel()
+	}
+	c.wg.Wait()
+	c.ctx, c.cancel, c.running = nil, nil, nil
+}
+
+// buildCollectors создает сборщики для всех источников из конфига
+func buildCollectors(saver productcollector.ProductSaver, cfg config.CollectorConfig, log *slog.Logger) ([]*productcollector.ProductCollector, error) {
+	res := make([]*productcollector.ProductCollector, 0, len(cfg.Sources))
+	for _, source := range cfg.Sources {
+		src, err := productcollector.SourceByName(source)
+		if err != nil {
+			return nil, err
+		}
+		sched, err := collectorSchedule(cfg)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, productcollector.NewProductCollector(saver, src, sched, log.With(slog.String("source", source))))
+	}
+
+	return res, nil
+}
+
+// collectorSchedule возвращает расписание сборов: cron, если он задан, иначе интервал с jitter.
+// У каждого сборщика свое расписание, чтобы jitter разводил источники между собой.
+func collectorSchedule(cfg config.CollectorConfig) (schedule.Schedule, error) {
+	if cfg.Cron != "" {
+		return schedule.Cron(cfg.Cron)
+	}
+
+	return schedule.Every(cfg.Interval, cfg.Jitter), nil
+}
@@ -0,0 +1,157 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"goapi/internal/config"
+	"goapi/internal/lib/authctx"
+	"goapi/internal/lib/catalog"
+	"goapi/internal/lib/logger/sl"
+	"goapi/internal/model"
+	"io"
+	"log/slog"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// Команды для эксплуатации: используют тот же конфиг и те же сервисы, что и сервер,
+// поэтому проверки и события каталога у них такие же, как у запросов к апи
+
+// CreateUser создает пользователя с ролью role и печатает его id в out
+func (a *App) CreateUser(cfg *config.Config, email, password string, role model.Role, out io.Writer) error {
+	const op = "app.createUser"
+
+	return a.withServices(cfg, op, func(ctx context.Context, svc *services) error {
+		id, err := svc.auth.CreateUser(ctx, email, password, role)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(out, "user %d created: %s (%s)\n", id, email, role)
+		return nil
+	})
+}
+
+// IssueToken выпускает токен пользователя без пароля и печатает его в out.
+// Нулевой ttl означает срок жизни токена из конфига.
+func (a *App) IssueToken(cfg *config.Config, email string, ttl time.Duration, out io.Writer) error {
+	const op = "app.issueToken"
+
+	return a.withServices(cfg, op, func(ctx context.Context, svc *services) error {
+		token, err := svc.auth.IssueToken(ctx, email, ttl)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintln(out, token)
+		return nil
+	})
+}
+
+// Collect собирает товары из источника source, а если он не задан - из источников конфига:
+// один раз при once, иначе по расписанию до SIGINT или SIGTERM. Выборы лидера команда
+// не проводит: ее явно запускает оператор
+func (a *App) Collect(cfg *config.Config, source string, once bool) error {
+	const op = "app.collect"
+
+	collectorCfg := cfg.Collector
+	if source != "" {
+		collectorCfg.Sources = []string{source}
+	}
+
+	return a.withServices(cfg, op, func(ctx context.Context, svc *services) error {
+		if !once {
+			ctx, stop := signal.NotifyContext(ctx, syscall.SIGTERM, syscall.SIGINT)
+			defer stop()
+
+			group := newCollectors(svc.product, a.log)
+			if err := group.Apply(collectorCfg); err != nil {
+				return err
+			}
+			group.Run(ctx)
+			return nil
+		}
+
+		collectors, err := buildCollectors(svc.product, collectorCfg, a.log)
+		if err != nil {
+			return err
+		}
+
+		var errs []error
+		for _, collector := range collectors {
+			if err := collector.CollectOnce(ctx); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		return errors.Join(errs...)
+	})
+}
+
+// ExportCatalog выгружает каталог в w в формате format
+func (a *App) ExportCatalog(cfg *config.Config, format catalog.Format, w io.Writer) error {
+	const op = "app.exportCatalog"
+
+	return a.withServices(cfg, op, func(ctx context.Context, svc *services) error {
+		return svc.catalog.ExportProducts(ctx, w, format)
+	})
+}
+
+// ImportCatalog загружает каталог из r и печатает отчет в out. Ошибки строк не прерывают импорт,
+// но команда завершается ошибкой, если хотя бы одна строка не загружена.
+func (a *App) ImportCatalog(cfg *config.Config, format catalog.Format, r io.Reader, dryRun bool, out io.Writer) error {
+	const op = "app.importCatalog"
+
+	return a.withServices(cfg, op, func(ctx context.Context, svc *services) error {
+		report, err := svc.catalog.ImportProducts(ctx, r, format, dryRun)
+		if err != nil {
+			return err
+		}
+
+		printImportReport(out, report)
+
+		if report.Failed > 0 {
+			return fmt.Errorf("%d of %d rows failed", report.Failed, report.Total)
+		}
+		return nil
+	})
+}
+
+// withServices собирает сервисы для одной команды и закрывает подключение к базе после нее
+func (a *App) withServices(cfg *config.Config, op string, fn func(ctx context.Context, svc *services) error) error {
+	log := a.log.With(
+		slog.String("op", op),
+	)
+
+	svc, err := newServices(cfg, a.log)
+	if err != nil {
+		log.Error("failed to initialize db", sl.Err(err))
+		return err
+	}
+	defer svc.Close()
+
+	// команды запускает оператор с доступом к базе, поэтому они выполняются с правами администратора
+	ctx := authctx.With(context.Background(), authctx.User{Role: model.RoleAdmin})
+	if err := fn(ctx, svc); err != nil {
+		log.Error("command failed", sl.Err(err))
+		return err
+	}
+
+	return nil
+}
+
+func printImportReport(out io.Writer, report model.ImportReport) {
+	mode := "imported"
+	if report.DryRun {
+		mode = "checked (dry run)"
+	}
+	fmt.Fprintf(out, "%s %d rows: %d created, %d updated, %d failed\n",
+		mode, report.Total, report.Created, report.Updated, report.Failed)
+
+	for _, row := range report.Rows {
+		if row.Status == model.ImportFailed {
+			fmt.Fprintf(out, "line %d (%s): %v\n", row.Line, row.SKU, row.Err)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"goapi/internal/config"
+	"goapi/internal/lib/catalog"
+	"goapi/internal/model"
+	"goapi/internal/service"
+)
+
+func TestCommands(t *testing.T) {
+	a := &App{log: slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))}
+	cfg := &config.Config{
+		StoragePaths: filepath.Join(t.TempDir(), "sso.db"),
+		DBConfig:     config.DataBaseConfig{Driver: "sqlite"},
+		TokenTTL:     time.Hour,
+		TokenSecret:  "test-token-secret-0123456789abcdef",
+	}
+
+	var out bytes.Buffer
+	require.NoError(t, a.Migrate(cfg, []string{"up"}, &out))
+
+	require.NoError(t, a.CreateUser(cfg, "admin@example.com", "Secret-pass1", model.RoleAdmin, &out))
+	assert.Contains(t, out.String(), "admin@example.com (admin)")
+	assert.ErrorIs(t, a.CreateUser(cfg, "root@example.com", "Secret-pass1", "root", &out), service.ErrInvalidRole)
+
+	out.Reset()
+	require.NoError(t, a.IssueToken(cfg, "admin@example.com", 0, &out))
+	assert.NotEmpty(t, strings.TrimSpace(out.String()))
+	assert.ErrorIs(t, a.IssueToken(cfg, "nobody@example.com", 0, &out), service.ErrUserNotFound)
+
+	svc, err := newServices(cfg, a.log)
+	require.NoError(t, err)
+	_, err = svc.category.AddCategory(context.Background(), "Phones")
+	require.NoError(t, err)
+	require.NoError(t, svc.Close())
+
+	out.Reset()
+	rows := "sku,name,categoryies\nSKU-1,Phone,Phones\nSKU-2,,Phones\n"
+	assert.Error(t, a.ImportCatalog(cfg, catalog.FormatCSV, strings.NewReader(rows), false, &out))
+	assert.Contains(t, out.String(), "imported 2 rows: 1 created, 0 updated, 1 failed")
+	assert.Contains(t, out.String(), "line 3 (SKU-2)")
+
+	out.Reset()
+	require.NoError(t, a.ImportCatalog(cfg, catalog.FormatCSV, strings.NewReader("sku,name,categoryies\nSKU-1,Smartphone,Phones\n"), false, &out))
+	require.NoError(t, a.ImportCatalog(cfg, catalog.FormatCSV, strings.NewReader("sku,name,categoryies\nSKU-3,Tablet,Phones\n"), true, &out))
+
+	svc, err = newServices(cfg, a.log)
+	require.NoError(t, err)
+	events, err := svc.outboxRep.PendingEvents(context.Background(), 10)
+	require.NoError(t, err)
+	require.NoError(t, svc.Close())
+
+	var types []model.EventType
+	for _, event := range events {
+		types = append(types, event.Type)
+	}
+	assert.Equal(t, []model.EventType{model.EventCategoryCreated, model.EventProductCreated, model.EventProductRenamed}, types)
+
+	out.Reset()
+	require.NoError(t, a.ExportCatalog(cfg, catalog.FormatJSONL, &out))
+	assert.Contains(t, out.String(), `"sku":"SKU-1"`)
+}
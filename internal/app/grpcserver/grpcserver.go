@@ -0,0 +1,52 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"net"
+
+	"google.golang.org/grpc"
+)
+
+type Server struct {
+	grpcServer *grpc.Server
+}
+
+func New(server *grpc.Server) *Server {
+	return &Server{
+		grpcServer: server,
+	}
+}
+
+// Run принимает вызовы на host:port до вызова Shutdown, после него возвращает nil.
+// Пустой host - все интерфейсы.
+func (s *Server) Run(host, port string) error {
+	lis, err := net.Listen("tcp", net.JoinHostPort(host, port))
+	if err != nil {
+		return err
+	}
+
+	if err := s.grpcServer.Serve(lis); !errors.Is(err, grpc.ErrServerStopped) {
+		return err
+	}
+
+	return nil
+}
+
+// Shutdown перестает принимать вызовы и ждет завершения текущих.
+// Если ctx истекает раньше, оставшиеся вызовы прерываются.
+func (s *Server) Shutdown(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		s.grpcServer.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		s.grpcServer.Stop()
+		return ctx.Err()
+	}
+}
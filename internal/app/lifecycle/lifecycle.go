@@ -0,0 +1,212 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"goapi/internal/lib/logger/sl"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+var (
+	ErrStoppedUnexpectedly = errors.New("component stopped unexpectedly")
+	ErrWorkersTimeout      = errors.New("background workers did not stop in time")
+)
+
+// Config - сроки остановки приложения
+type Config struct {
+	// Delay - пауза между началом остановки и остановкой серверов, за которую
+	// балансировщик успевает увидеть неготовность и перестать присылать запросы
+	Delay time.Duration
+	// WorkerTimeout - сколько ждать завершения фоновых задач после отмены их контекста
+	WorkerTimeout time.Duration
+}
+
+type server struct {
+	name     string
+	timeout  time.Duration
+	serve    func() error
+	shutdown func(ctx context.Context) error
+}
+
+type worker struct {
+	name string
+	run  func(ctx context.Context) error
+}
+
+type closer struct {
+	name  string
+	close func() error
+}
+
+// Manager запускает компоненты приложения и останавливает их по порядку:
+// хуки остановки, серверы (с дозавершением текущих запросов), фоновые задачи,
+// затем закрывает ресурсы в порядке, обратном добавлению.
+// Ошибка любого сервера или задачи до остановки останавливает все приложение.
+type Manager struct {
+	cfg Config
+	log *slog.Logger
+
+	hooks   []func()
+	servers []server
+	workers []worker
+	closers []closer
+}
+
+func New(cfg Config, log *slog.Logger) *Manager {
+	return &Manager{
+		cfg: cfg,
+		log: log,
+	}
+}
+
+// OnShutdown добавляет хук, который выполняется первым при остановке
+func (m *Manager) OnShutdown(hook func()) {
+	m.hooks = append(m.hooks, hook)
+}
+
+// AddServer добавляет сервер: serve работает до остановки, shutdown дозавершает
+// текущие запросы не дольше timeout. После shutdown serve должен вернуть nil.
+func (m *Manager) AddServer(name string, timeout time.Duration, serve func() error, shutdown func(ctx context.Context) error) {
+	m.servers = append(m.servers, server{name: name, timeout: timeout, serve: serve, shutdown: shutdown})
+}
+
+// AddWorker добавляет фоновую задачу, которая работает до отмены ctx.
+// Задачи останавливаются после серверов, чтобы запросы успели завершиться.
+func (m *Manager) AddWorker(name string, run func(ctx context.Context) error) {
+	m.workers = append(m.workers, worker{name: name, run: run})
+}
+
+// AddCloser добавляет ресурс, который закрывается после остановки задач
+func (m *Manager) AddCloser(name string, close func() error) {
+	m.closers = append(m.closers, closer{name: name, close: close})
+}
+
+// Run запускает серверы и задачи и ждет отмены ctx или ошибки одного из них,
+// после чего останавливает приложение. Возвращает причину остановки и ошибки остановки.
+func (m *Manager) Run(ctx context.Context) error {
+	const op = "lifecycle.Run"
+
+	log := m.log.With(
+		slog.String("op", op),
+	)
+
+	// ошибки после начала остановки уже не нужны, буфер не дает горутинам зависнуть
+	fatal := make(chan error, len(m.servers)+len(m.workers))
+
+	for _, s := range m.servers {
+		go func() {
+			err := s.serve()
+			if err == nil {
+				err = ErrStoppedUnexpectedly
+			}
+			fatal <- fmt.Errorf("server %s: %w", s.name, err)
+		}()
+	}
+
+	workersCtx, cancelWorkers := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancelWorkers()
+
+	var wg sync.WaitGroup
+	for _, w := range m.workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			err := w.run(workersCtx)
+			if workersCtx.Err() != nil {
+				return
+			}
+			if err == nil {
+				err = ErrStoppedUnexpectedly
+			}
+			fatal <- fmt.Errorf("worker %s: %w", w.name, err)
+		}()
+	}
+
+	var cause error
+	select {
+	case <-ctx.Done():
+	case cause = <-fatal:
+		log.Error("component failed, shutting down", sl.Err(cause))
+	}
+
+	errs := []error{cause}
+	errs = append(errs, m.shutdown(cause == nil, cancelWorkers, &wg)...)
+
+	return errors.Join(errs...)
+}
+
+// shutdown останавливает компоненты по порядку и возвращает ошибки остановки
+func (m *Manager) shutdown(graceful bool, cancelWorkers context.CancelFunc, wg *sync.WaitGroup) []error {
+	const op = "lifecycle.shutdown"
+
+	log := m.log.With(
+		slog.String("op", op),
+	)
+
+	for _, hook := range m.hooks {
+		hook()
+	}
+
+	// при отказе компонента ждать балансировщик незачем
+	if graceful && m.cfg.Delay > 0 {
+		log.Info("waiting before stopping servers", slog.Duration("delay", m.cfg.Delay))
+		time.Sleep(m.cfg.Delay)
+	}
+
+	var mu sync.Mutex
+	var errs []error
+	appendErr := func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		errs = append(errs, err)
+	}
+
+	var servers sync.WaitGroup
+	for _, s := range m.servers {
+		servers.Add(1)
+		go func() {
+			defer servers.Done()
+
+			ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+			defer cancel()
+
+			if err := s.shutdown(ctx); err != nil {
+				log.Error("failed to shut down server", slog.String("server", s.name), sl.Err(err))
+				appendErr(fmt.Errorf("server %s: %w", s.name, err))
+				return
+			}
+			log.Info("server stopped", slog.String("server", s.name))
+		}()
+	}
+	servers.Wait()
+
+	cancelWorkers()
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		log.Info("background workers stopped")
+	case <-time.After(m.cfg.WorkerTimeout):
+		log.Error("background workers did not stop in time", slog.Duration("timeout", m.cfg.WorkerTimeout))
+		errs = append(errs, ErrWorkersTimeout)
+	}
+
+	for i := len(m.closers) - 1; i >= 0; i-- {
+		c := m.closers[i]
+		if err := c.close(); err != nil {
+			log.Error("failed to close resource", slog.String("resource", c.name), sl.Err(err))
+			errs = append(errs, fmt.Errorf("%s: %w", c.name, err))
+		}
+	}
+
+	return errs
+}
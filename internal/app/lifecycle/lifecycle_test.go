@@ -0,0 +1,189 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// events - журнал событий компонентов в порядке их наступления
+type events struct {
+	mu   sync.Mutex
+	list []string
+}
+
+func (e *events) add(event string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.list = append(e.list, event)
+}
+
+func (e *events) get() []string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return append([]string(nil), e.list...)
+}
+
+// fakeServer работает, пока не вызван shutdown
+type fakeServer struct {
+	name    string
+	events  *events
+	started chan struct{}
+	stopped chan struct{}
+	err     error
+}
+
+func newFakeServer(name string, e *events) *fakeServer {
+	return &fakeServer{name: name, events: e, started: make(chan struct{}), stopped: make(chan struct{})}
+}
+
+func (s *fakeServer) serve() error {
+	close(s.started)
+	if s.err != nil {
+		return s.err
+	}
+
+	<-s.stopped
+	return nil
+}
+
+func (s *fakeServer) shutdown(ctx context.Context) error {
+	s.events.add(s.name + " shutdown")
+	close(s.stopped)
+	return nil
+}
+
+func testLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
+}
+
+// worker записывает свою остановку после отмены контекста, как задача с транзакцией в полете
+func addWorker(m *Manager, name string, e *events) {
+	m.AddWorker(name, func(ctx context.Context) error {
+		<-ctx.Done()
+		time.Sleep(10 * time.Millisecond)
+		e.add(name + " stopped")
+		return nil
+	})
+}
+
+func TestManagerShutdownOrder(t *testing.T) {
+	e := &events{}
+	m := New(Config{WorkerTimeout: time.Second}, testLogger())
+
+	http := newFakeServer("http", e)
+	m.AddServer("http", time.Second, http.serve, http.shutdown)
+	addWorker(m, "collector", e)
+	m.AddCloser("cache", func() error { e.add("cache closed"); return nil })
+	m.AddCloser("db", func() error { e.add("db closed"); return nil })
+	m.OnShutdown(func() { e.add("not ready") })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-http.started
+		cancel()
+	}()
+
+	assert.NoError(t, m.Run(ctx))
+	assert.Equal(t, []string{"not ready", "http shutdown", "collector stopped", "db closed", "cache closed"}, e.get())
+}
+
+func TestManagerFatalError(t *testing.T) {
+	errListen := errors.New("address already in use")
+
+	tests := []struct {
+		name        string
+		setup       func(m *Manager, e *events)
+		expectedErr error
+	}{
+		{
+			name: "ServerFailed",
+			setup: func(m *Manager, e *events) {
+				grpc := newFakeServer("grpc", e)
+				grpc.err = errListen
+				m.AddServer("grpc", time.Second, grpc.serve, func(context.Context) error { return nil })
+			},
+			expectedErr: errListen,
+		},
+		{
+			name: "WorkerFailed",
+			setup: func(m *Manager, e *events) {
+				m.AddWorker("relay", func(context.Context) error { return errListen })
+			},
+			expectedErr: errListen,
+		},
+		{
+			name: "WorkerReturned",
+			setup: func(m *Manager, e *events) {
+				m.AddWorker("relay", func(context.Context) error { return nil })
+			},
+			expectedErr: ErrStoppedUnexpectedly,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			e := &events{}
+			m := New(Config{Delay: time.Hour, WorkerTimeout: time.Second}, testLogger())
+
+			http := newFakeServer("http", e)
+			m.AddServer("http", time.Second, http.serve, http.shutdown)
+			addWorker(m, "collector", e)
+			m.AddCloser("db", func() error { e.add("db closed"); return nil })
+			test.setup(m, e)
+
+			// приложение останавливается само, без отмены контекста и без паузы Delay
+			err := m.Run(context.Background())
+
+			assert.ErrorIs(t, err, test.expectedErr)
+			assert.Equal(t, []string{"http shutdown", "collector stopped", "db closed"}, e.get())
+		})
+	}
+}
+
+func TestManagerWorkerTimeout(t *testing.T) {
+	e := &events{}
+	m := New(Config{WorkerTimeout: 20 * time.Millisecond}, testLogger())
+
+	m.AddWorker("stuck", func(context.Context) error {
+		select {}
+	})
+	m.AddCloser("db", func() error { e.add("db closed"); return nil })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	assert.ErrorIs(t, m.Run(ctx), ErrWorkersTimeout)
+	assert.Equal(t, []string{"db closed"}, e.get())
+}
+
+func TestManagerShutdownErrors(t *testing.T) {
+	errDrain := errors.New("drain timeout")
+	errClose := errors.New("close failed")
+
+	m := New(Config{WorkerTimeout: time.Second}, testLogger())
+	stopped := make(chan struct{})
+	m.AddServer("http", time.Second, func() error {
+		<-stopped
+		return nil
+	}, func(context.Context) error {
+		close(stopped)
+		return errDrain
+	})
+	m.AddCloser("db", func() error { return errClose })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := m.Run(ctx)
+	assert.ErrorIs(t, err, errDrain)
+	assert.ErrorIs(t, err, errClose)
+}
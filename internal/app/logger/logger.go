@@ -11,17 +11,33 @@ const (
 	envProd  = "prod"
 )
 
-func SetupLogger(env string) *slog.Logger {
+// SetupLogger создает логгер для окружения env. Уровень логирования берется из level
+// и может меняться во время работы.
+func SetupLogger(env string, level *slog.LevelVar) *slog.Logger {
 	var log *slog.Logger
 
 	switch env {
 	case envLocal:
-		log = slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
-	case envDev:
-		log = slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
-	case envProd:
-		log = slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo}))
+		log = slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: level}))
+	case envDev, envProd:
+		log = slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: level}))
+	default:
+		log = slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: level}))
 	}
 
 	return log
 }
+
+// Level возвращает уровень логирования name, а если он не задан - уровень по умолчанию для окружения env
+func Level(env, name string) slog.Level {
+	var level slog.Level
+	if name != "" && level.UnmarshalText([]byte(name)) == nil {
+		return level
+	}
+
+	if env == envProd {
+		return slog.LevelInfo
+	}
+
+	return slog.LevelDebug
+}
@@ -0,0 +1,99 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"goapi/internal/config"
+	"goapi/internal/lib/logger/sl"
+	"goapi/internal/repository/sqlstore"
+	"io"
+	"log/slog"
+	"strconv"
+	"text/tabwriter"
+)
+
+var ErrMigrateUsage = errors.New("usage: migrate up | down [N|all] | status")
+
+// Migrate выполняет команду миграций базы из конфига:
+// up применяет все миграции, down [N|all] откатывает N последних (по умолчанию одну),
+// status выводит версию схемы и список миграций в out
+func (a *App) Migrate(cfg *config.Config, args []string, out io.Writer) error {
+	const op = "app.migrate"
+
+	log := a.log.With(
+		slog.String("op", op),
+	)
+
+	if len(args) == 0 {
+		return ErrMigrateUsage
+	}
+
+	db, err := openDB(cfg)
+	if err != nil {
+		log.Error("failed to initialize db", sl.Err(err))
+		return err
+	}
+	defer db.Close()
+
+	migrator := sqlstore.NewMigrator(db, a.log)
+	ctx := context.Background()
+
+	switch args[0] {
+	case "up":
+		return migrator.Up(ctx)
+	case "down":
+		steps, err := downSteps(args[1:])
+		if err != nil {
+			return err
+		}
+		return migrator.Down(ctx, steps)
+	case "status":
+		status, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		return printMigrationStatus(out, status)
+	default:
+		return ErrMigrateUsage
+	}
+}
+
+// downSteps разбирает число откатываемых миграций, all - все миграции
+func downSteps(args []string) (int, error) {
+	switch {
+	case len(args) == 0:
+		return 1, nil
+	case len(args) > 1:
+		return 0, ErrMigrateUsage
+	case args[0] == "all":
+		return 0, nil
+	}
+
+	steps, err := strconv.Atoi(args[0])
+	if err != nil || steps <= 0 {
+		return 0, ErrMigrateUsage
+	}
+
+	return steps, nil
+}
+
+func printMigrationStatus(out io.Writer, status sqlstore.MigrationStatus) error {
+	version := strconv.FormatInt(status.Version, 10)
+	if status.Dirty {
+		version += " (dirty)"
+	}
+	fmt.Fprintf(out, "schema version: %s\n\n", version)
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tSTATUS")
+	for _, migration := range status.Migrations {
+		state := "pending"
+		if migration.Applied {
+			state = "applied"
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\n", migration.Version, migration.Name, state)
+	}
+
+	return w.Flush()
+}
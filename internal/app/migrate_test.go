@@ -0,0 +1,56 @@
+package app
+
+import (
+	"bytes"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"goapi/internal/config"
+)
+
+func TestDownSteps(t *testing.T) {
+	tests := []struct {
+		name          string
+		args          []string
+		expectedSteps int
+		expectedErr   error
+	}{
+		{name: "Default", expectedSteps: 1},
+		{name: "Number", args: []string{"3"}, expectedSteps: 3},
+		{name: "All", args: []string{"all"}, expectedSteps: 0},
+		{name: "Zero", args: []string{"0"}, expectedErr: ErrMigrateUsage},
+		{name: "Not A Number", args: []string{"many"}, expectedErr: ErrMigrateUsage},
+		{name: "Extra Args", args: []string{"1", "2"}, expectedErr: ErrMigrateUsage},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			steps, err := downSteps(test.args)
+			assert.ErrorIs(t, err, test.expectedErr)
+			assert.Equal(t, test.expectedSteps, steps)
+		})
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	a := &App{log: slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))}
+	cfg := &config.Config{
+		StoragePaths: filepath.Join(t.TempDir(), "sso.db"),
+		DBConfig:     config.DataBaseConfig{Driver: "sqlite"},
+	}
+
+	var out bytes.Buffer
+	require.NoError(t, a.Migrate(cfg, []string{"up"}, &out))
+	require.NoError(t, a.Migrate(cfg, []string{"down", "all"}, &out))
+	require.NoError(t, a.Migrate(cfg, []string{"status"}, &out))
+
+	assert.Contains(t, out.String(), "schema version: 0")
+	assert.NotContains(t, out.String(), "applied")
+
+	assert.ErrorIs(t, a.Migrate(cfg, []string{"sideways"}, &out), ErrMigrateUsage)
+	assert.ErrorIs(t, a.Migrate(cfg, nil, &out), ErrMigrateUsage)
+}
@@ -0,0 +1,40 @@
+package outbox
+
+import (
+	"context"
+	"goapi/internal/lib/logger/sl"
+	"log/slog"
+	"time"
+)
+
+type PurgeStore interface {
+	DeleteDeliveredEvents(ctx context.Context, retention time.Duration) (int64, error)
+}
+
+// Purge раз в interval удаляет события, доставленные больше retention назад, пока не отменен ctx.
+// Удаленные события уже нельзя повторить в потоке событий по Last-Event-ID.
+func Purge(ctx context.Context, store PurgeStore, log *slog.Logger, interval, retention time.Duration) {
+	const op = "outbox.Purge"
+
+	log = log.With(
+		slog.String("op", op),
+	)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			n, err := store.DeleteDeliveredEvents(ctx, retention)
+			if err != nil {
+				log.Error("error purge delivered events", sl.Err(err))
+				continue
+			}
+
+			log.Debug("delivered events purged", slog.Int64("count", n))
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package outbox
+
+import (
+	"context"
+	"goapi/internal/lib/logger/sl"
+	"goapi/internal/model"
+	"log/slog"
+	"time"
+)
+
+const (
+	maxRetryDelay = 10 * time.Minute
+)
+
+// Relay доставляет события из outbox получателям.
+// Событие отмечается доставленным только после того, как его приняли все получатели,
+// поэтому при сбое оно будет доставлено повторно (at-least-once). Relay не занимает события,
+// поэтому в кластере он должен работать на одной реплике - под арендой лидера.
+type Relay struct {
+	store     Store
+	sinks     []Sink
+	log       *slog.Logger
+	interval  time.Duration
+	batchSize int
+}
+
+type Store interface {
+	PendingEvents(ctx context.Context, limit int) ([]model.Event, error)
+	MarkEventDelivered(ctx context.Context, id int64) error
+	RetryEvent(ctx context.Context, id int64, delay time.Duration) error
+}
+
+// Sink - получатель событий. Доставка at-least-once и без порядка: событие может прийти
+// повторно, а неудачное событие повторяется позже, когда следующие события того же агрегата
+// уже доставлены. Получатель отбрасывает повторы по Event.ID и не полагается на порядок.
+type Sink interface {
+	Deliver(ctx context.Context, event model.Event) error
+}
+
+func NewRelay(store Store, sinks []Sink, log *slog.Logger, interval time.Duration, batchSize int) *Relay {
+	return &Relay{
+		store:     store,
+		sinks:     sinks,
+		log:       log,
+		interval:  interval,
+		batchSize: batchSize,
+	}
+}
+
+// Run раз в interval доставляет накопившиеся события, пока не отменен ctx
+func (r *Relay) Run(ctx context.Context) {
+	const op = "outbox.Run"
+
+	log := r.log.With(
+		slog.String("op", op),
+	)
+
+	log.Info("outbox relay started")
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info("outbox relay stopped")
+			return
+		case <-ticker.C:
+			// пока outbox отдает полные пачки, продолжаем без ожидания
+			for {
+				n, err := r.relay(ctx)
+				if err != nil {
+					log.Error("error relay events", sl.Err(err))
+					break
+				}
+				if n < r.batchSize || ctx.Err() != nil {
+					break
+				}
+			}
+		}
+	}
+}
+
+// relay доставляет одну пачку событий и возвращает ее размер
+func (r *Relay) relay(ctx context.Context) (int, error) {
+	const op = "outbox.relay"
+
+	log := r.log.With(
+		slog.String("op", op),
+	)
+
+	events, err := r.store.PendingEvents(ctx, r.batchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, event := range events {
+		if err := r.deliver(ctx, event); err != nil {
+			delay := retryDelay(r.interval, event.Attempts)
+			log.Warn("event is not delivered",
+				slog.Int64("id", event.ID),
+				slog.String("type", string(event.Type)),
+				slog.Duration("retry_in", delay),
+				sl.Err(err),
+			)
+
+			if err := r.store.RetryEvent(ctx, event.ID, delay); err != nil {
+				return 0, err
+			}
+			continue
+		}
+
+		if err := r.store.MarkEventDelivered(ctx, event.ID); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(events), nil
+}
+
+func (r *Relay) deliver(ctx context.Context, event model.Event) error {
+	for _, sink := range r.sinks {
+		if err := sink.Deliver(ctx, event); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// retryDelay удваивает задержку с каждой неудачной попыткой, но не больше maxRetryDelay
+func retryDelay(interval time.Duration, attempts int) time.Duration {
+	delay := interval
+	for i := 0; i < attempts && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+
+	return min(delay, maxRetryDelay)
+}
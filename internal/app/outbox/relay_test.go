@@ -0,0 +1,74 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"goapi/internal/model"
+)
+
+type memoryStore struct {
+	events    []model.Event
+	delivered []int64
+	retried   map[int64]time.Duration
+}
+
+func (s *memoryStore) PendingEvents(ctx context.Context, limit int) ([]model.Event, error) {
+	return s.events[:min(limit, len(s.events))], nil
+}
+
+func (s *memoryStore) MarkEventDelivered(ctx context.Context, id int64) error {
+	s.delivered = append(s.delivered, id)
+	return nil
+}
+
+func (s *memoryStore) RetryEvent(ctx context.Context, id int64, delay time.Duration) error {
+	s.retried[id] = delay
+	return nil
+}
+
+type failingSink struct {
+	failID int64
+}
+
+func (s failingSink) Deliver(ctx context.Context, event model.Event) error {
+	if event.ID == s.failID {
+		return errors.New("sink is unavailable")
+	}
+	return nil
+}
+
+func TestRelay(t *testing.T) {
+	store := &memoryStore{
+		events: []model.Event{
+			{ID: 1, Type: model.EventProductCreated, AggregateID: 10},
+			{ID: 2, Type: model.EventProductRenamed, AggregateID: 10, Attempts: 2},
+			{ID: 3, Type: model.EventProductDeleted, AggregateID: 10},
+		},
+		retried: make(map[int64]time.Duration),
+	}
+	memory := NewMemorySink()
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	relay := NewRelay(store, []Sink{memory, failingSink{failID: 2}}, logger, time.Second, 10)
+
+	n, err := relay.relay(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 3, n)
+
+	assert.Equal(t, []int64{1, 3}, store.delivered)
+	assert.Equal(t, map[int64]time.Duration{2: 4 * time.Second}, store.retried)
+	// первый получатель увидит событие 2 повторно - доставка at-least-once
+	assert.Len(t, memory.Events(), 3)
+}
+
+func TestRetryDelay(t *testing.T) {
+	assert.Equal(t, time.Second, retryDelay(time.Second, 0))
+	assert.Equal(t, 8*time.Second, retryDelay(time.Second, 3))
+	assert.Equal(t, maxRetryDelay, retryDelay(time.Second, 100))
+}
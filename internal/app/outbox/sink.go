@@ -0,0 +1,98 @@
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"goapi/internal/model"
+	"log/slog"
+	"net/http"
+	"sync"
+)
+
+// LogSink пишет события в лог
+type LogSink struct {
+	log *slog.Logger
+}
+
+func NewLogSink(log *slog.Logger) *LogSink {
+	return &LogSink{log: log}
+}
+
+func (s *LogSink) Deliver(ctx context.Context, event model.Event) error {
+	s.log.Info("catalog event",
+		slog.Int64("id", event.ID),
+		slog.String("type", string(event.Type)),
+		slog.Int64("aggregate_id", event.AggregateID),
+		slog.String("payload", string(event.Payload)),
+	)
+
+	return nil
+}
+
+// WebhookSink отправляет события POST-запросом с телом JSON.
+// Получатель должен ответить 2xx, иначе доставка повторится.
+type WebhookSink struct {
+	url    string
+	client *http.Client
+}
+
+func NewWebhookSink(url string, client *http.Client) *WebhookSink {
+	return &WebhookSink{
+		url:    url,
+		client: client,
+	}
+}
+
+func (s *WebhookSink) Deliver(ctx context.Context, event model.Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with %s", resp.Status)
+	}
+
+	return nil
+}
+
+// MemorySink запоминает доставленные события, используется в тестах
+type MemorySink struct {
+	mu     sync.Mutex
+	events []model.Event
+}
+
+func NewMemorySink() *MemorySink {
+	return &MemorySink{}
+}
+
+func (s *MemorySink) Deliver(ctx context.Context, event model.Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.events = append(s.events, event)
+
+	return nil
+}
+
+// Events возвращает копию доставленных событий
+func (s *MemorySink) Events() []model.Event {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]model.Event(nil), s.events...)
+}
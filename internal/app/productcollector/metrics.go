@@ -0,0 +1,49 @@
+package productcollector
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	collectorRuns = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "goapi",
+		Subsystem: "collector",
+		Name:      "runs_total",
+		Help:      "Product collection runs by source and result.",
+	}, []string{"source", "result"})
+
+	productsFetched = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "goapi",
+		Subsystem: "collector",
+		Name:      "products_fetched_total",
+		Help:      "Products received from the source api.",
+	}, []string{"source"})
+
+	productsSaved = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "goapi",
+		Subsystem: "collector",
+		Name:      "products_saved_total",
+		Help:      "Valid products saved to the catalog.",
+	}, []string{"source"})
+
+	lastSuccess = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "goapi",
+		Subsystem: "collector",
+		Name:      "last_success_timestamp_seconds",
+		Help:      "Unix time of the last successful collection run.",
+	}, []string{"source"})
+)
+
+// observeRun учитывает завершенный сбор товаров из источника
+func observeRun(source string, err error) {
+	if err != nil {
+		collectorRuns.WithLabelValues(source, "failure").Inc()
+		return
+	}
+
+	collectorRuns.WithLabelValues(source, "success").Inc()
+	lastSuccess.WithLabelValues(source).Set(float64(time.Now().Unix()))
+}
@@ -3,34 +3,88 @@ package productcollector
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"goapi/internal/lib/logger/sl"
+	"goapi/internal/lib/schedule"
+	"goapi/internal/lib/validate"
 	"goapi/internal/model"
 	"log/slog"
 	"net/http"
+	"sync/atomic"
 	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const (
-	apiURL         = "https://petstore.swagger.io/v2/pet/findByStatus?status=available"
-	tickerInterval = 30 * time.Minute
+	apiURL = "https://petstore.swagger.io/v2/pet/findByStatus?status=available"
+
+	DefaultSource = "petstore"
+
+	// stuckIntervals - через сколько промежутков расписания без завершенного сбора сборщик считается зависшим
+	stuckIntervals = 2
+)
+
+var (
+	ErrUnknownSource = errors.New("unknown product source")
+	ErrStuck         = errors.New("product collector is stuck")
 )
 
+var tracer = otel.Tracer("goapi/internal/app/productcollector")
+
+// Source - открытое апи, из которого собираются товары
+type Source struct {
+	Name string
+	URL  string
+}
+
+// sources - известные источники товаров по имени
+var sources = map[string]string{
+	DefaultSource: apiURL,
+}
+
+// SourceByName возвращает источник товаров по имени
+func SourceByName(name string) (Source, error) {
+	url, ok := sources[name]
+	if !ok {
+		return Source{}, fmt.Errorf("%w %q", ErrUnknownSource, name)
+	}
+
+	return Source{Name: name, URL: url}, nil
+}
+
 type ProductCollector struct {
 	ProductSaver
-	log *slog.Logger
+	source   Source
+	schedule schedule.Schedule
+	clock    schedule.Clock
+	log      *slog.Logger
+
+	// deadline - время в наносекундах unix, к которому должен завершиться следующий сбор
+	deadline atomic.Int64
 }
 
 type ProductSaver interface {
 	AddProducts(ctx context.Context, products []model.Product) error
 }
 
-func NewProductCollector(saver ProductSaver, log *slog.Logger) *ProductCollector {
+func NewProductCollector(saver ProductSaver, source Source, sched schedule.Schedule, log *slog.Logger) *ProductCollector {
 	return &ProductCollector{
 		ProductSaver: saver,
+		source:       source,
+		schedule:     sched,
+		clock:        schedule.RealClock{},
 		log:          log,
 	}
 }
 
+// Collect собирает товары сразу после запуска и затем по расписанию, пока не отменен ctx
 func (p *ProductCollector) Collect(ctx context.Context) {
 	const op = "productcollector.Collect"
 
@@ -39,44 +93,134 @@ func (p *ProductCollector) Collect(ctx context.Context) {
 	)
 
 	log.Info("collect product")
+	p.touch()
+
+	schedule.Run(ctx, p.clock, p.schedule, func(ctx context.Context) {
+		if err := p.CollectOnce(ctx); err != nil {
+			log.Error("failed to collect product", sl.Err(err))
+		}
+		p.touch()
+	})
+
+	log.Info("stop collect product")
+}
+
+// Alive проверяет, что сборщик не завис: следующий сбор должен завершиться не позже,
+// чем через stuckIntervals промежутков расписания. Незапущенный сборщик не считается зависшим.
+func (p *ProductCollector) Alive(now time.Time) error {
+	deadline := p.deadline.Load()
+	if deadline == 0 {
+		return nil
+	}
+
+	if late := now.Sub(time.Unix(0, deadline)); late > 0 {
+		return fmt.Errorf("%w: source %s is late for %s", ErrStuck, p.source.Name, late.Round(time.Second))
+	}
+
+	return nil
+}
+
+// touch отмечает запуск или завершение сбора и переносит срок следующего
+func (p *ProductCollector) touch() {
+	now := p.clock.Now()
+	next := p.schedule.Next(now)
+	if next.IsZero() {
+		p.deadline.Store(0)
+		return
+	}
+
+	p.deadline.Store(now.Add(stuckIntervals * next.Sub(now)).UnixNano())
+}
+
+// CollectOnce один раз забирает товары из источника и сохраняет прошедшие валидацию
+func (p *ProductCollector) CollectOnce(ctx context.Context) (err error) {
+	const op = "productcollector.CollectOnce"
 
-	ticker := time.NewTicker(tickerInterval)
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-ctx.Done():
-			fmt.Println("Stopping product data collection...")
-			return
-		case <-ticker.C:
-			log.Info("start collect product")
-
-			response, err := http.Get(apiURL)
-			if err != nil {
-				log.Error("HTTP request failed: %v", err)
-				continue
-			}
-			defer response.Body.Close()
-
-			if response.StatusCode != http.StatusOK {
-				log.Error("HTTP request failed: %s", response.Status)
-				continue
-			}
-
-			var products []model.Product
-			if err := json.NewDecoder(response.Body).Decode(&products); err != nil {
-				log.Error("Failed to decode JSON: %v", err)
-				continue
-			}
-
-			err = p.ProductSaver.AddProducts(ctx, products)
-			if err != nil {
-				log.Error("Failed to save product from api: %v", err)
-				continue
-			}
-			log.Info("collect product successfully")
-		default:
+	log := p.log.With(
+		slog.String("op", op),
+	)
+
+	log.Info("start collect product")
+
+	ctx, span := tracer.Start(ctx, op, trace.WithAttributes(attribute.String("collector.source", p.source.Name)))
+	defer func() {
+		observeRun(p.source.Name, err)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}()
+
+	response, err := p.fetch(ctx)
+	if err != nil {
+		return fmt.Errorf("%s %w", op, err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s unexpected status %s", op, response.Status)
+	}
+
+	var products []model.Product
+	if err := json.NewDecoder(response.Body).Decode(&products); err != nil {
+		return fmt.Errorf("%s %w", op, err)
+	}
+	productsFetched.WithLabelValues(p.source.Name).Add(float64(len(products)))
+
+	products = p.validProducts(products)
+	if len(products) == 0 {
+		log.Warn("no valid products to save")
+		return nil
+	}
+
+	if err := p.ProductSaver.AddProducts(ctx, products); err != nil {
+		return fmt.Errorf("%s %w", op, err)
+	}
+	productsSaved.WithLabelValues(p.source.Name).Add(float64(len(products)))
+
+	log.Info("collect product successfully", slog.Int("count", len(products)))
+
+	return nil
+}
+
+// fetch запрашивает товары у источника в отдельном спане и передает ему trace context в заголовках
+func (p *ProductCollector) fetch(ctx context.Context) (*http.Response, error) {
+	ctx, span := tracer.Start(ctx, http.MethodGet,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.HTTPRequestMethodKey.String(http.MethodGet),
+			semconv.URLFull(p.source.URL),
+		),
+	)
+	defer span.End()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, p.source.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(request.Header))
+
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	span.SetAttributes(semconv.HTTPResponseStatusCode(response.StatusCode))
+
+	return response, nil
+}
+
+// validProducts отбрасывает товары, не прошедшие общие правила валидации сервиса
+func (p *ProductCollector) validProducts(products []model.Product) []model.Product {
+	res := make([]model.Product, 0, len(products))
+	for _, product := range products {
+		if err := validate.Struct(product); err != nil {
+			p.log.Debug("skip invalid product", slog.String("name", product.Name), sl.Err(err))
 			continue
 		}
+		res = append(res, product)
 	}
+
+	return res
 }
@@ -0,0 +1,167 @@
+package productcollector
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"goapi/internal/lib/schedule"
+	"goapi/internal/model"
+)
+
+type memorySaver struct {
+	products []model.Product
+}
+
+func (s *memorySaver) AddProducts(ctx context.Context, products []model.Product) error {
+	s.products = append(s.products, products...)
+	return nil
+}
+
+func TestSourceByName(t *testing.T) {
+	source, err := SourceByName(DefaultSource)
+	require.NoError(t, err)
+	assert.Equal(t, Source{Name: DefaultSource, URL: apiURL}, source)
+
+	_, err = SourceByName("unknown")
+	assert.ErrorIs(t, err, ErrUnknownSource)
+}
+
+func TestCollectOnce(t *testing.T) {
+	tests := []struct {
+		name          string
+		status        int
+		body          string
+		expectedSaved int
+		expectErr     bool
+	}{
+		{
+			name:          "Ok",
+			status:        http.StatusOK,
+			body:          `[{"name":"Rex","categoryies":[{"name":"Dogs"}]},{"name":""}]`,
+			expectedSaved: 1,
+		},
+		{name: "Bad Status", status: http.StatusBadGateway, expectErr: true},
+		{name: "Bad Body", status: http.StatusOK, body: `{`, expectErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(test.status)
+				w.Write([]byte(test.body))
+			}))
+			defer srv.Close()
+
+			saver := &memorySaver{}
+			logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
+			source := Source{Name: "test-" + test.name, URL: srv.URL}
+			collector := NewProductCollector(saver, source, schedule.Every(time.Minute, 0), logger)
+
+			err := collector.CollectOnce(context.Background())
+			if test.expectErr {
+				assert.Error(t, err)
+				assert.Equal(t, 1.0, testutil.ToFloat64(collectorRuns.WithLabelValues(source.Name, "failure")))
+				assert.Zero(t, testutil.ToFloat64(lastSuccess.WithLabelValues(source.Name)))
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, saver.products, test.expectedSaved)
+			assert.Equal(t, 1.0, testutil.ToFloat64(collectorRuns.WithLabelValues(source.Name, "success")))
+			assert.Equal(t, 2.0, testutil.ToFloat64(productsFetched.WithLabelValues(source.Name)))
+			assert.Equal(t, float64(test.expectedSaved), testutil.ToFloat64(productsSaved.WithLabelValues(source.Name)))
+			assert.NotZero(t, testutil.ToFloat64(lastSuccess.WithLabelValues(source.Name)))
+		})
+	}
+}
+
+func TestCollectOnceTracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	var traceparent string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+		w.Write([]byte(`[]`))
+	}))
+	defer srv.Close()
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	collector := NewProductCollector(&memorySaver{}, Source{Name: "traced", URL: srv.URL}, schedule.Every(time.Minute, 0), logger)
+	require.NoError(t, collector.CollectOnce(context.Background()))
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+
+	fetch, run := spans[0], spans[1]
+	assert.Equal(t, "productcollector.CollectOnce", run.Name())
+	assert.Equal(t, run.SpanContext().SpanID(), fetch.Parent().SpanID())
+	assert.Contains(t, traceparent, fetch.SpanContext().TraceID().String())
+	assert.Contains(t, traceparent, fetch.SpanContext().SpanID().String())
+}
+
+func TestCollect(t *testing.T) {
+	hits := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits <- struct{}{}
+		w.Write([]byte(`[]`))
+	}))
+	defer srv.Close()
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	collector := NewProductCollector(&memorySaver{}, Source{Name: "scheduled", URL: srv.URL}, schedule.Every(time.Hour, 0), logger)
+	clock := schedule.NewFakeClock(time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC))
+	collector.clock = clock
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		collector.Collect(ctx)
+	}()
+
+	// первый сбор - сразу после запуска
+	<-hits
+
+	clock.BlockUntil(1)
+	clock.Advance(59 * time.Minute)
+	select {
+	case <-hits:
+		t.Fatal("collected before the interval elapsed")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	clock.Advance(time.Minute)
+	<-hits
+
+	clock.BlockUntil(1)
+	cancel()
+	<-done
+}
+
+func TestAlive(t *testing.T) {
+	collector := NewProductCollector(&memorySaver{}, Source{Name: "test"}, schedule.Every(time.Minute, 0), slog.Default())
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	collector.clock = schedule.NewFakeClock(now)
+
+	// незапущенный сборщик не зависает
+	assert.NoError(t, collector.Alive(now.Add(time.Hour)))
+
+	collector.touch()
+
+	assert.NoError(t, collector.Alive(now.Add(time.Minute)))
+	assert.NoError(t, collector.Alive(now.Add(2*time.Minute)))
+	assert.ErrorIs(t, collector.Alive(now.Add(3*time.Minute)), ErrStuck)
+}
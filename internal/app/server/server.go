@@ -2,6 +2,8 @@ package server
 
 import (
 	"context"
+	"errors"
+	"net"
 	"net/http"
 	"time"
 )
@@ -10,18 +12,40 @@ type Server struct {
 	httpServer *http.Server
 }
 
-func (s *Server) Run(port string, handler http.Handler) error {
-	s.httpServer = &http.Server{
-		Addr:           ":" + port,
-		Handler:        handler,
-		MaxHeaderBytes: 1 << 20, // 1 MB
-		ReadTimeout:    10 * time.Second,
-		WriteTimeout:   10 * time.Second,
+// New создает сервер на адресе host:port. Пустой host - все интерфейсы
+func New(host, port string, handler http.Handler) *Server {
+	return &Server{
+		httpServer: &http.Server{
+			Addr:           net.JoinHostPort(host, port),
+			Handler:        handler,
+			MaxHeaderBytes: 1 << 20, // 1 MB
+			ReadTimeout:    10 * time.Second,
+			WriteTimeout:   10 * time.Second,
+		},
+	}
+}
+
+// Run принимает запросы до вызова Shutdown, после него возвращает nil
+func (s *Server) Run() error {
+	if err := s.httpServer.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return err
 	}
 
-	return s.httpServer.ListenAndServe()
+	return nil
 }
 
+// RegisterOnShutdown регистрирует f, которая вызывается в начале Shutdown. Shutdown ждет
+// завершения запросов, поэтому бесконечные ответы вроде потоков SSE должны закрываться в f.
+func (s *Server) RegisterOnShutdown(f func()) {
+	s.httpServer.RegisterOnShutdown(f)
+}
+
+// Shutdown перестает принимать соединения и ждет завершения текущих запросов.
+// Если ctx истекает раньше, оставшиеся соединения закрываются.
 func (s *Server) Shutdown(ctx context.Context) error {
-	return s.httpServer.Shutdown(ctx)
+	if err := s.httpServer.Shutdown(ctx); err != nil {
+		return errors.Join(err, s.httpServer.Close())
+	}
+
+	return nil
 }
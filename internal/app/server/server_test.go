@@ -0,0 +1,81 @@
+package server
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"goapi/internal/lib/eventstream"
+	"goapi/internal/model"
+)
+
+// emptyOutbox - outbox без событий
+type emptyOutbox struct{}
+
+func (emptyOutbox) LastEventID(ctx context.Context) (int64, error) {
+	return 0, nil
+}
+
+func (emptyOutbox) EventsAfter(ctx context.Context, afterID int64, limit int) ([]model.Event, error) {
+	return nil, nil
+}
+
+func (emptyOutbox) LatestEvents(ctx context.Context, afterID, untilID int64, limit int) ([]model.Event, error) {
+	return nil, nil
+}
+
+func TestShutdownClosesEventStreams(t *testing.T) {
+	hub := eventstream.NewHub(emptyOutbox{}, 10, time.Hour, time.Hour, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	// поток, как и handler.streamEvents, отвечает до отмены запроса или закрытия подписки
+	srv := New("127.0.0.1", "0", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sub, _, err := hub.Subscribe(r.Context(), 0)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		defer hub.Unsubscribe(sub)
+
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case _, ok := <-sub.Events():
+				if !ok {
+					return
+				}
+			}
+		}
+	}))
+	srv.RegisterOnShutdown(hub.Close)
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	served := make(chan error, 1)
+	go func() {
+		served <- srv.httpServer.Serve(l)
+	}()
+
+	resp, err := http.Get("http://" + l.Addr().String())
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	const timeout = 5 * time.Second
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	start := time.Now()
+	assert.NoError(t, srv.Shutdown(ctx))
+	assert.Less(t, time.Since(start), timeout/5)
+	assert.ErrorIs(t, <-served, http.ErrServerClosed)
+}
@@ -0,0 +1,65 @@
+package app
+
+import (
+	"github.com/jmoiron/sqlx"
+	"goapi/internal/config"
+	"goapi/internal/lib/jwt"
+	"goapi/internal/lib/webhook"
+	"goapi/internal/repository/sqlstore"
+	"goapi/internal/service"
+	"log/slog"
+	"net"
+)
+
+// services - репозитории и сервисы поверх одного подключения к базе.
+// Общие для сервера и команд командной строки, чтобы они работали одинаково.
+type services struct {
+	db *sqlx.DB
+
+	outboxRep  *sqlstore.OutboxRepository
+	webhookRep *sqlstore.WebhookRepository
+	leaseRep   *sqlstore.LeaseRepository
+	tokens     *jwt.Signer
+
+	auth        *service.AuthService
+	product     *service.ProductService
+	category    *service.CategoryService
+	catalog     *service.CatalogService
+	idempotency *service.IdempotencyService
+	webhook     *service.WebhookService
+}
+
+// newServices подключается к базе из конфига и собирает сервисы приложения
+func newServices(cfg *config.Config, log *slog.Logger) (*services, error) {
+	db, err := openDB(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	authRep := sqlstore.NewAuthRepository(db, log)
+	productRep := sqlstore.NewProductRepository(db, log)
+	categoryRep := sqlstore.NewCategoryRepository(db, log)
+	idempotencyRep := sqlstore.NewIdempotencyRepository(db, log)
+	outboxRep := sqlstore.NewOutboxRepository(db, log)
+	webhookRep := sqlstore.NewWebhookRepository(db, log)
+	transactor := sqlstore.NewTransactor(db, log)
+	tokens := jwt.NewSigner(cfg.TokenSecret)
+
+	return &services{
+		db:          db,
+		outboxRep:   outboxRep,
+		webhookRep:  webhookRep,
+		leaseRep:    sqlstore.NewLeaseRepository(db, log),
+		tokens:      tokens,
+		auth:        service.NewAuthService(authRep, authRep, tokens, log, cfg.TokenTTL),
+		product:     service.NewProductService(productRep, productRep, productRep, productRep, productRep, transactor, outboxRep, log),
+		category:    service.NewCategoryService(categoryRep, categoryRep, categoryRep, categoryRep, transactor, outboxRep, log),
+		catalog:     service.NewCatalogService(productRep, productRep, transactor, outboxRep, log),
+		idempotency: service.NewIdempotencyService(idempotencyRep, idempotencyRep, idempotencyRep, log, cfg.Idempotency.TTL, cfg.Idempotency.Lease),
+		webhook:     service.NewWebhookService(webhookRep, webhookRep, webhookRep, webhook.NewGuard(net.DefaultResolver), log),
+	}, nil
+}
+
+func (s *services) Close() error {
+	return s.db.Close()
+}
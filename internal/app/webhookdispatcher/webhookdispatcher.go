@@ -0,0 +1,177 @@
+package webhookdispatcher
+
+import (
+	"context"
+	"goapi/internal/lib/logger/sl"
+	"goapi/internal/lib/webhook"
+	"goapi/internal/model"
+	"log/slog"
+	"time"
+)
+
+// retrySchedule - задержки перед повторами доставки. После первой попытки и всех повторов
+// доставка считается неудачной, ее можно отправить заново вручную.
+var retrySchedule = []time.Duration{
+	time.Minute,
+	5 * time.Minute,
+	30 * time.Minute,
+	2 * time.Hour,
+	12 * time.Hour,
+}
+
+// Dispatcher отправляет подписанные доставки подписчикам и ведет журнал попыток
+type Dispatcher struct {
+	store     Store
+	sender    Sender
+	log       *slog.Logger
+	interval  time.Duration
+	batchSize int
+	lease     time.Duration
+	now       func() time.Time
+}
+
+type Store interface {
+	ClaimWebhookDeliveries(ctx context.Context, limit int, lease time.Duration) ([]model.WebhookDispatch, error)
+	RecordWebhookAttempt(
+		ctx context.Context,
+		deliveryID int64,
+		attempt model.WebhookAttempt,
+		status model.WebhookDeliveryStatus,
+		nextAttemptAt *time.Time,
+	) error
+}
+
+type Sender interface {
+	Send(ctx context.Context, r webhook.Request) (webhook.Response, error)
+}
+
+// NewDispatcher создает доставщика. Доставки пачки отправляются по очереди, поэтому
+// занятая пачка удерживается на время отправки всех ее доставок с таймаутом sendTimeout.
+func NewDispatcher(store Store, sender Sender, log *slog.Logger, interval time.Duration, batchSize int, sendTimeout time.Duration) *Dispatcher {
+	return &Dispatcher{
+		store:     store,
+		sender:    sender,
+		log:       log,
+		interval:  interval,
+		batchSize: batchSize,
+		lease:     time.Duration(batchSize)*sendTimeout + interval,
+		now:       time.Now,
+	}
+}
+
+// Run раз в interval отправляет доставки, время которых наступило, пока не отменен ctx
+func (d *Dispatcher) Run(ctx context.Context) {
+	const op = "webhookdispatcher.Run"
+
+	log := d.log.With(
+		slog.String("op", op),
+	)
+
+	log.Info("webhook dispatcher started")
+
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info("webhook dispatcher stopped")
+			return
+		case <-ticker.C:
+			if _, err := d.dispatch(ctx); err != nil {
+				log.Error("error dispatch webhooks", sl.Err(err))
+			}
+		}
+	}
+}
+
+// dispatch занимает и отправляет одну пачку доставок и возвращает ее размер.
+// Несколько доставщиков на разных репликах не отправят одну доставку дважды.
+func (d *Dispatcher) dispatch(ctx context.Context) (int, error) {
+	const op = "webhookdispatcher.dispatch"
+
+	log := d.log.With(
+		slog.String("op", op),
+	)
+
+	dispatches, err := d.store.ClaimWebhookDeliveries(ctx, d.batchSize, d.lease)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, dispatch := range dispatches {
+		attemptedAt := d.now()
+
+		resp, err := d.sender.Send(ctx, webhook.Request{
+			URL:        dispatch.URL,
+			Secret:     dispatch.Secret,
+			DeliveryID: dispatch.DeliveryID,
+			EventType:  string(dispatch.EventType),
+			Body:       dispatch.Event,
+		})
+
+		attempt := model.WebhookAttempt{
+			DurationMs:  resp.Duration.Milliseconds(),
+			AttemptedAt: attemptedAt,
+		}
+		if resp.Code != 0 {
+			attempt.ResponseCode = &resp.Code
+		}
+
+		status := model.WebhookDeliverySucceeded
+		var next *time.Time
+
+		if err != nil {
+			attempt.Error = err.Error()
+			status = model.WebhookDeliveryFailed
+
+			if delay, ok := retryDelay(dispatch.Attempts + 1); ok {
+				status = model.WebhookDeliveryPending
+				at := attemptedAt.Add(delay)
+				next = &at
+			}
+
+			log.Warn("webhook is not delivered",
+				slog.Int64("delivery_id", dispatch.DeliveryID),
+				slog.Int("attempt", dispatch.Attempts+1),
+				slog.String("status", string(status)),
+				sl.Err(err),
+			)
+		}
+
+		if err := d.store.RecordWebhookAttempt(ctx, dispatch.DeliveryID, attempt, status, next); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(dispatches), nil
+}
+
+// retryDelay возвращает задержку перед следующей попыткой после attempts неудачных
+func retryDelay(attempts int) (time.Duration, bool) {
+	if attempts < 1 || attempts > len(retrySchedule) {
+		return 0, false
+	}
+
+	return retrySchedule[attempts-1], true
+}
+
+// Enqueuer ставит доставки события подписчикам в очередь
+type Enqueuer interface {
+	EnqueueWebhookDeliveries(ctx context.Context, event model.Event) (int64, error)
+}
+
+// SubscriptionSink - получатель outbox, раскладывающий события по подпискам.
+// Постановка идемпотентна, поэтому повторная доставка события из outbox не дублирует отправки.
+type SubscriptionSink struct {
+	enqueuer Enqueuer
+}
+
+func NewSubscriptionSink(enqueuer Enqueuer) *SubscriptionSink {
+	return &SubscriptionSink{enqueuer: enqueuer}
+}
+
+func (s *SubscriptionSink) Deliver(ctx context.Context, event model.Event) error {
+	_, err := s.enqueuer.EnqueueWebhookDeliveries(ctx, event)
+	return err
+}
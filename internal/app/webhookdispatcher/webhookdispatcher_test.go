@@ -0,0 +1,153 @@
+package webhookdispatcher
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"goapi/internal/lib/webhook"
+	"goapi/internal/model"
+	"goapi/internal/repository/sqlstore"
+)
+
+type recordedAttempt struct {
+	deliveryID int64
+	attempt    model.WebhookAttempt
+	status     model.WebhookDeliveryStatus
+	next       *time.Time
+}
+
+type memoryStore struct {
+	due      []model.WebhookDispatch
+	attempts []recordedAttempt
+}
+
+func (s *memoryStore) ClaimWebhookDeliveries(ctx context.Context, limit int, lease time.Duration) ([]model.WebhookDispatch, error) {
+	return s.due, nil
+}
+
+func (s *memoryStore) RecordWebhookAttempt(
+	ctx context.Context,
+	deliveryID int64,
+	attempt model.WebhookAttempt,
+	status model.WebhookDeliveryStatus,
+	nextAttemptAt *time.Time,
+) error {
+	s.attempts = append(s.attempts, recordedAttempt{deliveryID, attempt, status, nextAttemptAt})
+	return nil
+}
+
+func TestDispatch(t *testing.T) {
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if err := webhook.Verify("partner-secret-123", r.Header, body, time.Minute, time.Now()); err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer receiver.Close()
+
+	event := []byte(`{"id":1,"type":"product.created","aggregateID":7,"payload":{"productID":7}}`)
+	store := &memoryStore{
+		due: []model.WebhookDispatch{
+			{DeliveryID: 1, URL: receiver.URL, Secret: "partner-secret-123", Event: event, EventType: model.EventProductCreated},
+			{DeliveryID: 2, URL: receiver.URL, Secret: "stale-secret-456", Event: event, EventType: model.EventProductCreated, Attempts: 1},
+			{DeliveryID: 3, URL: receiver.URL, Secret: "stale-secret-456", Event: event, EventType: model.EventProductCreated, Attempts: len(retrySchedule)},
+		},
+	}
+
+	now := time.Unix(1700000000, 0)
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	dispatcher := NewDispatcher(store, webhook.NewClient(receiver.Client()), logger, time.Second, 10, time.Second)
+	dispatcher.now = func() time.Time { return now }
+
+	n, err := dispatcher.dispatch(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 3, n)
+	assert.Len(t, store.attempts, 3)
+
+	ok := store.attempts[0]
+	assert.Equal(t, model.WebhookDeliverySucceeded, ok.status)
+	assert.Equal(t, http.StatusOK, *ok.attempt.ResponseCode)
+	assert.Nil(t, ok.next)
+
+	retry := store.attempts[1]
+	assert.Equal(t, model.WebhookDeliveryPending, retry.status)
+	assert.Equal(t, http.StatusUnauthorized, *retry.attempt.ResponseCode)
+	assert.NotEmpty(t, retry.attempt.Error)
+	assert.Equal(t, now.Add(retrySchedule[1]), *retry.next)
+
+	failed := store.attempts[2]
+	assert.Equal(t, model.WebhookDeliveryFailed, failed.status)
+	assert.Nil(t, failed.next)
+}
+
+func TestDispatchersSendOnce(t *testing.T) {
+	var requests atomic.Int32
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer receiver.Close()
+
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	path := filepath.Join(t.TempDir(), "test.db")
+
+	// у каждой реплики свое подключение к общей базе
+	open := func() *sqlstore.WebhookRepository {
+		db, err := sqlstore.NewSQLiteDB(path)
+		require.NoError(t, err)
+		t.Cleanup(func() { db.Close() })
+
+		return sqlstore.NewWebhookRepository(db, logger)
+	}
+
+	first := open()
+	db, err := sqlstore.NewSQLiteDB(path)
+	require.NoError(t, err)
+	defer db.Close()
+	require.NoError(t, sqlstore.NewMigrator(db, logger).Up(ctx))
+
+	userID, err := sqlstore.NewAuthRepository(db, logger).SaveUser(ctx, "user@example.com", []byte("hash"), model.RoleAdmin)
+	require.NoError(t, err)
+	_, err = first.CreateWebhook(ctx, model.Webhook{UserID: userID, URL: receiver.URL, EventTypes: []string{model.WebhookAllEvents}, Secret: "partner-secret-123"})
+	require.NoError(t, err)
+	_, err = first.EnqueueWebhookDeliveries(ctx, model.Event{ID: 1, Type: model.EventProductCreated, AggregateID: 7})
+	require.NoError(t, err)
+
+	dispatchers := []*Dispatcher{
+		NewDispatcher(first, webhook.NewClient(receiver.Client()), logger, time.Second, 10, time.Second),
+		NewDispatcher(open(), webhook.NewClient(receiver.Client()), logger, time.Second, 10, time.Second),
+	}
+
+	sent := make([]int, len(dispatchers))
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	for i, dispatcher := range dispatchers {
+		wg.Add(1)
+		go func(i int, dispatcher *Dispatcher) {
+			defer wg.Done()
+			<-start
+			n, err := dispatcher.dispatch(ctx)
+			assert.NoError(t, err)
+			sent[i] = n
+		}(i, dispatcher)
+	}
+	close(start)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), requests.Load())
+	assert.Equal(t, 1, sent[0]+sent[1])
+}
@@ -1,55 +1,200 @@
 package config
 
 import (
+	"errors"
 	"flag"
+	"fmt"
 	"os"
 	"time"
 
 	"github.com/ilyakaznacheev/cleanenv"
 )
 
-// Config - структура конфига
+// Config - структура конфига. Любое поле переопределяется переменной окружения
+// с префиксом GOAPI_ и путем поля в yaml, например GOAPI_DB_PASSWORD, а секреты удобнее
+// передавать файлом: GOAPI_DB_PASSWORD_FILE=/run/secrets/db_password.
+// Поля с тегом secret скрываются при выводе конфига, поля с тегом reload применяются
+// по SIGHUP без перезапуска.
 type Config struct {
-	Env          string         `yaml:"env" env-default:"local"`
-	StoragePaths string         `yaml:"storage_paths" env-required:"true"`
-	TokenTTL     time.Duration  `yaml:"token_ttl" env-required:"true"`
-	Port         int            `yaml:"port" env-default:"8080"`
-	SConfig      ServerConfig   `yaml:"server" env-required:"true"`
-	DBConfig     DataBaseConfig `yaml:"db" env-required:"true"`
+	Env          string            `yaml:"env" env-default:"local"`
+	LogLevel     string            `yaml:"log_level" reload:"true"`
+	StoragePaths string            `yaml:"storage_paths"`
+	TokenTTL     time.Duration     `yaml:"token_ttl"`
+	TokenSecret  string            `yaml:"token_secret" secret:"true"`
+	Idempotency  IdempotencyConfig `yaml:"idempotency"`
+	Outbox       OutboxConfig      `yaml:"outbox"`
+	Webhooks     WebhooksConfig    `yaml:"webhooks"`
+	Stream       StreamConfig      `yaml:"stream"`
+	GraphQL      GraphQLConfig     `yaml:"graphql"`
+	Collector    CollectorConfig   `yaml:"collector"`
+	Leader       LeaderConfig      `yaml:"leader_election"`
+	Tracing      TracingConfig     `yaml:"tracing"`
+	Health       HealthConfig      `yaml:"health"`
+	Shutdown     ShutdownConfig    `yaml:"shutdown"`
+	SConfig      ServerConfig      `yaml:"server"`
+	GRPC         GRPCConfig        `yaml:"grpc"`
+	DBConfig     DataBaseConfig    `yaml:"db"`
+
+	// path - файл, из которого загружен конфиг, по нему конфиг перечитывается
+	path string
 }
 
+// ServerConfig - HTTP API. Host - адрес, на котором слушает сервер; пустой - все интерфейсы
 type ServerConfig struct {
-	Port string `yaml:"port" env-default:"8080"`
-	Host string `yaml:"host" env-default:"localhost"`
+	Port            string        `yaml:"port" env-default:"8080"`
+	Host            string        `yaml:"host"`
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" env-default:"15s"`
+}
+
+// HealthConfig - проверки готовности /readyz. CheckTimeout ограничивает каждую проверку
+type HealthConfig struct {
+	CheckTimeout time.Duration `yaml:"check_timeout" env-default:"2s"`
+}
+
+// ShutdownConfig - остановка приложения. Delay - пауза после перехода /readyz в 503
+// до остановки серверов, WorkerTimeout - сколько ждать завершения фоновых задач
+type ShutdownConfig struct {
+	Delay         time.Duration `yaml:"delay"`
+	WorkerTimeout time.Duration `yaml:"worker_timeout" env-default:"30s"`
+}
+
+// GRPCConfig - gRPC API каталога на отдельном порту. Host, как и у HTTP-сервера:
+// пусто - все интерфейсы
+type GRPCConfig struct {
+	Port            string        `yaml:"port" env-default:"9090"`
+	Host            string        `yaml:"host"`
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" env-default:"10s"`
+}
+
+// IdempotencyConfig - время хранения ответов на запросы с Idempotency-Key. Lease - сколько ключ
+// занят выполняющимся запросом: не меньше самого долгого запроса, иначе ответ такого запроса не сохранится,
+// а его повтор выполнится еще раз
+type IdempotencyConfig struct {
+	TTL           time.Duration `yaml:"ttl" env-default:"24h"`
+	Lease         time.Duration `yaml:"lease" env-default:"1m"`
+	PurgeInterval time.Duration `yaml:"purge_interval" env-default:"1h"`
+}
+
+// OutboxConfig - доставка событий каталога. Sinks - список получателей: log, webhook.
+// Доставленные события хранятся Retention и удаляются раз в PurgeInterval
+type OutboxConfig struct {
+	Interval       time.Duration `yaml:"interval" env-default:"1s"`
+	BatchSize      int           `yaml:"batch_size" env-default:"100"`
+	Sinks          []string      `yaml:"sinks" env-default:"log"`
+	WebhookURL     string        `yaml:"webhook_url"`
+	WebhookTimeout time.Duration `yaml:"webhook_timeout" env-default:"5s"`
+	Retention      time.Duration `yaml:"retention" env-default:"168h"`
+	PurgeInterval  time.Duration `yaml:"purge_interval" env-default:"1h"`
+}
+
+// WebhooksConfig - доставка событий подписчикам вебхуков
+type WebhooksConfig struct {
+	Interval  time.Duration `yaml:"interval" env-default:"5s"`
+	BatchSize int           `yaml:"batch_size" env-default:"50"`
+	Timeout   time.Duration `yaml:"timeout" env-default:"10s"`
+}
+
+// StreamConfig - поток событий SSE: сколько событий повторять по Last-Event-ID,
+// как часто читать outbox и интервал heartbeat
+type StreamConfig struct {
+	BufferSize int           `yaml:"buffer_size" env-default:"1000"`
+	Interval   time.Duration `yaml:"interval" env-default:"1s"`
+	Heartbeat  time.Duration `yaml:"heartbeat" env-default:"15s"`
+}
+
+// GraphQLConfig - ограничения запросов GraphQL, 0 отключает ограничение
+type GraphQLConfig struct {
+	MaxDepth      int `yaml:"max_depth" env-default:"10"`
+	MaxComplexity int `yaml:"max_complexity" env-default:"1000"`
+	// MaxListSize - наибольший размер страницы списка, не отключается
+	MaxListSize int `yaml:"max_list_size" env-default:"100"`
 }
 
+// CollectorConfig - сбор товаров из открытых апи: имена источников и расписание.
+// Сборы идут с интервалом Interval плюс случайная задержка до Jitter, либо по Cron, если он задан.
+type CollectorConfig struct {
+	Sources  []string      `yaml:"sources" env-default:"petstore" reload:"true"`
+	Interval time.Duration `yaml:"interval" env-default:"30m" reload:"true"`
+	Jitter   time.Duration `yaml:"jitter" reload:"true"`
+	Cron     string        `yaml:"cron" reload:"true"`
+}
+
+// LeaderConfig - выборы лидера среди реплик через аренду в базе: сборщики товаров и доставка
+// outbox работают только на реплике, которая владеет арендой. Без выборов они работают на
+// каждой реплике, поэтому выключать выборы можно, только если реплика одна.
+type LeaderConfig struct {
+	Enabled       bool          `yaml:"enabled"`
+	LeaseTTL      time.Duration `yaml:"lease_ttl" env-default:"30s"`
+	RenewInterval time.Duration `yaml:"renew_interval" env-default:"10s"`
+}
+
+// TracingConfig - трассировка OpenTelemetry. Exporter: none, stdout (для локальной отладки)
+// или otlp - отправка в коллектор по OTLP/HTTP на endpoint
+type TracingConfig struct {
+	Exporter    string  `yaml:"exporter" env-default:"none"`
+	Endpoint    string  `yaml:"endpoint"`
+	Insecure    bool    `yaml:"insecure"`
+	ServiceName string  `yaml:"service_name" env-default:"goapi"`
+	SampleRatio float64 `yaml:"sample_ratio" env-default:"1"`
+}
+
+// DataBaseConfig - подключение к базе. Driver sqlite хранит базу в файле storage_paths,
+// остальные поля нужны только для postgres.
 type DataBaseConfig struct {
+	Driver   string `yaml:"driver" env-default:"postgres"`
 	Port     string `yaml:"port" env-default:"5436"`
 	Host     string `yaml:"host" env-default:"localhost"`
-	Username string `yaml:"username" env-required:"true"`
-	DBName   string `yaml:"dbname" env-required:"true"`
-	SSLMode  string `yaml:"ssl_mode" env-default:"5436"`
-	Password string `yaml:"password" env-required:"true"`
+	Username string `yaml:"username"`
+	DBName   string `yaml:"dbname"`
+	SSLMode  string `yaml:"ssl_mode" env-default:"disable"`
+	Password string `yaml:"password" secret:"true"`
+	// MigrateOnStart применяет встроенные миграции при запуске приложения. Без значения по умолчанию:
+	// cleanenv подставляет его вместо false из файла.
+	MigrateOnStart bool `yaml:"migrate_on_start"`
 }
 
-// MustLoad получает структуру конфига
+// MustLoad получает структуру конфига по пути из флага --config или CONFIG_PATH
 func MustLoad() *Config {
-	path := fetchConfigFlags()
+	cfg, err := LoadFromFlags()
+	if err != nil {
+		panic(err)
+	}
+
+	return cfg
+}
+
+// LoadFromFlags загружает конфиг по пути из флага --config или CONFIG_PATH
+func LoadFromFlags() (*Config, error) {
+	return Load(fetchConfigFlags())
+}
+
+// Load читает конфиг из файла path, применяет переменные окружения и проверяет результат.
+// Если конфиг прочитан, но не прошел проверку, возвращаются и конфиг, и все ошибки сразу.
+func Load(path string) (*Config, error) {
 	if path == "" {
-		panic("config path is empty")
+		return nil, errors.New("config path is empty")
 	}
 
-	if _, err := os.Stat(path); os.IsNotExist(err) {
-		panic("config file does not exist" + path)
+	if _, err := os.Stat(path); err != nil {
+		return nil, fmt.Errorf("config file is not available: %w", err)
 	}
 
-	var cfg Config
+	cfg := Config{path: path}
 
 	if err := cleanenv.ReadConfig(path, &cfg); err != nil {
-		panic("failed to read config" + err.Error())
+		return nil, fmt.Errorf("failed to read config %s: %w", path, err)
+	}
+
+	errs := applyEnv(&cfg)
+	if err := cfg.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+
+	if len(errs) > 0 {
+		return &cfg, fmt.Errorf("invalid config %s:\n%w", path, errors.Join(errs...))
 	}
 
-	return &cfg
+	return &cfg, nil
 }
 
 // fetchConfigFlags получает путь до конфига либо из флага командной строки либо через переменную окружения
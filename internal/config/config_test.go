@@ -0,0 +1,167 @@
+package config
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testConfig = `
+env: "local"
+token_ttl: "1h"
+token_secret: "test-token-secret-0123456789abcdef"
+server:
+  port: "8000"
+db:
+  driver: "postgres"
+  username: "postgres"
+  dbname: "postgres"
+  password: "qwerty"
+`
+
+func writeConfig(t *testing.T, data string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(data), 0o600))
+	return path
+}
+
+func TestLoadDefaults(t *testing.T) {
+	cfg, err := Load(writeConfig(t, testConfig))
+	require.NoError(t, err)
+
+	assert.Equal(t, "disable", cfg.DBConfig.SSLMode)
+	assert.Equal(t, "5436", cfg.DBConfig.Port)
+	assert.Equal(t, []string{"log"}, cfg.Outbox.Sinks)
+	assert.Equal(t, 168*time.Hour, cfg.Outbox.Retention)
+	assert.False(t, cfg.DBConfig.MigrateOnStart)
+	// сервер по умолчанию слушает на всех интерфейсах
+	assert.Empty(t, cfg.SConfig.Host)
+}
+
+func TestLoadEnvOverrides(t *testing.T) {
+	secret := filepath.Join(t.TempDir(), "db_password")
+	require.NoError(t, os.WriteFile(secret, []byte("from-file\n"), 0o600))
+
+	t.Setenv("GOAPI_TOKEN_TTL", "15m")
+	t.Setenv("GOAPI_SERVER_PORT", "9000")
+	t.Setenv("GOAPI_OUTBOX_SINKS", "log, webhook")
+	t.Setenv("GOAPI_OUTBOX_WEBHOOK_URL", "https://example.com/events")
+	t.Setenv("GOAPI_DB_MIGRATE_ON_START", "true")
+	t.Setenv("GOAPI_DB_PASSWORD_FILE", secret)
+
+	cfg, err := Load(writeConfig(t, testConfig))
+	require.NoError(t, err)
+
+	assert.Equal(t, 15*time.Minute, cfg.TokenTTL)
+	assert.Equal(t, "9000", cfg.SConfig.Port)
+	assert.Equal(t, []string{"log", "webhook"}, cfg.Outbox.Sinks)
+	assert.True(t, cfg.DBConfig.MigrateOnStart)
+	assert.Equal(t, "from-file", cfg.DBConfig.Password)
+}
+
+func TestLoadReportsAllErrors(t *testing.T) {
+	t.Setenv("GOAPI_TOKEN_TTL", "soon")
+	t.Setenv("GOAPI_GRPC_PORT", "70000")
+	t.Setenv("GOAPI_DB_SSL_MODE", "5436")
+	t.Setenv("GOAPI_DB_PASSWORD", "qwerty")
+	t.Setenv("GOAPI_DB_PASSWORD_FILE", "/run/secrets/db_password")
+	t.Setenv("GOAPI_OUTBOX_SINKS", "kafka")
+	t.Setenv("GOAPI_COLLECTOR_CRON", "every hour")
+	t.Setenv("GOAPI_TOKEN_SECRET", "short")
+
+	cfg, err := Load(writeConfig(t, testConfig))
+	require.Error(t, err)
+	require.NotNil(t, cfg)
+
+	for _, msg := range []string{
+		`token_ttl: invalid GOAPI_TOKEN_TTL`,
+		`grpc.port: "70000" is not a valid port`,
+		`db.ssl_mode: "5436" is not one of`,
+		`db.password: both GOAPI_DB_PASSWORD and GOAPI_DB_PASSWORD_FILE are set`,
+		`outbox.sinks: "kafka" is not one of`,
+		`collector.cron: cron "every hour"`,
+		`token_secret: must be at least 32 bytes, got 5`,
+	} {
+		assert.Contains(t, err.Error(), msg)
+	}
+}
+
+func TestLoadMissingFile(t *testing.T) {
+	cfg, err := Load(filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.Error(t, err)
+	assert.Nil(t, cfg)
+
+	_, err = Load("")
+	assert.Error(t, err)
+}
+
+func TestValidateSQLite(t *testing.T) {
+	cfg, err := Load(writeConfig(t, testConfig))
+	require.NoError(t, err)
+
+	cfg.DBConfig = DataBaseConfig{Driver: "sqlite"}
+	assert.ErrorContains(t, cfg.Validate(), "storage_paths: is required by the sqlite driver")
+
+	cfg.StoragePaths = "./storage/sso.db"
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestValidateTokenSecret(t *testing.T) {
+	tests := []struct {
+		name          string
+		env           string
+		secret        string
+		expectedError string
+	}{
+		{name: "Empty", env: "local", secret: "", expectedError: "token_secret: is required"},
+		{name: "Example In Local", env: "local", secret: exampleTokenSecret},
+		{name: "Example In Prod", env: "prod", secret: exampleTokenSecret, expectedError: "token_secret: the example secret is allowed only in env local"},
+		{name: "Own Secret In Prod", env: "prod", secret: "prod-token-secret-0123456789abcdef"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg, err := Load(writeConfig(t, testConfig))
+			require.NoError(t, err)
+
+			cfg.Env = test.env
+			cfg.TokenSecret = test.secret
+
+			err = cfg.Validate()
+			if test.expectedError == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, test.expectedError)
+		})
+	}
+}
+
+// TestExampleConfigHasNoSecrets следит, чтобы секреты не возвращались в общий конфиг
+func TestExampleConfigHasNoSecrets(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("..", "..", "config", "config.yaml"))
+	require.NoError(t, err)
+
+	assert.NotRegexp(t, `(?m)^\s*(token_secret|password):`, string(data))
+}
+
+func TestPrintRedacted(t *testing.T) {
+	cfg, err := Load(writeConfig(t, testConfig))
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	require.NoError(t, cfg.PrintRedacted(&out))
+
+	assert.Contains(t, out.String(), "GOAPI_DB_PASSWORD")
+	assert.Contains(t, out.String(), redacted)
+	assert.NotContains(t, out.String(), "qwerty")
+	assert.NotContains(t, out.String(), "test-token-secret")
+	assert.Contains(t, out.String(), "token_ttl")
+}
@@ -0,0 +1,167 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+const (
+	// EnvPrefix - префикс переменных окружения, переопределяющих поля конфига
+	EnvPrefix = "GOAPI_"
+	// fileSuffix - переменная с этим суффиксом содержит путь к файлу со значением поля
+	fileSuffix = "_FILE"
+
+	redacted = "******"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// field - поле конфига: путь в yaml, имя переменной окружения и значение
+type field struct {
+	key    string
+	env    string
+	secret bool
+	reload bool
+	value  reflect.Value
+}
+
+// fields обходит конфиг и возвращает все поля-значения в порядке объявления
+func fields(cfg *Config) []field {
+	return appendFields(nil, reflect.ValueOf(cfg).Elem(), "")
+}
+
+func appendFields(res []field, v reflect.Value, prefix string) []field {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("yaml"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+
+		key := prefix + name
+		if t.Field(i).Type.Kind() == reflect.Struct {
+			res = appendFields(res, v.Field(i), key+".")
+			continue
+		}
+
+		res = append(res, field{
+			key:    key,
+			env:    EnvPrefix + strings.ToUpper(strings.ReplaceAll(key, ".", "_")),
+			secret: t.Field(i).Tag.Get("secret") == "true",
+			reload: t.Field(i).Tag.Get("reload") == "true",
+			value:  v.Field(i),
+		})
+	}
+
+	return res
+}
+
+// applyEnv переопределяет поля конфига переменными окружения GOAPI_* и GOAPI_*_FILE.
+// Возвращает ошибки всех переменных, а не только первой.
+func applyEnv(cfg *Config) []error {
+	var errs []error
+
+	for _, f := range fields(cfg) {
+		value, ok := os.LookupEnv(f.env)
+
+		if path, fromFile := os.LookupEnv(f.env + fileSuffix); fromFile {
+			if ok {
+				errs = append(errs, fmt.Errorf("%s: both %s and %s%s are set", f.key, f.env, f.env, fileSuffix))
+				continue
+			}
+
+			data, err := os.ReadFile(path)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: read %s%s: %w", f.key, f.env, fileSuffix, err))
+				continue
+			}
+			value, ok = strings.TrimRight(string(data), "\r\n"), true
+		}
+
+		if !ok {
+			continue
+		}
+
+		if err := setValue(f.value, value); err != nil {
+			errs = append(errs, fmt.Errorf("%s: invalid %s: %w", f.key, f.env, err))
+		}
+	}
+
+	return errs
+}
+
+// setValue разбирает строку в поле конфига. Списки разделяются запятыми.
+func setValue(v reflect.Value, s string) error {
+	if v.Type() == durationType {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Int:
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(n))
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Slice:
+		items := make([]string, 0)
+		for _, item := range strings.Split(s, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		v.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("unsupported field type %s", v.Type())
+	}
+
+	return nil
+}
+
+// PrintRedacted выводит итоговый конфиг с переменными окружения для каждого поля,
+// значения секретов скрыты
+func (c *Config) PrintRedacted(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "KEY\tVALUE\tENV")
+
+	for _, f := range fields(c) {
+		value := fmt.Sprint(f.value.Interface())
+		if f.value.Kind() == reflect.Slice {
+			value = strings.Join(f.value.Interface().([]string), ",")
+		}
+		switch {
+		case value == "":
+			value = `""`
+		case f.secret:
+			value = redacted
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", f.key, value, f.env)
+	}
+
+	return tw.Flush()
+}
@@ -0,0 +1,42 @@
+package config
+
+import "reflect"
+
+// Reload заново загружает конфиг из того же файла и переменных окружения
+func (c *Config) Reload() (*Config, error) {
+	return Load(c.path)
+}
+
+// Changes сравнивает конфиги и возвращает измененные поля: применяемые на лету
+// и требующие перезапуска приложения
+func Changes(current, next *Config) (reloadable, restart []string) {
+	nextFields := fields(next)
+	for i, f := range fields(current) {
+		if reflect.DeepEqual(f.value.Interface(), nextFields[i].value.Interface()) {
+			continue
+		}
+
+		if f.reload {
+			reloadable = append(reloadable, f.key)
+		} else {
+			restart = append(restart, f.key)
+		}
+	}
+
+	return reloadable, restart
+}
+
+// WithReloadable возвращает копию конфига, в которую перенесены применяемые на лету поля из next.
+// Остальные поля остаются такими, с какими приложение запущено.
+func (c *Config) WithReloadable(next *Config) *Config {
+	res := *c
+
+	nextFields := fields(next)
+	for i, f := range fields(&res) {
+		if f.reload {
+			f.value.Set(nextFields[i].value)
+		}
+	}
+
+	return &res
+}
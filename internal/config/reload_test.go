@@ -0,0 +1,51 @@
+package config
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReload(t *testing.T) {
+	path := writeConfig(t, testConfig)
+
+	cfg, err := Load(path)
+	require.NoError(t, err)
+
+	changed := strings.Replace(testConfig, `env: "local"`, "env: \"local\"\nlog_level: \"warn\"\ncollector:\n  interval: \"5m\"", 1)
+	changed = strings.Replace(changed, `port: "8000"`, `port: "8001"`, 1)
+	require.NoError(t, os.WriteFile(path, []byte(changed), 0o600))
+
+	next, err := cfg.Reload()
+	require.NoError(t, err)
+
+	reloadable, restart := Changes(cfg, next)
+	assert.Equal(t, []string{"log_level", "collector.interval"}, reloadable)
+	assert.Equal(t, []string{"server.port"}, restart)
+
+	applied := cfg.WithReloadable(next)
+	assert.Equal(t, "warn", applied.LogLevel)
+	assert.Equal(t, 5*time.Minute, applied.Collector.Interval)
+	assert.Equal(t, "8000", applied.SConfig.Port)
+	assert.Empty(t, cfg.LogLevel)
+
+	reloadable, restart = Changes(applied, next)
+	assert.Empty(t, reloadable)
+	assert.Equal(t, []string{"server.port"}, restart)
+}
+
+func TestReloadInvalid(t *testing.T) {
+	path := writeConfig(t, testConfig)
+
+	cfg, err := Load(path)
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(path, []byte(testConfig+"log_level: \"loud\"\n"), 0o600))
+
+	_, err = cfg.Reload()
+	assert.ErrorContains(t, err, "log_level")
+}
@@ -0,0 +1,130 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"goapi/internal/lib/schedule"
+	"log/slog"
+	"slices"
+	"strconv"
+	"time"
+)
+
+const (
+	// minTokenSecret - минимальная длина ключа подписи токенов: HS256 требует не меньше 256 бит
+	minTokenSecret = 32
+	// exampleTokenSecret - ключ из config/local.env.example, он годится только для env local
+	exampleTokenSecret = "local-dev-token-secret-change-me-0000"
+)
+
+var (
+	envs     = []string{"local", "dev", "prod"}
+	drivers  = []string{"postgres", "sqlite"}
+	sslModes = []string{"disable", "allow", "prefer", "require", "verify-ca", "verify-full"}
+	sinks    = []string{"log", "webhook"}
+	tracers  = []string{"none", "stdout", "otlp"}
+)
+
+// Validate проверяет значения конфига и возвращает все найденные ошибки сразу
+func (c *Config) Validate() error {
+	var errs []error
+	check := func(ok bool, key, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf("%s: %s", key, fmt.Sprintf(format, args...)))
+		}
+	}
+	oneOf := func(key, value string, allowed []string) {
+		check(slices.Contains(allowed, value), key, "%q is not one of %v", value, allowed)
+	}
+	positive := func(key string, d time.Duration) {
+		check(d > 0, key, "duration must be positive, got %s", d)
+	}
+	port := func(key, value string) {
+		n, err := strconv.Atoi(value)
+		check(err == nil && n > 0 && n <= 65535, key, "%q is not a valid port", value)
+	}
+
+	oneOf("env", c.Env, envs)
+	if c.LogLevel != "" {
+		var level slog.Level
+		check(level.UnmarshalText([]byte(c.LogLevel)) == nil, "log_level", "%q is not one of [debug info warn error]", c.LogLevel)
+	}
+	positive("token_ttl", c.TokenTTL)
+	check(c.TokenSecret != "", "token_secret", "is required, set GOAPI_TOKEN_SECRET or GOAPI_TOKEN_SECRET_FILE")
+	check(c.TokenSecret == "" || len(c.TokenSecret) >= minTokenSecret, "token_secret",
+		"must be at least %d bytes, got %d", minTokenSecret, len(c.TokenSecret))
+	check(c.Env == "local" || c.TokenSecret != exampleTokenSecret, "token_secret",
+		"the example secret is allowed only in env local, got env %q", c.Env)
+
+	positive("idempotency.ttl", c.Idempotency.TTL)
+	positive("idempotency.lease", c.Idempotency.Lease)
+	check(c.Idempotency.Lease <= c.Idempotency.TTL, "idempotency.lease",
+		"must not exceed ttl %s, got %s", c.Idempotency.TTL, c.Idempotency.Lease)
+	positive("idempotency.purge_interval", c.Idempotency.PurgeInterval)
+
+	positive("outbox.interval", c.Outbox.Interval)
+	positive("outbox.webhook_timeout", c.Outbox.WebhookTimeout)
+	positive("outbox.retention", c.Outbox.Retention)
+	positive("outbox.purge_interval", c.Outbox.PurgeInterval)
+	check(c.Outbox.BatchSize > 0, "outbox.batch_size", "must be positive, got %d", c.Outbox.BatchSize)
+	for _, sink := range c.Outbox.Sinks {
+		oneOf("outbox.sinks", sink, sinks)
+	}
+	check(!slices.Contains(c.Outbox.Sinks, "webhook") || c.Outbox.WebhookURL != "",
+		"outbox.webhook_url", "is required by the webhook sink")
+
+	positive("webhooks.interval", c.Webhooks.Interval)
+	positive("webhooks.timeout", c.Webhooks.Timeout)
+	check(c.Webhooks.BatchSize > 0, "webhooks.batch_size", "must be positive, got %d", c.Webhooks.BatchSize)
+
+	check(c.Stream.BufferSize > 0, "stream.buffer_size", "must be positive, got %d", c.Stream.BufferSize)
+	positive("stream.interval", c.Stream.Interval)
+	positive("stream.heartbeat", c.Stream.Heartbeat)
+
+	check(c.GraphQL.MaxDepth >= 0, "graphql.max_depth", "must not be negative, got %d", c.GraphQL.MaxDepth)
+	check(c.GraphQL.MaxComplexity >= 0, "graphql.max_complexity", "must not be negative, got %d", c.GraphQL.MaxComplexity)
+	check(c.GraphQL.MaxListSize > 0, "graphql.max_list_size", "must be positive, got %d", c.GraphQL.MaxListSize)
+
+	check(len(c.Collector.Sources) > 0, "collector.sources", "at least one source is required")
+	positive("collector.interval", c.Collector.Interval)
+	check(c.Collector.Jitter >= 0, "collector.jitter", "duration must not be negative, got %s", c.Collector.Jitter)
+	if c.Collector.Cron != "" {
+		_, err := schedule.Cron(c.Collector.Cron)
+		check(err == nil, "collector.cron", "%v", err)
+	}
+
+	positive("leader_election.lease_ttl", c.Leader.LeaseTTL)
+	positive("leader_election.renew_interval", c.Leader.RenewInterval)
+	check(c.Leader.RenewInterval < c.Leader.LeaseTTL, "leader_election.renew_interval",
+		"must be less than lease_ttl %s, got %s", c.Leader.LeaseTTL, c.Leader.RenewInterval)
+
+	oneOf("tracing.exporter", c.Tracing.Exporter, tracers)
+	check(c.Tracing.Exporter != "otlp" || c.Tracing.Endpoint != "", "tracing.endpoint", "is required by the otlp exporter")
+	check(c.Tracing.ServiceName != "", "tracing.service_name", "is required")
+	check(c.Tracing.SampleRatio >= 0 && c.Tracing.SampleRatio <= 1, "tracing.sample_ratio", "must be between 0 and 1, got %v", c.Tracing.SampleRatio)
+
+	positive("health.check_timeout", c.Health.CheckTimeout)
+
+	check(c.Shutdown.Delay >= 0, "shutdown.delay", "duration must not be negative, got %s", c.Shutdown.Delay)
+	positive("shutdown.worker_timeout", c.Shutdown.WorkerTimeout)
+
+	port("server.port", c.SConfig.Port)
+	positive("server.shutdown_timeout", c.SConfig.ShutdownTimeout)
+	port("grpc.port", c.GRPC.Port)
+	positive("grpc.shutdown_timeout", c.GRPC.ShutdownTimeout)
+
+	oneOf("db.driver", c.DBConfig.Driver, drivers)
+	switch c.DBConfig.Driver {
+	case "sqlite":
+		check(c.StoragePaths != "", "storage_paths", "is required by the sqlite driver")
+	case "postgres":
+		port("db.port", c.DBConfig.Port)
+		oneOf("db.ssl_mode", c.DBConfig.SSLMode, sslModes)
+		check(c.DBConfig.Host != "", "db.host", "is required")
+		check(c.DBConfig.Username != "", "db.username", "is required")
+		check(c.DBConfig.DBName != "", "db.dbname", "is required")
+		check(c.DBConfig.Password != "", "db.password", "is required")
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,216 @@
+package graphqlapi
+
+import (
+	"context"
+	"fmt"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+	"goapi/internal/lib/apperr"
+	"goapi/internal/lib/logger/sl"
+	"goapi/internal/model"
+	"log/slog"
+)
+
+//go:generate mockgen -source=graphqlapi.go -destination=mock/mock.go
+
+const (
+	internalErrorCode   = "internal_error"
+	internalErrorDetail = "internal server error"
+)
+
+var (
+	ErrMutationNotAllowed = apperr.Validation("mutation_not_allowed", "mutations are allowed only in POST requests")
+)
+
+// Schema - GraphQL API каталога поверх тех же сервисов, что и HTTP API
+type Schema struct {
+	product  ProductService
+	category CategoryService
+	limits   Limits
+	schema   graphql.Schema
+	log      *slog.Logger
+}
+
+type ProductService interface {
+	AddProduct(ctx context.Context, name string, categoryies []string) (int64, error)
+	DeleteProduct(ctx context.Context, id int64, version int64) error
+	EditProductName(ctx context.Context, id int64, name string, version int64) (int64, error)
+	EditProductCategory(ctx context.Context, id int64, categoryies []model.Category, version int64) (int64, error)
+	GetProduct(ctx context.Context, id int64) (model.Product, error)
+	GetAllProducts(ctx context.Context, tag string) ([]model.Product, error)
+	GetCategoryProducts(ctx context.Context, category string) ([]model.Product, error)
+	SearchProducts(ctx context.Context, query string, limit int) ([]model.Product, error)
+	GetProductsCategoryies(ctx context.Context, productIDs []int64) (map[int64][]model.Category, error)
+	GetCategoryiesProducts(ctx context.Context, categoryIDs []int64) (map[int64][]model.Product, error)
+}
+
+type CategoryService interface {
+	AddCategory(ctx context.Context, name string) (int64, error)
+	DeleteCategory(ctx context.Context, id int64, version int64) error
+	EditCategory(ctx context.Context, id int64, name string, version int64) (int64, error)
+	GetCategory(ctx context.Context, id int64) (model.Category, error)
+	GetAllCategoryies(ctx context.Context, tag string) ([]model.Category, error)
+}
+
+// Request - запрос GraphQL. ReadOnly запрещает мутации, например для GET-запросов.
+type Request struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName,omitempty"`
+	Variables     map[string]any `json:"variables,omitempty"`
+	ReadOnly      bool           `json:"-"`
+}
+
+// Response - ответ GraphQL: данные и ошибки с кодом в extensions.code
+type Response struct {
+	Data   any                        `json:"data,omitempty"`
+	Errors []gqlerrors.FormattedError `json:"errors,omitempty"`
+}
+
+func NewSchema(p ProductService, c CategoryService, limits Limits, l *slog.Logger) (*Schema, error) {
+	s := &Schema{
+		product:  p,
+		category: c,
+		limits:   limits,
+		log:      l,
+	}
+
+	schema, err := s.build()
+	if err != nil {
+		return nil, fmt.Errorf("graphqlapi.NewSchema %w", err)
+	}
+	s.schema = schema
+
+	return s, nil
+}
+
+// Execute разбирает запрос, проверяет его и ограничения глубины и сложности, затем выполняет.
+// Загрузчики связей создаются на каждый запрос, поэтому кэш не живет дольше запроса.
+func (s *Schema) Execute(ctx context.Context, req Request) *Response {
+	const op = "graphqlapi.Execute"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("operation", req.OperationName),
+	)
+
+	doc, err := parser.Parse(parser.ParseParams{
+		Source: source.NewSource(&source.Source{Body: []byte(req.Query), Name: "GraphQL request"}),
+	})
+	if err != nil {
+		log.Warn("error parse query", sl.Err(err))
+		return &Response{Errors: formatErrors(gqlerrors.FormatErrors(err))}
+	}
+
+	validation := graphql.ValidateDocument(&s.schema, doc, nil)
+	if !validation.IsValid {
+		log.Warn("query is invalid", slog.Int("errors", len(validation.Errors)))
+		return &Response{Errors: formatErrors(validation.Errors)}
+	}
+
+	operation := findOperation(doc, req.OperationName)
+	if operation != nil && operation.Operation == ast.OperationTypeMutation && req.ReadOnly {
+		return &Response{Errors: formatErrors(gqlerrors.FormatErrors(ErrMutationNotAllowed))}
+	}
+
+	if err := s.limits.check(&s.schema, doc, operation, req.Variables); err != nil {
+		log.Warn("query exceeds limits", sl.Err(err))
+		return &Response{Errors: formatErrors(gqlerrors.FormatErrors(err))}
+	}
+
+	result := graphql.Execute(graphql.ExecuteParams{
+		Schema:        s.schema,
+		AST:           doc,
+		OperationName: req.OperationName,
+		Args:          req.Variables,
+		Context:       withLoaders(ctx, s.newLoaders()),
+	})
+
+	for _, e := range result.Errors {
+		if apperr.KindOf(originalError(e)) == apperr.KindInternal {
+			log.Error("error execute query", sl.Err(originalError(e)))
+		}
+	}
+
+	return &Response{Data: result.Data, Errors: formatErrors(result.Errors)}
+}
+
+// findOperation возвращает выполняемую операцию документа: по имени или единственную
+func findOperation(doc *ast.Document, name string) *ast.OperationDefinition {
+	var found *ast.OperationDefinition
+	for _, def := range doc.Definitions {
+		operation, ok := def.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+
+		if name == "" || (operation.Name != nil && operation.Name.Value == name) {
+			if found != nil && name == "" {
+				return nil
+			}
+			found = operation
+		}
+	}
+
+	return found
+}
+
+// formatErrors добавляет к ошибкам extensions.code. Внутренние ошибки скрываются,
+// как в problem details HTTP API.
+func formatErrors(errs []gqlerrors.FormattedError) []gqlerrors.FormattedError {
+	for i, e := range errs {
+		if e.Extensions != nil {
+			continue
+		}
+
+		orig := originalError(e)
+		if _, ok := orig.(*gqlerrors.Error); ok || orig == nil {
+			// ошибка разбора или проверки запроса
+			errs[i].Extensions = map[string]any{"code": "invalid_query"}
+			continue
+		}
+
+		appErr, ok := apperr.As(orig)
+		if !ok || appErr.Kind == apperr.KindInternal {
+			errs[i].Message = internalErrorDetail
+			errs[i].Extensions = map[string]any{"code": internalErrorCode}
+			continue
+		}
+
+		errs[i].Message = appErr.Message
+		errs[i].Extensions = extensions(appErr)
+	}
+
+	return errs
+}
+
+// originalError достает исходную ошибку резолвера из ошибок graphql-go
+func originalError(err error) error {
+	for {
+		switch e := err.(type) {
+		case gqlerrors.FormattedError:
+			if e.OriginalError() == nil {
+				return err
+			}
+			err = e.OriginalError()
+		case *gqlerrors.Error:
+			if e.OriginalError == nil {
+				return err
+			}
+			err = e.OriginalError
+		default:
+			return err
+		}
+	}
+}
+
+func extensions(err *apperr.Error) map[string]any {
+	ext := map[string]any{"code": err.Code}
+	if len(err.Fields) > 0 {
+		ext["fields"] = err.Fields
+	}
+
+	return ext
+}
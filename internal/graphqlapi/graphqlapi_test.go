@@ -0,0 +1,332 @@
+package graphqlapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/stretchr/testify/assert"
+	mock_graphqlapi "goapi/internal/graphqlapi/mock"
+	"goapi/internal/model"
+	"goapi/internal/service"
+	"log/slog"
+	"os"
+	"testing"
+)
+
+func newTestSchema(t *testing.T, p ProductService, c CategoryService, limits Limits) *Schema {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	s, err := NewSchema(p, c, limits, logger)
+	assert.NoError(t, err)
+
+	return s
+}
+
+// toJSON приводит ответ к виду, который увидит клиент
+func toJSON(t *testing.T, v any) string {
+	data, err := json.Marshal(v)
+	assert.NoError(t, err)
+
+	return string(data)
+}
+
+func TestExecuteBatchesRelations(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	product := mock_graphqlapi.NewMockProductService(ctrl)
+	category := mock_graphqlapi.NewMockCategoryService(ctrl)
+
+	category.EXPECT().GetAllCategoryies(gomock.Any(), service.TagGetAllCategoryies).Return([]model.Category{
+		{ID: 1, Name: "Category1"},
+		{ID: 2, Name: "Category2"},
+	}, nil)
+
+	// связи всех объектов уровня загружаются одним вызовом
+	product.EXPECT().GetCategoryiesProducts(gomock.Any(), []int64{1, 2}).Return(map[int64][]model.Product{
+		1: {{ID: 10, Name: "Product10"}, {ID: 11, Name: "Product11"}},
+		2: {{ID: 10, Name: "Product10"}},
+	}, nil).Times(1)
+	product.EXPECT().GetProductsCategoryies(gomock.Any(), []int64{10, 11}).Return(map[int64][]model.Category{
+		10: {{ID: 1, Name: "Category1"}, {ID: 2, Name: "Category2"}},
+		11: {{ID: 1, Name: "Category1"}},
+	}, nil).Times(1)
+
+	s := newTestSchema(t, product, category, Limits{})
+
+	resp := s.Execute(context.Background(), Request{
+		Query: `{ categoryies { name products { id categoryies { name } } } }`,
+	})
+
+	assert.Empty(t, resp.Errors)
+	assert.JSONEq(t, `{"categoryies":[
+		{"name":"Category1","products":[
+			{"id":"10","categoryies":[{"name":"Category1"},{"name":"Category2"}]},
+			{"id":"11","categoryies":[{"name":"Category1"}]}
+		]},
+		{"name":"Category2","products":[
+			{"id":"10","categoryies":[{"name":"Category1"},{"name":"Category2"}]}
+		]}
+	]}`, toJSON(t, resp.Data))
+}
+
+func TestExecute(t *testing.T) {
+	type mockBehavior func(p *mock_graphqlapi.MockProductService, c *mock_graphqlapi.MockCategoryService)
+
+	tests := []struct {
+		name          string
+		request       Request
+		limits        Limits
+		mockBehavior  mockBehavior
+		expectedData  string
+		expectedCodes []string
+	}{
+		{
+			name:    "Product",
+			request: Request{Query: `query($id: ID!) { product(id: $id) { id name version categoryies { name } } }`, Variables: map[string]any{"id": "1"}},
+			mockBehavior: func(p *mock_graphqlapi.MockProductService, c *mock_graphqlapi.MockCategoryService) {
+				p.EXPECT().GetProduct(gomock.Any(), int64(1)).Return(model.Product{
+					ID: 1, Name: "Product1", Version: 3, Categoryies: []model.Category{{ID: 1, Name: "Category1"}},
+				}, nil)
+			},
+			expectedData: `{"product":{"id":"1","name":"Product1","version":3,"categoryies":[{"name":"Category1"}]}}`,
+		},
+		{
+			name:    "Search",
+			request: Request{Query: `{ search(query: "phone", limit: 5) { name } }`},
+			mockBehavior: func(p *mock_graphqlapi.MockProductService, c *mock_graphqlapi.MockCategoryService) {
+				p.EXPECT().SearchProducts(gomock.Any(), "phone", 5).Return([]model.Product{{ID: 1, Name: "Phone"}}, nil)
+			},
+			expectedData: `{"search":[{"name":"Phone"}]}`,
+		},
+		{
+			name:    "Search Default Limit",
+			request: Request{Query: `{ search(query: "phone") { name } }`},
+			mockBehavior: func(p *mock_graphqlapi.MockProductService, c *mock_graphqlapi.MockCategoryService) {
+				p.EXPECT().SearchProducts(gomock.Any(), "phone", service.DefaultSearchResults).Return([]model.Product{}, nil)
+			},
+			expectedData: `{"search":[]}`,
+		},
+		{
+			name:    "Rename Product",
+			request: Request{Query: `mutation { renameProduct(id: "1", name: "Product2", version: 2) { name version } }`},
+			mockBehavior: func(p *mock_graphqlapi.MockProductService, c *mock_graphqlapi.MockCategoryService) {
+				p.EXPECT().EditProductName(gomock.Any(), int64(1), "Product2", int64(2)).Return(int64(3), nil)
+				p.EXPECT().GetProduct(gomock.Any(), int64(1)).Return(model.Product{ID: 1, Name: "Product2", Version: 3}, nil)
+			},
+			expectedData: `{"renameProduct":{"name":"Product2","version":3}}`,
+		},
+		{
+			name:    "Set Product Categoryies",
+			request: Request{Query: `mutation { setProductCategoryies(id: 1, categoryies: [{id: 2}, {name: "Category3"}], version: 1) { id } }`},
+			mockBehavior: func(p *mock_graphqlapi.MockProductService, c *mock_graphqlapi.MockCategoryService) {
+				p.EXPECT().EditProductCategory(gomock.Any(), int64(1), []model.Category{{ID: 2}, {Name: "Category3"}}, int64(1)).Return(int64(2), nil)
+				p.EXPECT().GetProduct(gomock.Any(), int64(1)).Return(model.Product{ID: 1}, nil)
+			},
+			expectedData: `{"setProductCategoryies":{"id":"1"}}`,
+		},
+		{
+			name:    "Delete Category",
+			request: Request{Query: `mutation { deleteCategory(id: "2", version: 4) }`},
+			mockBehavior: func(p *mock_graphqlapi.MockProductService, c *mock_graphqlapi.MockCategoryService) {
+				c.EXPECT().DeleteCategory(gomock.Any(), int64(2), int64(4)).Return(nil)
+			},
+			expectedData: `{"deleteCategory":true}`,
+		},
+		{
+			name:    "Not Found",
+			request: Request{Query: `{ category(id: "5") { name } }`},
+			mockBehavior: func(p *mock_graphqlapi.MockProductService, c *mock_graphqlapi.MockCategoryService) {
+				c.EXPECT().GetCategory(gomock.Any(), int64(5)).Return(model.Category{}, service.ErrCategoryNotFound)
+			},
+			expectedData:  `{"category":null}`,
+			expectedCodes: []string{"category_not_found"},
+		},
+		{
+			name:          "Invalid ID",
+			request:       Request{Query: `{ product(id: "abc") { name } }`},
+			mockBehavior:  func(p *mock_graphqlapi.MockProductService, c *mock_graphqlapi.MockCategoryService) {},
+			expectedData:  `{"product":null}`,
+			expectedCodes: []string{"invalid_id"},
+		},
+		{
+			name:    "Internal Error",
+			request: Request{Query: `{ products { name } }`},
+			mockBehavior: func(p *mock_graphqlapi.MockProductService, c *mock_graphqlapi.MockCategoryService) {
+				p.EXPECT().GetAllProducts(gomock.Any(), service.TagGetAllProducts).Return(nil, errors.New("pq: connection refused"))
+			},
+			expectedCodes: []string{internalErrorCode},
+		},
+		{
+			name:    "Products Page",
+			request: Request{Query: `{ products(limit: 1, offset: 1) { name } }`},
+			mockBehavior: func(p *mock_graphqlapi.MockProductService, c *mock_graphqlapi.MockCategoryService) {
+				p.EXPECT().GetAllProducts(gomock.Any(), service.TagGetAllProducts).Return([]model.Product{
+					{ID: 1, Name: "Product1"}, {ID: 2, Name: "Product2"}, {ID: 3, Name: "Product3"},
+				}, nil)
+			},
+			expectedData: `{"products":[{"name":"Product2"}]}`,
+		},
+		{
+			name:    "Default Page Is Capped",
+			request: Request{Query: `{ categoryies { name } }`},
+			limits:  Limits{MaxListSize: 2},
+			mockBehavior: func(p *mock_graphqlapi.MockProductService, c *mock_graphqlapi.MockCategoryService) {
+				c.EXPECT().GetAllCategoryies(gomock.Any(), service.TagGetAllCategoryies).Return([]model.Category{
+					{ID: 1, Name: "Category1"}, {ID: 2, Name: "Category2"}, {ID: 3, Name: "Category3"},
+				}, nil)
+			},
+			expectedData: `{"categoryies":[{"name":"Category1"},{"name":"Category2"}]}`,
+		},
+		{
+			name:    "Nested Page",
+			request: Request{Query: `{ product(id: 1) { categoryies(offset: 1) { name } } }`},
+			mockBehavior: func(p *mock_graphqlapi.MockProductService, c *mock_graphqlapi.MockCategoryService) {
+				p.EXPECT().GetProduct(gomock.Any(), int64(1)).Return(model.Product{
+					ID: 1, Categoryies: []model.Category{{ID: 1, Name: "Category1"}, {ID: 2, Name: "Category2"}},
+				}, nil)
+			},
+			expectedData: `{"product":{"categoryies":[{"name":"Category2"}]}}`,
+		},
+		{
+			name:    "Limit Above Max",
+			request: Request{Query: `{ categoryies(limit: 3) { name } }`},
+			limits:  Limits{MaxListSize: 2},
+			mockBehavior: func(p *mock_graphqlapi.MockProductService, c *mock_graphqlapi.MockCategoryService) {
+				c.EXPECT().GetAllCategoryies(gomock.Any(), service.TagGetAllCategoryies).Return([]model.Category{}, nil)
+			},
+			expectedCodes: []string{"invalid_limit"},
+		},
+		{
+			name:    "Negative Offset",
+			request: Request{Query: `{ categoryies(offset: -1) { name } }`},
+			mockBehavior: func(p *mock_graphqlapi.MockProductService, c *mock_graphqlapi.MockCategoryService) {
+				c.EXPECT().GetAllCategoryies(gomock.Any(), service.TagGetAllCategoryies).Return([]model.Category{}, nil)
+			},
+			expectedCodes: []string{"invalid_offset"},
+		},
+		{
+			name:          "Syntax Error",
+			request:       Request{Query: `{ products { name }`},
+			mockBehavior:  func(p *mock_graphqlapi.MockProductService, c *mock_graphqlapi.MockCategoryService) {},
+			expectedCodes: []string{"invalid_query"},
+		},
+		{
+			name:          "Missing Version",
+			request:       Request{Query: `mutation { deleteCategory(id: "2") }`},
+			mockBehavior:  func(p *mock_graphqlapi.MockProductService, c *mock_graphqlapi.MockCategoryService) {},
+			expectedCodes: []string{"invalid_query"},
+		},
+		{
+			name:          "Mutation In Read Only",
+			request:       Request{Query: `mutation { deleteCategory(id: "2", version: 4) }`, ReadOnly: true},
+			mockBehavior:  func(p *mock_graphqlapi.MockProductService, c *mock_graphqlapi.MockCategoryService) {},
+			expectedCodes: []string{"mutation_not_allowed"},
+		},
+		{
+			name:          "Too Deep",
+			request:       Request{Query: `{ categoryies { products { categoryies { products { name } } } } }`},
+			limits:        Limits{MaxDepth: 4},
+			mockBehavior:  func(p *mock_graphqlapi.MockProductService, c *mock_graphqlapi.MockCategoryService) {},
+			expectedCodes: []string{"query_too_deep"},
+		},
+		{
+			name: "Too Deep Through Fragment",
+			request: Request{Query: `
+				{ categoryies { ...withProducts } }
+				fragment withProducts on Category { products { categoryies { products { name } } } }
+			`},
+			limits:        Limits{MaxDepth: 4},
+			mockBehavior:  func(p *mock_graphqlapi.MockProductService, c *mock_graphqlapi.MockCategoryService) {},
+			expectedCodes: []string{"query_too_deep"},
+		},
+		{
+			name:          "Too Complex",
+			request:       Request{Query: `query($limit: Int) { search(query: "a", limit: $limit) { name categoryies { name } } }`, Variables: map[string]any{"limit": float64(100)}},
+			limits:        Limits{MaxComplexity: 500},
+			mockBehavior:  func(p *mock_graphqlapi.MockProductService, c *mock_graphqlapi.MockCategoryService) {},
+			expectedCodes: []string{"query_too_complex"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			product := mock_graphqlapi.NewMockProductService(ctrl)
+			category := mock_graphqlapi.NewMockCategoryService(ctrl)
+			test.mockBehavior(product, category)
+
+			s := newTestSchema(t, product, category, test.limits)
+
+			resp := s.Execute(context.Background(), test.request)
+
+			var codes []string
+			for _, e := range resp.Errors {
+				codes = append(codes, e.Extensions["code"].(string))
+			}
+			assert.Equal(t, test.expectedCodes, codes)
+
+			if test.expectedData != "" {
+				assert.JSONEq(t, test.expectedData, toJSON(t, resp.Data))
+			}
+		})
+	}
+}
+
+func TestLimits(t *testing.T) {
+	s := newTestSchema(t, nil, nil, Limits{})
+
+	tests := []struct {
+		name               string
+		query              string
+		expectedDepth      int
+		expectedComplexity int
+	}{
+		{
+			name:               "Scalar Fields",
+			query:              `{ product(id: 1) { id name } }`,
+			expectedDepth:      2,
+			expectedComplexity: 3,
+		},
+		{
+			name:               "List With Limit",
+			query:              `{ search(query: "a", limit: 5) { id categoryies { name } } }`,
+			expectedDepth:      3,
+			expectedComplexity: 1 + 5*(1+1+defaultMaxListSize),
+		},
+		{
+			name:               "Inline Fragment",
+			query:              `{ categoryies { ... on Category { name } } }`,
+			expectedDepth:      2,
+			expectedComplexity: 1 + defaultMaxListSize,
+		},
+		{
+			name:               "Limit Above Max",
+			query:              `{ categoryies(limit: 100000) { products(limit: 2) { name } } }`,
+			expectedDepth:      3,
+			expectedComplexity: 1 + defaultMaxListSize*(1+2),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			doc, err := parser.Parse(parser.ParseParams{Source: test.query})
+			assert.NoError(t, err)
+
+			operation := findOperation(doc, "")
+
+			limits := Limits{MaxDepth: test.expectedDepth, MaxComplexity: test.expectedComplexity}
+			assert.NoError(t, limits.check(&s.schema, doc, operation, nil))
+
+			limits = Limits{MaxDepth: test.expectedDepth - 1}
+			assert.ErrorIs(t, limits.check(&s.schema, doc, operation, nil), ErrQueryTooDeep)
+
+			limits = Limits{MaxComplexity: test.expectedComplexity - 1}
+			assert.ErrorIs(t, limits.check(&s.schema, doc, operation, nil), ErrQueryTooComplex)
+		})
+	}
+}
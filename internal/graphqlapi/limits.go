@@ -0,0 +1,229 @@
+package graphqlapi
+
+import (
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	"goapi/internal/lib/apperr"
+	"strconv"
+)
+
+const (
+	// defaultMaxListSize - размер страницы списка, если MaxListSize не задан
+	defaultMaxListSize = 100
+)
+
+var (
+	ErrQueryTooDeep    = apperr.Validation("query_too_deep", "query exceeds the maximum depth")
+	ErrQueryTooComplex = apperr.Validation("query_too_complex", "query exceeds the maximum complexity")
+	ErrInvalidLimit    = apperr.Validation("invalid_limit", "limit must be between 1 and the maximum list size")
+	ErrInvalidOffset   = apperr.Validation("invalid_offset", "offset must not be negative")
+)
+
+// Limits - ограничения запроса. Нулевые MaxDepth и MaxComplexity отключают ограничение.
+// Размер страницы любого списка не больше MaxListSize, без аргумента limit отдается
+// полная страница; это ограничение не отключается, 0 означает defaultMaxListSize.
+// Сложность - число полей с учетом размера списков: поле-список умножает стоимость
+// вложенных полей на limit, а без него - на MaxListSize.
+type Limits struct {
+	MaxDepth      int
+	MaxComplexity int
+	MaxListSize   int
+}
+
+// maxListSize - наибольший размер страницы списка
+func (l Limits) maxListSize() int {
+	if l.MaxListSize <= 0 {
+		return defaultMaxListSize
+	}
+
+	return l.MaxListSize
+}
+
+// page возвращает страницу items по аргументам limit и offset поля
+func page[T any](l Limits, args map[string]any, items []T) ([]T, error) {
+	limit, err := l.limit(args)
+	if err != nil {
+		return nil, err
+	}
+
+	offset, _ := args["offset"].(int)
+	if offset < 0 {
+		return nil, ErrInvalidOffset.WithFields(apperr.FieldError{Field: "offset", Message: ErrInvalidOffset.Message})
+	}
+
+	if offset >= len(items) {
+		return []T{}, nil
+	}
+	items = items[offset:]
+
+	return items[:min(limit, len(items))], nil
+}
+
+// limit возвращает аргумент limit поля или MaxListSize, если он не задан
+func (l Limits) limit(args map[string]any) (int, error) {
+	limit, ok := args["limit"].(int)
+	if !ok {
+		return l.maxListSize(), nil
+	}
+
+	if limit < 1 || limit > l.maxListSize() {
+		return 0, ErrInvalidLimit.WithFields(apperr.FieldError{Field: "limit", Message: ErrInvalidLimit.Message})
+	}
+
+	return limit, nil
+}
+
+// check считает глубину и сложность операции до ее выполнения
+func (l Limits) check(schema *graphql.Schema, doc *ast.Document, operation *ast.OperationDefinition, variables map[string]any) error {
+	if operation == nil {
+		return nil
+	}
+
+	w := &limitsWalker{
+		schema:    schema,
+		maxList:   l.maxListSize(),
+		fragments: make(map[string]*ast.FragmentDefinition),
+		variables: variables,
+	}
+	for _, def := range doc.Definitions {
+		if fragment, ok := def.(*ast.FragmentDefinition); ok {
+			w.fragments[fragment.Name.Value] = fragment
+		}
+	}
+
+	var root *graphql.Object
+	switch operation.Operation {
+	case ast.OperationTypeQuery:
+		root = schema.QueryType()
+	case ast.OperationTypeMutation:
+		root = schema.MutationType()
+	}
+
+	depth, complexity := w.selectionSet(operation.SelectionSet, root, map[string]bool{})
+
+	if l.MaxDepth > 0 && depth > l.MaxDepth {
+		return ErrQueryTooDeep
+	}
+
+	if l.MaxComplexity > 0 && complexity > l.MaxComplexity {
+		return ErrQueryTooComplex
+	}
+
+	return nil
+}
+
+type limitsWalker struct {
+	schema    *graphql.Schema
+	maxList   int
+	fragments map[string]*ast.FragmentDefinition
+	variables map[string]any
+}
+
+// selectionSet возвращает глубину и сложность набора полей. visited защищает
+// от циклов фрагментов, хотя их уже отсекает проверка документа.
+func (w *limitsWalker) selectionSet(set *ast.SelectionSet, parent *graphql.Object, visited map[string]bool) (int, int) {
+	if set == nil {
+		return 0, 0
+	}
+
+	var depth, complexity int
+	for _, selection := range set.Selections {
+		var d, c int
+
+		switch s := selection.(type) {
+		case *ast.Field:
+			d, c = w.field(s, parent, visited)
+		case *ast.InlineFragment:
+			d, c = w.selectionSet(s.SelectionSet, w.typeCondition(s.TypeCondition, parent), visited)
+		case *ast.FragmentSpread:
+			fragment, ok := w.fragments[s.Name.Value]
+			if !ok || visited[s.Name.Value] {
+				continue
+			}
+			visited[s.Name.Value] = true
+			d, c = w.selectionSet(fragment.SelectionSet, w.typeCondition(fragment.TypeCondition, parent), visited)
+			delete(visited, s.Name.Value)
+		}
+
+		depth = max(depth, d)
+		complexity += c
+	}
+
+	return depth, complexity
+}
+
+func (w *limitsWalker) field(field *ast.Field, parent *graphql.Object, visited map[string]bool) (int, int) {
+	if field.SelectionSet == nil {
+		return 1, 1
+	}
+
+	var (
+		child *graphql.Object
+		list  bool
+	)
+	if parent != nil {
+		if def, ok := parent.Fields()[field.Name.Value]; ok {
+			child, list = unwrap(def.Type)
+		}
+	}
+
+	depth, complexity := w.selectionSet(field.SelectionSet, child, visited)
+	if list {
+		complexity *= w.listSize(field)
+	}
+
+	return depth + 1, complexity + 1
+}
+
+// listSize - размер списка из аргумента limit, но не больше maxList: больше сервер не отдаст
+func (w *limitsWalker) listSize(field *ast.Field) int {
+	for _, arg := range field.Arguments {
+		if arg.Name.Value != "limit" {
+			continue
+		}
+
+		switch v := arg.Value.(type) {
+		case *ast.IntValue:
+			if n, err := strconv.Atoi(v.Value); err == nil && n > 0 {
+				return min(n, w.maxList)
+			}
+		case *ast.Variable:
+			switch n := w.variables[v.Name.Value].(type) {
+			case int:
+				return min(max(n, 1), w.maxList)
+			case float64:
+				return min(max(int(n), 1), w.maxList)
+			}
+		}
+	}
+
+	return w.maxList
+}
+
+func (w *limitsWalker) typeCondition(named *ast.Named, parent *graphql.Object) *graphql.Object {
+	if named == nil {
+		return parent
+	}
+
+	object, _ := w.schema.Type(named.Name.Value).(*graphql.Object)
+
+	return object
+}
+
+// unwrap снимает NonNull и List с типа поля и сообщает, список ли это
+func unwrap(t graphql.Output) (*graphql.Object, bool) {
+	var list bool
+	for {
+		switch v := t.(type) {
+		case *graphql.NonNull:
+			t = v.OfType
+		case *graphql.List:
+			list = true
+			t = v.OfType
+		case *graphql.Object:
+			return v, list
+		default:
+			return nil, list
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package graphqlapi
+
+import (
+	"context"
+	"goapi/internal/model"
+	"sync"
+)
+
+type loadersKey struct{}
+
+// loaders - загрузчики связей одного запроса
+type loaders struct {
+	productCategoryies *loader[int64, []model.Category]
+	categoryProducts   *loader[int64, []model.Product]
+}
+
+func (s *Schema) newLoaders() *loaders {
+	return &loaders{
+		productCategoryies: newLoader(s.product.GetProductsCategoryies),
+		categoryProducts:   newLoader(s.product.GetCategoryiesProducts),
+	}
+}
+
+func withLoaders(ctx context.Context, l *loaders) context.Context {
+	return context.WithValue(ctx, loadersKey{}, l)
+}
+
+func loadersFrom(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey{}).(*loaders)
+}
+
+// loader откладывает загрузку связей до первого обращения к результату.
+// graphql-go вычисляет отложенные значения одного уровня после того, как собраны все
+// ключи уровня, поэтому связи всех объектов уровня загружаются одним запросом.
+type loader[K comparable, V any] struct {
+	mu      sync.Mutex
+	fetch   func(ctx context.Context, keys []K) (map[K]V, error)
+	pending []K
+	queued  map[K]struct{}
+	cache   map[K]V
+	errs    map[K]error
+}
+
+func newLoader[K comparable, V any](fetch func(ctx context.Context, keys []K) (map[K]V, error)) *loader[K, V] {
+	return &loader[K, V]{
+		fetch:  fetch,
+		queued: make(map[K]struct{}),
+		cache:  make(map[K]V),
+		errs:   make(map[K]error),
+	}
+}
+
+// load ставит ключ в очередь и возвращает отложенный результат, который резолвер
+// оборачивает в функцию func() (any, error) для graphql-go
+func (l *loader[K, V]) load(ctx context.Context, key K) func() (V, error) {
+	l.mu.Lock()
+	_, cached := l.cache[key]
+	_, queued := l.queued[key]
+	if !cached && !queued {
+		l.pending = append(l.pending, key)
+		l.queued[key] = struct{}{}
+	}
+	l.mu.Unlock()
+
+	return func() (V, error) {
+		return l.get(ctx, key)
+	}
+}
+
+func (l *loader[K, V]) get(ctx context.Context, key K) (V, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if _, ok := l.queued[key]; ok {
+		l.flush(ctx)
+	}
+
+	return l.cache[key], l.errs[key]
+}
+
+// flush загружает все ключи из очереди одним запросом
+func (l *loader[K, V]) flush(ctx context.Context) {
+	keys := l.pending
+	l.pending = nil
+
+	values, err := l.fetch(ctx, keys)
+	for _, key := range keys {
+		delete(l.queued, key)
+
+		if err != nil {
+			l.errs[key] = err
+			continue
+		}
+		l.cache[key] = values[key]
+	}
+}
@@ -0,0 +1,387 @@
+package graphqlapi
+
+import (
+	"github.com/graphql-go/graphql"
+	"goapi/internal/lib/apperr"
+	"goapi/internal/model"
+	"goapi/internal/service"
+	"strconv"
+)
+
+var (
+	ErrInvalidID = apperr.Validation("invalid_id", "id must be a positive integer")
+)
+
+// build описывает типы, запросы и мутации каталога
+func (s *Schema) build() (graphql.Schema, error) {
+	category := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Category",
+		Fields: graphql.Fields{
+			"id":      &graphql.Field{Type: graphql.NewNonNull(graphql.ID), Resolve: categoryField(func(c model.Category) any { return c.ID })},
+			"name":    &graphql.Field{Type: graphql.NewNonNull(graphql.String), Resolve: categoryField(func(c model.Category) any { return c.Name })},
+			"version": &graphql.Field{Type: graphql.Int, Resolve: categoryField(func(c model.Category) any { return c.Version })},
+		},
+	})
+
+	product := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Product",
+		Fields: graphql.Fields{
+			"id":      &graphql.Field{Type: graphql.NewNonNull(graphql.ID), Resolve: productField(func(p model.Product) any { return p.ID })},
+			"sku":     &graphql.Field{Type: graphql.String, Resolve: productField(func(p model.Product) any { return p.SKU })},
+			"name":    &graphql.Field{Type: graphql.NewNonNull(graphql.String), Resolve: productField(func(p model.Product) any { return p.Name })},
+			"version": &graphql.Field{Type: graphql.Int, Resolve: productField(func(p model.Product) any { return p.Version })},
+		},
+	})
+
+	products := graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(product)))
+	categoryies := graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(category)))
+
+	limit := &graphql.ArgumentConfig{Type: graphql.Int, Description: "Page size, at most the server list limit"}
+	offset := &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: 0}
+
+	product.AddFieldConfig("categoryies", &graphql.Field{
+		Type:    categoryies,
+		Args:    graphql.FieldConfigArgument{"limit": limit, "offset": offset},
+		Resolve: s.productCategoryies,
+	})
+	category.AddFieldConfig("products", &graphql.Field{
+		Type:    products,
+		Args:    graphql.FieldConfigArgument{"limit": limit, "offset": offset},
+		Resolve: s.categoryProducts,
+	})
+
+	categoryInput := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "CategoryInput",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"id":   &graphql.InputObjectFieldConfig{Type: graphql.ID},
+			"name": &graphql.InputObjectFieldConfig{Type: graphql.String},
+		},
+	})
+
+	id := &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)}
+	name := &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)}
+	version := &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int), Description: "Expected version"}
+
+	query := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"product": &graphql.Field{
+				Type:    product,
+				Args:    graphql.FieldConfigArgument{"id": id},
+				Resolve: s.getProduct,
+			},
+			"products": &graphql.Field{
+				Type: products,
+				Args: graphql.FieldConfigArgument{
+					"category": &graphql.ArgumentConfig{Type: graphql.String},
+					"limit":    limit,
+					"offset":   offset,
+				},
+				Resolve: s.listProducts,
+			},
+			"search": &graphql.Field{
+				Type: products,
+				Args: graphql.FieldConfigArgument{
+					"query": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+					"limit": &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: service.DefaultSearchResults},
+				},
+				Resolve: s.searchProducts,
+			},
+			"category": &graphql.Field{
+				Type:    category,
+				Args:    graphql.FieldConfigArgument{"id": id},
+				Resolve: s.getCategory,
+			},
+			"categoryies": &graphql.Field{
+				Type:    categoryies,
+				Args:    graphql.FieldConfigArgument{"limit": limit, "offset": offset},
+				Resolve: s.listCategoryies,
+			},
+		},
+	})
+
+	mutation := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Mutation",
+		Fields: graphql.Fields{
+			"addProduct": &graphql.Field{
+				Type: graphql.NewNonNull(product),
+				Args: graphql.FieldConfigArgument{
+					"name":        name,
+					"categoryies": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String)))},
+				},
+				Resolve: s.addProduct,
+			},
+			"renameProduct": &graphql.Field{
+				Type:    graphql.NewNonNull(product),
+				Args:    graphql.FieldConfigArgument{"id": id, "name": name, "version": version},
+				Resolve: s.renameProduct,
+			},
+			"setProductCategoryies": &graphql.Field{
+				Type: graphql.NewNonNull(product),
+				Args: graphql.FieldConfigArgument{
+					"id":          id,
+					"categoryies": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(categoryInput)))},
+					"version":     version,
+				},
+				Resolve: s.setProductCategoryies,
+			},
+			"deleteProduct": &graphql.Field{
+				Type:    graphql.NewNonNull(graphql.Boolean),
+				Args:    graphql.FieldConfigArgument{"id": id, "version": version},
+				Resolve: s.deleteProduct,
+			},
+			"addCategory": &graphql.Field{
+				Type:    graphql.NewNonNull(category),
+				Args:    graphql.FieldConfigArgument{"name": name},
+				Resolve: s.addCategory,
+			},
+			"renameCategory": &graphql.Field{
+				Type:    graphql.NewNonNull(category),
+				Args:    graphql.FieldConfigArgument{"id": id, "name": name, "version": version},
+				Resolve: s.renameCategory,
+			},
+			"deleteCategory": &graphql.Field{
+				Type:    graphql.NewNonNull(graphql.Boolean),
+				Args:    graphql.FieldConfigArgument{"id": id, "version": version},
+				Resolve: s.deleteCategory,
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{
+		Query:    query,
+		Mutation: mutation,
+	})
+}
+
+func productField(get func(p model.Product) any) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (any, error) {
+		return get(p.Source.(model.Product)), nil
+	}
+}
+
+func categoryField(get func(c model.Category) any) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (any, error) {
+		return get(p.Source.(model.Category)), nil
+	}
+}
+
+// productCategoryies отдает загруженные категории товара, иначе загружает их пакетом
+func (s *Schema) productCategoryies(p graphql.ResolveParams) (any, error) {
+	product := p.Source.(model.Product)
+	if product.Categoryies != nil {
+		return result(page(s.limits, p.Args, product.Categoryies))
+	}
+
+	load := loadersFrom(p.Context).productCategoryies.load(p.Context, int64(product.ID))
+
+	return func() (any, error) {
+		categoryies, err := load()
+		if err != nil {
+			return nil, err
+		}
+
+		return result(page(s.limits, p.Args, categoryies))
+	}, nil
+}
+
+func (s *Schema) categoryProducts(p graphql.ResolveParams) (any, error) {
+	category := p.Source.(model.Category)
+
+	load := loadersFrom(p.Context).categoryProducts.load(p.Context, int64(category.ID))
+
+	return func() (any, error) {
+		products, err := load()
+		if err != nil {
+			return nil, err
+		}
+
+		return result(page(s.limits, p.Args, products))
+	}, nil
+}
+
+func (s *Schema) getProduct(p graphql.ResolveParams) (any, error) {
+	id, err := argID(p.Args, "id")
+	if err != nil {
+		return nil, err
+	}
+
+	return result(s.product.GetProduct(p.Context, id))
+}
+
+func (s *Schema) listProducts(p graphql.ResolveParams) (any, error) {
+	var (
+		products []model.Product
+		err      error
+	)
+	if category, ok := p.Args["category"].(string); ok {
+		products, err = s.product.GetCategoryProducts(p.Context, category)
+	} else {
+		products, err = s.product.GetAllProducts(p.Context, service.TagGetAllProducts)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return result(page(s.limits, p.Args, products))
+}
+
+func (s *Schema) searchProducts(p graphql.ResolveParams) (any, error) {
+	limit, err := s.limits.limit(p.Args)
+	if err != nil {
+		return nil, err
+	}
+
+	return result(s.product.SearchProducts(p.Context, p.Args["query"].(string), limit))
+}
+
+func (s *Schema) getCategory(p graphql.ResolveParams) (any, error) {
+	id, err := argID(p.Args, "id")
+	if err != nil {
+		return nil, err
+	}
+
+	return result(s.category.GetCategory(p.Context, id))
+}
+
+func (s *Schema) listCategoryies(p graphql.ResolveParams) (any, error) {
+	categoryies, err := s.category.GetAllCategoryies(p.Context, service.TagGetAllCategoryies)
+	if err != nil {
+		return nil, err
+	}
+
+	return result(page(s.limits, p.Args, categoryies))
+}
+
+func (s *Schema) addProduct(p graphql.ResolveParams) (any, error) {
+	var names []string
+	for _, name := range p.Args["categoryies"].([]any) {
+		names = append(names, name.(string))
+	}
+
+	id, err := s.product.AddProduct(p.Context, p.Args["name"].(string), names)
+	if err != nil {
+		return nil, err
+	}
+
+	return result(s.product.GetProduct(p.Context, id))
+}
+
+func (s *Schema) renameProduct(p graphql.ResolveParams) (any, error) {
+	id, err := argID(p.Args, "id")
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.product.EditProductName(p.Context, id, p.Args["name"].(string), argVersion(p.Args)); err != nil {
+		return nil, err
+	}
+
+	return result(s.product.GetProduct(p.Context, id))
+}
+
+func (s *Schema) setProductCategoryies(p graphql.ResolveParams) (any, error) {
+	id, err := argID(p.Args, "id")
+	if err != nil {
+		return nil, err
+	}
+
+	var categoryies []model.Category
+	for _, arg := range p.Args["categoryies"].([]any) {
+		input := arg.(map[string]any)
+
+		var category model.Category
+		if _, ok := input["id"]; ok {
+			categoryID, err := argID(input, "id")
+			if err != nil {
+				return nil, err
+			}
+			category.ID = int(categoryID)
+		}
+		category.Name, _ = input["name"].(string)
+
+		categoryies = append(categoryies, category)
+	}
+
+	if _, err := s.product.EditProductCategory(p.Context, id, categoryies, argVersion(p.Args)); err != nil {
+		return nil, err
+	}
+
+	return result(s.product.GetProduct(p.Context, id))
+}
+
+func (s *Schema) deleteProduct(p graphql.ResolveParams) (any, error) {
+	id, err := argID(p.Args, "id")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.product.DeleteProduct(p.Context, id, argVersion(p.Args)); err != nil {
+		return nil, err
+	}
+
+	return true, nil
+}
+
+func (s *Schema) addCategory(p graphql.ResolveParams) (any, error) {
+	id, err := s.category.AddCategory(p.Context, p.Args["name"].(string))
+	if err != nil {
+		return nil, err
+	}
+
+	return result(s.category.GetCategory(p.Context, id))
+}
+
+func (s *Schema) renameCategory(p graphql.ResolveParams) (any, error) {
+	id, err := argID(p.Args, "id")
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.category.EditCategory(p.Context, id, p.Args["name"].(string), argVersion(p.Args)); err != nil {
+		return nil, err
+	}
+
+	return result(s.category.GetCategory(p.Context, id))
+}
+
+func (s *Schema) deleteCategory(p graphql.ResolveParams) (any, error) {
+	id, err := argID(p.Args, "id")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.category.DeleteCategory(p.Context, id, argVersion(p.Args)); err != nil {
+		return nil, err
+	}
+
+	return true, nil
+}
+
+// result отбрасывает значение при ошибке: graphql-go иначе выведет его в data рядом с ошибкой
+func result[T any](v T, err error) (any, error) {
+	if err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
+
+// argID разбирает аргумент типа ID: graphql-go передает его строкой
+func argID(args map[string]any, name string) (int64, error) {
+	value, _ := args[name].(string)
+
+	id, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || id <= 0 {
+		return 0, ErrInvalidID.WithFields(apperr.FieldError{Field: name, Message: ErrInvalidID.Message})
+	}
+
+	return id, nil
+}
+
+// argVersion возвращает ожидаемую версию, аргумент обязателен по схеме
+func argVersion(args map[string]any) int64 {
+	version, _ := args["version"].(int)
+
+	return int64(version)
+}
@@ -0,0 +1,46 @@
+package grpchandler
+
+import (
+	"context"
+	catalogv1 "goapi/api/catalog/v1"
+	"goapi/internal/lib/logger/logctx"
+	"goapi/internal/lib/logger/sl"
+	"log/slog"
+)
+
+type authServer struct {
+	catalogv1.UnimplementedAuthServiceServer
+	h *Handler
+}
+
+func (s *authServer) SignUp(ctx context.Context, req *catalogv1.SignUpRequest) (*catalogv1.SignUpResponse, error) {
+	const op = "grpchandler.SignUp"
+
+	log := logctx.From(ctx, s.h.log).With(
+		slog.String("op", op),
+	)
+
+	id, err := s.h.auth.Register(ctx, req.GetEmail(), req.GetPassword())
+	if err != nil {
+		log.Warn("error register user", sl.Err(err))
+		return nil, err
+	}
+
+	return &catalogv1.SignUpResponse{UserId: id}, nil
+}
+
+func (s *authServer) SignIn(ctx context.Context, req *catalogv1.SignInRequest) (*catalogv1.SignInResponse, error) {
+	const op = "grpchandler.SignIn"
+
+	log := logctx.From(ctx, s.h.log).With(
+		slog.String("op", op),
+	)
+
+	token, err := s.h.auth.Login(ctx, req.GetEmail(), req.GetPassword())
+	if err != nil {
+		log.Warn("error login user", sl.Err(err))
+		return nil, err
+	}
+
+	return &catalogv1.SignInResponse{Token: token}, nil
+}
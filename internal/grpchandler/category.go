@@ -0,0 +1,114 @@
+package grpchandler
+
+import (
+	"context"
+	catalogv1 "goapi/api/catalog/v1"
+	"goapi/internal/lib/logger/logctx"
+	"goapi/internal/lib/logger/sl"
+	"goapi/internal/service"
+	"log/slog"
+)
+
+type categoryServer struct {
+	catalogv1.UnimplementedCategoryServiceServer
+	h *Handler
+}
+
+func (s *categoryServer) AddCategory(ctx context.Context, req *catalogv1.AddCategoryRequest) (*catalogv1.AddCategoryResponse, error) {
+	const op = "grpchandler.AddCategory"
+
+	log := logctx.From(ctx, s.h.log).With(
+		slog.String("op", op),
+	)
+
+	if _, err := getUserId(ctx); err != nil {
+		return nil, err
+	}
+
+	id, err := s.h.category.AddCategory(ctx, req.GetName())
+	if err != nil {
+		log.Error("error add category", sl.Err(err))
+		return nil, err
+	}
+
+	return &catalogv1.AddCategoryResponse{Id: id}, nil
+}
+
+func (s *categoryServer) GetCategory(ctx context.Context, req *catalogv1.GetCategoryRequest) (*catalogv1.Category, error) {
+	const op = "grpchandler.GetCategory"
+
+	log := logctx.From(ctx, s.h.log).With(
+		slog.String("op", op),
+	)
+
+	if _, err := getUserId(ctx); err != nil {
+		return nil, err
+	}
+
+	category, err := s.h.category.GetCategory(ctx, req.GetId())
+	if err != nil {
+		log.Error("error get category", sl.Err(err))
+		return nil, err
+	}
+
+	return categoryToProto(category), nil
+}
+
+func (s *categoryServer) ListCategoryies(ctx context.Context, req *catalogv1.ListCategoryiesRequest) (*catalogv1.ListCategoryiesResponse, error) {
+	const op = "grpchandler.ListCategoryies"
+
+	log := logctx.From(ctx, s.h.log).With(
+		slog.String("op", op),
+	)
+
+	if _, err := getUserId(ctx); err != nil {
+		return nil, err
+	}
+
+	categoryies, err := s.h.category.GetAllCategoryies(ctx, service.TagGetAllCategoryies)
+	if err != nil {
+		log.Error("error list categoryies", sl.Err(err))
+		return nil, err
+	}
+
+	return &catalogv1.ListCategoryiesResponse{Categoryies: categoryiesToProto(categoryies)}, nil
+}
+
+func (s *categoryServer) RenameCategory(ctx context.Context, req *catalogv1.RenameCategoryRequest) (*catalogv1.VersionResponse, error) {
+	const op = "grpchandler.RenameCategory"
+
+	log := logctx.From(ctx, s.h.log).With(
+		slog.String("op", op),
+	)
+
+	if _, err := getUserId(ctx); err != nil {
+		return nil, err
+	}
+
+	version, err := s.h.category.EditCategory(ctx, req.GetId(), req.GetName(), req.GetVersion())
+	if err != nil {
+		log.Error("error rename category", sl.Err(err))
+		return nil, err
+	}
+
+	return &catalogv1.VersionResponse{Id: req.GetId(), Version: version}, nil
+}
+
+func (s *categoryServer) DeleteCategory(ctx context.Context, req *catalogv1.DeleteCategoryRequest) (*catalogv1.DeleteCategoryResponse, error) {
+	const op = "grpchandler.DeleteCategory"
+
+	log := logctx.From(ctx, s.h.log).With(
+		slog.String("op", op),
+	)
+
+	if _, err := getUserId(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := s.h.category.DeleteCategory(ctx, req.GetId(), req.GetVersion()); err != nil {
+		log.Error("error delete category", sl.Err(err))
+		return nil, err
+	}
+
+	return &catalogv1.DeleteCategoryResponse{}, nil
+}
@@ -0,0 +1,108 @@
+package grpchandler
+
+import (
+	"context"
+	catalogv1 "goapi/api/catalog/v1"
+	"goapi/internal/model"
+	"log/slog"
+
+	"google.golang.org/grpc"
+)
+
+//go:generate mockgen -source=grpchandler.go -destination=mock/mock.go
+
+// Handler - gRPC API каталога поверх тех же сервисов, что и HTTP API
+type Handler struct {
+	auth     AuthService
+	tokens   TokenParser
+	product  ProductService
+	category CategoryService
+	log      *slog.Logger
+}
+
+type AuthService interface {
+	Login(ctx context.Context, email, password string) (token string, err error)
+	Register(ctx context.Context, email, password string) (userID int64, err error)
+}
+
+// TokenParser проверяет подпись токена и возвращает id и роль пользователя
+type TokenParser interface {
+	ParseToken(token string) (int64, model.Role, error)
+}
+
+type ProductService interface {
+	AddProduct(ctx context.Context, name string, categoryies []string) (int64, error)
+	DeleteProduct(ctx context.Context, id int64, version int64) error
+	EditProductName(ctx context.Context, id int64, name string, version int64) (int64, error)
+	EditProductCategory(ctx context.Context, id int64, categoryies []model.Category, version int64) (int64, error)
+	GetProduct(ctx context.Context, id int64) (model.Product, error)
+	GetAllProducts(ctx context.Context, tag string) ([]model.Product, error)
+	GetCategoryProducts(ctx context.Context, category string) ([]model.Product, error)
+}
+
+type CategoryService interface {
+	AddCategory(ctx context.Context, name string) (int64, error)
+	DeleteCategory(ctx context.Context, id int64, version int64) error
+	EditCategory(ctx context.Context, id int64, name string, version int64) (int64, error)
+	GetCategory(ctx context.Context, id int64) (model.Category, error)
+	GetAllCategoryies(ctx context.Context, tag string) ([]model.Category, error)
+}
+
+func NewHandler(a AuthService, t TokenParser, p ProductService, c CategoryService, l *slog.Logger) *Handler {
+	return &Handler{
+		auth:     a,
+		tokens:   t,
+		product:  p,
+		category: c,
+		log:      l,
+	}
+}
+
+// Init создает gRPC-сервер с проверкой токена и зарегистрированными сервисами каталога
+func (h *Handler) Init() *grpc.Server {
+	server := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(h.requestInterceptor, h.errorInterceptor, h.authInterceptor),
+	)
+
+	catalogv1.RegisterAuthServiceServer(server, &authServer{h: h})
+	catalogv1.RegisterProductServiceServer(server, &productServer{h: h})
+	catalogv1.RegisterCategoryServiceServer(server, &categoryServer{h: h})
+
+	return server
+}
+
+func toProto(product model.Product) *catalogv1.Product {
+	return &catalogv1.Product{
+		Id:          int64(product.ID),
+		Sku:         product.SKU,
+		Name:        product.Name,
+		Version:     product.Version,
+		Categoryies: categoryiesToProto(product.Categoryies),
+	}
+}
+
+func categoryToProto(category model.Category) *catalogv1.Category {
+	return &catalogv1.Category{
+		Id:      int64(category.ID),
+		Name:    category.Name,
+		Version: category.Version,
+	}
+}
+
+func categoryiesToProto(categoryies []model.Category) []*catalogv1.Category {
+	res := make([]*catalogv1.Category, 0, len(categoryies))
+	for _, category := range categoryies {
+		res = append(res, categoryToProto(category))
+	}
+
+	return res
+}
+
+func categoryiesFromProto(categoryies []*catalogv1.Category) []model.Category {
+	res := make([]model.Category, 0, len(categoryies))
+	for _, category := range categoryies {
+		res = append(res, model.Category{ID: int(category.GetId()), Name: category.GetName()})
+	}
+
+	return res
+}
@@ -0,0 +1,237 @@
+package grpchandler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	catalogv1 "goapi/api/catalog/v1"
+	mock_grpchandler "goapi/internal/grpchandler/mock"
+	"goapi/internal/lib/apperr"
+	"goapi/internal/lib/jwt"
+	"goapi/internal/lib/requestid"
+	"goapi/internal/model"
+	"log/slog"
+	"os"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// dial запускает сервер на соединении в памяти и возвращает клиентское подключение к нему
+func dial(t *testing.T, h *Handler) *grpc.ClientConn {
+	lis := bufconn.Listen(1 << 20)
+
+	server := h.Init()
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	assert.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return conn
+}
+
+var testSigner = jwt.NewSigner("test-signing-key")
+
+func withToken(t *testing.T, ctx context.Context) context.Context {
+	token, err := testSigner.NewToken(model.User{ID: 1}, time.Minute)
+	assert.NoError(t, err)
+
+	return metadata.AppendToOutgoingContext(ctx, authorizationMetadata, "Bearer "+token)
+}
+
+func TestGetProduct(t *testing.T) {
+	type mockBehavior func(s *mock_grpchandler.MockProductService)
+
+	tests := []struct {
+		name           string
+		auth           bool
+		mockBehavior   mockBehavior
+		expectedCode   codes.Code
+		expectedReason string
+	}{
+		{
+			name: "Ok",
+			auth: true,
+			mockBehavior: func(s *mock_grpchandler.MockProductService) {
+				s.EXPECT().GetProduct(gomock.Any(), int64(1)).Return(model.Product{
+					ID:          1,
+					Name:        "Product1",
+					Version:     2,
+					Categoryies: []model.Category{{ID: 3, Name: "Category1"}},
+				}, nil)
+			},
+			expectedCode: codes.OK,
+		},
+		{
+			name:           "No Token",
+			mockBehavior:   func(s *mock_grpchandler.MockProductService) {},
+			expectedCode:   codes.Unauthenticated,
+			expectedReason: "empty_auth_header",
+		},
+		{
+			name: "Not Found",
+			auth: true,
+			mockBehavior: func(s *mock_grpchandler.MockProductService) {
+				s.EXPECT().GetProduct(gomock.Any(), int64(1)).
+					Return(model.Product{}, apperr.NotFound("product_not_found", "product not found"))
+			},
+			expectedCode:   codes.NotFound,
+			expectedReason: "product_not_found",
+		},
+		{
+			name: "Internal",
+			auth: true,
+			mockBehavior: func(s *mock_grpchandler.MockProductService) {
+				s.EXPECT().GetProduct(gomock.Any(), int64(1)).
+					Return(model.Product{}, context.DeadlineExceeded)
+			},
+			expectedCode:   codes.Internal,
+			expectedReason: internalErrorCode,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockProductService := mock_grpchandler.NewMockProductService(ctrl)
+			test.mockBehavior(mockProductService)
+
+			logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
+			client := catalogv1.NewProductServiceClient(dial(t, NewHandler(nil, testSigner, mockProductService, nil, logger)))
+
+			ctx := context.Background()
+			if test.auth {
+				ctx = withToken(t, ctx)
+			}
+
+			product, err := client.GetProduct(ctx, &catalogv1.GetProductRequest{Id: 1})
+
+			st := status.Convert(err)
+			assert.Equal(t, test.expectedCode, st.Code())
+
+			if test.expectedCode == codes.OK {
+				assert.Equal(t, "Product1", product.GetName())
+				assert.Equal(t, int64(2), product.GetVersion())
+				assert.Equal(t, "Category1", product.GetCategoryies()[0].GetName())
+				return
+			}
+
+			var reason string
+			for _, detail := range st.Details() {
+				if info, ok := detail.(*errdetails.ErrorInfo); ok {
+					reason = info.GetReason()
+				}
+			}
+			assert.Equal(t, test.expectedReason, reason)
+		})
+	}
+}
+
+func TestSignInWithoutToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAuthService := mock_grpchandler.NewMockAuthService(ctrl)
+	mockAuthService.EXPECT().Login(gomock.Any(), "user@example.com", "password").Return("token", nil)
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	client := catalogv1.NewAuthServiceClient(dial(t, NewHandler(mockAuthService, testSigner, nil, nil, logger)))
+
+	resp, err := client.SignIn(context.Background(), &catalogv1.SignInRequest{Email: "user@example.com", Password: "password"})
+	assert.NoError(t, err)
+	assert.Equal(t, "token", resp.GetToken())
+}
+
+func TestRenameCategoryValidation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCategoryService := mock_grpchandler.NewMockCategoryService(ctrl)
+	mockCategoryService.EXPECT().EditCategory(gomock.Any(), int64(1), "", int64(3)).
+		Return(int64(0), apperr.InvalidField("category_name_empty", "/name", "category name is empty"))
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	client := catalogv1.NewCategoryServiceClient(dial(t, NewHandler(nil, testSigner, nil, mockCategoryService, logger)))
+
+	_, err := client.RenameCategory(withToken(t, context.Background()), &catalogv1.RenameCategoryRequest{Id: 1, Version: 3})
+
+	st := status.Convert(err)
+	assert.Equal(t, codes.InvalidArgument, st.Code())
+
+	var violations []*errdetails.BadRequest_FieldViolation
+	for _, detail := range st.Details() {
+		if badRequest, ok := detail.(*errdetails.BadRequest); ok {
+			violations = badRequest.GetFieldViolations()
+		}
+	}
+	assert.Len(t, violations, 1)
+	assert.Equal(t, "/name", violations[0].GetField())
+}
+
+func TestRequestID(t *testing.T) {
+	tests := []struct {
+		name       string
+		requestID  string
+		expectedID string
+	}{
+		{name: "Generated"},
+		{name: "FromClient", requestID: "client-id-1", expectedID: "client-id-1"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockAuthService := mock_grpchandler.NewMockAuthService(ctrl)
+			mockAuthService.EXPECT().Login(gomock.Any(), "user@example.com", "password").Return("token", nil)
+
+			var buf bytes.Buffer
+			logger := slog.New(slog.NewJSONHandler(&buf, nil))
+			client := catalogv1.NewAuthServiceClient(dial(t, NewHandler(mockAuthService, testSigner, nil, nil, logger)))
+
+			ctx := context.Background()
+			if test.requestID != "" {
+				ctx = metadata.AppendToOutgoingContext(ctx, requestIDMetadata, test.requestID)
+			}
+
+			var header metadata.MD
+			_, err := client.SignIn(ctx, &catalogv1.SignInRequest{Email: "user@example.com", Password: "password"}, grpc.Header(&header))
+			assert.NoError(t, err)
+
+			values := header.Get(requestIDMetadata)
+			assert.Len(t, values, 1)
+			assert.True(t, requestid.Valid(values[0]))
+			if test.expectedID != "" {
+				assert.Equal(t, test.expectedID, values[0])
+			}
+
+			var access map[string]any
+			assert.NoError(t, json.Unmarshal(buf.Bytes(), &access))
+			assert.Equal(t, "request", access["msg"])
+			assert.Equal(t, values[0], access["request_id"])
+			assert.Equal(t, "/catalog.v1.AuthService/SignIn", access["method"])
+			assert.Equal(t, codes.OK.String(), access["code"])
+		})
+	}
+}
@@ -0,0 +1,207 @@
+package grpchandler
+
+import (
+	"context"
+	"goapi/internal/lib/apperr"
+	"goapi/internal/lib/authctx"
+	"goapi/internal/lib/logger/logctx"
+	"goapi/internal/lib/logger/sl"
+	"goapi/internal/lib/requestid"
+	"log/slog"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+)
+
+const (
+	authorizationMetadata = "authorization"
+	requestIDMetadata     = "x-request-id"
+	authServicePrefix     = "/catalog.v1.AuthService/"
+
+	errorDomain         = "goapi"
+	internalErrorCode   = "internal_error"
+	internalErrorDetail = "internal server error"
+)
+
+var (
+	ErrEmptyAuthMetadata   = apperr.Unauthorized("empty_auth_header", "empty auth header")
+	ErrInvalidAuthMetadata = apperr.Unauthorized("invalid_auth_header", "invalid auth header")
+	ErrInvalidToken        = apperr.Unauthorized("invalid_token", "invalid token")
+	ErrUserIDNotFound      = apperr.Unauthorized("user_id_not_found", "user id not found")
+)
+
+type userKey struct{}
+
+// authInterceptor проверяет токен из метаданных authorization для всех методов, кроме AuthService
+func (h *Handler) authInterceptor(
+	ctx context.Context,
+	req any,
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (any, error) {
+	if strings.HasPrefix(info.FullMethod, authServicePrefix) {
+		return handler(ctx, req)
+	}
+
+	values := metadata.ValueFromIncomingContext(ctx, authorizationMetadata)
+	if len(values) == 0 || values[0] == "" {
+		return nil, ErrEmptyAuthMetadata
+	}
+
+	parts := strings.Split(values[0], " ")
+	if len(parts) != 2 || parts[0] != "Bearer" || parts[1] == "" {
+		return nil, ErrInvalidAuthMetadata
+	}
+
+	userID, role, err := h.tokens.ParseToken(parts[1])
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	ctx = authctx.With(ctx, authctx.User{ID: userID, Role: role})
+	ctx = logctx.With(ctx, logctx.From(ctx, h.log).With(slog.Int64("user_id", userID)))
+
+	return handler(context.WithValue(ctx, userKey{}, userID), req)
+}
+
+// requestInterceptor присваивает вызову идентификатор из метаданных x-request-id (или новый),
+// возвращает его в заголовке ответа, кладет в контекст логгер с request_id и пишет access log
+func (h *Handler) requestInterceptor(
+	ctx context.Context,
+	req any,
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (any, error) {
+	start := time.Now()
+
+	var id string
+	if values := metadata.ValueFromIncomingContext(ctx, requestIDMetadata); len(values) > 0 {
+		id = values[0]
+	}
+	id = requestid.FromClient(id)
+	_ = grpc.SetHeader(ctx, metadata.Pairs(requestIDMetadata, id))
+
+	log := h.log.With(slog.String("request_id", id))
+	if span := trace.SpanContextFromContext(ctx); span.HasTraceID() {
+		log = log.With(slog.String("trace_id", span.TraceID().String()))
+	}
+	ctx = logctx.With(ctx, log)
+
+	resp, err := handler(ctx, req)
+
+	code := status.Code(err)
+	level := slog.LevelInfo
+	switch code {
+	case codes.OK:
+	case codes.Internal, codes.Unknown:
+		level = slog.LevelError
+	default:
+		level = slog.LevelWarn
+	}
+
+	log.LogAttrs(ctx, level, "request",
+		slog.String("method", info.FullMethod),
+		slog.String("code", code.String()),
+		slog.Duration("duration", time.Since(start)),
+	)
+
+	return resp, err
+}
+
+func getUserId(ctx context.Context) (int64, error) {
+	id, ok := ctx.Value(userKey{}).(int64)
+	if !ok {
+		return 0, ErrUserIDNotFound
+	}
+
+	return id, nil
+}
+
+// errorInterceptor переводит доменные ошибки в статусы gRPC. Код ошибки передается
+// в ErrorInfo.Reason, ошибки полей - в BadRequest, как в problem details HTTP API.
+func (h *Handler) errorInterceptor(
+	ctx context.Context,
+	req any,
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (any, error) {
+	resp, err := handler(ctx, req)
+	if err == nil {
+		return resp, nil
+	}
+
+	if _, ok := status.FromError(err); ok {
+		return nil, err
+	}
+
+	st := newStatus(err)
+	if st.Code() == codes.Internal {
+		logctx.From(ctx, h.log).Error("internal error",
+			slog.String("op", "grpchandler.errorInterceptor"),
+			slog.String("method", info.FullMethod),
+			sl.Err(err),
+		)
+	}
+
+	return nil, st.Err()
+}
+
+func newStatus(err error) *status.Status {
+	appErr, ok := apperr.As(err)
+	if !ok || appErr.Kind == apperr.KindInternal {
+		code := internalErrorCode
+		if ok {
+			code = appErr.Code
+		}
+
+		st, _ := status.New(codes.Internal, internalErrorDetail).
+			WithDetails(&errdetails.ErrorInfo{Reason: code, Domain: errorDomain})
+		return st
+	}
+
+	st := status.New(statusCode(appErr.Kind), appErr.Message)
+
+	details := []protoadapt.MessageV1{&errdetails.ErrorInfo{Reason: appErr.Code, Domain: errorDomain}}
+	if len(appErr.Fields) > 0 {
+		violations := make([]*errdetails.BadRequest_FieldViolation, 0, len(appErr.Fields))
+		for _, field := range appErr.Fields {
+			violations = append(violations, &errdetails.BadRequest_FieldViolation{
+				Field:       field.Field,
+				Description: field.Message,
+			})
+		}
+		details = append(details, &errdetails.BadRequest{FieldViolations: violations})
+	}
+
+	if withDetails, err := st.WithDetails(details...); err == nil {
+		return withDetails
+	}
+
+	return st
+}
+
+func statusCode(kind apperr.Kind) codes.Code {
+	switch kind {
+	case apperr.KindNotFound:
+		return codes.NotFound
+	case apperr.KindConflict:
+		return codes.AlreadyExists
+	case apperr.KindValidation:
+		return codes.InvalidArgument
+	case apperr.KindUnauthorized:
+		return codes.Unauthenticated
+	case apperr.KindForbidden:
+		return codes.PermissionDenied
+	case apperr.KindPreconditionFailed, apperr.KindPreconditionRequired:
+		return codes.FailedPrecondition
+	default:
+		return codes.Internal
+	}
+}
@@ -0,0 +1,148 @@
+package grpchandler
+
+import (
+	"context"
+	catalogv1 "goapi/api/catalog/v1"
+	"goapi/internal/lib/logger/logctx"
+	"goapi/internal/lib/logger/sl"
+	"goapi/internal/model"
+	"goapi/internal/service"
+	"log/slog"
+)
+
+type productServer struct {
+	catalogv1.UnimplementedProductServiceServer
+	h *Handler
+}
+
+func (s *productServer) AddProduct(ctx context.Context, req *catalogv1.AddProductRequest) (*catalogv1.AddProductResponse, error) {
+	const op = "grpchandler.AddProduct"
+
+	log := logctx.From(ctx, s.h.log).With(
+		slog.String("op", op),
+	)
+
+	if _, err := getUserId(ctx); err != nil {
+		return nil, err
+	}
+
+	id, err := s.h.product.AddProduct(ctx, req.GetName(), req.GetCategoryies())
+	if err != nil {
+		log.Error("error add product", sl.Err(err))
+		return nil, err
+	}
+
+	return &catalogv1.AddProductResponse{Id: id}, nil
+}
+
+func (s *productServer) GetProduct(ctx context.Context, req *catalogv1.GetProductRequest) (*catalogv1.Product, error) {
+	const op = "grpchandler.GetProduct"
+
+	log := logctx.From(ctx, s.h.log).With(
+		slog.String("op", op),
+	)
+
+	if _, err := getUserId(ctx); err != nil {
+		return nil, err
+	}
+
+	product, err := s.h.product.GetProduct(ctx, req.GetId())
+	if err != nil {
+		log.Error("error get product", sl.Err(err))
+		return nil, err
+	}
+
+	return toProto(product), nil
+}
+
+func (s *productServer) ListProducts(ctx context.Context, req *catalogv1.ListProductsRequest) (*catalogv1.ListProductsResponse, error) {
+	const op = "grpchandler.ListProducts"
+
+	log := logctx.From(ctx, s.h.log).With(
+		slog.String("op", op),
+	)
+
+	if _, err := getUserId(ctx); err != nil {
+		return nil, err
+	}
+
+	var (
+		products []model.Product
+		err      error
+	)
+	if req.GetCategory() == "" {
+		products, err = s.h.product.GetAllProducts(ctx, service.TagGetAllProducts)
+	} else {
+		products, err = s.h.product.GetCategoryProducts(ctx, req.GetCategory())
+	}
+	if err != nil {
+		log.Error("error list products", sl.Err(err))
+		return nil, err
+	}
+
+	resp := &catalogv1.ListProductsResponse{Products: make([]*catalogv1.Product, 0, len(products))}
+	for _, product := range products {
+		resp.Products = append(resp.Products, toProto(product))
+	}
+
+	return resp, nil
+}
+
+func (s *productServer) RenameProduct(ctx context.Context, req *catalogv1.RenameProductRequest) (*catalogv1.VersionResponse, error) {
+	const op = "grpchandler.RenameProduct"
+
+	log := logctx.From(ctx, s.h.log).With(
+		slog.String("op", op),
+	)
+
+	if _, err := getUserId(ctx); err != nil {
+		return nil, err
+	}
+
+	version, err := s.h.product.EditProductName(ctx, req.GetId(), req.GetName(), req.GetVersion())
+	if err != nil {
+		log.Error("error rename product", sl.Err(err))
+		return nil, err
+	}
+
+	return &catalogv1.VersionResponse{Id: req.GetId(), Version: version}, nil
+}
+
+func (s *productServer) SetProductCategoryies(ctx context.Context, req *catalogv1.SetProductCategoryiesRequest) (*catalogv1.VersionResponse, error) {
+	const op = "grpchandler.SetProductCategoryies"
+
+	log := logctx.From(ctx, s.h.log).With(
+		slog.String("op", op),
+	)
+
+	if _, err := getUserId(ctx); err != nil {
+		return nil, err
+	}
+
+	version, err := s.h.product.EditProductCategory(ctx, req.GetId(), categoryiesFromProto(req.GetCategoryies()), req.GetVersion())
+	if err != nil {
+		log.Error("error set product categoryies", sl.Err(err))
+		return nil, err
+	}
+
+	return &catalogv1.VersionResponse{Id: req.GetId(), Version: version}, nil
+}
+
+func (s *productServer) DeleteProduct(ctx context.Context, req *catalogv1.DeleteProductRequest) (*catalogv1.DeleteProductResponse, error) {
+	const op = "grpchandler.DeleteProduct"
+
+	log := logctx.From(ctx, s.h.log).With(
+		slog.String("op", op),
+	)
+
+	if _, err := getUserId(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := s.h.product.DeleteProduct(ctx, req.GetId(), req.GetVersion()); err != nil {
+		log.Error("error delete product", sl.Err(err))
+		return nil, err
+	}
+
+	return &catalogv1.DeleteProductResponse{}, nil
+}
@@ -1,76 +1,73 @@
 package handler
 
 import (
-	"fmt"
 	"github.com/gin-gonic/gin"
+	"goapi/internal/lib/logger/logctx"
+	"goapi/internal/lib/logger/sl"
 	"log/slog"
 	"net/http"
 )
 
 type signInInput struct {
-	Email    string `json:"email" binding:"required"`
-	Password string `json:"password" binding:"required"`
+	Email    string `json:"email" validate:"email_address"`
+	Password string `json:"password" validate:"password"`
 }
 
 func (h *Handler) signIn(c *gin.Context) {
 	const op = "handler.signIn"
 
-	log := h.log.With(
+	log := logctx.From(c.Request.Context(), h.log).With(
 		slog.String("op", op),
 	)
 
 	var input signInInput
 
-	if err := c.BindJSON(&input); err != nil {
-		newErrorResponse(c, http.StatusBadRequest, InvalidInputBodyErr)
-		log.Error("error bind json:", fmt.Errorf("%s", InvalidInputBodyErr))
+	if err := bindJSON(c, &input); err != nil {
+		newErrorResponse(c, err)
+		log.Warn("error bind json", sl.Err(err))
 		return
 	}
 
 	token, err := h.auth.Login(c.Request.Context(), input.Email, input.Password)
 	if err != nil {
-		newErrorResponse(c, http.StatusInternalServerError, err.Error())
-		log.Error("error login", err)
+		newErrorResponse(c, err)
+		log.Error("error login", sl.Err(err))
 		return
 	}
 
 	log.Info("Handler sign in")
 
-	c.JSON(http.StatusOK, map[string]interface{}{
-		"token": token,
-	})
+	c.JSON(http.StatusOK, tokenResponse{Token: token})
 }
 
 type signUpType struct {
-	Email    string `json:"email" binding:"required"`
-	Password string `json:"password" binding:"required"`
+	Email    string `json:"email" validate:"email_address"`
+	Password string `json:"password" validate:"new_password"`
 }
 
 func (h *Handler) signUp(c *gin.Context) {
 	const op = "handler.signUp"
 
-	log := h.log.With(
+	log := logctx.From(c.Request.Context(), h.log).With(
 		slog.String("op", op),
 	)
 
 	var input signUpType
 
-	if err := c.BindJSON(&input); err != nil {
-		newErrorResponse(c, http.StatusBadRequest, InvalidInputBodyErr)
-		log.Error("error bind json:", fmt.Errorf("%s", InvalidInputBodyErr))
+	if err := bindJSON(c, &input); err != nil {
+		newErrorResponse(c, err)
+		log.Warn("error bind json", sl.Err(err))
 		return
 	}
 
 	id, err := h.auth.Register(c.Request.Context(), input.Email, input.Password)
 	if err != nil {
-		newErrorResponse(c, http.StatusInternalServerError, err.Error())
-		log.Error("error register ", err)
+		newErrorResponse(c, err)
+		log.Error("error register ", sl.Err(err))
 		return
 	}
 
 	log.Info("Handler sign up")
 
-	c.JSON(http.StatusOK, map[string]interface{}{
-		"id": id,
-	})
+	c.JSON(http.StatusOK, idResponse{ID: id})
 }
@@ -47,9 +47,10 @@ func TestSignUpOk(t *testing.T) {
 
 			mockAuthService := service_mocks.NewMockAuthService(ctrl)
 			logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
-			h := NewHandler(mockAuthService, nil, nil, logger)
+			h := NewHandler(Deps{Auth: mockAuthService}, logger)
 
 			r := gin.Default()
+			r.Use(h.errorHandler)
 			r.POST("/auth/sign-up", h.signUp)
 
 			mockAuthService.EXPECT().
@@ -80,7 +81,8 @@ func TestSignUpFailed(t *testing.T) {
 			"Ok",
 			signUpType{},
 			http.StatusBadRequest,
-			"{\"message\":\"invalid input body\"}",
+			`{"type":"about:blank","title":"Bad Request","status":400,"detail":"request validation failed","instance":"/auth/sign-up","code":"validation_failed",` +
+				`"errors":[{"field":"/email","code":"required","message":"is required"},{"field":"/password","code":"required","message":"is required"}]}`,
 		},
 	}
 	for _, params := range inputParams {
@@ -90,9 +92,10 @@ func TestSignUpFailed(t *testing.T) {
 
 			mockAuthService := service_mocks.NewMockAuthService(ctrl)
 			logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
-			h := NewHandler(mockAuthService, nil, nil, logger)
+			h := NewHandler(Deps{Auth: mockAuthService}, logger)
 
 			r := gin.Default()
+			r.Use(h.errorHandler)
 			r.POST("/auth/sign-up", h.signUp)
 
 			w := httptest.NewRecorder()
@@ -114,9 +117,10 @@ func TestSignInOk(t *testing.T) {
 
 	mockAuthService := service_mocks.NewMockAuthService(ctrl)
 	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
-	h := NewHandler(mockAuthService, nil, nil, logger)
+	h := NewHandler(Deps{Auth: mockAuthService}, logger)
 
 	r := gin.Default()
+	r.Use(h.errorHandler)
 	r.POST("/auth/sign-in", h.signIn)
 
 	expectedToken := "mockToken"
@@ -142,9 +146,10 @@ func TestSignInFailed(t *testing.T) {
 
 	mockAuthService := service_mocks.NewMockAuthService(ctrl)
 	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
-	h := NewHandler(mockAuthService, nil, nil, logger)
+	h := NewHandler(Deps{Auth: mockAuthService}, logger)
 
 	r := gin.Default()
+	r.Use(h.errorHandler)
 	r.POST("/auth/sign-in", h.signIn)
 
 	w := httptest.NewRecorder()
@@ -155,5 +160,10 @@ func TestSignInFailed(t *testing.T) {
 
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 
-	assert.Equal(t, "{\"message\":\"invalid input body\"}", w.Body.String())
+	assert.Equal(t, problemContentType, w.Header().Get("Content-Type"))
+	assert.Equal(t,
+		`{"type":"about:blank","title":"Bad Request","status":400,"detail":"request validation failed","instance":"/auth/sign-in","code":"validation_failed",`+
+			`"errors":[{"field":"/email","code":"required","message":"is required"},{"field":"/password","code":"required","message":"is required"}]}`,
+		w.Body.String(),
+	)
 }
@@ -0,0 +1,206 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"goapi/internal/lib/apperr"
+	"goapi/internal/lib/catalog"
+	"goapi/internal/lib/logger/logctx"
+	"goapi/internal/lib/logger/sl"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+const (
+	importFileField = "file"
+	maxImportSize   = 32 << 20
+)
+
+var (
+	ErrImportFileMissing  = apperr.InvalidField("file_required", "/file", "file is required")
+	ErrImportFileTooLarge = apperr.InvalidField("file_too_large", "/file", fmt.Sprintf("file must be at most %d bytes", maxImportSize))
+)
+
+type exportQuery struct {
+	Format string `json:"format" form:"format" validate:"required,oneof=csv jsonl"`
+}
+
+type importQuery struct {
+	Format string `json:"format" form:"format" validate:"omitempty,oneof=csv jsonl"`
+	DryRun bool   `json:"dry_run" form:"dry_run"`
+}
+
+type importForm struct {
+	File string `json:"file" format:"binary" validate:"required"`
+}
+
+type importResponse struct {
+	DryRun  bool             `json:"dryRun"`
+	Total   int              `json:"total"`
+	Created int              `json:"created"`
+	Updated int              `json:"updated"`
+	Failed  int              `json:"failed"`
+	Errors  []importRowError `json:"errors"`
+}
+
+type importRowError struct {
+	Line  int     `json:"line"`
+	SKU   string  `json:"sku,omitempty"`
+	Error problem `json:"error"`
+}
+
+func (h *Handler) exportCatalog(c *gin.Context) {
+	const op = "handler.exportCatalog"
+
+	log := logctx.From(c.Request.Context(), h.log).With(
+		slog.String("op", op),
+	)
+
+	_, err := getUserId(c)
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	var query exportQuery
+
+	if err := bindQuery(c, &query); err != nil {
+		newErrorResponse(c, err)
+		log.Warn("error bind query", sl.Err(err))
+		return
+	}
+
+	format := catalog.Format(query.Format)
+
+	// выгрузка большого каталога идет дольше WriteTimeout сервера
+	if err := http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{}); err != nil {
+		log.Warn("error reset write deadline", sl.Err(err))
+	}
+
+	w := &exportWriter{c: c, format: format}
+	if err := h.catalog.ExportProducts(c.Request.Context(), w, format); err != nil {
+		// если данные уже отправлены, ответ можно только оборвать
+		newErrorResponse(c, err)
+		log.Error("error export catalog", sl.Err(err))
+		return
+	}
+	w.writeHeader()
+
+	log.Info("Handler catalog exported")
+}
+
+// exportWriter отправляет заголовки файла выгрузки только с первой записанной строкой,
+// чтобы ошибка до нее вернулась обычным ответом, а не пустым файлом
+type exportWriter struct {
+	c      *gin.Context
+	format catalog.Format
+}
+
+func (w *exportWriter) Write(p []byte) (int, error) {
+	w.writeHeader()
+	return w.c.Writer.Write(p)
+}
+
+func (w *exportWriter) writeHeader() {
+	if w.c.Writer.Written() {
+		return
+	}
+
+	w.c.Header("Content-Type", w.format.ContentType())
+	w.c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="catalog.%s"`, w.format))
+	w.c.Status(http.StatusOK)
+	w.c.Writer.WriteHeaderNow()
+}
+
+func (h *Handler) importCatalog(c *gin.Context) {
+	const op = "handler.importCatalog"
+
+	log := logctx.From(c.Request.Context(), h.log).With(
+		slog.String("op", op),
+	)
+
+	_, err := getUserId(c)
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	var query importQuery
+
+	if err := bindQuery(c, &query); err != nil {
+		newErrorResponse(c, err)
+		log.Warn("error bind query", sl.Err(err))
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportSize)
+
+	header, err := c.FormFile(importFileField)
+	if err != nil {
+		var sizeErr *http.MaxBytesError
+		if errors.As(err, &sizeErr) {
+			err = ErrImportFileTooLarge
+		} else {
+			err = ErrImportFileMissing
+		}
+		newErrorResponse(c, err)
+		log.Warn("error read file", sl.Err(err))
+		return
+	}
+
+	format := catalog.Format(query.Format)
+	if format == "" {
+		format, err = catalog.FormatFromFilename(header.Filename)
+		if err != nil {
+			newErrorResponse(c, err)
+			log.Warn("unknown file format", slog.String("filename", header.Filename))
+			return
+		}
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		newErrorResponse(c, err)
+		log.Error("error open file", sl.Err(err))
+		return
+	}
+	defer file.Close()
+
+	report, err := h.catalog.ImportProducts(c.Request.Context(), file, format, query.DryRun)
+	if err != nil {
+		newErrorResponse(c, err)
+		log.Error("error import catalog", sl.Err(err))
+		return
+	}
+
+	response := importResponse{
+		DryRun:  report.DryRun,
+		Total:   report.Total,
+		Created: report.Created,
+		Updated: report.Updated,
+		Failed:  report.Failed,
+		Errors:  make([]importRowError, 0, report.Failed),
+	}
+
+	for _, row := range report.Rows {
+		if row.Err == nil {
+			continue
+		}
+
+		if apperr.KindOf(row.Err) == apperr.KindInternal {
+			log.Error("import row failed", slog.Int("line", row.Line), sl.Err(row.Err))
+		}
+
+		response.Errors = append(response.Errors, importRowError{
+			Line:  row.Line,
+			SKU:   row.SKU,
+			Error: newProblem(row.Err, ""),
+		})
+	}
+
+	log.Info("Handler catalog imported", slog.Bool("dry_run", report.DryRun))
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,213 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	service_mocks "goapi/internal/handler/mock"
+	"goapi/internal/lib/apperr"
+	"goapi/internal/lib/catalog"
+	"goapi/internal/model"
+	"io"
+	"log/slog"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+func TestExportCatalog(t *testing.T) {
+	type mockBehavior func(s *service_mocks.MockCatalogService)
+
+	tests := []struct {
+		name                string
+		query               string
+		mockBehavior        mockBehavior
+		expectedStatus      int
+		expectedContentType string
+		expectedBody        string
+	}{
+		{
+			name:  "CSV",
+			query: "?format=csv",
+			mockBehavior: func(s *service_mocks.MockCatalogService) {
+				s.EXPECT().ExportProducts(gomock.Any(), gomock.Any(), catalog.FormatCSV).DoAndReturn(
+					func(ctx context.Context, w io.Writer, format catalog.Format) error {
+						_, err := io.WriteString(w, "id,sku,name,categoryies\n")
+						return err
+					})
+			},
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "text/csv; charset=utf-8",
+			expectedBody:        "id,sku,name,categoryies\n",
+		},
+		{
+			name:  "Empty",
+			query: "?format=jsonl",
+			mockBehavior: func(s *service_mocks.MockCatalogService) {
+				s.EXPECT().ExportProducts(gomock.Any(), gomock.Any(), catalog.FormatJSONL).Return(nil)
+			},
+			expectedStatus:      http.StatusOK,
+			expectedContentType: catalog.FormatJSONL.ContentType(),
+		},
+		{
+			name:                "Unknown Format",
+			query:               "?format=xml",
+			mockBehavior:        func(s *service_mocks.MockCatalogService) {},
+			expectedStatus:      http.StatusBadRequest,
+			expectedContentType: problemContentType,
+		},
+		{
+			name:  "Service Failure",
+			query: "?format=jsonl",
+			mockBehavior: func(s *service_mocks.MockCatalogService) {
+				s.EXPECT().ExportProducts(gomock.Any(), gomock.Any(), catalog.FormatJSONL).Return(
+					apperr.Internal("export_failed", "export failed"))
+			},
+			expectedStatus:      http.StatusInternalServerError,
+			expectedContentType: problemContentType,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockCatalogService := service_mocks.NewMockCatalogService(ctrl)
+			test.mockBehavior(mockCatalogService)
+
+			logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
+			h := NewHandler(Deps{Catalog: mockCatalogService}, logger)
+
+			r := gin.New()
+			r.Use(h.errorHandler)
+			r.GET("/api/v1/export", func(c *gin.Context) {
+				c.Set(userCtx, int64(1))
+			}, h.exportCatalog)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/api/v1/export"+test.query, nil)
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, test.expectedStatus, w.Code)
+			assert.Equal(t, test.expectedContentType, w.Header().Get("Content-Type"))
+			// ошибка до первой строки не выглядит как скачанный файл
+			assert.Equal(t, test.expectedStatus == http.StatusOK, w.Header().Get("Content-Disposition") != "")
+			if test.expectedBody != "" {
+				assert.Equal(t, test.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestImportCatalog(t *testing.T) {
+	type mockBehavior func(s *service_mocks.MockCatalogService)
+
+	tests := []struct {
+		name           string
+		query          string
+		filename       string
+		mockBehavior   mockBehavior
+		expectedStatus int
+		expectedBody   importResponse
+	}{
+		{
+			name:     "Dry Run",
+			query:    "?dry_run=true",
+			filename: "catalog.jsonl",
+			mockBehavior: func(s *service_mocks.MockCatalogService) {
+				s.EXPECT().ImportProducts(gomock.Any(), gomock.Any(), catalog.FormatJSONL, true).Return(
+					model.ImportReport{
+						DryRun:  true,
+						Total:   2,
+						Created: 1,
+						Failed:  1,
+						Rows: []model.ImportResult{
+							{Line: 1, SKU: "SKU-1", ID: 1, Status: model.ImportCreated},
+							{Line: 2, SKU: "SKU-2", Status: model.ImportFailed, Err: apperr.InvalidField("duplicate_sku", "/sku", "duplicate")},
+						},
+					}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: importResponse{
+				DryRun:  true,
+				Total:   2,
+				Created: 1,
+				Failed:  1,
+				Errors: []importRowError{
+					{
+						Line: 2,
+						SKU:  "SKU-2",
+						Error: problem{
+							Type:   problemTypeDefault,
+							Title:  http.StatusText(http.StatusBadRequest),
+							Status: http.StatusBadRequest,
+							Detail: "duplicate",
+							Code:   "duplicate_sku",
+							Errors: []apperr.FieldError{{Field: "/sku", Message: "duplicate"}},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:           "Unknown Extension",
+			filename:       "catalog.xml",
+			mockBehavior:   func(s *service_mocks.MockCatalogService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Invalid Dry Run",
+			query:          "?dry_run=maybe",
+			filename:       "catalog.csv",
+			mockBehavior:   func(s *service_mocks.MockCatalogService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockCatalogService := service_mocks.NewMockCatalogService(ctrl)
+			test.mockBehavior(mockCatalogService)
+
+			logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
+			h := NewHandler(Deps{Catalog: mockCatalogService}, logger)
+
+			r := gin.New()
+			r.Use(h.errorHandler)
+			r.POST("/api/v1/import", func(c *gin.Context) {
+				c.Set(userCtx, int64(1))
+			}, h.importCatalog)
+
+			var body bytes.Buffer
+			form := multipart.NewWriter(&body)
+			part, err := form.CreateFormFile(importFileField, test.filename)
+			assert.NoError(t, err)
+			_, err = part.Write([]byte(`{"sku":"SKU-1","name":"Product1","categoryies":["Category1"]}`))
+			assert.NoError(t, err)
+			assert.NoError(t, form.Close())
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/api/v1/import"+test.query, &body)
+			req.Header.Set("Content-Type", form.FormDataContentType())
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, test.expectedStatus, w.Code)
+			if test.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var response importResponse
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, test.expectedBody, response)
+		})
+	}
+}
@@ -1,80 +1,85 @@
 package handler
 
 import (
-	"fmt"
 	"github.com/gin-gonic/gin"
+	"goapi/internal/lib/logger/logctx"
+	"goapi/internal/lib/logger/sl"
 	"log/slog"
 	"net/http"
 )
 
 type addCategory struct {
-	Name string `json:"name" binding:"required"`
+	Name string `json:"name" validate:"category_name"`
 }
 
 func (h *Handler) addCategory(c *gin.Context) {
 	const op = "handler.addCategory"
 
-	log := h.log.With(
+	log := logctx.From(c.Request.Context(), h.log).With(
 		slog.String("op", op),
 	)
 
 	_, err := getUserId(c)
 	if err != nil {
-		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		newErrorResponse(c, err)
 		return
 	}
 
 	var input addCategory
 
-	if err := c.BindJSON(&input); err != nil {
-		newErrorResponse(c, http.StatusBadRequest, InvalidInputBodyErr)
-		log.Error("error bind json:", fmt.Errorf("%s", InvalidInputBodyErr))
+	if err := bindJSON(c, &input); err != nil {
+		newErrorResponse(c, err)
+		log.Warn("error bind json", sl.Err(err))
 		return
 	}
 
 	id, err := h.category.AddCategory(c.Request.Context(), input.Name)
 	if err != nil {
-		newErrorResponse(c, http.StatusInternalServerError, err.Error())
-		log.Error("error added category", err)
+		newErrorResponse(c, err)
+		log.Error("error added category", sl.Err(err))
 		return
 	}
 
 	log.Info("Handler category added")
 
-	c.JSON(http.StatusOK, map[string]interface{}{
-		"categoryID": id,
-	})
+	c.JSON(http.StatusOK, categoryIDResponse{CategoryID: id})
 }
 
 type deleteCategory struct {
-	ID int64 `json:"id" binding:"required"`
+	ID int64 `json:"id" validate:"entity_id"`
 }
 
 func (h *Handler) deleteCategory(c *gin.Context) {
 	const op = "handler.deleteCategory"
 
-	log := h.log.With(
+	log := logctx.From(c.Request.Context(), h.log).With(
 		slog.String("op", op),
 	)
 
 	_, err := getUserId(c)
 	if err != nil {
-		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		newErrorResponse(c, err)
+		return
+	}
+
+	version, err := ifMatch(c)
+	if err != nil {
+		newErrorResponse(c, err)
 		return
 	}
 
 	var input deleteCategory
 
-	if err := c.BindJSON(&input); err != nil {
-		newErrorResponse(c, http.StatusBadRequest, InvalidInputBodyErr)
-		log.Error("error bind json:", fmt.Errorf("%s", InvalidInputBodyErr))
+	if err := bindJSON(c, &input); err != nil {
+		newErrorResponse(c, err)
+		log.Warn("error bind json", sl.Err(err))
 		return
 	}
 
-	err = h.category.DeleteCategory(c.Request.Context(), input.ID)
+	err = h.category.DeleteCategory(c.Request.Context(), input.ID, version)
 	if err != nil {
-		newErrorResponse(c, http.StatusInternalServerError, err.Error())
-		log.Error("error added category", err)
+		newErrorResponse(c, err)
+		log.Error("error added category", sl.Err(err))
 		return
 	}
 
@@ -84,80 +89,227 @@ func (h *Handler) deleteCategory(c *gin.Context) {
 }
 
 type editCategory struct {
-	ID   int64  `json:"id" binding:"required"`
-	Name string `json:"name" binding:"required"`
+	ID   int64  `json:"id" validate:"entity_id"`
+	Name string `json:"name" validate:"category_name"`
 }
 
 func (h *Handler) editCategory(c *gin.Context) {
 	const op = "handler.editCategory"
 
-	log := h.log.With(
+	log := logctx.From(c.Request.Context(), h.log).With(
 		slog.String("op", op),
 	)
 
 	_, err := getUserId(c)
 	if err != nil {
-		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		newErrorResponse(c, err)
+		return
+	}
+
+	version, err := ifMatch(c)
+	if err != nil {
+		newErrorResponse(c, err)
 		return
 	}
 
 	var input editCategory
 
-	if err := c.BindJSON(&input); err != nil {
-		newErrorResponse(c, http.StatusBadRequest, InvalidInputBodyErr)
-		log.Error("error bind json:", fmt.Errorf("%s", InvalidInputBodyErr))
+	if err := bindJSON(c, &input); err != nil {
+		newErrorResponse(c, err)
+		log.Warn("error bind json", sl.Err(err))
 		return
 	}
 
-	id, err := h.category.EditCategory(c.Request.Context(), input.ID, input.Name)
+	version, err = h.category.EditCategory(c.Request.Context(), input.ID, input.Name, version)
 	if err != nil {
-		newErrorResponse(c, http.StatusInternalServerError, err.Error())
-		log.Error("error added category", err)
+		newErrorResponse(c, err)
+		log.Error("error added category", sl.Err(err))
 		return
 	}
 
 	log.Info("Handler category edited")
 
-	c.JSON(http.StatusOK, map[string]interface{}{
-		"categoryID": id,
-	})
+	setETag(c, version)
+	c.JSON(http.StatusOK, categoryIDResponse{CategoryID: input.ID})
 }
 
 type getAllCategoryiesType struct {
-	Tag string `json:"tag" binding:"required"`
+	Tag string `json:"tag" validate:"required"`
 }
 
 func (h *Handler) getAllCategory(c *gin.Context) {
 	const op = "handler.getAllCategoryies"
 
-	log := h.log.With(
+	log := logctx.From(c.Request.Context(), h.log).With(
 		slog.String("op", op),
 	)
 
 	_, err := getUserId(c)
 	if err != nil {
-		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		newErrorResponse(c, err)
 		return
 	}
 
 	var input getAllCategoryiesType
 
-	if err := c.BindJSON(&input); err != nil {
-		newErrorResponse(c, http.StatusBadRequest, InvalidInputBodyErr)
-		log.Error("error bind json:", fmt.Errorf("%s", InvalidInputBodyErr))
+	if err := bindJSON(c, &input); err != nil {
+		newErrorResponse(c, err)
+		log.Warn("error bind json", sl.Err(err))
 		return
 	}
 
 	categoryies, err := h.category.GetAllCategoryies(c.Request.Context(), input.Tag)
 	if err != nil {
-		newErrorResponse(c, http.StatusInternalServerError, err.Error())
-		log.Error("error getting all products", err)
+		newErrorResponse(c, err)
+		log.Error("error getting all products", sl.Err(err))
 		return
 	}
 
 	log.Info("Handler getting all categoryies")
 
-	c.JSON(http.StatusOK, map[string]interface{}{
-		"categoryies": categoryies,
-	})
+	c.JSON(http.StatusOK, categoryiesResponse{Categoryies: categoryies})
+}
+
+// listCategoryies возвращает все категории с числом товаров в каждой
+func (h *Handler) listCategoryies(c *gin.Context) {
+	const op = "handler.listCategoryies"
+
+	log := logctx.From(c.Request.Context(), h.log).With(
+		slog.String("op", op),
+	)
+
+	_, err := getUserId(c)
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	categoryies, err := h.category.GetCategoryiesSummary(c.Request.Context())
+	if err != nil {
+		newErrorResponse(c, err)
+		log.Error("error getting categoryies summary", sl.Err(err))
+		return
+	}
+
+	log.Info("Handler getting categoryies summary")
+
+	c.JSON(http.StatusOK, categoryiesSummaryResponse{Categoryies: categoryies})
+}
+
+func (h *Handler) getCategory(c *gin.Context) {
+	const op = "handler.getCategory"
+
+	log := logctx.From(c.Request.Context(), h.log).With(
+		slog.String("op", op),
+	)
+
+	_, err := getUserId(c)
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	id, err := paramID(c)
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	category, err := h.category.GetCategory(c.Request.Context(), id)
+	if err != nil {
+		newErrorResponse(c, err)
+		log.Error("error getting category", sl.Err(err))
+		return
+	}
+
+	log.Info("Handler getting category")
+
+	setETag(c, category.Version)
+	c.JSON(http.StatusOK, category)
+}
+
+type patchCategoryType struct {
+	Name string `json:"name" validate:"category_name"`
+}
+
+func (h *Handler) patchCategory(c *gin.Context) {
+	const op = "handler.patchCategory"
+
+	log := logctx.From(c.Request.Context(), h.log).With(
+		slog.String("op", op),
+	)
+
+	_, err := getUserId(c)
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	id, err := paramID(c)
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	version, err := ifMatch(c)
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	var input patchCategoryType
+
+	if err := bindJSON(c, &input); err != nil {
+		newErrorResponse(c, err)
+		log.Warn("error bind json", sl.Err(err))
+		return
+	}
+
+	version, err = h.category.EditCategory(c.Request.Context(), id, input.Name, version)
+	if err != nil {
+		newErrorResponse(c, err)
+		log.Error("error edit category", sl.Err(err))
+		return
+	}
+
+	log.Info("Handler category edited")
+
+	setETag(c, version)
+	c.JSON(http.StatusOK, categoryVersionResponse{CategoryID: id, Version: version})
+}
+
+func (h *Handler) removeCategory(c *gin.Context) {
+	const op = "handler.removeCategory"
+
+	log := logctx.From(c.Request.Context(), h.log).With(
+		slog.String("op", op),
+	)
+
+	_, err := getUserId(c)
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	id, err := paramID(c)
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	version, err := ifMatch(c)
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	if err := h.category.DeleteCategory(c.Request.Context(), id, version); err != nil {
+		newErrorResponse(c, err)
+		log.Error("error delete category", sl.Err(err))
+		return
+	}
+
+	log.Info("Handler category deleted")
+
+	c.Status(http.StatusNoContent)
 }
@@ -2,10 +2,12 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"github.com/gin-gonic/gin"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 	service_mocks "goapi/internal/handler/mock"
+	"goapi/internal/model"
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
@@ -20,9 +22,10 @@ func TestAddCategoryFailed(t *testing.T) {
 
 	mockCategoryService := service_mocks.NewMockCategoryService(ctrl)
 	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
-	h := NewHandler(nil, nil, mockCategoryService, logger)
+	h := NewHandler(Deps{Category: mockCategoryService}, logger)
 
 	r := gin.Default()
+	r.Use(h.errorHandler)
 	r.POST("/api/category/add", h.signIn)
 
 	w := httptest.NewRecorder()
@@ -38,5 +41,67 @@ func TestAddCategoryFailed(t *testing.T) {
 	var response map[string]interface{}
 	json.Unmarshal(w.Body.Bytes(), &response)
 
-	assert.Equal(t, "{\"message\":\"invalid input body\"}", w.Body.String())
+	assert.Equal(t,
+		`{"type":"about:blank","title":"Bad Request","status":400,"detail":"request validation failed","instance":"/api/category/add","code":"validation_failed",`+
+			`"errors":[{"field":"/email","code":"required","message":"is required"},{"field":"/password","code":"required","message":"is required"}]}`,
+		w.Body.String(),
+	)
+}
+
+func TestListCategoryies(t *testing.T) {
+	type mockBehavior func(s *service_mocks.MockCategoryService)
+
+	tests := []struct {
+		name           string
+		mockBehavior   mockBehavior
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "Ok",
+			mockBehavior: func(s *service_mocks.MockCategoryService) {
+				s.EXPECT().GetCategoryiesSummary(gomock.Any()).Return([]model.CategorySummary{
+					{Category: model.Category{ID: 1, Name: "Category1", Version: 2}, ProductCount: 3},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"categoryies":[{"id":1,"name":"Category1","version":2,"productCount":3}]}`,
+		},
+		{
+			name: "Internal Error",
+			mockBehavior: func(s *service_mocks.MockCategoryService) {
+				s.EXPECT().GetCategoryiesSummary(gomock.Any()).Return(nil, errors.New("pq: connection refused"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockCategoryService := service_mocks.NewMockCategoryService(ctrl)
+			test.mockBehavior(mockCategoryService)
+
+			logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
+			h := NewHandler(Deps{Category: mockCategoryService}, logger)
+
+			r := gin.New()
+			r.Use(h.errorHandler)
+			r.GET("/api/v1/categories", func(c *gin.Context) {
+				c.Set(userCtx, int64(1))
+			}, h.listCategoryies)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/api/v1/categories", nil)
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, test.expectedStatus, w.Code)
+
+			if test.expectedBody != "" {
+				assert.JSONEq(t, test.expectedBody, w.Body.String())
+			}
+		})
+	}
 }
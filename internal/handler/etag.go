@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"goapi/internal/lib/apperr"
+	"strconv"
+	"strings"
+)
+
+const (
+	headerETag    = "ETag"
+	headerIfMatch = "If-Match"
+)
+
+var (
+	ErrPreconditionRequired = apperr.PreconditionRequired("if_match_required", "If-Match header is required")
+	ErrInvalidIfMatch       = apperr.PreconditionFailed("invalid_if_match", "If-Match header must contain a single strong entity tag")
+	ErrInvalidID            = apperr.InvalidField("invalid_id", "/id", "id must be a positive integer")
+)
+
+// setETag отдает версию ресурса в заголовке ETag
+func setETag(c *gin.Context, version int64) {
+	c.Header(headerETag, strconv.Quote(strconv.FormatInt(version, 10)))
+}
+
+// ifMatch возвращает версию из заголовка If-Match, он обязателен для всех изменений.
+// Слабые теги и "*" не принимаются: изменение без проверки версии затирает чужие правки.
+func ifMatch(c *gin.Context) (int64, error) {
+	value := strings.TrimSpace(c.GetHeader(headerIfMatch))
+	if value == "" {
+		return 0, ErrPreconditionRequired
+	}
+
+	tag, err := strconv.Unquote(value)
+	if err != nil || !strings.HasPrefix(value, `"`) {
+		return 0, ErrInvalidIfMatch
+	}
+
+	version, err := strconv.ParseInt(tag, 10, 64)
+	if err != nil || version <= 0 {
+		return 0, ErrInvalidIfMatch
+	}
+
+	return version, nil
+}
+
+// paramID разбирает идентификатор ресурса из пути
+func paramID(c *gin.Context) (int64, error) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		return 0, ErrInvalidID
+	}
+
+	return id, nil
+}
@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIfMatch(t *testing.T) {
+	tests := []struct {
+		name            string
+		header          string
+		expectedVersion int64
+		expectedError   error
+	}{
+		{name: "Strong Tag", header: `"3"`, expectedVersion: 3},
+		{name: "Missing", header: "", expectedError: ErrPreconditionRequired},
+		{name: "Any", header: "*", expectedError: ErrInvalidIfMatch},
+		{name: "Weak Tag", header: `W/"3"`, expectedError: ErrInvalidIfMatch},
+		{name: "Unquoted", header: "3", expectedError: ErrInvalidIfMatch},
+		{name: "List", header: `"3", "4"`, expectedError: ErrInvalidIfMatch},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest("PATCH", "/", nil)
+			if test.header != "" {
+				c.Request.Header.Set(headerIfMatch, test.header)
+			}
+
+			version, err := ifMatch(c)
+			assert.ErrorIs(t, err, test.expectedError)
+			assert.Equal(t, test.expectedVersion, version)
+		})
+	}
+}
+
+func TestSetETag(t *testing.T) {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	setETag(c, 7)
+	assert.Equal(t, `"7"`, w.Header().Get(headerETag))
+}
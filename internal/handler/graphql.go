@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"goapi/internal/graphqlapi"
+	"goapi/internal/lib/apperr"
+	"goapi/internal/lib/logger/logctx"
+	"goapi/internal/lib/logger/sl"
+	"log/slog"
+	"net/http"
+)
+
+var (
+	ErrInvalidGraphQLVariables = apperr.InvalidField("invalid_variables", "variables", "variables must be a JSON object")
+)
+
+type graphQLRequest struct {
+	Query         string         `json:"query" validate:"required"`
+	OperationName string         `json:"operationName,omitempty"`
+	Variables     map[string]any `json:"variables,omitempty"`
+}
+
+// graphQLQueryParams - запрос GraphQL в строке запроса, переменные передаются строкой JSON
+type graphQLQueryParams struct {
+	Query         string `json:"query" form:"query" validate:"required"`
+	OperationName string `json:"operationName" form:"operationName"`
+	Variables     string `json:"variables" form:"variables"`
+}
+
+// graphQL выполняет запрос или мутацию GraphQL.
+// Ошибки выполнения возвращаются в поле errors со статусом 200, как принято в GraphQL.
+func (h *Handler) graphQL(c *gin.Context) {
+	const op = "handler.graphQL"
+
+	log := logctx.From(c.Request.Context(), h.log).With(
+		slog.String("op", op),
+	)
+
+	_, err := getUserId(c)
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	var input graphQLRequest
+
+	if err := bindJSON(c, &input); err != nil {
+		newErrorResponse(c, err)
+		log.Warn("error bind json", sl.Err(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, h.graphql.Execute(c.Request.Context(), graphqlapi.Request{
+		Query:         input.Query,
+		OperationName: input.OperationName,
+		Variables:     input.Variables,
+	}))
+}
+
+// graphQLQuery выполняет запрос GraphQL из строки запроса. Мутации через GET запрещены.
+func (h *Handler) graphQLQuery(c *gin.Context) {
+	const op = "handler.graphQLQuery"
+
+	log := logctx.From(c.Request.Context(), h.log).With(
+		slog.String("op", op),
+	)
+
+	_, err := getUserId(c)
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	var query graphQLQueryParams
+
+	if err := bindQuery(c, &query); err != nil {
+		newErrorResponse(c, err)
+		log.Warn("error bind query", sl.Err(err))
+		return
+	}
+
+	var variables map[string]any
+	if query.Variables != "" {
+		if err := json.Unmarshal([]byte(query.Variables), &variables); err != nil {
+			newErrorResponse(c, ErrInvalidGraphQLVariables)
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, h.graphql.Execute(c.Request.Context(), graphqlapi.Request{
+		Query:         query.Query,
+		OperationName: query.OperationName,
+		Variables:     variables,
+		ReadOnly:      true,
+	}))
+}
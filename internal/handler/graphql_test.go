@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"goapi/internal/graphqlapi"
+	service_mocks "goapi/internal/handler/mock"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"testing"
+)
+
+func TestGraphQL(t *testing.T) {
+	type mockBehavior func(s *service_mocks.MockGraphQL)
+
+	tests := []struct {
+		name           string
+		method         string
+		target         string
+		body           string
+		mockBehavior   mockBehavior
+		expectedStatus int
+		expectedCode   string
+	}{
+		{
+			name:   "Post",
+			method: http.MethodPost,
+			target: "/graphql",
+			body:   `{"query": "query($id: ID!) { product(id: $id) { name } }", "variables": {"id": "1"}}`,
+			mockBehavior: func(s *service_mocks.MockGraphQL) {
+				s.EXPECT().Execute(gomock.Any(), graphqlapi.Request{
+					Query:     "query($id: ID!) { product(id: $id) { name } }",
+					Variables: map[string]any{"id": "1"},
+				}).Return(&graphqlapi.Response{Data: map[string]any{"product": map[string]any{"name": "Product1"}}})
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:   "Get Is Read Only",
+			method: http.MethodGet,
+			target: "/graphql?" + url.Values{"query": {"{ categoryies { name } }"}, "variables": {`{"limit": 5}`}}.Encode(),
+			mockBehavior: func(s *service_mocks.MockGraphQL) {
+				s.EXPECT().Execute(gomock.Any(), graphqlapi.Request{
+					Query:     "{ categoryies { name } }",
+					Variables: map[string]any{"limit": float64(5)},
+					ReadOnly:  true,
+				}).Return(&graphqlapi.Response{Data: map[string]any{"categoryies": []any{}}})
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Empty Query",
+			method:         http.MethodPost,
+			target:         "/graphql",
+			body:           `{"query": ""}`,
+			mockBehavior:   func(s *service_mocks.MockGraphQL) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Invalid Variables",
+			method:         http.MethodGet,
+			target:         "/graphql?" + url.Values{"query": {"{ categoryies { name } }"}, "variables": {"[1]"}}.Encode(),
+			mockBehavior:   func(s *service_mocks.MockGraphQL) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   "invalid_variables",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockGraphQL := service_mocks.NewMockGraphQL(ctrl)
+			test.mockBehavior(mockGraphQL)
+
+			logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
+			h := NewHandler(Deps{GraphQL: mockGraphQL}, logger)
+
+			r := gin.New()
+			r.Use(h.errorHandler)
+			setUser := func(c *gin.Context) {
+				c.Set(userCtx, int64(1))
+			}
+			r.GET("/graphql", setUser, h.graphQLQuery)
+			r.POST("/graphql", setUser, h.graphQL)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(test.method, test.target, bytes.NewBufferString(test.body))
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, test.expectedStatus, w.Code)
+
+			if test.expectedCode != "" {
+				var p problem
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &p))
+				assert.Equal(t, test.expectedCode, p.Code)
+			}
+		})
+	}
+}
@@ -2,9 +2,21 @@ package handler
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"github.com/gin-gonic/gin"
+	"goapi/internal/graphqlapi"
+	"goapi/internal/lib/apperr"
+	"goapi/internal/lib/catalog"
+	"goapi/internal/lib/eventstream"
+	"goapi/internal/lib/health"
+	"goapi/internal/lib/openapi"
+	"goapi/internal/lib/validate"
 	"goapi/internal/model"
+	"io"
 	"log/slog"
+	"strings"
+	"time"
 )
 
 //go:generate mockgen -source=handler.go -destination=mock/mock.go
@@ -13,11 +25,25 @@ const (
 	InvalidInputBodyErr = "invalid input body"
 )
 
+var (
+	ErrInvalidInputBody = apperr.Validation("invalid_body", InvalidInputBodyErr)
+	ErrRouteNotFound    = apperr.NotFound("route_not_found", "route not found")
+	ErrInvalidQuery     = apperr.Validation("invalid_query", "invalid query parameters")
+)
+
 type Handler struct {
-	auth     AuthService
-	product  ProductService
-	category CategoryService
-	log      *slog.Logger
+	auth        AuthService
+	tokens      TokenParser
+	product     ProductService
+	category    CategoryService
+	catalog     CatalogService
+	idempotency IdempotencyService
+	webhook     WebhookService
+	stream      EventStream
+	graphql     GraphQL
+	health      Health
+	log         *slog.Logger
+	spec        *openapi.Document
 }
 
 type AuthService interface {
@@ -25,28 +51,98 @@ type AuthService interface {
 	Register(ctx context.Context, email, password string) (userID int64, err error)
 }
 
+// TokenParser проверяет подпись токена и возвращает id и роль пользователя
+type TokenParser interface {
+	ParseToken(token string) (int64, model.Role, error)
+}
+
 type ProductService interface {
 	AddProduct(ctx context.Context, name string, categoryies []string) (int64, error)
-	DeleteProduct(ctx context.Context, id int64) error
-	EditProductName(ctx context.Context, id int64, name string) (int64, error)
-	EditProductCategory(ctx context.Context, id int64, categoryies []model.Category) (int64, error)
+	DeleteProduct(ctx context.Context, id int64, version int64) error
+	EditProductName(ctx context.Context, id int64, name string, version int64) (int64, error)
+	EditProductCategory(ctx context.Context, id int64, categoryies []model.Category, version int64) (int64, error)
+	GetProduct(ctx context.Context, id int64) (model.Product, error)
 	GetAllProducts(ctx context.Context, tag string) ([]model.Product, error)
 	GetCategoryProducts(ctx context.Context, category string) ([]model.Product, error)
+	BatchProducts(ctx context.Context, operations []model.ProductOperation, atomic bool) ([]model.ProductOperationResult, error)
 }
 
 type CategoryService interface {
 	AddCategory(ctx context.Context, name string) (int64, error)
-	DeleteCategory(ctx context.Context, id int64) error
-	EditCategory(ctx context.Context, id int64, name string) (int64, error)
+	DeleteCategory(ctx context.Context, id int64, version int64) error
+	EditCategory(ctx context.Context, id int64, name string, version int64) (int64, error)
+	GetCategory(ctx context.Context, id int64) (model.Category, error)
 	GetAllCategoryies(ctx context.Context, tag string) ([]model.Category, error)
+	GetCategoryiesSummary(ctx context.Context) ([]model.CategorySummary, error)
+}
+
+type IdempotencyService interface {
+	Begin(ctx context.Context, userID int64, key, fingerprint string) (string, *model.IdempotentResponse, error)
+	Complete(ctx context.Context, userID int64, key, owner string, resp model.IdempotentResponse) error
+	Release(ctx context.Context, userID int64, key, owner string) error
+}
+
+type WebhookService interface {
+	CreateWebhook(ctx context.Context, userID int64, url string, eventTypes []string, secret string) (model.Webhook, error)
+	ListWebhooks(ctx context.Context, userID int64) ([]model.Webhook, error)
+	GetWebhook(ctx context.Context, userID, id int64) (model.Webhook, error)
+	UpdateWebhook(ctx context.Context, userID, id int64, update model.WebhookUpdate, version int64) (model.Webhook, error)
+	DeleteWebhook(ctx context.Context, userID, id int64, version int64) error
+	ListWebhookDeliveries(ctx context.Context, userID, webhookID int64, limit int) ([]model.WebhookDelivery, error)
+	GetWebhookDelivery(ctx context.Context, userID, webhookID, id int64) (model.WebhookDelivery, error)
+	RedeliverWebhook(ctx context.Context, userID, webhookID, id int64) error
+}
+
+// EventStream - поток изменений каталога для Server-Sent Events
+type EventStream interface {
+	Subscribe(ctx context.Context, lastEventID int64) (*eventstream.Subscription, []model.Event, error)
+	Unsubscribe(sub *eventstream.Subscription)
+	Heartbeat() time.Duration
+}
+
+// GraphQL - выполнение запросов GraphQL к каталогу
+type GraphQL interface {
+	Execute(ctx context.Context, req graphqlapi.Request) *graphqlapi.Response
 }
 
-func NewHandler(a AuthService, p ProductService, c CategoryService, l *slog.Logger) *Handler {
+// Health - проверка готовности приложения принимать запросы
+type Health interface {
+	Ready(ctx context.Context) health.Report
+}
+
+type CatalogService interface {
+	ExportProducts(ctx context.Context, w io.Writer, format catalog.Format) error
+	ImportProducts(ctx context.Context, r io.Reader, format catalog.Format, dryRun bool) (model.ImportReport, error)
+}
+
+// Deps - зависимости обработчиков. Поле можно не задавать, если его маршруты не вызываются,
+// поэтому тесты указывают только то, что проверяют.
+type Deps struct {
+	Auth        AuthService
+	Tokens      TokenParser
+	Product     ProductService
+	Category    CategoryService
+	Catalog     CatalogService
+	Idempotency IdempotencyService
+	Webhook     WebhookService
+	Stream      EventStream
+	GraphQL     GraphQL
+	Health      Health
+}
+
+func NewHandler(deps Deps, l *slog.Logger) *Handler {
 	return &Handler{
-		auth:     a,
-		product:  p,
-		category: c,
-		log:      l,
+		auth:        deps.Auth,
+		tokens:      deps.Tokens,
+		product:     deps.Product,
+		category:    deps.Category,
+		catalog:     deps.Catalog,
+		idempotency: deps.Idempotency,
+		webhook:     deps.Webhook,
+		stream:      deps.Stream,
+		graphql:     deps.GraphQL,
+		health:      deps.Health,
+		log:         l,
 	}
 }
 
@@ -57,7 +153,18 @@ func (h *Handler) Init() *gin.Engine {
 		slog.String("op", op),
 	)
 
+	h.spec = newOpenAPI(endpoints())
+
 	router := gin.New()
+	router.Use(h.tracing, h.requestLogger, h.metrics, h.errorHandler)
+
+	router.GET("/healthz", h.healthz)
+	router.GET("/readyz", h.readyz)
+	router.GET("/version", h.version)
+	router.GET("/metrics", h.prometheusMetrics)
+	router.GET("/openapi.json", h.openAPI)
+	router.GET("/docs", h.docs)
+	router.GET("/docs/swagger-init.js", h.docsScript)
 
 	auth := router.Group("/auth")
 	{
@@ -65,7 +172,7 @@ func (h *Handler) Init() *gin.Engine {
 		auth.POST("/sign-in", h.signIn)
 	}
 
-	api := router.Group("/api", h.userIdentity)
+	api := router.Group("/api", h.userIdentity, h.idempotent)
 	{
 		product := api.Group("/product")
 		{
@@ -80,13 +187,92 @@ func (h *Handler) Init() *gin.Engine {
 		category := api.Group("/category")
 		{
 			category.POST("/add", h.addCategory)
-			category.POST("/delete", h.editCategory)
-			category.POST("/edit", h.deleteCategory)
+			category.POST("/delete", h.deleteCategory)
+			category.POST("/edit", h.editCategory)
 			category.POST("/get-all", h.getAllCategory)
 		}
 	}
 
+	graphql := router.Group("/graphql", h.userIdentity, h.idempotent)
+	{
+		graphql.GET("", h.graphQLQuery)
+		graphql.POST("", h.graphQL)
+	}
+
+	v1 := router.Group("/api/v1", h.userIdentity, h.idempotent)
+	{
+		v1.POST("/products:batch", customMethod("batch", h.batchProducts))
+		v1.GET("/products/:id", h.getProduct)
+		v1.PATCH("/products/:id", h.patchProduct)
+		v1.PUT("/products/:id/categoryies", h.putProductCategoryies)
+		v1.DELETE("/products/:id", h.removeProduct)
+
+		v1.GET("/categories", h.listCategoryies)
+		v1.GET("/categories/:id", h.getCategory)
+		v1.PATCH("/categories/:id", h.patchCategory)
+		v1.DELETE("/categories/:id", h.removeCategory)
+
+		v1.GET("/export", h.exportCatalog)
+		v1.POST("/import", h.importCatalog)
+
+		v1.GET("/events/stream", h.streamEvents)
+
+		v1.POST("/webhooks", h.createWebhook)
+		v1.GET("/webhooks", h.listWebhooks)
+		v1.GET("/webhooks/:id", h.getWebhook)
+		v1.PATCH("/webhooks/:id", h.patchWebhook)
+		v1.DELETE("/webhooks/:id", h.removeWebhook)
+		v1.GET("/webhooks/:id/deliveries", h.listWebhookDeliveries)
+		v1.GET("/webhooks/:id/deliveries/:delivery", h.getWebhookDelivery)
+		v1.POST("/webhooks/:id/deliveries/:delivery/redeliver", h.redeliverWebhook)
+	}
+
 	log.Info("Handler init")
 
 	return router
 }
+
+// customMethod обслуживает маршрут вида /resource:method.
+// gin не экранирует ':' в пути, поэтому суффикс регистрируется как параметр
+// и обработчик вызывается только при точном совпадении имени метода.
+func customMethod(name string, handler gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Param(name) != ":"+name {
+			newErrorResponse(c, ErrRouteNotFound)
+			return
+		}
+
+		handler(c)
+	}
+}
+
+// bindJSON декодирует тело запроса и проверяет его общими правилами валидации
+func bindJSON(c *gin.Context, input any) error {
+	if c.Request == nil || c.Request.Body == nil {
+		return ErrInvalidInputBody
+	}
+
+	if err := json.NewDecoder(c.Request.Body).Decode(input); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) && typeErr.Field != "" {
+			return ErrInvalidInputBody.WithFields(apperr.FieldError{
+				Field:   "/" + strings.ReplaceAll(typeErr.Field, ".", "/"),
+				Code:    "type",
+				Message: "must be " + typeErr.Type.String(),
+			})
+		}
+
+		return ErrInvalidInputBody
+	}
+
+	return validate.Struct(input)
+}
+
+// bindQuery разбирает параметры строки запроса по тегам form и проверяет их
+func bindQuery(c *gin.Context, input any) error {
+	if err := c.ShouldBindQuery(input); err != nil {
+		return ErrInvalidQuery
+	}
+
+	return validate.Struct(input)
+}
@@ -0,0 +1,36 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"goapi/internal/lib/buildinfo"
+	"goapi/internal/lib/health"
+	"net/http"
+)
+
+type healthResponse struct {
+	Status string `json:"status"`
+}
+
+// healthz отвечает, пока процесс жив и обслуживает запросы. Зависимости не проверяются,
+// чтобы оркестратор не перезапускал приложение из-за недоступной базы.
+func (h *Handler) healthz(c *gin.Context) {
+	c.JSON(http.StatusOK, healthResponse{Status: health.StatusOK})
+}
+
+// readyz проверяет, готово ли приложение принимать запросы. При неготовности
+// отвечает 503 с тем же отчетом, чтобы было видно, какая проверка не прошла.
+func (h *Handler) readyz(c *gin.Context) {
+	report := h.health.Ready(c.Request.Context())
+
+	status := http.StatusOK
+	if !report.Ready() {
+		status = http.StatusServiceUnavailable
+	}
+
+	c.JSON(status, report)
+}
+
+// version отдает сведения о сборке
+func (h *Handler) version(c *gin.Context) {
+	c.JSON(http.StatusOK, buildinfo.Get())
+}
@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	service_mocks "goapi/internal/handler/mock"
+	"goapi/internal/lib/buildinfo"
+	"goapi/internal/lib/health"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"runtime"
+	"testing"
+)
+
+func TestReadyz(t *testing.T) {
+	tests := []struct {
+		name           string
+		report         health.Report
+		expectedStatus int
+	}{
+		{
+			name:           "Ready",
+			report:         health.Report{Status: health.StatusOK, Checks: map[string]string{"db": health.StatusOK}},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "NotReady",
+			report:         health.Report{Status: health.StatusFail, Checks: map[string]string{"db": "connection refused"}},
+			expectedStatus: http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockHealth := service_mocks.NewMockHealth(ctrl)
+			mockHealth.EXPECT().Ready(gomock.Any()).Return(test.report)
+
+			logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
+			h := NewHandler(Deps{Health: mockHealth}, logger)
+
+			router := gin.New()
+			router.GET("/readyz", h.readyz)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+			assert.Equal(t, test.expectedStatus, w.Code)
+
+			var report health.Report
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+			assert.Equal(t, test.report, report)
+		})
+	}
+}
+
+func TestHealthzAndVersion(t *testing.T) {
+	h := &Handler{}
+
+	router := gin.New()
+	router.GET("/healthz", h.healthz)
+	router.GET("/version", h.version)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"status":"ok"}`, w.Body.String())
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/version", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var info buildinfo.Info
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &info))
+	assert.Equal(t, buildinfo.Version, info.Version)
+	assert.Equal(t, runtime.Version(), info.GoVersion)
+}
@@ -0,0 +1,178 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"goapi/internal/lib/apperr"
+	"goapi/internal/lib/logger/logctx"
+	"goapi/internal/lib/logger/sl"
+	"goapi/internal/model"
+	"io"
+	"log/slog"
+	"net/http"
+)
+
+const (
+	headerIdempotencyKey      = "Idempotency-Key"
+	headerIdempotentReplayed  = "Idempotent-Replayed"
+	maxIdempotencyKeyLength   = 255
+	maxIdempotentRequestBytes = maxImportSize
+)
+
+var (
+	ErrInvalidIdempotencyKey = apperr.Validation("invalid_idempotency_key", "Idempotency-Key header must be 1-255 characters")
+	ErrRequestTooLarge       = apperr.Validation("request_too_large", "request body is too large")
+)
+
+// replayHeaders - заголовки ответа, которые сохраняются вместе с телом
+var replayHeaders = []string{"Content-Type", headerETag}
+
+// idempotent выполняет POST-запрос с заголовком Idempotency-Key не более одного раза:
+// первый ответ сохраняется, повтор с тем же ключом получает его без изменений.
+// Ответы с внутренней ошибкой не сохраняются, чтобы запрос можно было повторить.
+func (h *Handler) idempotent(c *gin.Context) {
+	const op = "handler.idempotent"
+
+	if c.Request.Method != http.MethodPost {
+		return
+	}
+
+	key := c.GetHeader(headerIdempotencyKey)
+	if key == "" {
+		return
+	}
+
+	log := logctx.From(c.Request.Context(), h.log).With(
+		slog.String("op", op),
+	)
+
+	if len(key) > maxIdempotencyKeyLength {
+		newErrorResponse(c, ErrInvalidIdempotencyKey)
+		return
+	}
+
+	userID, err := getUserId(c)
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	fingerprint, err := requestFingerprint(c)
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	owner, stored, err := h.idempotency.Begin(c.Request.Context(), userID, key, fingerprint)
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	if stored != nil {
+		replay(c, stored)
+		return
+	}
+
+	recorder := &responseRecorder{ResponseWriter: c.Writer}
+	c.Writer = recorder
+
+	// при панике ключ освобождается сразу, иначе повтор запроса получал бы 409 до конца аренды
+	defer func() {
+		if p := recover(); p != nil {
+			c.Writer = recorder.ResponseWriter
+			if err := h.idempotency.Release(context.WithoutCancel(c.Request.Context()), userID, key, owner); err != nil {
+				log.Error("error release idempotency key", sl.Err(err))
+			}
+			panic(p)
+		}
+	}()
+
+	c.Next()
+
+	// ошибку нужно записать здесь, иначе ее отрисует errorHandler уже после сохранения
+	h.renderError(c)
+	c.Writer = recorder.ResponseWriter
+
+	// клиент мог отключиться, но результат запроса сохранить все равно нужно
+	ctx := context.WithoutCancel(c.Request.Context())
+
+	if recorder.Status() >= http.StatusInternalServerError {
+		if err := h.idempotency.Release(ctx, userID, key, owner); err != nil {
+			log.Error("error release idempotency key", sl.Err(err))
+		}
+		return
+	}
+
+	resp := model.IdempotentResponse{
+		Fingerprint: fingerprint,
+		Status:      recorder.Status(),
+		Header:      make(map[string]string, len(replayHeaders)),
+		Body:        recorder.body.Bytes(),
+	}
+	for _, name := range replayHeaders {
+		if value := recorder.Header().Get(name); value != "" {
+			resp.Header[name] = value
+		}
+	}
+
+	// ответ уже отдан клиенту: если аренда ключа истекла, повтор запроса выполнится заново
+	if err := h.idempotency.Complete(ctx, userID, key, owner, resp); err != nil {
+		log.Error("error save idempotent response", sl.Err(err))
+	}
+}
+
+// requestFingerprint считает отпечаток запроса, чтобы ключ нельзя было
+// переиспользовать для другого запроса. Тело читается целиком и возвращается в запрос.
+func requestFingerprint(c *gin.Context) (string, error) {
+	hash := sha256.New()
+	hash.Write([]byte(c.Request.Method + " " + c.Request.URL.RequestURI() + "\n"))
+
+	if c.Request.Body != nil {
+		body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxIdempotentRequestBytes))
+		if err != nil {
+			var sizeErr *http.MaxBytesError
+			if errors.As(err, &sizeErr) {
+				return "", ErrRequestTooLarge
+			}
+			return "", ErrInvalidInputBody
+		}
+
+		hash.Write(body)
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// replay отдает сохраненный ответ
+func replay(c *gin.Context, resp *model.IdempotentResponse) {
+	for name, value := range resp.Header {
+		c.Header(name, value)
+	}
+	c.Header(headerIdempotentReplayed, "true")
+
+	c.Status(resp.Status)
+	_, _ = c.Writer.Write(resp.Body)
+	c.Abort()
+}
+
+// responseRecorder копирует тело ответа, чтобы его можно было сохранить
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
@@ -0,0 +1,187 @@
+package handler
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	service_mocks "goapi/internal/handler/mock"
+	"goapi/internal/lib/apperr"
+	"goapi/internal/model"
+	"log/slog"
+	"os"
+)
+
+func TestIdempotent(t *testing.T) {
+	type mockBehavior func(i *service_mocks.MockIdempotencyService, p *service_mocks.MockProductService)
+
+	tests := []struct {
+		name             string
+		key              string
+		mockBehavior     mockBehavior
+		expectedStatus   int
+		expectedBody     string
+		expectedReplayed string
+	}{
+		{
+			name: "Without Key",
+			mockBehavior: func(i *service_mocks.MockIdempotencyService, p *service_mocks.MockProductService) {
+				p.EXPECT().AddProduct(gomock.Any(), "Test Product", []string{"Category1"}).Return(int64(1), nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"productID":1}`,
+		},
+		{
+			name: "First Request",
+			key:  "key-1",
+			mockBehavior: func(i *service_mocks.MockIdempotencyService, p *service_mocks.MockProductService) {
+				i.EXPECT().Begin(gomock.Any(), int64(1), "key-1", gomock.Any()).Return("owner-1", nil, nil)
+				p.EXPECT().AddProduct(gomock.Any(), "Test Product", []string{"Category1"}).Return(int64(1), nil)
+				i.EXPECT().Complete(gomock.Any(), int64(1), "key-1", "owner-1", gomock.Any()).DoAndReturn(
+					func(_ any, _ int64, _, _ string, resp model.IdempotentResponse) error {
+						assert.Equal(t, http.StatusOK, resp.Status)
+						assert.Equal(t, `{"productID":1}`, string(resp.Body))
+						assert.Equal(t, "application/json; charset=utf-8", resp.Header["Content-Type"])
+						return nil
+					})
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"productID":1}`,
+		},
+		{
+			name: "Replay",
+			key:  "key-1",
+			mockBehavior: func(i *service_mocks.MockIdempotencyService, p *service_mocks.MockProductService) {
+				i.EXPECT().Begin(gomock.Any(), int64(1), "key-1", gomock.Any()).Return("", &model.IdempotentResponse{
+					Status: http.StatusOK,
+					Header: map[string]string{"Content-Type": "application/json; charset=utf-8"},
+					Body:   []byte(`{"productID":1}`),
+				}, nil)
+			},
+			expectedStatus:   http.StatusOK,
+			expectedBody:     `{"productID":1}`,
+			expectedReplayed: "true",
+		},
+		{
+			name: "In Flight",
+			key:  "key-1",
+			mockBehavior: func(i *service_mocks.MockIdempotencyService, p *service_mocks.MockProductService) {
+				i.EXPECT().Begin(gomock.Any(), int64(1), "key-1", gomock.Any()).
+					Return("", nil, apperr.Conflict("idempotency_key_in_flight", "request with this idempotency key is still in progress"))
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name: "Domain Error Stored",
+			key:  "key-1",
+			mockBehavior: func(i *service_mocks.MockIdempotencyService, p *service_mocks.MockProductService) {
+				i.EXPECT().Begin(gomock.Any(), int64(1), "key-1", gomock.Any()).Return("owner-1", nil, nil)
+				p.EXPECT().AddProduct(gomock.Any(), "Test Product", []string{"Category1"}).
+					Return(int64(0), apperr.NotFound("category_not_found", "category not found"))
+				i.EXPECT().Complete(gomock.Any(), int64(1), "key-1", "owner-1", gomock.Any()).DoAndReturn(
+					func(_ any, _ int64, _, _ string, resp model.IdempotentResponse) error {
+						assert.Equal(t, http.StatusNotFound, resp.Status)
+						assert.Equal(t, problemContentType, resp.Header["Content-Type"])
+						return nil
+					})
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name: "Internal Error Released",
+			key:  "key-1",
+			mockBehavior: func(i *service_mocks.MockIdempotencyService, p *service_mocks.MockProductService) {
+				i.EXPECT().Begin(gomock.Any(), int64(1), "key-1", gomock.Any()).Return("owner-1", nil, nil)
+				p.EXPECT().AddProduct(gomock.Any(), "Test Product", []string{"Category1"}).
+					Return(int64(0), apperr.Internal("product_not_saved", "product is not saved"))
+				i.EXPECT().Release(gomock.Any(), int64(1), "key-1", "owner-1").Return(nil)
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockIdempotencyService := service_mocks.NewMockIdempotencyService(ctrl)
+			mockProductService := service_mocks.NewMockProductService(ctrl)
+			test.mockBehavior(mockIdempotencyService, mockProductService)
+
+			logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
+			h := NewHandler(Deps{Product: mockProductService, Idempotency: mockIdempotencyService}, logger)
+
+			r := gin.New()
+			r.Use(h.errorHandler)
+			r.POST("/api/product/add", func(c *gin.Context) {
+				c.Set(userCtx, int64(1))
+			}, h.idempotent, h.addProduct)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/api/product/add",
+				bytes.NewBufferString(`{"name": "Test Product", "categoryies": ["Category1"]}`))
+			if test.key != "" {
+				req.Header.Set(headerIdempotencyKey, test.key)
+			}
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, test.expectedStatus, w.Code)
+			if test.expectedBody != "" {
+				assert.Equal(t, test.expectedBody, w.Body.String())
+			}
+			assert.Equal(t, test.expectedReplayed, w.Header().Get(headerIdempotentReplayed))
+		})
+	}
+}
+
+func TestIdempotentPanicReleasesKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockIdempotencyService := service_mocks.NewMockIdempotencyService(ctrl)
+	mockIdempotencyService.EXPECT().Begin(gomock.Any(), int64(1), "key-1", gomock.Any()).Return("owner-1", nil, nil)
+	mockIdempotencyService.EXPECT().Release(gomock.Any(), int64(1), "key-1", "owner-1").Return(nil)
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	h := NewHandler(Deps{Idempotency: mockIdempotencyService}, logger)
+
+	r := gin.New()
+	r.POST("/api/product/add", func(c *gin.Context) {
+		c.Set(userCtx, int64(1))
+	}, h.idempotent, func(c *gin.Context) {
+		panic("boom")
+	})
+
+	req := httptest.NewRequest("POST", "/api/product/add", bytes.NewBufferString(`{}`))
+	req.Header.Set(headerIdempotencyKey, "key-1")
+
+	// паника не проглатывается: ее обрабатывает тот, кто выше по стеку
+	assert.PanicsWithValue(t, "boom", func() {
+		r.ServeHTTP(httptest.NewRecorder(), req)
+	})
+}
+
+func TestRequestFingerprint(t *testing.T) {
+	fingerprint := func(path, body string) string {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest("POST", path, bytes.NewBufferString(body))
+
+		fp, err := requestFingerprint(c)
+		assert.NoError(t, err)
+
+		rest := new(bytes.Buffer)
+		_, _ = rest.ReadFrom(c.Request.Body)
+		assert.Equal(t, body, rest.String())
+
+		return fp
+	}
+
+	assert.Equal(t, fingerprint("/api/product/add", `{"name":"a"}`), fingerprint("/api/product/add", `{"name":"a"}`))
+	assert.NotEqual(t, fingerprint("/api/product/add", `{"name":"a"}`), fingerprint("/api/product/add", `{"name":"b"}`))
+	assert.NotEqual(t, fingerprint("/api/product/add", `{}`), fingerprint("/api/category/add", `{}`))
+}
@@ -1,11 +1,16 @@
 package handler
 
 import (
-	"errors"
 	"github.com/gin-gonic/gin"
-	"goapi/internal/lib/jwt"
+	"go.opentelemetry.io/otel/trace"
+	"goapi/internal/lib/apperr"
+	"goapi/internal/lib/authctx"
+	"goapi/internal/lib/logger/logctx"
+	"goapi/internal/lib/requestid"
+	"log/slog"
 	"net/http"
 	"strings"
+	"time"
 )
 
 const (
@@ -13,42 +18,92 @@ const (
 	userCtx             = "userId"
 )
 
+var (
+	ErrEmptyAuthHeader   = apperr.Unauthorized("empty_auth_header", "empty auth header")
+	ErrInvalidAuthHeader = apperr.Unauthorized("invalid_auth_header", "invalid auth header")
+	ErrEmptyToken        = apperr.Unauthorized("empty_token", "token is empty")
+	ErrInvalidToken      = apperr.Unauthorized("invalid_token", "invalid token")
+	ErrUserIDNotFound    = apperr.Unauthorized("user_id_not_found", "user id not found")
+	ErrUserIDInvalidType = apperr.Unauthorized("user_id_invalid_type", "user id is of invalid type")
+)
+
 func (h *Handler) userIdentity(c *gin.Context) {
 	header := c.GetHeader(authorizationHeader)
 	if header == "" {
-		newErrorResponse(c, http.StatusUnauthorized, "empty auth header")
+		newErrorResponse(c, ErrEmptyAuthHeader)
 		return
 	}
 
 	headerParts := strings.Split(header, " ")
 	if len(headerParts) != 2 || headerParts[0] != "Bearer" {
-		newErrorResponse(c, http.StatusUnauthorized, "invalid auth header")
+		newErrorResponse(c, ErrInvalidAuthHeader)
 		return
 	}
 
 	if len(headerParts[1]) == 0 {
-		newErrorResponse(c, http.StatusUnauthorized, "token is empty")
+		newErrorResponse(c, ErrEmptyToken)
 		return
 	}
 
-	userId, err := jwt.ParseToken(headerParts[1])
+	userId, role, err := h.tokens.ParseToken(headerParts[1])
 	if err != nil {
-		newErrorResponse(c, http.StatusUnauthorized, err.Error())
+		newErrorResponse(c, ErrInvalidToken)
 		return
 	}
 
 	c.Set(userCtx, userId)
+
+	ctx := authctx.With(c.Request.Context(), authctx.User{ID: userId, Role: role})
+	c.Request = c.Request.WithContext(logctx.With(ctx, logctx.From(ctx, h.log).With(slog.Int64("user_id", userId))))
+}
+
+// requestLogger присваивает запросу X-Request-ID (или принимает его от клиента), кладет в контекст
+// логгер с request_id и trace_id и после ответа пишет access log
+func (h *Handler) requestLogger(c *gin.Context) {
+	start := time.Now()
+
+	id := requestid.FromClient(c.GetHeader(requestid.Header))
+	c.Header(requestid.Header, id)
+
+	log := h.log.With(slog.String("request_id", id))
+	if span := trace.SpanContextFromContext(c.Request.Context()); span.HasTraceID() {
+		log = log.With(slog.String("trace_id", span.TraceID().String()))
+	}
+	c.Request = c.Request.WithContext(logctx.With(c.Request.Context(), log))
+
+	c.Next()
+
+	status := c.Writer.Status()
+	level := slog.LevelInfo
+	switch {
+	case status >= http.StatusInternalServerError:
+		level = slog.LevelError
+	case status >= http.StatusBadRequest:
+		level = slog.LevelWarn
+	}
+
+	// логгер берется заново: после userIdentity в нем есть user_id
+	logctx.From(c.Request.Context(), log).LogAttrs(c.Request.Context(), level, "request",
+		slog.String("method", c.Request.Method),
+		slog.String("path", c.Request.URL.Path),
+		slog.String("route", c.FullPath()),
+		slog.Int("status", status),
+		slog.Int("bytes", max(c.Writer.Size(), 0)),
+		slog.Duration("duration", time.Since(start)),
+		slog.String("client_ip", c.ClientIP()),
+		slog.String("user_agent", c.Request.UserAgent()),
+	)
 }
 
 func getUserId(c *gin.Context) (int64, error) {
 	id, ok := c.Get(userCtx)
 	if !ok {
-		return 0, errors.New("user id not found")
+		return 0, ErrUserIDNotFound
 	}
 
 	idInt, ok := id.(int64)
 	if !ok {
-		return 0, errors.New("user id is of invalid type")
+		return 0, ErrUserIDInvalidType
 	}
 
 	return idInt, nil
@@ -1,20 +1,31 @@
 package handler
 
 import (
+	"bytes"
+	"encoding/json"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
+	"goapi/internal/lib/jwt"
+	"goapi/internal/lib/logger/logctx"
+	"goapi/internal/lib/requestid"
+	"goapi/internal/model"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 )
 
+var testSigner = jwt.NewSigner("test-signing-key")
+
 func TestUserIdentityFailed(t *testing.T) {
 	c, _ := gin.CreateTestContext(httptest.NewRecorder())
 
 	c.Request, _ = http.NewRequest("GET", "/test", nil)
 	c.Request.Header.Set("Authorization", "Bearer test-failed-token")
 
-	h := &Handler{}
+	h := &Handler{tokens: testSigner}
 
 	h.userIdentity(c)
 
@@ -32,3 +43,99 @@ func TestUserIdentityFailed(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, 123, id)
 }*/
+
+func TestRequestLogger(t *testing.T) {
+	tests := []struct {
+		name        string
+		requestID   string
+		status      int
+		expectedID  string
+		expectedLvl string
+	}{
+		{
+			name:        "Generated",
+			status:      http.StatusOK,
+			expectedLvl: "INFO",
+		},
+		{
+			name:        "FromClient",
+			requestID:   "client-id-1",
+			status:      http.StatusNotFound,
+			expectedID:  "client-id-1",
+			expectedLvl: "WARN",
+		},
+		{
+			name:        "InvalidFromClient",
+			requestID:   "bad id\n",
+			status:      http.StatusInternalServerError,
+			expectedLvl: "ERROR",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			h := &Handler{log: slog.New(slog.NewJSONHandler(&buf, nil))}
+
+			var handlerID string
+			r := gin.New()
+			r.Use(h.requestLogger)
+			r.GET("/items/:id", func(c *gin.Context) {
+				logctx.From(c.Request.Context(), nil).Info("inside")
+				handlerID = c.Writer.Header().Get(requestid.Header)
+				c.Status(test.status)
+			})
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/items/1", nil)
+			if test.requestID != "" {
+				req.Header.Set(requestid.Header, test.requestID)
+			}
+			r.ServeHTTP(w, req)
+
+			id := w.Header().Get(requestid.Header)
+			assert.True(t, requestid.Valid(id))
+			assert.Equal(t, id, handlerID)
+			if test.expectedID != "" {
+				assert.Equal(t, test.expectedID, id)
+			}
+
+			lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+			assert.Len(t, lines, 2)
+
+			var inside, access map[string]any
+			assert.NoError(t, json.Unmarshal([]byte(lines[0]), &inside))
+			assert.NoError(t, json.Unmarshal([]byte(lines[1]), &access))
+
+			assert.Equal(t, id, inside["request_id"])
+			assert.Equal(t, id, access["request_id"])
+			assert.Equal(t, "request", access["msg"])
+			assert.Equal(t, test.expectedLvl, access["level"])
+			assert.Equal(t, "/items/:id", access["route"])
+			assert.Equal(t, "/items/1", access["path"])
+			assert.Equal(t, float64(test.status), access["status"])
+		})
+	}
+}
+
+func TestUserIdentityAddsUserToLogger(t *testing.T) {
+	var buf bytes.Buffer
+	h := &Handler{tokens: testSigner, log: slog.New(slog.NewJSONHandler(&buf, nil))}
+
+	token, err := testSigner.NewToken(model.User{ID: 7}, time.Minute)
+	assert.NoError(t, err)
+
+	r := gin.New()
+	r.Use(h.requestLogger)
+	r.GET("/me", h.userIdentity, func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/me", nil)
+	req.Header.Set(authorizationHeader, "Bearer "+token)
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	var access map[string]any
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &access))
+	assert.Equal(t, float64(7), access["user_id"])
+}
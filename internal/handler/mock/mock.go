@@ -6,8 +6,14 @@ package mock_handler
 
 import (
 	context "context"
+	graphqlapi "goapi/internal/graphqlapi"
+	catalog "goapi/internal/lib/catalog"
+	eventstream "goapi/internal/lib/eventstream"
+	health "goapi/internal/lib/health"
 	model "goapi/internal/model"
+	io "io"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 )
@@ -65,6 +71,45 @@ func (mr *MockAuthServiceMockRecorder) Register(ctx, email, password interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Register", reflect.TypeOf((*MockAuthService)(nil).Register), ctx, email, password)
 }
 
+// MockTokenParser is a mock of TokenParser interface.
+type MockTokenParser struct {
+	ctrl     *gomock.Controller
+	recorder *MockTokenParserMockRecorder
+}
+
+// MockTokenParserMockRecorder is the mock recorder for MockTokenParser.
+type MockTokenParserMockRecorder struct {
+	mock *MockTokenParser
+}
+
+// NewMockTokenParser creates a new mock instance.
+func NewMockTokenParser(ctrl *gomock.Controller) *MockTokenParser {
+	mock := &MockTokenParser{ctrl: ctrl}
+	mock.recorder = &MockTokenParserMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTokenParser) EXPECT() *MockTokenParserMockRecorder {
+	return m.recorder
+}
+
+// ParseToken mocks base method.
+func (m *MockTokenParser) ParseToken(token string) (int64, model.Role, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ParseToken", token)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(model.Role)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ParseToken indicates an expected call of ParseToken.
+func (mr *MockTokenParserMockRecorder) ParseToken(token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ParseToken", reflect.TypeOf((*MockTokenParser)(nil).ParseToken), token)
+}
+
 // MockProductService is a mock of ProductService interface.
 type MockProductService struct {
 	ctrl     *gomock.Controller
@@ -103,48 +148,63 @@ func (mr *MockProductServiceMockRecorder) AddProduct(ctx, name, categoryies inte
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddProduct", reflect.TypeOf((*MockProductService)(nil).AddProduct), ctx, name, categoryies)
 }
 
+// BatchProducts mocks base method.
+func (m *MockProductService) BatchProducts(ctx context.Context, operations []model.ProductOperation, atomic bool) ([]model.ProductOperationResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BatchProducts", ctx, operations, atomic)
+	ret0, _ := ret[0].([]model.ProductOperationResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BatchProducts indicates an expected call of BatchProducts.
+func (mr *MockProductServiceMockRecorder) BatchProducts(ctx, operations, atomic interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchProducts", reflect.TypeOf((*MockProductService)(nil).BatchProducts), ctx, operations, atomic)
+}
+
 // DeleteProduct mocks base method.
-func (m *MockProductService) DeleteProduct(ctx context.Context, id int64) error {
+func (m *MockProductService) DeleteProduct(ctx context.Context, id, version int64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteProduct", ctx, id)
+	ret := m.ctrl.Call(m, "DeleteProduct", ctx, id, version)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteProduct indicates an expected call of DeleteProduct.
-func (mr *MockProductServiceMockRecorder) DeleteProduct(ctx, id interface{}) *gomock.Call {
+func (mr *MockProductServiceMockRecorder) DeleteProduct(ctx, id, version interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteProduct", reflect.TypeOf((*MockProductService)(nil).DeleteProduct), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteProduct", reflect.TypeOf((*MockProductService)(nil).DeleteProduct), ctx, id, version)
 }
 
 // EditProductCategory mocks base method.
-func (m *MockProductService) EditProductCategory(ctx context.Context, id int64, categoryies []model.Category) (int64, error) {
+func (m *MockProductService) EditProductCategory(ctx context.Context, id int64, categoryies []model.Category, version int64) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "EditProductCategory", ctx, id, categoryies)
+	ret := m.ctrl.Call(m, "EditProductCategory", ctx, id, categoryies, version)
 	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // EditProductCategory indicates an expected call of EditProductCategory.
-func (mr *MockProductServiceMockRecorder) EditProductCategory(ctx, id, categoryies interface{}) *gomock.Call {
+func (mr *MockProductServiceMockRecorder) EditProductCategory(ctx, id, categoryies, version interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EditProductCategory", reflect.TypeOf((*MockProductService)(nil).EditProductCategory), ctx, id, categoryies)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EditProductCategory", reflect.TypeOf((*MockProductService)(nil).EditProductCategory), ctx, id, categoryies, version)
 }
 
 // EditProductName mocks base method.
-func (m *MockProductService) EditProductName(ctx context.Context, id int64, name string) (int64, error) {
+func (m *MockProductService) EditProductName(ctx context.Context, id int64, name string, version int64) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "EditProductName", ctx, id, name)
+	ret := m.ctrl.Call(m, "EditProductName", ctx, id, name, version)
 	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // EditProductName indicates an expected call of EditProductName.
-func (mr *MockProductServiceMockRecorder) EditProductName(ctx, id, name interface{}) *gomock.Call {
+func (mr *MockProductServiceMockRecorder) EditProductName(ctx, id, name, version interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EditProductName", reflect.TypeOf((*MockProductService)(nil).EditProductName), ctx, id, name)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EditProductName", reflect.TypeOf((*MockProductService)(nil).EditProductName), ctx, id, name, version)
 }
 
 // GetAllProducts mocks base method.
@@ -177,6 +237,21 @@ func (mr *MockProductServiceMockRecorder) GetCategoryProducts(ctx, category inte
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCategoryProducts", reflect.TypeOf((*MockProductService)(nil).GetCategoryProducts), ctx, category)
 }
 
+// GetProduct mocks base method.
+func (m *MockProductService) GetProduct(ctx context.Context, id int64) (model.Product, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProduct", ctx, id)
+	ret0, _ := ret[0].(model.Product)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProduct indicates an expected call of GetProduct.
+func (mr *MockProductServiceMockRecorder) GetProduct(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProduct", reflect.TypeOf((*MockProductService)(nil).GetProduct), ctx, id)
+}
+
 // MockCategoryService is a mock of CategoryService interface.
 type MockCategoryService struct {
 	ctrl     *gomock.Controller
@@ -216,32 +291,32 @@ func (mr *MockCategoryServiceMockRecorder) AddCategory(ctx, name interface{}) *g
 }
 
 // DeleteCategory mocks base method.
-func (m *MockCategoryService) DeleteCategory(ctx context.Context, id int64) error {
+func (m *MockCategoryService) DeleteCategory(ctx context.Context, id, version int64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteCategory", ctx, id)
+	ret := m.ctrl.Call(m, "DeleteCategory", ctx, id, version)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteCategory indicates an expected call of DeleteCategory.
-func (mr *MockCategoryServiceMockRecorder) DeleteCategory(ctx, id interface{}) *gomock.Call {
+func (mr *MockCategoryServiceMockRecorder) DeleteCategory(ctx, id, version interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCategory", reflect.TypeOf((*MockCategoryService)(nil).DeleteCategory), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCategory", reflect.TypeOf((*MockCategoryService)(nil).DeleteCategory), ctx, id, version)
 }
 
 // EditCategory mocks base method.
-func (m *MockCategoryService) EditCategory(ctx context.Context, id int64, name string) (int64, error) {
+func (m *MockCategoryService) EditCategory(ctx context.Context, id int64, name string, version int64) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "EditCategory", ctx, id, name)
+	ret := m.ctrl.Call(m, "EditCategory", ctx, id, name, version)
 	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // EditCategory indicates an expected call of EditCategory.
-func (mr *MockCategoryServiceMockRecorder) EditCategory(ctx, id, name interface{}) *gomock.Call {
+func (mr *MockCategoryServiceMockRecorder) EditCategory(ctx, id, name, version interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EditCategory", reflect.TypeOf((*MockCategoryService)(nil).EditCategory), ctx, id, name)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EditCategory", reflect.TypeOf((*MockCategoryService)(nil).EditCategory), ctx, id, name, version)
 }
 
 // GetAllCategoryies mocks base method.
@@ -258,3 +333,432 @@ func (mr *MockCategoryServiceMockRecorder) GetAllCategoryies(ctx, tag interface{
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllCategoryies", reflect.TypeOf((*MockCategoryService)(nil).GetAllCategoryies), ctx, tag)
 }
+
+// GetCategory mocks base method.
+func (m *MockCategoryService) GetCategory(ctx context.Context, id int64) (model.Category, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCategory", ctx, id)
+	ret0, _ := ret[0].(model.Category)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCategory indicates an expected call of GetCategory.
+func (mr *MockCategoryServiceMockRecorder) GetCategory(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCategory", reflect.TypeOf((*MockCategoryService)(nil).GetCategory), ctx, id)
+}
+
+// GetCategoryiesSummary mocks base method.
+func (m *MockCategoryService) GetCategoryiesSummary(ctx context.Context) ([]model.CategorySummary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCategoryiesSummary", ctx)
+	ret0, _ := ret[0].([]model.CategorySummary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCategoryiesSummary indicates an expected call of GetCategoryiesSummary.
+func (mr *MockCategoryServiceMockRecorder) GetCategoryiesSummary(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCategoryiesSummary", reflect.TypeOf((*MockCategoryService)(nil).GetCategoryiesSummary), ctx)
+}
+
+// MockIdempotencyService is a mock of IdempotencyService interface.
+type MockIdempotencyService struct {
+	ctrl     *gomock.Controller
+	recorder *MockIdempotencyServiceMockRecorder
+}
+
+// MockIdempotencyServiceMockRecorder is the mock recorder for MockIdempotencyService.
+type MockIdempotencyServiceMockRecorder struct {
+	mock *MockIdempotencyService
+}
+
+// NewMockIdempotencyService creates a new mock instance.
+func NewMockIdempotencyService(ctrl *gomock.Controller) *MockIdempotencyService {
+	mock := &MockIdempotencyService{ctrl: ctrl}
+	mock.recorder = &MockIdempotencyServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIdempotencyService) EXPECT() *MockIdempotencyServiceMockRecorder {
+	return m.recorder
+}
+
+// Begin mocks base method.
+func (m *MockIdempotencyService) Begin(ctx context.Context, userID int64, key, fingerprint string) (string, *model.IdempotentResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Begin", ctx, userID, key, fingerprint)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(*model.IdempotentResponse)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Begin indicates an expected call of Begin.
+func (mr *MockIdempotencyServiceMockRecorder) Begin(ctx, userID, key, fingerprint interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Begin", reflect.TypeOf((*MockIdempotencyService)(nil).Begin), ctx, userID, key, fingerprint)
+}
+
+// Complete mocks base method.
+func (m *MockIdempotencyService) Complete(ctx context.Context, userID int64, key, owner string, resp model.IdempotentResponse) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Complete", ctx, userID, key, owner, resp)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Complete indicates an expected call of Complete.
+func (mr *MockIdempotencyServiceMockRecorder) Complete(ctx, userID, key, owner, resp interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Complete", reflect.TypeOf((*MockIdempotencyService)(nil).Complete), ctx, userID, key, owner, resp)
+}
+
+// Release mocks base method.
+func (m *MockIdempotencyService) Release(ctx context.Context, userID int64, key, owner string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Release", ctx, userID, key, owner)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Release indicates an expected call of Release.
+func (mr *MockIdempotencyServiceMockRecorder) Release(ctx, userID, key, owner interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Release", reflect.TypeOf((*MockIdempotencyService)(nil).Release), ctx, userID, key, owner)
+}
+
+// MockWebhookService is a mock of WebhookService interface.
+type MockWebhookService struct {
+	ctrl     *gomock.Controller
+	recorder *MockWebhookServiceMockRecorder
+}
+
+// MockWebhookServiceMockRecorder is the mock recorder for MockWebhookService.
+type MockWebhookServiceMockRecorder struct {
+	mock *MockWebhookService
+}
+
+// NewMockWebhookService creates a new mock instance.
+func NewMockWebhookService(ctrl *gomock.Controller) *MockWebhookService {
+	mock := &MockWebhookService{ctrl: ctrl}
+	mock.recorder = &MockWebhookServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWebhookService) EXPECT() *MockWebhookServiceMockRecorder {
+	return m.recorder
+}
+
+// CreateWebhook mocks base method.
+func (m *MockWebhookService) CreateWebhook(ctx context.Context, userID int64, url string, eventTypes []string, secret string) (model.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhook", ctx, userID, url, eventTypes, secret)
+	ret0, _ := ret[0].(model.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWebhook indicates an expected call of CreateWebhook.
+func (mr *MockWebhookServiceMockRecorder) CreateWebhook(ctx, userID, url, eventTypes, secret interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhook", reflect.TypeOf((*MockWebhookService)(nil).CreateWebhook), ctx, userID, url, eventTypes, secret)
+}
+
+// DeleteWebhook mocks base method.
+func (m *MockWebhookService) DeleteWebhook(ctx context.Context, userID, id, version int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhook", ctx, userID, id, version)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWebhook indicates an expected call of DeleteWebhook.
+func (mr *MockWebhookServiceMockRecorder) DeleteWebhook(ctx, userID, id, version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhook", reflect.TypeOf((*MockWebhookService)(nil).DeleteWebhook), ctx, userID, id, version)
+}
+
+// GetWebhook mocks base method.
+func (m *MockWebhookService) GetWebhook(ctx context.Context, userID, id int64) (model.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhook", ctx, userID, id)
+	ret0, _ := ret[0].(model.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhook indicates an expected call of GetWebhook.
+func (mr *MockWebhookServiceMockRecorder) GetWebhook(ctx, userID, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhook", reflect.TypeOf((*MockWebhookService)(nil).GetWebhook), ctx, userID, id)
+}
+
+// GetWebhookDelivery mocks base method.
+func (m *MockWebhookService) GetWebhookDelivery(ctx context.Context, userID, webhookID, id int64) (model.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhookDelivery", ctx, userID, webhookID, id)
+	ret0, _ := ret[0].(model.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhookDelivery indicates an expected call of GetWebhookDelivery.
+func (mr *MockWebhookServiceMockRecorder) GetWebhookDelivery(ctx, userID, webhookID, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookDelivery", reflect.TypeOf((*MockWebhookService)(nil).GetWebhookDelivery), ctx, userID, webhookID, id)
+}
+
+// ListWebhookDeliveries mocks base method.
+func (m *MockWebhookService) ListWebhookDeliveries(ctx context.Context, userID, webhookID int64, limit int) ([]model.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWebhookDeliveries", ctx, userID, webhookID, limit)
+	ret0, _ := ret[0].([]model.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWebhookDeliveries indicates an expected call of ListWebhookDeliveries.
+func (mr *MockWebhookServiceMockRecorder) ListWebhookDeliveries(ctx, userID, webhookID, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebhookDeliveries", reflect.TypeOf((*MockWebhookService)(nil).ListWebhookDeliveries), ctx, userID, webhookID, limit)
+}
+
+// ListWebhooks mocks base method.
+func (m *MockWebhookService) ListWebhooks(ctx context.Context, userID int64) ([]model.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWebhooks", ctx, userID)
+	ret0, _ := ret[0].([]model.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWebhooks indicates an expected call of ListWebhooks.
+func (mr *MockWebhookServiceMockRecorder) ListWebhooks(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebhooks", reflect.TypeOf((*MockWebhookService)(nil).ListWebhooks), ctx, userID)
+}
+
+// RedeliverWebhook mocks base method.
+func (m *MockWebhookService) RedeliverWebhook(ctx context.Context, userID, webhookID, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RedeliverWebhook", ctx, userID, webhookID, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RedeliverWebhook indicates an expected call of RedeliverWebhook.
+func (mr *MockWebhookServiceMockRecorder) RedeliverWebhook(ctx, userID, webhookID, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RedeliverWebhook", reflect.TypeOf((*MockWebhookService)(nil).RedeliverWebhook), ctx, userID, webhookID, id)
+}
+
+// UpdateWebhook mocks base method.
+func (m *MockWebhookService) UpdateWebhook(ctx context.Context, userID, id int64, update model.WebhookUpdate, version int64) (model.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWebhook", ctx, userID, id, update, version)
+	ret0, _ := ret[0].(model.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateWebhook indicates an expected call of UpdateWebhook.
+func (mr *MockWebhookServiceMockRecorder) UpdateWebhook(ctx, userID, id, update, version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWebhook", reflect.TypeOf((*MockWebhookService)(nil).UpdateWebhook), ctx, userID, id, update, version)
+}
+
+// MockEventStream is a mock of EventStream interface.
+type MockEventStream struct {
+	ctrl     *gomock.Controller
+	recorder *MockEventStreamMockRecorder
+}
+
+// MockEventStreamMockRecorder is the mock recorder for MockEventStream.
+type MockEventStreamMockRecorder struct {
+	mock *MockEventStream
+}
+
+// NewMockEventStream creates a new mock instance.
+func NewMockEventStream(ctrl *gomock.Controller) *MockEventStream {
+	mock := &MockEventStream{ctrl: ctrl}
+	mock.recorder = &MockEventStreamMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEventStream) EXPECT() *MockEventStreamMockRecorder {
+	return m.recorder
+}
+
+// Heartbeat mocks base method.
+func (m *MockEventStream) Heartbeat() time.Duration {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Heartbeat")
+	ret0, _ := ret[0].(time.Duration)
+	return ret0
+}
+
+// Heartbeat indicates an expected call of Heartbeat.
+func (mr *MockEventStreamMockRecorder) Heartbeat() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Heartbeat", reflect.TypeOf((*MockEventStream)(nil).Heartbeat))
+}
+
+// Subscribe mocks base method.
+func (m *MockEventStream) Subscribe(ctx context.Context, lastEventID int64) (*eventstream.Subscription, []model.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Subscribe", ctx, lastEventID)
+	ret0, _ := ret[0].(*eventstream.Subscription)
+	ret1, _ := ret[1].([]model.Event)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Subscribe indicates an expected call of Subscribe.
+func (mr *MockEventStreamMockRecorder) Subscribe(ctx, lastEventID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subscribe", reflect.TypeOf((*MockEventStream)(nil).Subscribe), ctx, lastEventID)
+}
+
+// Unsubscribe mocks base method.
+func (m *MockEventStream) Unsubscribe(sub *eventstream.Subscription) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Unsubscribe", sub)
+}
+
+// Unsubscribe indicates an expected call of Unsubscribe.
+func (mr *MockEventStreamMockRecorder) Unsubscribe(sub interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unsubscribe", reflect.TypeOf((*MockEventStream)(nil).Unsubscribe), sub)
+}
+
+// MockGraphQL is a mock of GraphQL interface.
+type MockGraphQL struct {
+	ctrl     *gomock.Controller
+	recorder *MockGraphQLMockRecorder
+}
+
+// MockGraphQLMockRecorder is the mock recorder for MockGraphQL.
+type MockGraphQLMockRecorder struct {
+	mock *MockGraphQL
+}
+
+// NewMockGraphQL creates a new mock instance.
+func NewMockGraphQL(ctrl *gomock.Controller) *MockGraphQL {
+	mock := &MockGraphQL{ctrl: ctrl}
+	mock.recorder = &MockGraphQLMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockGraphQL) EXPECT() *MockGraphQLMockRecorder {
+	return m.recorder
+}
+
+// Execute mocks base method.
+func (m *MockGraphQL) Execute(ctx context.Context, req graphqlapi.Request) *graphqlapi.Response {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Execute", ctx, req)
+	ret0, _ := ret[0].(*graphqlapi.Response)
+	return ret0
+}
+
+// Execute indicates an expected call of Execute.
+func (mr *MockGraphQLMockRecorder) Execute(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Execute", reflect.TypeOf((*MockGraphQL)(nil).Execute), ctx, req)
+}
+
+// MockHealth is a mock of Health interface.
+type MockHealth struct {
+	ctrl     *gomock.Controller
+	recorder *MockHealthMockRecorder
+}
+
+// MockHealthMockRecorder is the mock recorder for MockHealth.
+type MockHealthMockRecorder struct {
+	mock *MockHealth
+}
+
+// NewMockHealth creates a new mock instance.
+func NewMockHealth(ctrl *gomock.Controller) *MockHealth {
+	mock := &MockHealth{ctrl: ctrl}
+	mock.recorder = &MockHealthMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHealth) EXPECT() *MockHealthMockRecorder {
+	return m.recorder
+}
+
+// Ready mocks base method.
+func (m *MockHealth) Ready(ctx context.Context) health.Report {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Ready", ctx)
+	ret0, _ := ret[0].(health.Report)
+	return ret0
+}
+
+// Ready indicates an expected call of Ready.
+func (mr *MockHealthMockRecorder) Ready(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ready", reflect.TypeOf((*MockHealth)(nil).Ready), ctx)
+}
+
+// MockCatalogService is a mock of CatalogService interface.
+type MockCatalogService struct {
+	ctrl     *gomock.Controller
+	recorder *MockCatalogServiceMockRecorder
+}
+
+// MockCatalogServiceMockRecorder is the mock recorder for MockCatalogService.
+type MockCatalogServiceMockRecorder struct {
+	mock *MockCatalogService
+}
+
+// NewMockCatalogService creates a new mock instance.
+func NewMockCatalogService(ctrl *gomock.Controller) *MockCatalogService {
+	mock := &MockCatalogService{ctrl: ctrl}
+	mock.recorder = &MockCatalogServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCatalogService) EXPECT() *MockCatalogServiceMockRecorder {
+	return m.recorder
+}
+
+// ExportProducts mocks base method.
+func (m *MockCatalogService) ExportProducts(ctx context.Context, w io.Writer, format catalog.Format) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportProducts", ctx, w, format)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExportProducts indicates an expected call of ExportProducts.
+func (mr *MockCatalogServiceMockRecorder) ExportProducts(ctx, w, format interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportProducts", reflect.TypeOf((*MockCatalogService)(nil).ExportProducts), ctx, w, format)
+}
+
+// ImportProducts mocks base method.
+func (m *MockCatalogService) ImportProducts(ctx context.Context, r io.Reader, format catalog.Format, dryRun bool) (model.ImportReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportProducts", ctx, r, format, dryRun)
+	ret0, _ := ret[0].(model.ImportReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportProducts indicates an expected call of ImportProducts.
+func (mr *MockCatalogServiceMockRecorder) ImportProducts(ctx, r, format, dryRun interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportProducts", reflect.TypeOf((*MockCatalogService)(nil).ImportProducts), ctx, r, format, dryRun)
+}
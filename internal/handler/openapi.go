@@ -0,0 +1,290 @@
+package handler
+
+import (
+	"embed"
+	"github.com/gin-gonic/gin"
+	"goapi/internal/graphqlapi"
+	"goapi/internal/lib/buildinfo"
+	"goapi/internal/lib/catalog"
+	"goapi/internal/lib/health"
+	"goapi/internal/lib/openapi"
+	"goapi/internal/model"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	apiTitle   = "goapi"
+	apiVersion = "1.0.0"
+
+	bearerAuth = "bearerAuth"
+
+	jsonContentType       = "application/json"
+	htmlContentType       = "text/html; charset=utf-8"
+	javascriptContentType = "text/javascript; charset=utf-8"
+	multipartContentType  = "multipart/form-data"
+)
+
+// swaggerUIDist - точная версия swagger-ui-dist: версии в npm неизменяемы
+const swaggerUIDist = "https://unpkg.com/swagger-ui-dist@5.17.14/"
+
+// docsPolicy разрешает странице документации только файлы swagger-ui-dist этой версии и свой скрипт
+var docsPolicy = strings.Join([]string{
+	"default-src 'none'",
+	"script-src 'self' " + swaggerUIDist,
+	"style-src 'unsafe-inline' " + swaggerUIDist,
+	"img-src 'self' data:",
+	"connect-src 'self'",
+}, "; ")
+
+//go:embed docs
+var docsFS embed.FS
+
+// endpoint - описание маршрута для спецификации OpenAPI
+type endpoint struct {
+	method   string
+	path     string
+	tag      string
+	summary  string
+	auth     bool
+	request  any
+	response any
+}
+
+// withQuery - запрос с параметрами строки запроса и, возможно, телом
+type withQuery struct {
+	query any
+	body  any
+}
+
+// formData - тело запроса в формате multipart/form-data
+type formData struct {
+	form any
+}
+
+// stream - ответ файлом в одном из форматов вместо JSON
+type stream struct {
+	contentTypes []string
+}
+
+// endpoints - все маршруты, регистрируемые в Init. Тест сверяет их с роутером.
+func endpoints() []endpoint {
+	return []endpoint{
+		{http.MethodGet, "/healthz", "ops", "Liveness probe", false, nil, healthResponse{}},
+		{http.MethodGet, "/readyz", "ops", "Readiness probe", false, nil, health.Report{}},
+		{http.MethodGet, "/version", "ops", "Build information", false, nil, buildinfo.Info{}},
+		{http.MethodGet, "/metrics", "ops", "Prometheus metrics", false, nil, stream{[]string{metricsContentType}}},
+		{http.MethodGet, "/openapi.json", "docs", "OpenAPI specification", false, nil, nil},
+		{http.MethodGet, "/docs", "docs", "Swagger UI", false, nil, nil},
+		{http.MethodGet, "/docs/swagger-init.js", "docs", "Swagger UI script", false, nil, nil},
+
+		{http.MethodPost, "/auth/sign-up", "auth", "Register a new user", false, signUpType{}, idResponse{}},
+		{http.MethodPost, "/auth/sign-in", "auth", "Get an access token", false, signInInput{}, tokenResponse{}},
+
+		{http.MethodPost, "/api/product/add", "product", "Add a product", true, addProductType{}, productIDResponse{}},
+		{http.MethodPost, "/api/product/delete", "product", "Delete a product", true, deleteProductType{}, ""},
+		{http.MethodPost, "/api/product/edit-name", "product", "Rename a product", true, editProduct{}, productIDResponse{}},
+		{http.MethodPost, "/api/product/edit-categoryies", "product", "Replace product categories", true, editProductCategoryiesType{}, productIDResponse{}},
+		{http.MethodPost, "/api/product/get-all", "product", "List all products", true, getAllProductsType{}, productsResponse{}},
+		{http.MethodPost, "/api/product/get", "product", "List products of a category", true, getProductType{}, productsResponse{}},
+
+		{http.MethodPost, "/api/category/add", "category", "Add a category", true, addCategory{}, categoryIDResponse{}},
+		{http.MethodPost, "/api/category/delete", "category", "Delete a category", true, deleteCategory{}, ""},
+		{http.MethodPost, "/api/category/edit", "category", "Rename a category", true, editCategory{}, categoryIDResponse{}},
+		{http.MethodPost, "/api/category/get-all", "category", "List all categories", true, getAllCategoryiesType{}, categoryiesResponse{}},
+
+		{http.MethodGet, "/graphql", "graphql", "Run a read-only GraphQL query", true, withQuery{graphQLQueryParams{}, nil}, graphqlapi.Response{}},
+		{http.MethodPost, "/graphql", "graphql", "Run a GraphQL query or mutation", true, graphQLRequest{}, graphqlapi.Response{}},
+
+		{http.MethodPost, "/api/v1/products:batch", "product", "Apply a batch of product operations", true, batchProductsType{}, batchProductsResponse{}},
+		{http.MethodGet, "/api/v1/products/:id", "product", "Get a product with its version", true, nil, model.Product{}},
+		{http.MethodPatch, "/api/v1/products/:id", "product", "Rename a product", true, patchProductType{}, productVersionResponse{}},
+		{http.MethodPut, "/api/v1/products/:id/categoryies", "product", "Replace product categories", true, putProductCategoryiesType{}, productVersionResponse{}},
+		{http.MethodDelete, "/api/v1/products/:id", "product", "Delete a product", true, nil, nil},
+		{http.MethodGet, "/api/v1/categories", "category", "List categories with product counts", true, nil, categoryiesSummaryResponse{}},
+		{http.MethodGet, "/api/v1/categories/:id", "category", "Get a category with its version", true, nil, model.Category{}},
+		{http.MethodPatch, "/api/v1/categories/:id", "category", "Rename a category", true, patchCategoryType{}, categoryVersionResponse{}},
+		{http.MethodDelete, "/api/v1/categories/:id", "category", "Delete a category", true, nil, nil},
+		{http.MethodGet, "/api/v1/export", "catalog", "Export the catalog", true, withQuery{exportQuery{}, nil}, stream{[]string{catalog.FormatCSV.ContentType(), catalog.FormatJSONL.ContentType()}}},
+		{http.MethodPost, "/api/v1/import", "catalog", "Import products by SKU", true, withQuery{importQuery{}, formData{importForm{}}}, importResponse{}},
+		{http.MethodGet, "/api/v1/events/stream", "event", "Stream catalog changes as Server-Sent Events", true, withQuery{streamQuery{}, nil}, stream{[]string{eventStreamContentType}}},
+		{http.MethodPost, "/api/v1/webhooks", "webhook", "Subscribe to catalog events", true, createWebhookType{}, model.Webhook{}},
+		{http.MethodGet, "/api/v1/webhooks", "webhook", "List webhook subscriptions", true, nil, webhooksResponse{}},
+		{http.MethodGet, "/api/v1/webhooks/:id", "webhook", "Get a webhook subscription", true, nil, model.Webhook{}},
+		{http.MethodPatch, "/api/v1/webhooks/:id", "webhook", "Update a webhook subscription", true, patchWebhookType{}, model.Webhook{}},
+		{http.MethodDelete, "/api/v1/webhooks/:id", "webhook", "Delete a webhook subscription", true, nil, nil},
+		{http.MethodGet, "/api/v1/webhooks/:id/deliveries", "webhook", "List recent deliveries", true, withQuery{webhookDeliveriesQuery{}, nil}, webhookDeliveriesResponse{}},
+		{http.MethodGet, "/api/v1/webhooks/:id/deliveries/:delivery", "webhook", "Get a delivery with its attempt log", true, nil, model.WebhookDelivery{}},
+		{http.MethodPost, "/api/v1/webhooks/:id/deliveries/:delivery/redeliver", "webhook", "Queue a delivery for redelivery", true, nil, statusResponse{}},
+	}
+}
+
+func newOpenAPI(endpoints []endpoint) *openapi.Document {
+	doc := openapi.New(apiTitle, apiVersion)
+
+	doc.Components.SecuritySchemes[bearerAuth] = openapi.SecurityScheme{
+		Type:         "http",
+		Scheme:       "bearer",
+		BearerFormat: "JWT",
+	}
+
+	problemSchema := doc.Schema(problem{})
+
+	for _, e := range endpoints {
+		op := &openapi.Operation{
+			OperationID: operationID(e.method, e.path),
+			Summary:     e.summary,
+			Tags:        []string{e.tag},
+			Responses: map[string]openapi.Response{
+				strconv.Itoa(http.StatusOK): {Description: "OK"},
+				"default": {
+					Description: "Error",
+					Content: map[string]openapi.MediaType{
+						problemContentType: {Schema: problemSchema},
+					},
+				},
+			},
+		}
+
+		if e.auth {
+			op.Security = []map[string][]string{{bearerAuth: {}}}
+		}
+
+		op.Parameters = append(op.Parameters, pathParameters(e.path)...)
+
+		// изменения через PUT/PATCH/DELETE требуют версию ресурса
+		switch e.method {
+		case http.MethodPut, http.MethodPatch, http.MethodDelete:
+			op.Parameters = append(op.Parameters, openapi.Parameter{
+				Name:     headerIfMatch,
+				In:       "header",
+				Required: true,
+				Schema:   &openapi.Schema{Type: "string"},
+			})
+		case http.MethodPost:
+			if e.auth {
+				op.Parameters = append(op.Parameters, openapi.Parameter{
+					Name:   headerIdempotencyKey,
+					In:     "header",
+					Schema: &openapi.Schema{Type: "string"},
+				})
+			}
+		}
+
+		// поток событий продолжается с последнего полученного события
+		if s, ok := e.response.(stream); ok && slices.Contains(s.contentTypes, eventStreamContentType) {
+			op.Parameters = append(op.Parameters, openapi.Parameter{
+				Name:   headerLastEventID,
+				In:     "header",
+				Schema: &openapi.Schema{Type: "string"},
+			})
+		}
+
+		request := e.request
+		if q, ok := request.(withQuery); ok {
+			op.Parameters = append(op.Parameters, doc.QueryParameters(q.query)...)
+			request = q.body
+		}
+
+		if request != nil {
+			contentType := jsonContentType
+			if m, ok := request.(formData); ok {
+				contentType = multipartContentType
+				request = m.form
+			}
+
+			op.RequestBody = &openapi.RequestBody{
+				Required: true,
+				Content: map[string]openapi.MediaType{
+					contentType: {Schema: doc.Schema(request)},
+				},
+			}
+		}
+
+		if e.response != nil {
+			var content map[string]openapi.MediaType
+
+			if s, ok := e.response.(stream); ok {
+				content = make(map[string]openapi.MediaType, len(s.contentTypes))
+				for _, contentType := range s.contentTypes {
+					content[contentType] = openapi.MediaType{Schema: &openapi.Schema{Type: "string"}}
+				}
+			} else {
+				content = map[string]openapi.MediaType{
+					jsonContentType: {Schema: doc.Schema(e.response)},
+				}
+			}
+
+			op.Responses[strconv.Itoa(http.StatusOK)] = openapi.Response{
+				Description: "OK",
+				Content:     content,
+			}
+		}
+
+		if e.method == http.MethodDelete && e.response == nil {
+			delete(op.Responses, strconv.Itoa(http.StatusOK))
+			op.Responses[strconv.Itoa(http.StatusNoContent)] = openapi.Response{Description: "No Content"}
+		}
+
+		doc.AddOperation(e.method, e.path, op)
+	}
+
+	return doc
+}
+
+// pathParameters описывает параметры пути gin (:id) как целочисленные идентификаторы
+func pathParameters(path string) []openapi.Parameter {
+	var params []openapi.Parameter
+	for _, part := range strings.Split(path, "/") {
+		if strings.HasPrefix(part, ":") {
+			params = append(params, openapi.Parameter{
+				Name:     part[1:],
+				In:       "path",
+				Required: true,
+				Schema:   &openapi.Schema{Type: "integer", Format: "int64"},
+			})
+		}
+	}
+
+	return params
+}
+
+// operationID строит идентификатор операции из метода и пути: post /api/product/add -> postApiProductAdd
+func operationID(method, path string) string {
+	var b strings.Builder
+	b.WriteString(strings.ToLower(method))
+
+	for _, part := range strings.FieldsFunc(path, func(r rune) bool {
+		return r == '/' || r == '-' || r == '.' || r == ':' || r == '{' || r == '}'
+	}) {
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+
+	return b.String()
+}
+
+func (h *Handler) openAPI(c *gin.Context) {
+	c.JSON(http.StatusOK, h.spec)
+}
+
+func (h *Handler) docs(c *gin.Context) {
+	serveDoc(c, "docs/swagger.html", htmlContentType)
+}
+
+func (h *Handler) docsScript(c *gin.Context) {
+	serveDoc(c, "docs/swagger-init.js", javascriptContentType)
+}
+
+// serveDoc отдает встроенный файл страницы документации
+func serveDoc(c *gin.Context, name, contentType string) {
+	data, err := docsFS.ReadFile(name)
+	if err != nil {
+		c.AbortWithStatus(http.StatusNotFound)
+		return
+	}
+
+	c.Header("Content-Security-Policy", docsPolicy)
+	c.Data(http.StatusOK, contentType, data)
+}
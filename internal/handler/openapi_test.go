@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"goapi/internal/lib/openapi"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+func TestOpenAPICoversRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	h := NewHandler(Deps{}, logger)
+
+	router := h.Init()
+
+	for _, route := range router.Routes() {
+		assert.Truef(t, h.spec.HasOperation(route.Method, route.Path),
+			"route %s %s is missing from the OpenAPI specification", route.Method, route.Path)
+	}
+
+	assert.Len(t, endpoints(), len(router.Routes()), "specification describes routes that are not registered")
+}
+
+func TestOpenAPIEndpoint(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	h := NewHandler(Deps{}, logger)
+
+	router := h.Init()
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/openapi.json", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var doc openapi.Document
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &doc))
+	assert.Equal(t, openapi.Version, doc.OpenAPI)
+
+	op := doc.Paths["/api/product/add"]["post"]
+	assert.NotNil(t, op)
+	assert.Equal(t, "postApiProductAdd", op.OperationID)
+	assert.Equal(t, "#/components/schemas/addProductType", op.RequestBody.Content[jsonContentType].Schema.Ref)
+	assert.ElementsMatch(t, []string{"name", "categoryies"}, doc.Components.Schemas["addProductType"].Required)
+
+	w = httptest.NewRecorder()
+	req = httptest.NewRequest("GET", "/docs", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), swaggerUIDist+"swagger-ui-bundle.js")
+	assert.Contains(t, w.Header().Get("Content-Security-Policy"), "script-src 'self' "+swaggerUIDist)
+
+	w = httptest.NewRecorder()
+	req = httptest.NewRequest("GET", "/docs/swagger-init.js", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, javascriptContentType, w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Body.String(), "/openapi.json")
+}
@@ -1,83 +1,88 @@
 package handler
 
 import (
-	"fmt"
 	"github.com/gin-gonic/gin"
+	"goapi/internal/lib/logger/logctx"
+	"goapi/internal/lib/logger/sl"
 	"goapi/internal/model"
 	"log/slog"
 	"net/http"
 )
 
 type addProductType struct {
-	Name        string   `json:"name" binding:"required"`
-	Categoryies []string `json:"categoryies" binding:"required"`
+	Name        string   `json:"name" validate:"product_name"`
+	Categoryies []string `json:"categoryies" validate:"category_names"`
 }
 
 func (h *Handler) addProduct(c *gin.Context) {
 	const op = "handler.addProduct"
 
-	log := h.log.With(
+	log := logctx.From(c.Request.Context(), h.log).With(
 		slog.String("op", op),
 	)
 
 	_, err := getUserId(c)
 	if err != nil {
-		newErrorResponse(c, http.StatusInternalServerError, err.Error())
-		log.Error("err get userID", err)
+		newErrorResponse(c, err)
+		log.Error("err get userID", sl.Err(err))
 		return
 	}
 
 	var input addProductType
 
-	if err := c.BindJSON(&input); err != nil {
-		newErrorResponse(c, http.StatusBadRequest, InvalidInputBodyErr)
-		log.Error("error bind json:", fmt.Errorf("%s", InvalidInputBodyErr))
+	if err := bindJSON(c, &input); err != nil {
+		newErrorResponse(c, err)
+		log.Warn("error bind json", sl.Err(err))
 		return
 	}
 
 	id, err := h.product.AddProduct(c.Request.Context(), input.Name, input.Categoryies)
 	if err != nil {
-		newErrorResponse(c, http.StatusInternalServerError, err.Error())
-		log.Error("error added product", err)
+		newErrorResponse(c, err)
+		log.Error("error added product", sl.Err(err))
 		return
 	}
 
 	log.Info("Handler product added")
 
-	c.JSON(http.StatusOK, map[string]interface{}{
-		"productID": id,
-	})
+	c.JSON(http.StatusOK, productIDResponse{ProductID: id})
 }
 
 type deleteProductType struct {
-	ID int64 `json:"id" binding:"required"`
+	ID int64 `json:"id" validate:"entity_id"`
 }
 
 func (h *Handler) deleteProduct(c *gin.Context) {
 	const op = "handler.deleteProduct"
 
-	log := h.log.With(
+	log := logctx.From(c.Request.Context(), h.log).With(
 		slog.String("op", op),
 	)
 
 	_, err := getUserId(c)
 	if err != nil {
-		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		newErrorResponse(c, err)
+		return
+	}
+
+	version, err := ifMatch(c)
+	if err != nil {
+		newErrorResponse(c, err)
 		return
 	}
 
 	var input deleteProductType
 
-	if err := c.BindJSON(&input); err != nil {
-		newErrorResponse(c, http.StatusBadRequest, InvalidInputBodyErr)
-		log.Error("error bind json:", fmt.Errorf("%s", InvalidInputBodyErr))
+	if err := bindJSON(c, &input); err != nil {
+		newErrorResponse(c, err)
+		log.Warn("error bind json", sl.Err(err))
 		return
 	}
 
-	err = h.product.DeleteProduct(c.Request.Context(), input.ID)
+	err = h.product.DeleteProduct(c.Request.Context(), input.ID, version)
 	if err != nil {
-		newErrorResponse(c, http.StatusInternalServerError, err.Error())
-		log.Error("error delete product", err)
+		newErrorResponse(c, err)
+		log.Error("error delete product", sl.Err(err))
 		return
 	}
 
@@ -87,159 +92,333 @@ func (h *Handler) deleteProduct(c *gin.Context) {
 }
 
 type editProduct struct {
-	ID   int64  `json:"id" binding:"required"`
-	Name string `json:"name" binding:"required"`
+	ID   int64  `json:"id" validate:"entity_id"`
+	Name string `json:"name" validate:"product_name"`
 }
 
 func (h *Handler) editProductName(c *gin.Context) {
 	const op = "handler.editProduct"
 
-	log := h.log.With(
+	log := logctx.From(c.Request.Context(), h.log).With(
 		slog.String("op", op),
 	)
 
 	_, err := getUserId(c)
 	if err != nil {
-		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		newErrorResponse(c, err)
+		return
+	}
+
+	version, err := ifMatch(c)
+	if err != nil {
+		newErrorResponse(c, err)
 		return
 	}
 
 	var input editProduct
 
-	if err := c.BindJSON(&input); err != nil {
-		newErrorResponse(c, http.StatusBadRequest, InvalidInputBodyErr)
-		log.Error("error bind json:", fmt.Errorf("%s", InvalidInputBodyErr))
+	if err := bindJSON(c, &input); err != nil {
+		newErrorResponse(c, err)
+		log.Warn("error bind json", sl.Err(err))
 		return
 	}
 
-	productID, err := h.product.EditProductName(c.Request.Context(), input.ID, input.Name)
+	version, err = h.product.EditProductName(c.Request.Context(), input.ID, input.Name, version)
 	if err != nil {
-		newErrorResponse(c, http.StatusInternalServerError, err.Error())
-		log.Error("error edit product", err)
+		newErrorResponse(c, err)
+		log.Error("error edit product", sl.Err(err))
 		return
 	}
 
 	log.Info("Handler product edited")
 
-	c.JSON(http.StatusOK, map[string]interface{}{
-		"productID": productID,
-	})
+	setETag(c, version)
+	c.JSON(http.StatusOK, productIDResponse{ProductID: input.ID})
 }
 
 type editProductCategoryiesType struct {
-	ID          int64            `json:"id" binding:"required"`
-	Categoryies []model.Category `json:"categoryies" binding:"required"`
+	ID          int64            `json:"id" validate:"entity_id"`
+	Categoryies []model.Category `json:"categoryies" validate:"required,min=1,category_list"`
 }
 
 func (h *Handler) editProductCategoryies(c *gin.Context) {
 	const op = "handler.editProduct"
 
-	log := h.log.With(
+	log := logctx.From(c.Request.Context(), h.log).With(
 		slog.String("op", op),
 	)
 
 	_, err := getUserId(c)
 	if err != nil {
-		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		newErrorResponse(c, err)
+		return
+	}
+
+	version, err := ifMatch(c)
+	if err != nil {
+		newErrorResponse(c, err)
 		return
 	}
 
 	var input editProductCategoryiesType
 
-	if err := c.BindJSON(&input); err != nil {
-		newErrorResponse(c, http.StatusBadRequest, InvalidInputBodyErr)
-		log.Error("error bind json:", fmt.Errorf("%s", InvalidInputBodyErr))
+	if err := bindJSON(c, &input); err != nil {
+		newErrorResponse(c, err)
+		log.Warn("error bind json", sl.Err(err))
 		return
 	}
 
-	productID, err := h.product.EditProductCategory(c.Request.Context(), input.ID, input.Categoryies)
+	version, err = h.product.EditProductCategory(c.Request.Context(), input.ID, input.Categoryies, version)
 	if err != nil {
-		newErrorResponse(c, http.StatusInternalServerError, err.Error())
-		log.Error("error edit product", err)
+		newErrorResponse(c, err)
+		log.Error("error edit product", sl.Err(err))
 		return
 	}
 
 	log.Info("Handler product edited")
 
-	c.JSON(http.StatusOK, map[string]interface{}{
-		"productID": productID,
-	})
+	setETag(c, version)
+	c.JSON(http.StatusOK, productIDResponse{ProductID: input.ID})
 }
 
 type getAllProductsType struct {
-	Tag string `json:"tag" binding:"required"`
+	Tag string `json:"tag" validate:"required"`
 }
 
 func (h *Handler) getAllProducts(c *gin.Context) {
 	const op = "handler.getAllProducts"
 
-	log := h.log.With(
+	log := logctx.From(c.Request.Context(), h.log).With(
 		slog.String("op", op),
 	)
 
 	_, err := getUserId(c)
 	if err != nil {
-		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		newErrorResponse(c, err)
 		return
 	}
 
 	var input getAllProductsType
 
-	if err := c.BindJSON(&input); err != nil {
-		newErrorResponse(c, http.StatusBadRequest, InvalidInputBodyErr)
-		log.Error("error bind json:", fmt.Errorf("%s", InvalidInputBodyErr))
+	if err := bindJSON(c, &input); err != nil {
+		newErrorResponse(c, err)
+		log.Warn("error bind json", sl.Err(err))
 		return
 	}
 
 	products, err := h.product.GetAllProducts(c.Request.Context(), input.Tag)
 	if err != nil {
-		newErrorResponse(c, http.StatusInternalServerError, err.Error())
-		log.Error("error getting all products", err)
+		newErrorResponse(c, err)
+		log.Error("error getting all products", sl.Err(err))
 		return
 	}
 
 	log.Info("Handler getting all product")
 
-	c.JSON(http.StatusOK, map[string]interface{}{
-		"products": products,
-	})
+	c.JSON(http.StatusOK, productsResponse{Products: products})
 }
 
 type getProductType struct {
-	Category string `json:"category" binding:"required"`
+	Category string `json:"category" validate:"category_name"`
 }
 
 func (h *Handler) getProducts(c *gin.Context) {
 	const op = "handler.getProduct"
 
-	log := h.log.With(
+	log := logctx.From(c.Request.Context(), h.log).With(
 		slog.String("op", op),
 	)
 
 	_, err := getUserId(c)
 	if err != nil {
-		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		newErrorResponse(c, err)
 		return
 	}
 
 	var input getProductType
 
-	if err := c.BindJSON(&input); err != nil {
-		newErrorResponse(c, http.StatusBadRequest, InvalidInputBodyErr)
-		log.Error("error bind json:", fmt.Errorf("%s", InvalidInputBodyErr))
+	if err := bindJSON(c, &input); err != nil {
+		newErrorResponse(c, err)
+		log.Warn("error bind json", sl.Err(err))
 		return
 	}
 
 	products, err := h.product.GetCategoryProducts(c.Request.Context(), input.Category)
 	if err != nil {
-		newErrorResponse(c, http.StatusInternalServerError, err.Error())
-		log.Error("error getting product", err)
+		newErrorResponse(c, err)
+		log.Error("error getting product", sl.Err(err))
+		return
+	}
+
+	log.Info("Handler getting product")
+
+	c.JSON(http.StatusOK, productsResponse{Products: products})
+}
+
+func (h *Handler) getProduct(c *gin.Context) {
+	const op = "handler.getProduct"
+
+	log := logctx.From(c.Request.Context(), h.log).With(
+		slog.String("op", op),
+	)
+
+	_, err := getUserId(c)
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	id, err := paramID(c)
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	product, err := h.product.GetProduct(c.Request.Context(), id)
+	if err != nil {
+		newErrorResponse(c, err)
+		log.Error("error getting product", sl.Err(err))
 		return
 	}
 
 	log.Info("Handler getting product")
 
-	c.JSON(http.StatusOK, map[string]interface{}{
-		"products": products,
-	})
+	setETag(c, product.Version)
+	c.JSON(http.StatusOK, product)
+}
+
+type patchProductType struct {
+	Name string `json:"name" validate:"product_name"`
+}
+
+func (h *Handler) patchProduct(c *gin.Context) {
+	const op = "handler.patchProduct"
+
+	log := logctx.From(c.Request.Context(), h.log).With(
+		slog.String("op", op),
+	)
+
+	_, err := getUserId(c)
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	id, err := paramID(c)
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	version, err := ifMatch(c)
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	var input patchProductType
+
+	if err := bindJSON(c, &input); err != nil {
+		newErrorResponse(c, err)
+		log.Warn("error bind json", sl.Err(err))
+		return
+	}
+
+	version, err = h.product.EditProductName(c.Request.Context(), id, input.Name, version)
+	if err != nil {
+		newErrorResponse(c, err)
+		log.Error("error edit product", sl.Err(err))
+		return
+	}
+
+	log.Info("Handler product edited")
+
+	setETag(c, version)
+	c.JSON(http.StatusOK, productVersionResponse{ProductID: id, Version: version})
+}
+
+type putProductCategoryiesType struct {
+	Categoryies []model.Category `json:"categoryies" validate:"required,min=1,category_list"`
+}
+
+func (h *Handler) putProductCategoryies(c *gin.Context) {
+	const op = "handler.putProductCategoryies"
+
+	log := logctx.From(c.Request.Context(), h.log).With(
+		slog.String("op", op),
+	)
+
+	_, err := getUserId(c)
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	id, err := paramID(c)
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	version, err := ifMatch(c)
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	var input putProductCategoryiesType
+
+	if err := bindJSON(c, &input); err != nil {
+		newErrorResponse(c, err)
+		log.Warn("error bind json", sl.Err(err))
+		return
+	}
+
+	version, err = h.product.EditProductCategory(c.Request.Context(), id, input.Categoryies, version)
+	if err != nil {
+		newErrorResponse(c, err)
+		log.Error("error edit product", sl.Err(err))
+		return
+	}
+
+	log.Info("Handler product edited")
+
+	setETag(c, version)
+	c.JSON(http.StatusOK, productVersionResponse{ProductID: id, Version: version})
+}
+
+func (h *Handler) removeProduct(c *gin.Context) {
+	const op = "handler.removeProduct"
+
+	log := logctx.From(c.Request.Context(), h.log).With(
+		slog.String("op", op),
+	)
+
+	_, err := getUserId(c)
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	id, err := paramID(c)
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	version, err := ifMatch(c)
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	if err := h.product.DeleteProduct(c.Request.Context(), id, version); err != nil {
+		newErrorResponse(c, err)
+		log.Error("error delete product", sl.Err(err))
+		return
+	}
+
+	log.Info("Handler product deleted")
+
+	c.Status(http.StatusNoContent)
 }
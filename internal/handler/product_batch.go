@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"goapi/internal/lib/apperr"
+	"goapi/internal/lib/logger/logctx"
+	"goapi/internal/lib/logger/sl"
+	"goapi/internal/model"
+	"log/slog"
+	"net/http"
+)
+
+const (
+	batchModeAtomic     = "atomic"
+	batchModeBestEffort = "best_effort"
+)
+
+type batchProductsType struct {
+	Mode       string                   `json:"mode" validate:"omitempty,oneof=atomic best_effort"`
+	Operations []model.ProductOperation `json:"operations" validate:"product_batch"`
+}
+
+type batchProductsResponse struct {
+	Mode      string               `json:"mode"`
+	Committed bool                 `json:"committed"`
+	Results   []batchProductResult `json:"results"`
+}
+
+type batchProductResult struct {
+	Index  int                          `json:"index"`
+	Op     model.ProductOperationType   `json:"op"`
+	Status model.ProductOperationStatus `json:"status"`
+	ID     int64                        `json:"id,omitempty"`
+	Error  *problem                     `json:"error,omitempty"`
+}
+
+func (h *Handler) batchProducts(c *gin.Context) {
+	const op = "handler.batchProducts"
+
+	log := logctx.From(c.Request.Context(), h.log).With(
+		slog.String("op", op),
+	)
+
+	_, err := getUserId(c)
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	var input batchProductsType
+
+	if err := bindJSON(c, &input); err != nil {
+		newErrorResponse(c, err)
+		log.Warn("error bind json", sl.Err(err))
+		return
+	}
+
+	if input.Mode == "" {
+		input.Mode = batchModeAtomic
+	}
+	atomic := input.Mode == batchModeAtomic
+
+	results, err := h.product.BatchProducts(c.Request.Context(), input.Operations, atomic)
+	if err != nil {
+		newErrorResponse(c, err)
+		log.Error("error batch products", sl.Err(err))
+		return
+	}
+
+	response := batchProductsResponse{
+		Mode:      input.Mode,
+		Committed: atomic,
+		Results:   make([]batchProductResult, 0, len(results)),
+	}
+
+	for _, res := range results {
+		item := batchProductResult{
+			Index:  res.Index,
+			Op:     res.Op,
+			Status: res.Status,
+			ID:     res.ID,
+		}
+
+		if res.Err != nil {
+			if apperr.KindOf(res.Err) == apperr.KindInternal {
+				log.Error("batch item failed", slog.Int("index", res.Index), sl.Err(res.Err))
+			}
+
+			p := newProblem(res.Err, "")
+			item.Error = &p
+		}
+
+		if atomic && res.Status != model.ProductOperationOK {
+			response.Committed = false
+		}
+		if !atomic && res.Status == model.ProductOperationOK {
+			response.Committed = true
+		}
+
+		response.Results = append(response.Results, item)
+	}
+
+	log.Info("Handler products batch applied", slog.Bool("committed", response.Committed))
+
+	c.JSON(http.StatusOK, response)
+}
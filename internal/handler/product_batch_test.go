@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	service_mocks "goapi/internal/handler/mock"
+	"goapi/internal/lib/apperr"
+	"goapi/internal/model"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+func TestBatchProducts(t *testing.T) {
+	type mockBehavior func(s *service_mocks.MockProductService)
+
+	notFound := apperr.NotFound("product_not_found", "product not found")
+
+	tests := []struct {
+		name              string
+		path              string
+		body              string
+		mockBehavior      mockBehavior
+		expectedStatus    int
+		expectedCommitted bool
+		expectedCodes     []string
+	}{
+		{
+			name: "Best Effort",
+			path: "/api/v1/products:batch",
+			body: `{"mode": "best_effort", "operations": [{"op": "delete", "id": 1}, {"op": "delete", "id": 2}]}`,
+			mockBehavior: func(s *service_mocks.MockProductService) {
+				s.EXPECT().BatchProducts(gomock.Any(), []model.ProductOperation{
+					{Op: model.ProductOperationDelete, ID: 1},
+					{Op: model.ProductOperationDelete, ID: 2},
+				}, false).Return([]model.ProductOperationResult{
+					{Index: 0, Op: model.ProductOperationDelete, ID: 1, Status: model.ProductOperationOK},
+					{Index: 1, Op: model.ProductOperationDelete, ID: 2, Status: model.ProductOperationFailed, Err: notFound},
+				}, nil)
+			},
+			expectedStatus:    http.StatusOK,
+			expectedCommitted: true,
+			expectedCodes:     []string{"", "product_not_found"},
+		},
+		{
+			name: "Atomic By Default",
+			path: "/api/v1/products:batch",
+			body: `{"operations": [{"op": "delete", "id": 1}, {"op": "delete", "id": 2}]}`,
+			mockBehavior: func(s *service_mocks.MockProductService) {
+				s.EXPECT().BatchProducts(gomock.Any(), gomock.Any(), true).Return([]model.ProductOperationResult{
+					{Index: 0, Op: model.ProductOperationDelete, ID: 1, Status: model.ProductOperationRolledBack},
+					{Index: 1, Op: model.ProductOperationDelete, ID: 2, Status: model.ProductOperationFailed, Err: notFound},
+				}, nil)
+			},
+			expectedStatus:    http.StatusOK,
+			expectedCommitted: false,
+			expectedCodes:     []string{"", "product_not_found"},
+		},
+		{
+			name:           "Unknown Mode",
+			path:           "/api/v1/products:batch",
+			body:           `{"mode": "partial", "operations": [{"op": "delete", "id": 1}]}`,
+			mockBehavior:   func(s *service_mocks.MockProductService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Unknown Method",
+			path:           "/api/v1/products:merge",
+			body:           `{"operations": [{"op": "delete", "id": 1}]}`,
+			mockBehavior:   func(s *service_mocks.MockProductService) {},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockProductService := service_mocks.NewMockProductService(ctrl)
+			test.mockBehavior(mockProductService)
+
+			logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
+			h := NewHandler(Deps{Product: mockProductService}, logger)
+
+			r := gin.New()
+			r.Use(h.errorHandler)
+			r.POST("/api/v1/products:batch", func(c *gin.Context) {
+				c.Set(userCtx, int64(1))
+			}, customMethod("batch", h.batchProducts))
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", test.path, bytes.NewBufferString(test.body))
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, test.expectedStatus, w.Code)
+			if test.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var response batchProductsResponse
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, test.expectedCommitted, response.Committed)
+			assert.Len(t, response.Results, len(test.expectedCodes))
+
+			for i, res := range response.Results {
+				if test.expectedCodes[i] == "" {
+					assert.Nil(t, res.Error)
+					continue
+				}
+				assert.Equal(t, test.expectedCodes[i], res.Error.Code)
+				assert.Equal(t, http.StatusNotFound, res.Error.Status)
+			}
+		})
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 	service_mocks "goapi/internal/handler/mock"
+	"goapi/internal/lib/apperr"
+	"goapi/internal/model"
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
@@ -21,7 +23,7 @@ func TestFailedAddProduct(t *testing.T) {
 	mockProductService := service_mocks.NewMockProductService(ctrl)
 
 	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
-	h := NewHandler(nil, mockProductService, nil, logger)
+	h := NewHandler(Deps{Product: mockProductService}, logger)
 
 	c, _ := gin.CreateTestContext(httptest.NewRecorder())
 
@@ -35,11 +37,9 @@ func TestFailedAddProduct(t *testing.T) {
 	req.Header.Set("Content-Type", "application/json")
 	c.Request = req
 
-	mockProductService.EXPECT().AddProduct(gomock.Any(), input.Name, input.Categoryies).Return(int64(1), nil)
-
 	h.addProduct(c)
 
-	assert.Equal(t, http.StatusInternalServerError, c.Writer.Status())
+	assert.Equal(t, http.StatusUnauthorized, c.Writer.Status())
 }
 
 func TestAddProduct(t *testing.T) {
@@ -49,7 +49,7 @@ func TestAddProduct(t *testing.T) {
 	mockProductService := service_mocks.NewMockProductService(ctrl)
 
 	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
-	h := NewHandler(nil, mockProductService, nil, logger)
+	h := NewHandler(Deps{Product: mockProductService}, logger)
 
 	c, _ := gin.CreateTestContext(httptest.NewRecorder())
 
@@ -83,7 +83,7 @@ func TestDeleteProduct(t *testing.T) {
 	mockProductService := service_mocks.NewMockProductService(ctrl)
 
 	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
-	h := NewHandler(nil, mockProductService, nil, logger)
+	h := NewHandler(Deps{Product: mockProductService}, logger)
 
 	c, _ := gin.CreateTestContext(httptest.NewRecorder())
 
@@ -99,11 +99,178 @@ func TestDeleteProduct(t *testing.T) {
 	reqBody, _ := json.Marshal(input)
 	req, _ := http.NewRequest("DELETE", "/api/product/delete", bytes.NewBuffer(reqBody))
 	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(headerIfMatch, `"2"`)
 	c.Request = req
 
-	mockProductService.EXPECT().DeleteProduct(gomock.Any(), input.ID).Return(nil)
+	mockProductService.EXPECT().DeleteProduct(gomock.Any(), input.ID, int64(2)).Return(nil)
 
 	h.deleteProduct(c)
 
 	assert.Equal(t, http.StatusOK, c.Writer.Status())
 }
+
+func TestAddProductValidation(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		expectedFields []string
+	}{
+		{
+			name:           "Wrong Type",
+			body:           `{"name": 1, "categoryies": ["Category1"]}`,
+			expectedFields: []string{"/name"},
+		},
+		{
+			name:           "Duplicate Categoryies",
+			body:           `{"name": "Test Product", "categoryies": ["Category1", "Category1"]}`,
+			expectedFields: []string{"/categoryies"},
+		},
+		{
+			name:           "Forbidden Characters",
+			body:           `{"name": "<b>Test</b>", "categoryies": ["Category1", "{x}"]}`,
+			expectedFields: []string{"/name", "/categoryies/1"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockProductService := service_mocks.NewMockProductService(ctrl)
+
+			logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
+			h := NewHandler(Deps{Product: mockProductService}, logger)
+
+			r := gin.New()
+			r.Use(h.errorHandler)
+			r.POST("/api/product/add", func(c *gin.Context) {
+				c.Set(userCtx, int64(1))
+			}, h.addProduct)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/api/product/add", bytes.NewBufferString(test.body))
+			r.ServeHTTP(w, req)
+
+			var p problem
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &p))
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+
+			fields := make([]string, 0, len(p.Errors))
+			for _, fe := range p.Errors {
+				fields = append(fields, fe.Field)
+			}
+			assert.Equal(t, test.expectedFields, fields)
+		})
+	}
+}
+
+func TestPatchProduct(t *testing.T) {
+	type mockBehavior func(s *service_mocks.MockProductService)
+
+	tests := []struct {
+		name           string
+		path           string
+		ifMatch        string
+		mockBehavior   mockBehavior
+		expectedStatus int
+		expectedETag   string
+		expectedCode   string
+	}{
+		{
+			name:    "Ok",
+			path:    "/api/v1/products/1",
+			ifMatch: `"2"`,
+			mockBehavior: func(s *service_mocks.MockProductService) {
+				s.EXPECT().EditProductName(gomock.Any(), int64(1), "Test Product", int64(2)).Return(int64(3), nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedETag:   `"3"`,
+		},
+		{
+			name:           "Missing If-Match",
+			path:           "/api/v1/products/1",
+			mockBehavior:   func(s *service_mocks.MockProductService) {},
+			expectedStatus: http.StatusPreconditionRequired,
+			expectedCode:   "if_match_required",
+		},
+		{
+			name:    "Version Mismatch",
+			path:    "/api/v1/products/1",
+			ifMatch: `"1"`,
+			mockBehavior: func(s *service_mocks.MockProductService) {
+				s.EXPECT().EditProductName(gomock.Any(), int64(1), "Test Product", int64(1)).Return(int64(0),
+					apperr.PreconditionFailed("product_version_mismatch", "product was modified by another request"))
+			},
+			expectedStatus: http.StatusPreconditionFailed,
+			expectedCode:   "product_version_mismatch",
+		},
+		{
+			name:           "Invalid ID",
+			path:           "/api/v1/products/abc",
+			ifMatch:        `"1"`,
+			mockBehavior:   func(s *service_mocks.MockProductService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   "invalid_id",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockProductService := service_mocks.NewMockProductService(ctrl)
+			test.mockBehavior(mockProductService)
+
+			logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
+			h := NewHandler(Deps{Product: mockProductService}, logger)
+
+			r := gin.New()
+			r.Use(h.errorHandler)
+			r.PATCH("/api/v1/products/:id", func(c *gin.Context) {
+				c.Set(userCtx, int64(1))
+			}, h.patchProduct)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("PATCH", test.path, bytes.NewBufferString(`{"name": "Test Product"}`))
+			if test.ifMatch != "" {
+				req.Header.Set(headerIfMatch, test.ifMatch)
+			}
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, test.expectedStatus, w.Code)
+			assert.Equal(t, test.expectedETag, w.Header().Get(headerETag))
+
+			if test.expectedCode != "" {
+				var p problem
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &p))
+				assert.Equal(t, test.expectedCode, p.Code)
+			}
+		})
+	}
+}
+
+func TestGetProduct(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockProductService := service_mocks.NewMockProductService(ctrl)
+	mockProductService.EXPECT().GetProduct(gomock.Any(), int64(1)).Return(model.Product{ID: 1, Name: "Test", Version: 4}, nil)
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	h := NewHandler(Deps{Product: mockProductService}, logger)
+
+	r := gin.New()
+	r.Use(h.errorHandler)
+	r.GET("/api/v1/products/:id", func(c *gin.Context) {
+		c.Set(userCtx, int64(1))
+	}, h.getProduct)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/api/v1/products/1", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `"4"`, w.Header().Get(headerETag))
+}
@@ -1,15 +1,153 @@
 package handler
 
-import "github.com/gin-gonic/gin"
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"goapi/internal/lib/apperr"
+	"goapi/internal/lib/logger/logctx"
+	"goapi/internal/lib/logger/sl"
+	"goapi/internal/model"
+	"log/slog"
+	"net/http"
+)
 
-type errorResponse struct {
-	Message string `json:"message"`
-}
+const (
+	problemContentType  = "application/problem+json"
+	problemTypeDefault  = "about:blank"
+	internalErrorCode   = "internal_error"
+	internalErrorDetail = "internal server error"
+)
 
 type statusResponse struct {
 	Status string `json:"status"`
 }
 
-func newErrorResponse(c *gin.Context, statusCode int, message string) {
-	c.AbortWithStatusJSON(statusCode, errorResponse{message})
+type idResponse struct {
+	ID int64 `json:"id"`
+}
+
+type tokenResponse struct {
+	Token string `json:"token"`
+}
+
+type productIDResponse struct {
+	ProductID int64 `json:"productID"`
+}
+
+type categoryIDResponse struct {
+	CategoryID int64 `json:"categoryID"`
+}
+
+type productVersionResponse struct {
+	ProductID int64 `json:"productID"`
+	Version   int64 `json:"version"`
+}
+
+type categoryVersionResponse struct {
+	CategoryID int64 `json:"categoryID"`
+	Version    int64 `json:"version"`
+}
+
+type productsResponse struct {
+	Products []model.Product `json:"products"`
+}
+
+type categoryiesResponse struct {
+	Categoryies []model.Category `json:"categoryies"`
+}
+
+type categoryiesSummaryResponse struct {
+	Categoryies []model.CategorySummary `json:"categoryies"`
+}
+
+// problem - тело ответа с ошибкой в формате RFC 7807
+type problem struct {
+	Type     string              `json:"type"`
+	Title    string              `json:"title"`
+	Status   int                 `json:"status"`
+	Detail   string              `json:"detail,omitempty"`
+	Instance string              `json:"instance,omitempty"`
+	Code     string              `json:"code"`
+	Errors   []apperr.FieldError `json:"errors,omitempty"`
+}
+
+// newErrorResponse прерывает обработку запроса, ответ формирует errorHandler
+func newErrorResponse(c *gin.Context, err error) {
+	_ = c.Error(err)
+	c.Status(problemStatus(err))
+	c.Abort()
+}
+
+// errorHandler переводит ошибки обработчиков в ответ application/problem+json
+func (h *Handler) errorHandler(c *gin.Context) {
+	c.Next()
+
+	h.renderError(c)
+}
+
+// renderError пишет ответ с последней ошибкой запроса, если обработчик еще ничего не записал
+func (h *Handler) renderError(c *gin.Context) {
+	if len(c.Errors) == 0 || c.Writer.Written() {
+		return
+	}
+
+	err := c.Errors.Last().Err
+	p := newProblem(err, c.Request.URL.Path)
+
+	if p.Status == http.StatusInternalServerError {
+		logctx.From(c.Request.Context(), h.log).Error("internal error",
+			slog.String("op", "handler.errorHandler"),
+			slog.String("path", c.Request.URL.Path),
+			sl.Err(err),
+		)
+	}
+
+	c.Header("Content-Type", problemContentType)
+	c.JSON(p.Status, p)
+}
+
+func newProblem(err error, instance string) problem {
+	status := problemStatus(err)
+
+	p := problem{
+		Type:     problemTypeDefault,
+		Title:    http.StatusText(status),
+		Status:   status,
+		Instance: instance,
+		Code:     internalErrorCode,
+		Detail:   internalErrorDetail,
+	}
+
+	var appErr *apperr.Error
+	if errors.As(err, &appErr) {
+		p.Code = appErr.Code
+		if appErr.Kind != apperr.KindInternal {
+			p.Detail = appErr.Message
+			p.Errors = appErr.Fields
+		}
+	}
+
+	return p
+}
+
+// problemStatus - единое соответствие вида доменной ошибки и HTTP статуса
+func problemStatus(err error) int {
+	switch apperr.KindOf(err) {
+	case apperr.KindNotFound:
+		return http.StatusNotFound
+	case apperr.KindConflict:
+		return http.StatusConflict
+	case apperr.KindValidation:
+		return http.StatusBadRequest
+	case apperr.KindUnauthorized:
+		return http.StatusUnauthorized
+	case apperr.KindForbidden:
+		return http.StatusForbidden
+	case apperr.KindPreconditionFailed:
+		return http.StatusPreconditionFailed
+	case apperr.KindPreconditionRequired:
+		return http.StatusPreconditionRequired
+	default:
+		return http.StatusInternalServerError
+	}
 }
@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"goapi/internal/lib/apperr"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+func TestErrorHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		err            error
+		expectedStatus int
+		expectedCode   string
+		expectedDetail string
+	}{
+		{
+			name:           "Not Found",
+			err:            fmt.Errorf("product.DeleteProduct %w", apperr.NotFound("product_not_found", "product not found")),
+			expectedStatus: http.StatusNotFound,
+			expectedCode:   "product_not_found",
+			expectedDetail: "product not found",
+		},
+		{
+			name:           "Conflict",
+			err:            apperr.Conflict("category_already_exists", "category already exist"),
+			expectedStatus: http.StatusConflict,
+			expectedCode:   "category_already_exists",
+			expectedDetail: "category already exist",
+		},
+		{
+			name:           "Validation",
+			err:            apperr.InvalidField("product_name_empty", "name", "product name is empty"),
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   "product_name_empty",
+			expectedDetail: "product name is empty",
+		},
+		{
+			name:           "Unauthorized",
+			err:            ErrInvalidToken,
+			expectedStatus: http.StatusUnauthorized,
+			expectedCode:   "invalid_token",
+			expectedDetail: "invalid token",
+		},
+		{
+			name:           "Forbidden",
+			err:            apperr.Forbidden("admin_required", "admin role is required"),
+			expectedStatus: http.StatusForbidden,
+			expectedCode:   "admin_required",
+			expectedDetail: "admin role is required",
+		},
+		{
+			name:           "Precondition Required",
+			err:            ErrPreconditionRequired,
+			expectedStatus: http.StatusPreconditionRequired,
+			expectedCode:   "if_match_required",
+			expectedDetail: "If-Match header is required",
+		},
+		{
+			name:           "Internal",
+			err:            errors.New("pq: connection refused"),
+			expectedStatus: http.StatusInternalServerError,
+			expectedCode:   internalErrorCode,
+			expectedDetail: internalErrorDetail,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
+			h := NewHandler(Deps{}, logger)
+
+			r := gin.New()
+			r.Use(h.errorHandler)
+			r.GET("/test", func(c *gin.Context) {
+				newErrorResponse(c, test.err)
+			})
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/test", nil)
+			r.ServeHTTP(w, req)
+
+			var p problem
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &p))
+
+			assert.Equal(t, test.expectedStatus, w.Code)
+			assert.Equal(t, problemContentType, w.Header().Get("Content-Type"))
+			assert.Equal(t, test.expectedStatus, p.Status)
+			assert.Equal(t, test.expectedCode, p.Code)
+			assert.Equal(t, test.expectedDetail, p.Detail)
+			assert.Equal(t, "/test", p.Instance)
+		})
+	}
+}
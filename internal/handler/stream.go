@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+	"goapi/internal/lib/apperr"
+	"goapi/internal/lib/eventstream"
+	"goapi/internal/lib/logger/logctx"
+	"goapi/internal/lib/logger/sl"
+	"goapi/internal/model"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	headerLastEventID      = "Last-Event-ID"
+	eventStreamContentType = "text/event-stream"
+
+	// streamRetry - пауза перед переподключением клиента, мс
+	streamRetry = 3000
+)
+
+var (
+	ErrInvalidLastEventID = apperr.InvalidField("invalid_last_event_id", headerLastEventID, "Last-Event-ID must be a non-negative integer")
+)
+
+type streamQuery struct {
+	Category string `json:"category" form:"category" validate:"omitempty,category_name"`
+}
+
+// streamEvents отправляет изменения каталога в формате Server-Sent Events.
+// Клиент с заголовком Last-Event-ID получает пропущенные события из outbox.
+func (h *Handler) streamEvents(c *gin.Context) {
+	const op = "handler.streamEvents"
+
+	log := logctx.From(c.Request.Context(), h.log).With(
+		slog.String("op", op),
+	)
+
+	_, err := getUserId(c)
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	var query streamQuery
+
+	if err := bindQuery(c, &query); err != nil {
+		newErrorResponse(c, err)
+		log.Warn("error bind query", sl.Err(err))
+		return
+	}
+
+	lastEventID, err := parseLastEventID(c)
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	sub, replay, err := h.stream.Subscribe(c.Request.Context(), lastEventID)
+	if err != nil {
+		newErrorResponse(c, err)
+		log.Error("error subscribe to event stream", sl.Err(err))
+		return
+	}
+	defer h.stream.Unsubscribe(sub)
+
+	// поток живет дольше WriteTimeout сервера
+	if err := http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{}); err != nil {
+		log.Warn("error reset write deadline", sl.Err(err))
+	}
+
+	c.Header("Content-Type", eventStreamContentType)
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	c.Render(-1, sse.Event{Event: "ready", Retry: streamRetry, Data: lastEventID})
+
+	for _, event := range replay {
+		writeStreamEvent(c, event, query.Category)
+	}
+	c.Writer.Flush()
+
+	log.Info("Handler event stream opened", slog.Int("replayed", len(replay)))
+
+	heartbeat := time.NewTicker(h.stream.Heartbeat())
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			log.Info("Handler event stream closed")
+			return
+		case event, ok := <-sub.Events():
+			if !ok {
+				// подписчик отстал или сервер останавливается, клиент переподключится с Last-Event-ID
+				log.Warn("event stream subscription closed")
+				return
+			}
+			writeStreamEvent(c, event, query.Category)
+		case <-heartbeat.C:
+			if _, err := c.Writer.WriteString(": heartbeat\n\n"); err != nil {
+				return
+			}
+		}
+
+		c.Writer.Flush()
+	}
+}
+
+// writeStreamEvent пишет событие в поток, если оно относится к категории
+func writeStreamEvent(c *gin.Context, event model.Event, category string) {
+	if !eventstream.InCategory(event, category) {
+		return
+	}
+
+	c.Render(-1, sse.Event{
+		Id:    strconv.FormatInt(event.ID, 10),
+		Event: string(event.Type),
+		Data:  event,
+	})
+}
+
+func parseLastEventID(c *gin.Context) (int64, error) {
+	header := c.GetHeader(headerLastEventID)
+	if header == "" {
+		return 0, nil
+	}
+
+	id, err := strconv.ParseInt(header, 10, 64)
+	if err != nil || id < 0 {
+		return 0, ErrInvalidLastEventID
+	}
+
+	return id, nil
+}
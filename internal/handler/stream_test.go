@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"goapi/internal/lib/eventstream"
+	"goapi/internal/model"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+)
+
+// outboxEvents - outbox в памяти для потока событий
+type outboxEvents []model.Event
+
+func (o outboxEvents) LastEventID(ctx context.Context) (int64, error) {
+	return o[len(o)-1].ID, nil
+}
+
+func (o outboxEvents) EventsAfter(ctx context.Context, afterID int64, limit int) ([]model.Event, error) {
+	return o.LatestEvents(ctx, afterID, o[len(o)-1].ID, limit)
+}
+
+func (o outboxEvents) LatestEvents(ctx context.Context, afterID, untilID int64, limit int) ([]model.Event, error) {
+	var res []model.Event
+	for _, e := range o {
+		if e.ID > afterID && e.ID <= untilID {
+			res = append(res, e)
+		}
+	}
+
+	return res, nil
+}
+
+func TestStreamEvents(t *testing.T) {
+	var outbox outboxEvents
+
+	save := func(id int64, e model.DomainEvent) {
+		payload, err := json.Marshal(e)
+		assert.NoError(t, err)
+		outbox = append(outbox, model.Event{ID: id, Type: e.EventType(), Payload: payload})
+	}
+
+	save(1, model.CategoryCreated{CategoryID: 1, Name: "Category1"})
+	save(2, model.ProductCreated{ProductID: 1, Name: "Product1", Categoryies: []model.Category{{Name: "Category2"}}})
+	save(3, model.ProductCreated{ProductID: 2, Name: "Product2", Categoryies: []model.Category{{Name: "Category1"}}})
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	hub := eventstream.NewHub(outbox, 10, time.Second, 10*time.Millisecond, logger)
+
+	tests := []struct {
+		name           string
+		path           string
+		lastEventID    string
+		expectedStatus int
+		expected       []string
+		unexpected     []string
+	}{
+		{
+			name:           "Replay Filtered",
+			path:           "/api/v1/events/stream?category=Category1",
+			lastEventID:    "1",
+			expectedStatus: http.StatusOK,
+			expected:       []string{"event:ready\nretry:3000\n", "id:3\nevent:product.created\n", ": heartbeat\n\n"},
+			unexpected:     []string{"id:1\n", "id:2\n"},
+		},
+		{
+			name:           "No Replay",
+			path:           "/api/v1/events/stream",
+			expectedStatus: http.StatusOK,
+			expected:       []string{"event:ready\n"},
+			unexpected:     []string{"id:1\n", "id:2\n", "id:3\n"},
+		},
+		{
+			name:           "Invalid Last-Event-ID",
+			path:           "/api/v1/events/stream",
+			lastEventID:    "abc",
+			expectedStatus: http.StatusBadRequest,
+			expected:       []string{"invalid_last_event_id"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			h := NewHandler(Deps{Stream: hub}, logger)
+
+			r := gin.New()
+			r.Use(h.errorHandler)
+			r.GET("/api/v1/events/stream", func(c *gin.Context) {
+				c.Set(userCtx, int64(1))
+			}, h.streamEvents)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", test.path, nil).WithContext(ctx)
+			if test.lastEventID != "" {
+				req.Header.Set(headerLastEventID, test.lastEventID)
+			}
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, test.expectedStatus, w.Code)
+			for _, s := range test.expected {
+				assert.Contains(t, w.Body.String(), s)
+			}
+			for _, s := range test.unexpected {
+				assert.NotContains(t, w.Body.String(), s)
+			}
+		})
+	}
+}
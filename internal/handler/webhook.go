@@ -0,0 +1,320 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"goapi/internal/lib/apperr"
+	"goapi/internal/lib/logger/logctx"
+	"goapi/internal/lib/logger/sl"
+	"goapi/internal/model"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+const (
+	webhookDeliveryQueued = "queued"
+)
+
+var (
+	ErrInvalidDeliveryID = apperr.InvalidField("invalid_delivery_id", "/delivery", "delivery must be a positive integer")
+)
+
+type webhooksResponse struct {
+	Webhooks []model.Webhook `json:"webhooks"`
+}
+
+type webhookDeliveriesResponse struct {
+	Deliveries []model.WebhookDelivery `json:"deliveries"`
+}
+
+type createWebhookType struct {
+	URL        string   `json:"url" validate:"webhook_url"`
+	EventTypes []string `json:"eventTypes" validate:"event_types"`
+	Secret     string   `json:"secret" validate:"webhook_secret"`
+}
+
+func (h *Handler) createWebhook(c *gin.Context) {
+	const op = "handler.createWebhook"
+
+	log := logctx.From(c.Request.Context(), h.log).With(
+		slog.String("op", op),
+	)
+
+	userID, err := getUserId(c)
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	var input createWebhookType
+
+	if err := bindJSON(c, &input); err != nil {
+		newErrorResponse(c, err)
+		log.Warn("error bind json", sl.Err(err))
+		return
+	}
+
+	webhook, err := h.webhook.CreateWebhook(c.Request.Context(), userID, input.URL, input.EventTypes, input.Secret)
+	if err != nil {
+		newErrorResponse(c, err)
+		log.Error("error create webhook", sl.Err(err))
+		return
+	}
+
+	log.Info("Handler webhook created")
+
+	setETag(c, webhook.Version)
+	c.JSON(http.StatusOK, webhook)
+}
+
+func (h *Handler) listWebhooks(c *gin.Context) {
+	const op = "handler.listWebhooks"
+
+	log := logctx.From(c.Request.Context(), h.log).With(
+		slog.String("op", op),
+	)
+
+	userID, err := getUserId(c)
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	webhooks, err := h.webhook.ListWebhooks(c.Request.Context(), userID)
+	if err != nil {
+		newErrorResponse(c, err)
+		log.Error("error list webhooks", sl.Err(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, webhooksResponse{Webhooks: webhooks})
+}
+
+func (h *Handler) getWebhook(c *gin.Context) {
+	const op = "handler.getWebhook"
+
+	log := logctx.From(c.Request.Context(), h.log).With(
+		slog.String("op", op),
+	)
+
+	userID, err := getUserId(c)
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	id, err := paramID(c)
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	webhook, err := h.webhook.GetWebhook(c.Request.Context(), userID, id)
+	if err != nil {
+		newErrorResponse(c, err)
+		log.Error("error get webhook", sl.Err(err))
+		return
+	}
+
+	setETag(c, webhook.Version)
+	c.JSON(http.StatusOK, webhook)
+}
+
+type patchWebhookType struct {
+	URL        *string  `json:"url" validate:"omitempty,webhook_url"`
+	EventTypes []string `json:"eventTypes" validate:"omitempty,event_types"`
+	Secret     *string  `json:"secret" validate:"omitempty,webhook_secret"`
+	Active     *bool    `json:"active"`
+}
+
+func (h *Handler) patchWebhook(c *gin.Context) {
+	const op = "handler.patchWebhook"
+
+	log := logctx.From(c.Request.Context(), h.log).With(
+		slog.String("op", op),
+	)
+
+	userID, err := getUserId(c)
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	id, err := paramID(c)
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	version, err := ifMatch(c)
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	var input patchWebhookType
+
+	if err := bindJSON(c, &input); err != nil {
+		newErrorResponse(c, err)
+		log.Warn("error bind json", sl.Err(err))
+		return
+	}
+
+	webhook, err := h.webhook.UpdateWebhook(c.Request.Context(), userID, id, model.WebhookUpdate{
+		URL:        input.URL,
+		EventTypes: input.EventTypes,
+		Secret:     input.Secret,
+		Active:     input.Active,
+	}, version)
+	if err != nil {
+		newErrorResponse(c, err)
+		log.Error("error update webhook", sl.Err(err))
+		return
+	}
+
+	log.Info("Handler webhook updated")
+
+	setETag(c, webhook.Version)
+	c.JSON(http.StatusOK, webhook)
+}
+
+func (h *Handler) removeWebhook(c *gin.Context) {
+	const op = "handler.removeWebhook"
+
+	log := logctx.From(c.Request.Context(), h.log).With(
+		slog.String("op", op),
+	)
+
+	userID, err := getUserId(c)
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	id, err := paramID(c)
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	version, err := ifMatch(c)
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	if err := h.webhook.DeleteWebhook(c.Request.Context(), userID, id, version); err != nil {
+		newErrorResponse(c, err)
+		log.Error("error delete webhook", sl.Err(err))
+		return
+	}
+
+	log.Info("Handler webhook deleted")
+
+	c.Status(http.StatusNoContent)
+}
+
+type webhookDeliveriesQuery struct {
+	Limit int `json:"limit" form:"limit" validate:"omitempty,min=1,max=200"`
+}
+
+func (h *Handler) listWebhookDeliveries(c *gin.Context) {
+	const op = "handler.listWebhookDeliveries"
+
+	log := logctx.From(c.Request.Context(), h.log).With(
+		slog.String("op", op),
+	)
+
+	userID, err := getUserId(c)
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	id, err := paramID(c)
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	var query webhookDeliveriesQuery
+
+	if err := bindQuery(c, &query); err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	deliveries, err := h.webhook.ListWebhookDeliveries(c.Request.Context(), userID, id, query.Limit)
+	if err != nil {
+		newErrorResponse(c, err)
+		log.Error("error list webhook deliveries", sl.Err(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, webhookDeliveriesResponse{Deliveries: deliveries})
+}
+
+func (h *Handler) getWebhookDelivery(c *gin.Context) {
+	const op = "handler.getWebhookDelivery"
+
+	log := logctx.From(c.Request.Context(), h.log).With(
+		slog.String("op", op),
+	)
+
+	userID, id, deliveryID, err := webhookDeliveryParams(c)
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	delivery, err := h.webhook.GetWebhookDelivery(c.Request.Context(), userID, id, deliveryID)
+	if err != nil {
+		newErrorResponse(c, err)
+		log.Error("error get webhook delivery", sl.Err(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, delivery)
+}
+
+func (h *Handler) redeliverWebhook(c *gin.Context) {
+	const op = "handler.redeliverWebhook"
+
+	log := logctx.From(c.Request.Context(), h.log).With(
+		slog.String("op", op),
+	)
+
+	userID, id, deliveryID, err := webhookDeliveryParams(c)
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	if err := h.webhook.RedeliverWebhook(c.Request.Context(), userID, id, deliveryID); err != nil {
+		newErrorResponse(c, err)
+		log.Error("error redeliver webhook", sl.Err(err))
+		return
+	}
+
+	log.Info("Handler webhook delivery queued")
+
+	c.JSON(http.StatusOK, statusResponse{Status: webhookDeliveryQueued})
+}
+
+// webhookDeliveryParams разбирает пользователя, подписку и доставку из запроса
+func webhookDeliveryParams(c *gin.Context) (userID, id, deliveryID int64, err error) {
+	if userID, err = getUserId(c); err != nil {
+		return 0, 0, 0, err
+	}
+
+	if id, err = paramID(c); err != nil {
+		return 0, 0, 0, err
+	}
+
+	deliveryID, err = strconv.ParseInt(c.Param("delivery"), 10, 64)
+	if err != nil || deliveryID <= 0 {
+		return 0, 0, 0, ErrInvalidDeliveryID
+	}
+
+	return userID, id, deliveryID, nil
+}
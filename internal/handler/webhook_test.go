@@ -0,0 +1,155 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	service_mocks "goapi/internal/handler/mock"
+	"goapi/internal/lib/apperr"
+	"goapi/internal/model"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+func TestCreateWebhook(t *testing.T) {
+	type mockBehavior func(s *service_mocks.MockWebhookService)
+
+	tests := []struct {
+		name           string
+		body           string
+		mockBehavior   mockBehavior
+		expectedStatus int
+		expectedCode   string
+	}{
+		{
+			name: "Ok",
+			body: `{"url": "https://example.com/hook", "eventTypes": ["product.created"]}`,
+			mockBehavior: func(s *service_mocks.MockWebhookService) {
+				s.EXPECT().CreateWebhook(gomock.Any(), int64(1), "https://example.com/hook", []string{"product.created"}, "").
+					Return(model.Webhook{ID: 1, Secret: "whsec_1", Version: 1}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Invalid URL",
+			body:           `{"url": "not a url", "eventTypes": ["product.created"]}`,
+			mockBehavior:   func(s *service_mocks.MockWebhookService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Unknown Event",
+			body: `{"url": "https://example.com/hook", "eventTypes": ["product.exploded"]}`,
+			mockBehavior: func(s *service_mocks.MockWebhookService) {
+				s.EXPECT().CreateWebhook(gomock.Any(), int64(1), "https://example.com/hook", []string{"product.exploded"}, "").
+					Return(model.Webhook{}, apperr.InvalidField("unknown_event_type", "/eventTypes", "unknown event type"))
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   "unknown_event_type",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockWebhookService := service_mocks.NewMockWebhookService(ctrl)
+			test.mockBehavior(mockWebhookService)
+
+			logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
+			h := NewHandler(Deps{Webhook: mockWebhookService}, logger)
+
+			r := gin.New()
+			r.Use(h.errorHandler)
+			r.POST("/api/v1/webhooks", func(c *gin.Context) {
+				c.Set(userCtx, int64(1))
+			}, h.createWebhook)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/api/v1/webhooks", bytes.NewBufferString(test.body))
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, test.expectedStatus, w.Code)
+
+			if test.expectedCode != "" {
+				var p problem
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &p))
+				assert.Equal(t, test.expectedCode, p.Code)
+			}
+		})
+	}
+}
+
+func TestRedeliverWebhook(t *testing.T) {
+	type mockBehavior func(s *service_mocks.MockWebhookService)
+
+	tests := []struct {
+		name           string
+		path           string
+		mockBehavior   mockBehavior
+		expectedStatus int
+		expectedCode   string
+	}{
+		{
+			name: "Ok",
+			path: "/api/v1/webhooks/1/deliveries/2/redeliver",
+			mockBehavior: func(s *service_mocks.MockWebhookService) {
+				s.EXPECT().RedeliverWebhook(gomock.Any(), int64(1), int64(1), int64(2)).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "Not Found",
+			path: "/api/v1/webhooks/1/deliveries/2/redeliver",
+			mockBehavior: func(s *service_mocks.MockWebhookService) {
+				s.EXPECT().RedeliverWebhook(gomock.Any(), int64(1), int64(1), int64(2)).
+					Return(apperr.NotFound("webhook_delivery_not_found", "webhook delivery not found"))
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedCode:   "webhook_delivery_not_found",
+		},
+		{
+			name:           "Invalid Delivery",
+			path:           "/api/v1/webhooks/1/deliveries/abc/redeliver",
+			mockBehavior:   func(s *service_mocks.MockWebhookService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   "invalid_delivery_id",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockWebhookService := service_mocks.NewMockWebhookService(ctrl)
+			test.mockBehavior(mockWebhookService)
+
+			logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
+			h := NewHandler(Deps{Webhook: mockWebhookService}, logger)
+
+			r := gin.New()
+			r.Use(h.errorHandler)
+			r.POST("/api/v1/webhooks/:id/deliveries/:delivery/redeliver", func(c *gin.Context) {
+				c.Set(userCtx, int64(1))
+			}, h.redeliverWebhook)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", test.path, nil)
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, test.expectedStatus, w.Code)
+
+			if test.expectedCode != "" {
+				var p problem
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &p))
+				assert.Equal(t, test.expectedCode, p.Code)
+			}
+		})
+	}
+}
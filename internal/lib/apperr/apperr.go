@@ -0,0 +1,119 @@
+package apperr
+
+import "errors"
+
+// Kind - вид доменной ошибки, по которому транспорт выбирает код ответа
+type Kind string
+
+const (
+	KindInternal     Kind = "internal"
+	KindNotFound     Kind = "not_found"
+	KindConflict     Kind = "conflict"
+	KindValidation   Kind = "validation"
+	KindUnauthorized Kind = "unauthorized"
+	KindForbidden    Kind = "forbidden"
+
+	KindPreconditionFailed   Kind = "precondition_failed"
+	KindPreconditionRequired Kind = "precondition_required"
+)
+
+// FieldError - ошибка конкретного поля входных данных
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code,omitempty"`
+	Message string `json:"message"`
+}
+
+// Error - доменная ошибка со стабильным машинным кодом
+type Error struct {
+	Kind    Kind
+	Code    string
+	Message string
+	Fields  []FieldError
+}
+
+func New(kind Kind, code, message string) *Error {
+	return &Error{
+		Kind:    kind,
+		Code:    code,
+		Message: message,
+	}
+}
+
+func NotFound(code, message string) *Error {
+	return New(KindNotFound, code, message)
+}
+
+func Conflict(code, message string) *Error {
+	return New(KindConflict, code, message)
+}
+
+func Validation(code, message string) *Error {
+	return New(KindValidation, code, message)
+}
+
+func Unauthorized(code, message string) *Error {
+	return New(KindUnauthorized, code, message)
+}
+
+func Forbidden(code, message string) *Error {
+	return New(KindForbidden, code, message)
+}
+
+func PreconditionFailed(code, message string) *Error {
+	return New(KindPreconditionFailed, code, message)
+}
+
+func PreconditionRequired(code, message string) *Error {
+	return New(KindPreconditionRequired, code, message)
+}
+
+func Internal(code, message string) *Error {
+	return New(KindInternal, code, message)
+}
+
+// InvalidField создает ошибку валидации одного поля
+func InvalidField(code, field, message string) *Error {
+	return Validation(code, message).WithFields(FieldError{Field: field, Message: message})
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Is сравнивает ошибки по виду и коду, поэтому копия с другими полями совпадает с исходной
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	if !ok {
+		return false
+	}
+
+	return e.Kind == t.Kind && e.Code == t.Code
+}
+
+// WithFields возвращает копию ошибки с добавленными ошибками полей
+func (e *Error) WithFields(fields ...FieldError) *Error {
+	res := *e
+	res.Fields = append(append([]FieldError{}, e.Fields...), fields...)
+
+	return &res
+}
+
+// As достает доменную ошибку из цепочки ошибок
+func As(err error) (*Error, bool) {
+	var e *Error
+	if errors.As(err, &e) {
+		return e, true
+	}
+
+	return nil, false
+}
+
+// KindOf возвращает вид ошибки, для неизвестных ошибок - KindInternal
+func KindOf(err error) Kind {
+	if e, ok := As(err); ok {
+		return e.Kind
+	}
+
+	return KindInternal
+}
@@ -0,0 +1,41 @@
+package apperr
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIs(t *testing.T) {
+	base := NotFound("product_not_found", "product not found")
+	withFields := base.WithFields(FieldError{Field: "id", Message: "unknown id"})
+
+	assert.True(t, errors.Is(fmt.Errorf("op %w", withFields), base))
+	assert.False(t, errors.Is(base, NotFound("category_not_found", "category not found")))
+	assert.False(t, errors.Is(base, Validation("product_not_found", "product not found")))
+	assert.Empty(t, base.Fields)
+}
+
+func TestKindOf(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected Kind
+	}{
+		{"Not Found", fmt.Errorf("op %w", NotFound("x", "x")), KindNotFound},
+		{"Conflict", Conflict("x", "x"), KindConflict},
+		{"Validation", InvalidField("x", "name", "x"), KindValidation},
+		{"Unauthorized", Unauthorized("x", "x"), KindUnauthorized},
+		{"Forbidden", Forbidden("x", "x"), KindForbidden},
+		{"Precondition Failed", PreconditionFailed("x", "x"), KindPreconditionFailed},
+		{"Unknown", errors.New("something wrong"), KindInternal},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, KindOf(test.err))
+		})
+	}
+}
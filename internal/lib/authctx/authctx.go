@@ -0,0 +1,32 @@
+package authctx
+
+import (
+	"context"
+	"goapi/internal/model"
+)
+
+// User - пользователь, от имени которого выполняется запрос
+type User struct {
+	ID   int64
+	Role model.Role
+}
+
+type ctxKey struct{}
+
+// With возвращает контекст с пользователем запроса. Его кладут транспорты после проверки токена,
+// а сервисы по нему проверяют права
+func With(ctx context.Context, user User) context.Context {
+	return context.WithValue(ctx, ctxKey{}, user)
+}
+
+// From возвращает пользователя запроса, ok == false, если его нет в контексте
+func From(ctx context.Context) (User, bool) {
+	user, ok := ctx.Value(ctxKey{}).(User)
+	return user, ok
+}
+
+// IsAdmin сообщает, выполняется ли запрос администратором
+func IsAdmin(ctx context.Context) bool {
+	user, ok := From(ctx)
+	return ok && user.Role == model.RoleAdmin
+}
@@ -0,0 +1,60 @@
+package buildinfo
+
+import (
+	"runtime"
+	"runtime/debug"
+)
+
+// Значения задаются при сборке:
+//
+//	go build -ldflags "-X goapi/internal/lib/buildinfo.Version=v1.2.0 \
+//		-X goapi/internal/lib/buildinfo.Commit=$(git rev-parse HEAD) \
+//		-X goapi/internal/lib/buildinfo.Time=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+//
+// Если они не заданы, коммит и время берутся из данных VCS, которые go build встраивает сам.
+var (
+	Version = "dev"
+	Commit  = ""
+	Time    = ""
+)
+
+// Info - сведения о сборке бинарника
+type Info struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	Time      string `json:"time"`
+	Modified  bool   `json:"modified"`
+	GoVersion string `json:"go_version"`
+}
+
+// Get возвращает сведения о сборке
+func Get() Info {
+	info := Info{
+		Version:   Version,
+		Commit:    Commit,
+		Time:      Time,
+		GoVersion: runtime.Version(),
+	}
+
+	build, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+
+	for _, setting := range build.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			if info.Commit == "" {
+				info.Commit = setting.Value
+			}
+		case "vcs.time":
+			if info.Time == "" {
+				info.Time = setting.Value
+			}
+		case "vcs.modified":
+			info.Modified = setting.Value == "true"
+		}
+	}
+
+	return info
+}
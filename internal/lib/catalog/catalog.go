@@ -0,0 +1,279 @@
+package catalog
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"goapi/internal/lib/apperr"
+	"goapi/internal/model"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Format - формат файла каталога
+type Format string
+
+const (
+	FormatCSV   Format = "csv"
+	FormatJSONL Format = "jsonl"
+)
+
+// CategorySeparator разделяет категории в одной ячейке CSV, в названиях категорий он запрещен
+const CategorySeparator = "|"
+
+const maxLineSize = 1 << 20
+
+var (
+	ErrUnknownFormat   = apperr.InvalidField("unknown_format", "/format", "format must be csv or jsonl")
+	ErrMalformedHeader = apperr.InvalidField("malformed_header", "/file", "csv header must contain sku, name and categoryies columns")
+	ErrMalformedRow    = apperr.Validation("malformed_row", "row cannot be parsed")
+)
+
+var csvHeader = []string{"id", "sku", "name", "categoryies"}
+
+// ParseFormat проверяет название формата
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(strings.ToLower(s)); f {
+	case FormatCSV, FormatJSONL:
+		return f, nil
+	default:
+		return "", ErrUnknownFormat
+	}
+}
+
+// FormatFromFilename определяет формат по расширению файла
+func FormatFromFilename(name string) (Format, error) {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".csv":
+		return FormatCSV, nil
+	case ".jsonl", ".ndjson":
+		return FormatJSONL, nil
+	default:
+		return "", ErrUnknownFormat
+	}
+}
+
+// ContentType - MIME тип файла в формате f
+func (f Format) ContentType() string {
+	if f == FormatCSV {
+		return "text/csv; charset=utf-8"
+	}
+	return "application/x-ndjson"
+}
+
+// Encoder записывает товары в поток по одному
+type Encoder interface {
+	Encode(product model.Product) error
+	Flush() error
+}
+
+func NewEncoder(f Format, w io.Writer) Encoder {
+	if f == FormatCSV {
+		return &csvEncoder{w: csv.NewWriter(w)}
+	}
+	return &jsonlEncoder{w: bufio.NewWriter(w)}
+}
+
+type csvEncoder struct {
+	w           *csv.Writer
+	wroteHeader bool
+}
+
+func (e *csvEncoder) Encode(product model.Product) error {
+	if !e.wroteHeader {
+		if err := e.w.Write(csvHeader); err != nil {
+			return err
+		}
+		e.wroteHeader = true
+	}
+
+	return e.w.Write([]string{
+		strconv.Itoa(product.ID),
+		product.SKU,
+		product.Name,
+		strings.Join(categoryNames(product.Categoryies), CategorySeparator),
+	})
+}
+
+func (e *csvEncoder) Flush() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+type jsonlEncoder struct {
+	w *bufio.Writer
+}
+
+type jsonlProduct struct {
+	ID          int      `json:"id"`
+	SKU         string   `json:"sku,omitempty"`
+	Name        string   `json:"name"`
+	Categoryies []string `json:"categoryies"`
+}
+
+func (e *jsonlEncoder) Encode(product model.Product) error {
+	line, err := json.Marshal(jsonlProduct{
+		ID:          product.ID,
+		SKU:         product.SKU,
+		Name:        product.Name,
+		Categoryies: categoryNames(product.Categoryies),
+	})
+	if err != nil {
+		return err
+	}
+
+	if _, err := e.w.Write(line); err != nil {
+		return err
+	}
+	return e.w.WriteByte('\n')
+}
+
+func (e *jsonlEncoder) Flush() error {
+	return e.w.Flush()
+}
+
+func categoryNames(categoryies []model.Category) []string {
+	res := make([]string, 0, len(categoryies))
+	for _, category := range categoryies {
+		res = append(res, category.Name)
+	}
+	return res
+}
+
+// RowError - строку файла не удалось разобрать, чтение можно продолжать
+type RowError struct {
+	Line int
+	Err  error
+}
+
+func (e *RowError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+func (e *RowError) Unwrap() error {
+	return e.Err
+}
+
+// Decoder читает строки импорта. В конце файла возвращает io.EOF,
+// для испорченной строки - *RowError.
+type Decoder interface {
+	Decode() (model.ImportRow, error)
+}
+
+func NewDecoder(f Format, r io.Reader) Decoder {
+	if f == FormatCSV {
+		reader := csv.NewReader(r)
+		reader.FieldsPerRecord = -1
+		reader.TrimLeadingSpace = true
+		return &csvDecoder{r: reader}
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+	return &jsonlDecoder{s: scanner}
+}
+
+type csvDecoder struct {
+	r       *csv.Reader
+	columns map[string]int
+}
+
+func (d *csvDecoder) Decode() (model.ImportRow, error) {
+	if d.columns == nil {
+		if err := d.readHeader(); err != nil {
+			return model.ImportRow{}, err
+		}
+	}
+
+	record, err := d.r.Read()
+	if err == io.EOF {
+		return model.ImportRow{}, io.EOF
+	}
+
+	line, _ := d.r.FieldPos(0)
+	if err != nil {
+		if parseErr, ok := err.(*csv.ParseError); ok {
+			return model.ImportRow{}, &RowError{Line: parseErr.Line, Err: ErrMalformedRow}
+		}
+		return model.ImportRow{}, err
+	}
+
+	row := model.ImportRow{
+		Line: line,
+		SKU:  d.field(record, "sku"),
+		Name: d.field(record, "name"),
+	}
+	if categoryies := d.field(record, "categoryies"); categoryies != "" {
+		row.Categoryies = strings.Split(categoryies, CategorySeparator)
+	}
+
+	return row, nil
+}
+
+func (d *csvDecoder) readHeader() error {
+	header, err := d.r.Read()
+	if err == io.EOF {
+		return io.EOF
+	}
+	if err != nil {
+		return ErrMalformedHeader
+	}
+
+	d.columns = make(map[string]int, len(header))
+	for i, column := range header {
+		d.columns[strings.ToLower(strings.TrimSpace(column))] = i
+	}
+
+	for _, column := range []string{"sku", "name", "categoryies"} {
+		if _, ok := d.columns[column]; !ok {
+			return ErrMalformedHeader
+		}
+	}
+
+	return nil
+}
+
+func (d *csvDecoder) field(record []string, column string) string {
+	i := d.columns[column]
+	if i >= len(record) {
+		return ""
+	}
+	return strings.TrimSpace(record[i])
+}
+
+type jsonlDecoder struct {
+	s      *bufio.Scanner
+	line   int
+	broken bool
+}
+
+func (d *jsonlDecoder) Decode() (model.ImportRow, error) {
+	for d.s.Scan() {
+		d.line++
+
+		data := bytes.TrimSpace(d.s.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+
+		row := model.ImportRow{Line: d.line}
+		if err := json.Unmarshal(data, &row); err != nil {
+			return model.ImportRow{}, &RowError{Line: d.line, Err: ErrMalformedRow}
+		}
+
+		return row, nil
+	}
+
+	// после ошибки сканера (слишком длинная строка) чтение продолжить нельзя
+	if err := d.s.Err(); err != nil && !d.broken {
+		d.broken = true
+		d.line++
+		return model.ImportRow{}, &RowError{Line: d.line, Err: ErrMalformedRow}
+	}
+
+	return model.ImportRow{}, io.EOF
+}
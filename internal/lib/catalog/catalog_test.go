@@ -0,0 +1,124 @@
+package catalog
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"goapi/internal/model"
+)
+
+func TestParseFormat(t *testing.T) {
+	f, err := ParseFormat("CSV")
+	assert.NoError(t, err)
+	assert.Equal(t, FormatCSV, f)
+
+	_, err = ParseFormat("xml")
+	assert.ErrorIs(t, err, ErrUnknownFormat)
+}
+
+func TestEncoder(t *testing.T) {
+	product := model.Product{
+		ID:   1,
+		SKU:  "SKU-1",
+		Name: "Product, large",
+		Categoryies: []model.Category{
+			{ID: 1, Name: "Category1"},
+			{ID: 2, Name: "Category2"},
+		},
+	}
+
+	tests := []struct {
+		format   Format
+		expected string
+	}{
+		{
+			format:   FormatCSV,
+			expected: "id,sku,name,categoryies\n1,SKU-1,\"Product, large\",Category1|Category2\n",
+		},
+		{
+			format:   FormatJSONL,
+			expected: `{"id":1,"sku":"SKU-1","name":"Product, large","categoryies":["Category1","Category2"]}` + "\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(string(test.format), func(t *testing.T) {
+			var buf bytes.Buffer
+
+			enc := NewEncoder(test.format, &buf)
+			assert.NoError(t, enc.Encode(product))
+			assert.NoError(t, enc.Flush())
+			assert.Equal(t, test.expected, buf.String())
+		})
+	}
+}
+
+func TestDecoder(t *testing.T) {
+	tests := []struct {
+		name          string
+		format        Format
+		input         string
+		expectedRows  []model.ImportRow
+		expectedLines []int
+	}{
+		{
+			name:   "CSV",
+			format: FormatCSV,
+			input:  "sku,name,categoryies\nSKU-1,Product1,Category1|Category2\nSKU-2,\"Bad\"quote\",Category1\nSKU-3,Product3,\n",
+			expectedRows: []model.ImportRow{
+				{Line: 2, SKU: "SKU-1", Name: "Product1", Categoryies: []string{"Category1", "Category2"}},
+				{Line: 4, SKU: "SKU-3", Name: "Product3"},
+			},
+			expectedLines: []int{3},
+		},
+		{
+			name:   "JSONL",
+			format: FormatJSONL,
+			input:  `{"sku":"SKU-1","name":"Product1","categoryies":["Category1"]}` + "\n\n{broken\n" + `{"sku":"SKU-2","name":"Product2"}`,
+			expectedRows: []model.ImportRow{
+				{Line: 1, SKU: "SKU-1", Name: "Product1", Categoryies: []string{"Category1"}},
+				{Line: 4, SKU: "SKU-2", Name: "Product2"},
+			},
+			expectedLines: []int{3},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dec := NewDecoder(test.format, strings.NewReader(test.input))
+
+			var rows []model.ImportRow
+			var lines []int
+			for {
+				row, err := dec.Decode()
+				if err == io.EOF {
+					break
+				}
+
+				var rowErr *RowError
+				if errors.As(err, &rowErr) {
+					assert.ErrorIs(t, err, ErrMalformedRow)
+					lines = append(lines, rowErr.Line)
+					continue
+				}
+
+				assert.NoError(t, err)
+				rows = append(rows, row)
+			}
+
+			assert.Equal(t, test.expectedRows, rows)
+			assert.Equal(t, test.expectedLines, lines)
+		})
+	}
+}
+
+func TestDecoderMalformedHeader(t *testing.T) {
+	dec := NewDecoder(FormatCSV, strings.NewReader("sku,title\nSKU-1,Product1\n"))
+
+	_, err := dec.Decode()
+	assert.ErrorIs(t, err, ErrMalformedHeader)
+}
@@ -0,0 +1,279 @@
+package eventstream
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"goapi/internal/lib/logger/sl"
+	"goapi/internal/model"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+const (
+	subscriberBuffer = 64
+
+	// tailBatch - сколько событий outbox читается за один запрос
+	tailBatch = 100
+	// gapTimeout - сколько ждать событие с пропущенным идентификатором
+	gapTimeout = 5 * time.Second
+)
+
+var (
+	ErrClosed = errors.New("event stream is closed")
+)
+
+// Hub раздает события каталога подписчикам потока. Каждая реплика сама читает outbox
+// по идентификаторам, независимо от relay и отметок о доставке, поэтому подписчики любой
+// реплики получают все события. Переподключившийся клиент продолжает с Last-Event-ID
+// по outbox, поэтому его может обслужить любая реплика.
+type Hub struct {
+	source     Source
+	size       int
+	interval   time.Duration
+	heartbeat  time.Duration
+	gapTimeout time.Duration
+	log        *slog.Logger
+
+	mu      sync.Mutex
+	started bool
+	closed  bool
+	// cursor - идентификатор последнего разосланного события
+	cursor      int64
+	subscribers map[*Subscription]struct{}
+
+	// gapSince - с какого момента Run ждет пропущенный идентификатор
+	gapSince time.Time
+}
+
+// Source - outbox, из которого Hub читает события
+type Source interface {
+	LastEventID(ctx context.Context) (int64, error)
+	EventsAfter(ctx context.Context, afterID int64, limit int) ([]model.Event, error)
+	LatestEvents(ctx context.Context, afterID, untilID int64, limit int) ([]model.Event, error)
+}
+
+// Subscription - подписка на поток. Канал Events закрывается, если подписчик не успевает
+// читать события; клиент должен переподключиться с последним полученным идентификатором.
+type Subscription struct {
+	events chan model.Event
+	// after - события до него включительно клиент уже получил
+	after int64
+}
+
+func (s *Subscription) Events() <-chan model.Event {
+	return s.events
+}
+
+// NewHub создает Hub, который раз в interval читает outbox и повторяет клиенту
+// не больше size пропущенных событий
+func NewHub(source Source, size int, interval, heartbeat time.Duration, log *slog.Logger) *Hub {
+	return &Hub{
+		source:      source,
+		size:        size,
+		interval:    interval,
+		heartbeat:   heartbeat,
+		gapTimeout:  gapTimeout,
+		log:         log,
+		subscribers: make(map[*Subscription]struct{}),
+	}
+}
+
+// Heartbeat возвращает интервал, с которым поток шлет комментарии для поддержания соединения
+func (h *Hub) Heartbeat() time.Duration {
+	return h.heartbeat
+}
+
+// Run раз в interval рассылает подписчикам новые события outbox, пока не отменен ctx.
+// Рассылаются события, записанные после запуска Hub.
+func (h *Hub) Run(ctx context.Context) {
+	const op = "eventstream.Run"
+
+	log := h.log.With(
+		slog.String("op", op),
+	)
+
+	log.Info("event stream started")
+
+	ticker := time.NewTicker(h.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := h.tail(ctx); err != nil && ctx.Err() == nil {
+			log.Error("error read outbox", sl.Err(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			log.Info("event stream stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// tail рассылает события outbox после cursor. Идентификатор событию выдается при записи,
+// а видно оно после фиксации транзакции, поэтому событие с меньшим идентификатором может
+// появиться позже следующего. На пропуске tail останавливается и ждет до gapTimeout,
+// после чего считает, что транзакция с пропущенным идентификатором откатилась.
+func (h *Hub) tail(ctx context.Context) error {
+	h.mu.Lock()
+	err := h.start(ctx)
+	cursor := h.cursor
+	h.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	for {
+		events, err := h.source.EventsAfter(ctx, cursor, tailBatch)
+		if err != nil {
+			return err
+		}
+
+		for _, event := range events {
+			if event.ID != cursor+1 && !h.gapExpired() {
+				return nil
+			}
+			h.gapSince = time.Time{}
+			h.publish(event)
+			cursor = event.ID
+		}
+
+		if len(events) < tailBatch {
+			return nil
+		}
+	}
+}
+
+// start запоминает последнее событие outbox, с которого начинается рассылка.
+// Вызывается под h.mu.
+func (h *Hub) start(ctx context.Context) error {
+	if h.started {
+		return nil
+	}
+
+	id, err := h.source.LastEventID(ctx)
+	if err != nil {
+		return err
+	}
+	h.cursor, h.started = id, true
+
+	return nil
+}
+
+// gapExpired сообщает, истекло ли ожидание пропущенного идентификатора
+func (h *Hub) gapExpired() bool {
+	if h.gapSince.IsZero() {
+		h.gapSince = time.Now()
+	}
+
+	return time.Since(h.gapSince) >= h.gapTimeout
+}
+
+// publish отправляет событие подписчикам. Подписчик, который не успевает читать, отключается.
+func (h *Hub) publish(event model.Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.cursor = event.ID
+	for sub := range h.subscribers {
+		if event.ID <= sub.after {
+			continue
+		}
+
+		select {
+		case sub.events <- event:
+		default:
+			delete(h.subscribers, sub)
+			close(sub.events)
+		}
+	}
+}
+
+// Subscribe подписывает на новые события и возвращает из outbox не больше size последних
+// событий после lastEventID, которые Hub уже разослал. Нулевой lastEventID означает подписку без повтора.
+func (h *Hub) Subscribe(ctx context.Context, lastEventID int64) (*Subscription, []model.Event, error) {
+	h.mu.Lock()
+	if h.closed {
+		h.mu.Unlock()
+		return nil, nil, ErrClosed
+	}
+	if err := h.start(ctx); err != nil {
+		h.mu.Unlock()
+		return nil, nil, err
+	}
+	sub := &Subscription{events: make(chan model.Event, subscriberBuffer), after: lastEventID}
+	h.subscribers[sub] = struct{}{}
+	cursor := h.cursor
+	h.mu.Unlock()
+
+	if lastEventID == 0 || lastEventID >= cursor {
+		return sub, nil, nil
+	}
+
+	replay, err := h.source.LatestEvents(ctx, lastEventID, cursor, h.size)
+	if err != nil {
+		h.Unsubscribe(sub)
+		return nil, nil, err
+	}
+
+	return sub, replay, nil
+}
+
+// Unsubscribe отменяет подписку
+func (h *Hub) Unsubscribe(sub *Subscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, ok := h.subscribers[sub]; ok {
+		delete(h.subscribers, sub)
+		close(sub.events)
+	}
+}
+
+// Close закрывает все подписки и больше не принимает новые. Открытые потоки завершаются
+// и не задерживают остановку сервера, а клиенты переподключаются к другой реплике.
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.closed = true
+	for sub := range h.subscribers {
+		delete(h.subscribers, sub)
+		close(sub.events)
+	}
+}
+
+// eventCategoryies - поля событий каталога, по которым определяется категория
+type eventCategoryies struct {
+	Name        string           `json:"name"`
+	Categoryies []model.Category `json:"categoryies"`
+}
+
+// InCategory проверяет, относится ли событие к категории: событие категории - по ее имени,
+// событие товара - по категориям товара. Пустая категория подходит любому событию.
+func InCategory(event model.Event, category string) bool {
+	if category == "" {
+		return true
+	}
+
+	var payload eventCategoryies
+	if err := json.Unmarshal(event.Payload, &payload); err != nil {
+		return false
+	}
+
+	switch event.Type {
+	case model.EventCategoryCreated, model.EventCategoryRenamed, model.EventCategoryDeleted:
+		return payload.Name == category
+	}
+
+	for _, c := range payload.Categoryies {
+		if c.Name == category {
+			return true
+		}
+	}
+
+	return false
+}
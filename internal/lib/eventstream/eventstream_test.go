@@ -0,0 +1,272 @@
+package eventstream
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"goapi/internal/model"
+)
+
+func event(t *testing.T, id int64, e model.DomainEvent) model.Event {
+	payload, err := json.Marshal(e)
+	assert.NoError(t, err)
+
+	return model.Event{ID: id, Type: e.EventType(), AggregateID: e.AggregateID(), Payload: payload}
+}
+
+// memorySource - outbox в памяти
+type memorySource struct {
+	mu     sync.Mutex
+	events []model.Event
+}
+
+func (s *memorySource) add(events ...model.Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.events = append(s.events, events...)
+	sort.Slice(s.events, func(i, j int) bool { return s.events[i].ID < s.events[j].ID })
+}
+
+func (s *memorySource) LastEventID(ctx context.Context) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.events) == 0 {
+		return 0, nil
+	}
+
+	return s.events[len(s.events)-1].ID, nil
+}
+
+func (s *memorySource) EventsAfter(ctx context.Context, afterID int64, limit int) ([]model.Event, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var res []model.Event
+	for _, e := range s.events {
+		if e.ID > afterID && len(res) < limit {
+			res = append(res, e)
+		}
+	}
+
+	return res, nil
+}
+
+func (s *memorySource) LatestEvents(ctx context.Context, afterID, untilID int64, limit int) ([]model.Event, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var res []model.Event
+	for _, e := range s.events {
+		if e.ID > afterID && e.ID <= untilID {
+			res = append(res, e)
+		}
+	}
+
+	return res[max(0, len(res)-limit):], nil
+}
+
+func newHub(source Source, size int) *Hub {
+	return NewHub(source, size, time.Hour, time.Second, slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func deleted(t *testing.T, ids ...int64) []model.Event {
+	events := make([]model.Event, 0, len(ids))
+	for _, id := range ids {
+		events = append(events, event(t, id, model.ProductDeleted{ProductID: id}))
+	}
+
+	return events
+}
+
+func eventIDs(events []model.Event) []int64 {
+	ids := make([]int64, 0, len(events))
+	for _, e := range events {
+		ids = append(ids, e.ID)
+	}
+
+	return ids
+}
+
+// receive читает из подписки n событий
+func receive(t *testing.T, sub *Subscription, n int) []int64 {
+	t.Helper()
+
+	ids := make([]int64, 0, n)
+	for range n {
+		select {
+		case e := <-sub.Events():
+			ids = append(ids, e.ID)
+		case <-time.After(time.Second):
+			t.Fatalf("received %v, want %d events", ids, n)
+		}
+	}
+
+	return ids
+}
+
+func TestHubReplay(t *testing.T) {
+	source := &memorySource{}
+	source.add(deleted(t, 1, 2, 3)...)
+	hub := newHub(source, 2)
+
+	// другая реплика: события доступны по Last-Event-ID, хотя этот Hub их не рассылал
+	sub, replay, err := hub.Subscribe(context.Background(), 0)
+	assert.NoError(t, err)
+	assert.Empty(t, replay)
+	hub.Unsubscribe(sub)
+
+	sub, replay, err = hub.Subscribe(context.Background(), 3)
+	assert.NoError(t, err)
+	assert.Empty(t, replay)
+	hub.Unsubscribe(sub)
+
+	source.add(deleted(t, 4)...)
+	assert.NoError(t, hub.tail(context.Background()))
+
+	// повторяются не больше size последних событий
+	sub, replay, err = hub.Subscribe(context.Background(), 1)
+	assert.NoError(t, err)
+	defer hub.Unsubscribe(sub)
+	assert.Equal(t, []int64{3, 4}, eventIDs(replay))
+
+	// после повтора подписка получает следующие события без пропусков и повторов
+	source.add(deleted(t, 5)...)
+	assert.NoError(t, hub.tail(context.Background()))
+	assert.Equal(t, []int64{5}, receive(t, sub, 1))
+}
+
+func TestHubTailWaitsForGap(t *testing.T) {
+	source := &memorySource{}
+	hub := newHub(source, 10)
+
+	sub, _, err := hub.Subscribe(context.Background(), 0)
+	assert.NoError(t, err)
+	defer hub.Unsubscribe(sub)
+
+	// событие 2 зафиксировано раньше события 1
+	source.add(deleted(t, 2)...)
+	assert.NoError(t, hub.tail(context.Background()))
+	assert.Empty(t, sub.Events())
+
+	source.add(deleted(t, 1)...)
+	assert.NoError(t, hub.tail(context.Background()))
+	assert.Equal(t, []int64{1, 2}, receive(t, sub, 2))
+
+	// транзакция события 3 откатилась: после gapTimeout Hub его пропускает
+	hub.gapTimeout = 0
+	source.add(deleted(t, 4)...)
+	assert.NoError(t, hub.tail(context.Background()))
+	assert.Equal(t, []int64{4}, receive(t, sub, 1))
+}
+
+func TestHubRun(t *testing.T) {
+	source := &memorySource{}
+	source.add(deleted(t, 1)...)
+	hub := NewHub(source, 10, 10*time.Millisecond, time.Second, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		hub.Run(ctx)
+	}()
+
+	sub, _, err := hub.Subscribe(context.Background(), 0)
+	assert.NoError(t, err)
+	defer hub.Unsubscribe(sub)
+
+	// рассылаются только события, записанные после запуска
+	source.add(deleted(t, 2, 3)...)
+	assert.Equal(t, []int64{2, 3}, receive(t, sub, 2))
+
+	cancel()
+	<-done
+}
+
+func TestHubSlowSubscriber(t *testing.T) {
+	source := &memorySource{}
+	hub := newHub(source, subscriberBuffer*2)
+
+	sub, _, err := hub.Subscribe(context.Background(), 0)
+	assert.NoError(t, err)
+
+	ids := make([]int64, 0, subscriberBuffer+1)
+	for id := int64(1); id <= subscriberBuffer+1; id++ {
+		ids = append(ids, id)
+	}
+	source.add(deleted(t, ids...)...)
+	assert.NoError(t, hub.tail(context.Background()))
+
+	received := 0
+	for range sub.Events() {
+		received++
+	}
+	assert.Equal(t, subscriberBuffer, received)
+
+	// отписка после закрытия канала безопасна
+	hub.Unsubscribe(sub)
+}
+
+func TestHubClose(t *testing.T) {
+	hub := newHub(&memorySource{}, 10)
+
+	sub, _, err := hub.Subscribe(context.Background(), 0)
+	assert.NoError(t, err)
+
+	hub.Close()
+
+	_, ok := <-sub.Events()
+	assert.False(t, ok)
+	hub.Unsubscribe(sub)
+
+	_, _, err = hub.Subscribe(context.Background(), 0)
+	assert.ErrorIs(t, err, ErrClosed)
+}
+
+func TestInCategory(t *testing.T) {
+	tests := []struct {
+		name     string
+		event    model.DomainEvent
+		category string
+		expected bool
+	}{
+		{
+			name:     "No Filter",
+			event:    model.ProductDeleted{ProductID: 1},
+			expected: true,
+		},
+		{
+			name:     "Product In Category",
+			event:    model.ProductRenamed{ProductID: 1, Name: "Product1", Categoryies: []model.Category{{Name: "Category1"}}},
+			category: "Category1",
+			expected: true,
+		},
+		{
+			name:     "Product In Other Category",
+			event:    model.ProductCreated{ProductID: 1, Name: "Category1", Categoryies: []model.Category{{Name: "Category2"}}},
+			category: "Category1",
+			expected: false,
+		},
+		{
+			name:     "Category",
+			event:    model.CategoryDeleted{CategoryID: 1, Name: "Category1"},
+			category: "Category1",
+			expected: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, InCategory(event(t, 1, test.event), test.category))
+		})
+	}
+}
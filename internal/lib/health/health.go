@@ -0,0 +1,117 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	StatusOK   = "ok"
+	StatusFail = "fail"
+)
+
+var ErrShuttingDown = errors.New("shutting down")
+
+// Check проверяет одну зависимость приложения, nil - зависимость в порядке
+type Check func(ctx context.Context) error
+
+// Report - результат проверки готовности: общий статус и статус каждой проверки
+type Report struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks"`
+}
+
+// Ready сообщает, прошли ли все проверки
+func (r Report) Ready() bool {
+	return r.Status == StatusOK
+}
+
+type namedCheck struct {
+	name  string
+	check Check
+}
+
+// Checker собирает проверки готовности приложения. После Shutdown приложение
+// считается неготовым, чтобы балансировщик перестал присылать запросы до остановки серверов.
+type Checker struct {
+	timeout time.Duration
+
+	mu     sync.RWMutex
+	checks []namedCheck
+
+	shuttingDown atomic.Bool
+}
+
+// NewChecker создает набор проверок, каждая из которых ограничена timeout
+func NewChecker(timeout time.Duration) *Checker {
+	return &Checker{timeout: timeout}
+}
+
+// Add добавляет проверку с именем name
+func (c *Checker) Add(name string, check Check) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.checks = append(c.checks, namedCheck{name: name, check: check})
+}
+
+// Shutdown переводит приложение в состояние остановки: готовность больше не проверяется
+func (c *Checker) Shutdown() {
+	c.shuttingDown.Store(true)
+}
+
+// Ready выполняет все проверки параллельно и возвращает отчет
+func (c *Checker) Ready(ctx context.Context) Report {
+	if c.shuttingDown.Load() {
+		return Report{Status: StatusFail, Checks: map[string]string{"shutdown": ErrShuttingDown.Error()}}
+	}
+
+	c.mu.RLock()
+	checks := c.checks
+	c.mu.RUnlock()
+
+	results := make([]error, len(checks))
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			results[i] = c.run(ctx, check.check)
+		}()
+	}
+	wg.Wait()
+
+	report := Report{Status: StatusOK, Checks: make(map[string]string, len(checks))}
+	for i, check := range checks {
+		if results[i] != nil {
+			report.Status = StatusFail
+			report.Checks[check.name] = results[i].Error()
+			continue
+		}
+		report.Checks[check.name] = StatusOK
+	}
+
+	return report
+}
+
+// run выполняет проверку не дольше timeout, даже если сама проверка контекст не учитывает
+func (c *Checker) run(ctx context.Context, check Check) error {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- check(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,93 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChecker(t *testing.T) {
+	tests := []struct {
+		name           string
+		checks         map[string]Check
+		shutdown       bool
+		expectedStatus string
+		expectedChecks map[string]string
+	}{
+		{
+			name:           "NoChecks",
+			expectedStatus: StatusOK,
+			expectedChecks: map[string]string{},
+		},
+		{
+			name: "OK",
+			checks: map[string]Check{
+				"db":         func(context.Context) error { return nil },
+				"collectors": func(context.Context) error { return nil },
+			},
+			expectedStatus: StatusOK,
+			expectedChecks: map[string]string{"db": StatusOK, "collectors": StatusOK},
+		},
+		{
+			name: "Failed",
+			checks: map[string]Check{
+				"db":         func(context.Context) error { return errors.New("connection refused") },
+				"collectors": func(context.Context) error { return nil },
+			},
+			expectedStatus: StatusFail,
+			expectedChecks: map[string]string{"db": "connection refused", "collectors": StatusOK},
+		},
+		{
+			name: "Timeout",
+			checks: map[string]Check{
+				"db": func(ctx context.Context) error {
+					<-ctx.Done()
+					return ctx.Err()
+				},
+			},
+			expectedStatus: StatusFail,
+			expectedChecks: map[string]string{"db": context.DeadlineExceeded.Error()},
+		},
+		{
+			name: "IgnoresContext",
+			checks: map[string]Check{
+				"db": func(context.Context) error {
+					time.Sleep(time.Second)
+					return nil
+				},
+			},
+			expectedStatus: StatusFail,
+			expectedChecks: map[string]string{"db": context.DeadlineExceeded.Error()},
+		},
+		{
+			name: "ShuttingDown",
+			checks: map[string]Check{
+				"db": func(context.Context) error { return nil },
+			},
+			shutdown:       true,
+			expectedStatus: StatusFail,
+			expectedChecks: map[string]string{"shutdown": ErrShuttingDown.Error()},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := NewChecker(50 * time.Millisecond)
+			for name, check := range test.checks {
+				c.Add(name, check)
+			}
+			if test.shutdown {
+				c.Shutdown()
+			}
+
+			report := c.Ready(context.Background())
+
+			assert.Equal(t, test.expectedStatus, report.Status)
+			assert.Equal(t, test.expectedStatus == StatusOK, report.Ready())
+			assert.Equal(t, test.expectedChecks, report.Checks)
+		})
+	}
+}
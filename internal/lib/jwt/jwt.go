@@ -9,43 +9,57 @@ import (
 	"github.com/dgrijalva/jwt-go"
 )
 
-const (
-	signingKey = "qrkjk#4#%35FSFJlja#4353KSFjH"
-)
-
 type tokenClaims struct {
 	jwt.StandardClaims
-	UserId int64 `json:"user_id"`
+	UserId int64  `json:"user_id"`
+	Role   string `json:"role,omitempty"`
+}
+
+// Signer выпускает и проверяет токены доступа, подписанные ключом HMAC из конфига
+type Signer struct {
+	key []byte
 }
 
-func NewToken(user model.User, duration time.Duration) (string, error) {
+func NewSigner(key string) *Signer {
+	return &Signer{key: []byte(key)}
+}
+
+func (s *Signer) NewToken(user model.User, duration time.Duration) (string, error) {
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, &tokenClaims{
 		jwt.StandardClaims{
 			ExpiresAt: time.Now().Add(duration).Unix(),
 			IssuedAt:  time.Now().Unix(),
 		},
 		int64(user.ID),
+		string(user.Role),
 	})
 
-	return token.SignedString([]byte(signingKey))
+	return token.SignedString(s.key)
 }
 
-func ParseToken(tokenString string) (int64, error) {
+// ParseToken проверяет подпись и срок токена и возвращает id и роль пользователя.
+// Токены без роли выпущены до ее появления и считаются токенами обычного пользователя.
+func (s *Signer) ParseToken(tokenString string) (int64, model.Role, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &tokenClaims{}, func(token *jwt.Token) (interface{}, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, errors.New("invalid signing method")
 		}
 
-		return []byte(signingKey), nil
+		return s.key, nil
 	})
 	if err != nil {
-		return 0, err
+		return 0, "", err
 	}
 
 	claims, ok := token.Claims.(*tokenClaims)
 	if !ok {
-		return 0, errors.New("token claims are not of type *tokenClaims")
+		return 0, "", errors.New("token claims are not of type *tokenClaims")
+	}
+
+	role := model.Role(claims.Role)
+	if role == "" {
+		role = model.RoleUser
 	}
 
-	return claims.UserId, nil
+	return claims.UserId, role, nil
 }
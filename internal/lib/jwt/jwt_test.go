@@ -14,12 +14,30 @@ func TestNewTokenAndParseToken(t *testing.T) {
 		PassHash: []byte("test"),
 	}
 
+	signer := NewSigner("test-signing-key")
+
 	duration := time.Hour
-	tokenString, err := NewToken(user, duration)
+	tokenString, err := signer.NewToken(user, duration)
 	assert.NoError(t, err)
 	assert.NotEmpty(t, tokenString)
 
-	userID, err := ParseToken(tokenString)
+	userID, role, err := signer.ParseToken(tokenString)
 	assert.NoError(t, err)
 	assert.Equal(t, int64(user.ID), userID)
+	assert.Equal(t, model.RoleUser, role)
+
+	admin, err := signer.NewToken(model.User{ID: 1, Role: model.RoleAdmin}, duration)
+	assert.NoError(t, err)
+
+	_, role, err = signer.ParseToken(admin)
+	assert.NoError(t, err)
+	assert.Equal(t, model.RoleAdmin, role)
+}
+
+func TestParseTokenWrongKey(t *testing.T) {
+	tokenString, err := NewSigner("test-signing-key").NewToken(model.User{ID: 1}, time.Hour)
+	assert.NoError(t, err)
+
+	_, _, err = NewSigner("another-key").ParseToken(tokenString)
+	assert.Error(t, err)
 }
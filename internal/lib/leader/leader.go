@@ -0,0 +1,183 @@
+package leader
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"goapi/internal/lib/logger/sl"
+	"goapi/internal/lib/schedule"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// releaseTimeout - сколько ждать освобождения аренды при остановке
+const releaseTimeout = 5 * time.Second
+
+var isLeader = promauto.NewGaugeVec(prometheus.GaugeOpts{
+	Namespace: "goapi",
+	Subsystem: "leader",
+	Name:      "is_leader",
+	Help:      "1 if this replica holds the lease, 0 otherwise.",
+}, []string{"lease"})
+
+// LeaseStore - общее для реплик хранилище аренд
+type LeaseStore interface {
+	AcquireLease(ctx context.Context, name, holder string, ttl time.Duration) (bool, error)
+	ReleaseLease(ctx context.Context, name, holder string) error
+}
+
+// Config - параметры выборов. Лидер продлевает аренду каждые RenewInterval,
+// остальные реплики с тем же интервалом пытаются ее занять. Если лидер умер,
+// его место занимают не позже чем через LeaseTTL + RenewInterval.
+type Config struct {
+	Name          string
+	LeaseTTL      time.Duration
+	RenewInterval time.Duration
+}
+
+// Elector выбирает среди реплик одну, которая выполняет задачу
+type Elector struct {
+	cfg   Config
+	id    string
+	store LeaseStore
+	clock schedule.Clock
+	log   *slog.Logger
+}
+
+func NewElector(cfg Config, id string, store LeaseStore, log *slog.Logger) *Elector {
+	return &Elector{
+		cfg:   cfg,
+		id:    id,
+		store: store,
+		clock: schedule.RealClock{},
+		log:   log.With(slog.String("lease", cfg.Name), slog.String("holder", id)),
+	}
+}
+
+// NewID возвращает идентификатор реплики: имя хоста и случайный суффикс,
+// чтобы перезапущенный процесс не продлил аренду предыдущего
+func NewID() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+
+	suffix := make([]byte, 4)
+	_, _ = rand.Read(suffix)
+
+	return fmt.Sprintf("%s-%s", host, hex.EncodeToString(suffix))
+}
+
+// Run участвует в выборах, пока не отменен ctx. Пока реплика - лидер, выполняется fn;
+// при потере аренды контекст fn отменяется, и Run ждет ее завершения, прежде чем
+// снова бороться за аренду. При остановке аренда освобождается.
+func (e *Elector) Run(ctx context.Context, fn func(ctx context.Context)) {
+	const op = "leader.Run"
+
+	log := e.log.With(
+		slog.String("op", op),
+	)
+
+	for ctx.Err() == nil {
+		acquired, err := e.store.AcquireLease(ctx, e.cfg.Name, e.id, e.cfg.LeaseTTL)
+		if err != nil && ctx.Err() == nil {
+			log.Error("failed to acquire lease", sl.Err(err))
+		}
+		if acquired {
+			e.lead(ctx, fn)
+		}
+
+		if !e.sleep(ctx, e.cfg.RenewInterval) {
+			return
+		}
+	}
+}
+
+// lead выполняет fn и продлевает аренду, пока она за репликой
+func (e *Elector) lead(ctx context.Context, fn func(ctx context.Context)) {
+	const op = "leader.lead"
+
+	log := e.log.With(
+		slog.String("op", op),
+	)
+
+	log.Info("became leader")
+	isLeader.WithLabelValues(e.cfg.Name).Set(1)
+
+	leaderCtx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		fn(leaderCtx)
+	}()
+
+	defer func() {
+		cancel()
+		<-done
+
+		isLeader.WithLabelValues(e.cfg.Name).Set(0)
+		e.release(ctx)
+		log.Info("stopped leading")
+	}()
+
+	for {
+		timer := e.clock.NewTimer(e.cfg.RenewInterval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-done:
+			timer.Stop()
+			return
+		case <-timer.C():
+		}
+
+		// продлить не удалось - аренда может истечь в любой момент, лидерство уступается сразу
+		renewed, err := e.renew(ctx)
+		if err != nil {
+			log.Error("failed to renew lease", sl.Err(err))
+			return
+		}
+		if !renewed {
+			log.Warn("lease lost")
+			return
+		}
+	}
+}
+
+// renew продлевает аренду. Продление, которое не успело за половину запаса LeaseTTL - RenewInterval,
+// считается неудачным: иначе аренда истекла бы, пока fn еще работает, и другая реплика
+// стала бы вторым лидером.
+func (e *Elector) renew(ctx context.Context) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, (e.cfg.LeaseTTL-e.cfg.RenewInterval)/2)
+	defer cancel()
+
+	return e.store.AcquireLease(ctx, e.cfg.Name, e.id, e.cfg.LeaseTTL)
+}
+
+// release освобождает аренду, чтобы другая реплика заняла ее, не дожидаясь истечения
+func (e *Elector) release(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), releaseTimeout)
+	defer cancel()
+
+	if err := e.store.ReleaseLease(ctx, e.cfg.Name, e.id); err != nil {
+		e.log.Error("failed to release lease", sl.Err(err))
+	}
+}
+
+// sleep ждет d и возвращает false, если раньше отменен ctx
+func (e *Elector) sleep(ctx context.Context, d time.Duration) bool {
+	timer := e.clock.NewTimer(d)
+	select {
+	case <-ctx.Done():
+		timer.Stop()
+		return false
+	case <-timer.C():
+		return true
+	}
+}
@@ -0,0 +1,207 @@
+package leader
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"goapi/internal/lib/schedule"
+)
+
+var errStore = errors.New("connection refused")
+
+// memoryStore - аренда в памяти, сроки которой считаются по FakeClock
+type memoryStore struct {
+	clock *schedule.FakeClock
+
+	mu      sync.Mutex
+	holder  string
+	expires time.Time
+	// failing - реплики, для которых хранилище недоступно
+	failing map[string]bool
+	// hanging - реплики, запросы которых висят до отмены контекста
+	hanging map[string]bool
+}
+
+func (s *memoryStore) AcquireLease(ctx context.Context, name, holder string, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	if s.hanging[holder] {
+		s.mu.Unlock()
+		<-ctx.Done()
+		return false, ctx.Err()
+	}
+	defer s.mu.Unlock()
+
+	if s.failing[holder] {
+		return false, errStore
+	}
+
+	now := s.clock.Now()
+	if s.holder != "" && s.holder != holder && now.Before(s.expires) {
+		return false, nil
+	}
+	s.holder, s.expires = holder, now.Add(ttl)
+
+	return true, nil
+}
+
+func (s *memoryStore) ReleaseLease(ctx context.Context, name, holder string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.failing[holder] {
+		return errStore
+	}
+	if s.holder == holder {
+		s.holder = ""
+	}
+
+	return nil
+}
+
+func (s *memoryStore) fail(holder string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.failing[holder] = true
+}
+
+// replica - запущенный Elector, задача которого сообщает о начале и конце лидерства
+type replica struct {
+	started chan struct{}
+	stopped chan struct{}
+	cancel  context.CancelFunc
+	done    chan struct{}
+}
+
+var testConfig = Config{Name: "collector", LeaseTTL: 30 * time.Second, RenewInterval: 10 * time.Second}
+
+func startReplica(cfg Config, id string, store *memoryStore) *replica {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	e := NewElector(cfg, id, store, logger)
+	e.clock = store.clock
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r := &replica{
+		started: make(chan struct{}, 1),
+		stopped: make(chan struct{}, 1),
+		cancel:  cancel,
+		done:    make(chan struct{}),
+	}
+
+	go func() {
+		defer close(r.done)
+		e.Run(ctx, func(ctx context.Context) {
+			r.started <- struct{}{}
+			<-ctx.Done()
+			r.stopped <- struct{}{}
+		})
+	}()
+
+	return r
+}
+
+func newStore() *memoryStore {
+	return &memoryStore{
+		clock:   schedule.NewFakeClock(time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)),
+		failing: make(map[string]bool),
+		hanging: make(map[string]bool),
+	}
+}
+
+func assertNotLeading(t *testing.T, r *replica) {
+	t.Helper()
+
+	select {
+	case <-r.started:
+		t.Fatal("replica became leader")
+	case <-time.After(10 * time.Millisecond):
+	}
+}
+
+func TestElectorSingleLeader(t *testing.T) {
+	store := newStore()
+
+	a := startReplica(testConfig, "a", store)
+	<-a.started
+
+	b := startReplica(testConfig, "b", store)
+	store.clock.BlockUntil(2)
+
+	// лидер продлевает аренду, вторая реплика ждет
+	for range 5 {
+		store.clock.Advance(testConfig.RenewInterval)
+		store.clock.BlockUntil(2)
+	}
+	assertNotLeading(t, b)
+
+	// при остановке лидер освобождает аренду, и ее сразу занимает другая реплика
+	a.cancel()
+	<-a.stopped
+	<-a.done
+
+	store.clock.Advance(testConfig.RenewInterval)
+	<-b.started
+
+	b.cancel()
+	<-b.done
+}
+
+func TestElectorTakeoverAfterCrash(t *testing.T) {
+	store := newStore()
+
+	a := startReplica(testConfig, "a", store)
+	<-a.started
+
+	b := startReplica(testConfig, "b", store)
+	store.clock.BlockUntil(2)
+
+	// реплика a потеряла связь с базой: она уступает лидерство, но освободить аренду не может
+	store.fail("a")
+	store.clock.Advance(testConfig.RenewInterval)
+	<-a.stopped
+
+	// b занимает аренду только после ее истечения
+	store.clock.BlockUntil(2)
+	store.clock.Advance(testConfig.RenewInterval)
+	store.clock.BlockUntil(2)
+	assertNotLeading(t, b)
+
+	store.clock.Advance(testConfig.RenewInterval)
+	<-b.started
+
+	a.cancel()
+	<-a.done
+	b.cancel()
+	<-b.done
+}
+
+func TestElectorRenewalTimeout(t *testing.T) {
+	store := newStore()
+
+	// запас до истечения аренды - 100ms реального времени, продление ждет половину
+	cfg := Config{Name: "collector", LeaseTTL: testConfig.RenewInterval + 100*time.Millisecond, RenewInterval: testConfig.RenewInterval}
+	a := startReplica(cfg, "a", store)
+	<-a.started
+
+	// база перестала отвечать: продление не должно висеть дольше аренды
+	store.mu.Lock()
+	store.hanging["a"] = true
+	store.mu.Unlock()
+
+	store.clock.BlockUntil(1)
+	store.clock.Advance(cfg.RenewInterval)
+
+	select {
+	case <-a.stopped:
+	case <-time.After(time.Second):
+		t.Fatal("leader kept running after the renewal timed out")
+	}
+
+	a.cancel()
+	<-a.done
+}
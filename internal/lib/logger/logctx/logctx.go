@@ -0,0 +1,23 @@
+package logctx
+
+import (
+	"context"
+	"log/slog"
+)
+
+type ctxKey struct{}
+
+// With возвращает контекст с логгером запроса: в нем уже есть request_id, trace_id и user_id,
+// поэтому записи всех слоев одного запроса можно собрать вместе
+func With(ctx context.Context, log *slog.Logger) context.Context {
+	return context.WithValue(ctx, ctxKey{}, log)
+}
+
+// From возвращает логгер запроса из контекста, а вне запроса - fallback
+func From(ctx context.Context, fallback *slog.Logger) *slog.Logger {
+	if log, ok := ctx.Value(ctxKey{}).(*slog.Logger); ok {
+		return log
+	}
+
+	return fallback
+}
@@ -0,0 +1,12 @@
+package sl
+
+import "log/slog"
+
+// Err оборачивает ошибку в атрибут лога
+func Err(err error) slog.Attr {
+	if err == nil {
+		return slog.String("error", "")
+	}
+
+	return slog.String("error", err.Error())
+}
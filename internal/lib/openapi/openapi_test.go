@@ -0,0 +1,55 @@
+package openapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testCategory struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name" validate:"category_name"`
+}
+
+type testProduct struct {
+	ID          int64          `json:"id" validate:"entity_id"`
+	Name        string         `json:"name,omitempty"`
+	Categoryies []testCategory `json:"categoryies" validate:"max=20,dive"`
+	Hidden      string         `json:"-"`
+}
+
+func TestSchema(t *testing.T) {
+	doc := New("test", "1")
+
+	s := doc.Schema(testProduct{})
+	assert.Equal(t, "#/components/schemas/testProduct", s.Ref)
+
+	product := doc.Components.Schemas["testProduct"]
+	assert.Equal(t, []string{"id"}, product.Required)
+	assert.Equal(t, &Schema{Type: "integer", Format: "int64"}, product.Properties["id"])
+	assert.Equal(t, "#/components/schemas/testCategory", product.Properties["categoryies"].Items.Ref)
+	assert.NotContains(t, product.Properties, "Hidden")
+
+	assert.Equal(t, []string{"name"}, doc.Components.Schemas["testCategory"].Required)
+}
+
+func TestPath(t *testing.T) {
+	assert.Equal(t, "/api/v1/products/{id}", Path("/api/v1/products/:id"))
+	assert.Equal(t, "/static/{filepath}", Path("/static/*filepath"))
+}
+
+type testQuery struct {
+	Format string `form:"format" validate:"required,oneof=csv jsonl"`
+	DryRun bool   `form:"dry_run"`
+	Skip   string
+}
+
+func TestQueryParameters(t *testing.T) {
+	doc := New("test", "1")
+
+	params := doc.QueryParameters(testQuery{})
+	assert.Equal(t, []Parameter{
+		{Name: "format", In: "query", Required: true, Schema: &Schema{Type: "string"}},
+		{Name: "dry_run", In: "query", Schema: &Schema{Type: "boolean"}},
+	}, params)
+}
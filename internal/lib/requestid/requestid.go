@@ -0,0 +1,49 @@
+package requestid
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// Header - заголовок HTTP и ключ метаданных gRPC с идентификатором запроса
+const Header = "X-Request-ID"
+
+const maxLength = 128
+
+// New создает случайный идентификатор запроса
+func New() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+
+	return hex.EncodeToString(b)
+}
+
+// Valid проверяет идентификатор, пришедший от клиента: он попадает в логи и заголовки ответа,
+// поэтому допускаются только латинские буквы, цифры и -_.:
+func Valid(id string) bool {
+	if id == "" || len(id) > maxLength {
+		return false
+	}
+
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '-', r == '_', r == '.', r == ':':
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+// FromClient возвращает идентификатор клиента, если он допустим, иначе новый
+func FromClient(id string) string {
+	if Valid(id) {
+		return id
+	}
+
+	return New()
+}
@@ -0,0 +1,35 @@
+package requestid
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromClient(t *testing.T) {
+	tests := []struct {
+		name string
+		id   string
+		kept bool
+	}{
+		{name: "UUID", id: "3f1c7a52-9d4e-4b7a-a1f0-6c2b9e8d1a47", kept: true},
+		{name: "Dotted", id: "lb-1.req_42:7", kept: true},
+		{name: "Empty"},
+		{name: "Spaces", id: "req 1"},
+		{name: "Newline", id: "req\nlevel=ERROR"},
+		{name: "Too Long", id: strings.Repeat("a", maxLength+1)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			id := FromClient(test.id)
+			if test.kept {
+				assert.Equal(t, test.id, id)
+				return
+			}
+			assert.NotEqual(t, test.id, id)
+			assert.True(t, Valid(id))
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package schedule
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+var ErrNeverRuns = errors.New("schedule never runs")
+
+// Schedule - расписание запусков. Next возвращает время следующего запуска после after,
+// нулевое время - если запусков больше не будет.
+type Schedule interface {
+	Next(after time.Time) time.Time
+}
+
+// interval - запуск через равные промежутки со случайной добавкой до jitter,
+// чтобы реплики не обращались к источнику одновременно
+type interval struct {
+	every  time.Duration
+	jitter time.Duration
+	rand   func(n int64) int64
+}
+
+// Every возвращает расписание с интервалом every и случайной задержкой до jitter
+func Every(every, jitter time.Duration) Schedule {
+	return &interval{
+		every:  every,
+		jitter: jitter,
+		rand:   rand.Int64N,
+	}
+}
+
+func (s *interval) Next(after time.Time) time.Time {
+	next := after.Add(s.every)
+	if s.jitter > 0 {
+		next = next.Add(time.Duration(s.rand(int64(s.jitter))))
+	}
+
+	return next
+}
+
+// Cron разбирает расписание в формате cron из пяти полей (минута, час, день месяца, месяц,
+// день недели) или описатель вида @hourly, @daily, @every 1h. Время считается в UTC.
+func Cron(expr string) (Schedule, error) {
+	spec, err := cron.ParseStandard(expr)
+	if err != nil {
+		return nil, fmt.Errorf("cron %q: %w", expr, err)
+	}
+
+	s := cronSchedule{spec}
+	if s.Next(time.Now()).IsZero() {
+		return nil, fmt.Errorf("cron %q: %w", expr, ErrNeverRuns)
+	}
+
+	return s, nil
+}
+
+type cronSchedule struct {
+	spec cron.Schedule
+}
+
+func (s cronSchedule) Next(after time.Time) time.Time {
+	return s.spec.Next(after.UTC())
+}
+
+// Run выполняет job сразу, а затем по расписанию s, пока не отменен ctx.
+// Запуски не перекрываются: следующий планируется от окончания предыдущего,
+// а пропущенные за время долгого запуска моменты расписания не догоняются.
+func Run(ctx context.Context, clock Clock, s Schedule, job func(ctx context.Context)) {
+	for ctx.Err() == nil {
+		job(ctx)
+
+		now := clock.Now()
+		next := s.Next(now)
+		if next.IsZero() {
+			<-ctx.Done()
+			return
+		}
+
+		timer := clock.NewTimer(next.Sub(now))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C():
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package schedule
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var start = time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+func TestEvery(t *testing.T) {
+	s := Every(time.Hour, 10*time.Minute).(*interval)
+	s.rand = func(n int64) int64 { return n / 2 }
+
+	assert.Equal(t, start.Add(time.Hour+5*time.Minute), s.Next(start))
+	assert.Equal(t, start.Add(time.Hour), Every(time.Hour, 0).Next(start))
+}
+
+func TestCron(t *testing.T) {
+	tests := []struct {
+		name         string
+		expr         string
+		after        time.Time
+		expectedNext time.Time
+		expectedErr  bool
+	}{
+		{
+			name:         "EveryQuarter",
+			expr:         "*/15 * * * *",
+			after:        start.Add(time.Minute),
+			expectedNext: start.Add(15 * time.Minute),
+		},
+		{
+			name:         "Daily",
+			expr:         "@daily",
+			after:        start,
+			expectedNext: time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:         "ConvertsToUTC",
+			expr:         "0 12 * * *",
+			after:        start.In(time.FixedZone("UTC+3", 3*60*60)),
+			expectedNext: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+		},
+		{
+			name:        "Invalid",
+			expr:        "every hour",
+			expectedErr: true,
+		},
+		{
+			name:        "NeverRuns",
+			expr:        "0 0 30 2 *",
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s, err := Cron(test.expr)
+			if test.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.True(t, test.expectedNext.Equal(s.Next(test.after)), s.Next(test.after))
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	clock := NewFakeClock(start)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	runs := make(chan time.Time)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		Run(ctx, clock, Every(time.Hour, 0), func(ctx context.Context) {
+			runs <- clock.Now()
+		})
+	}()
+
+	// первый запуск - сразу, без ожидания интервала
+	assert.Equal(t, start, <-runs)
+
+	clock.BlockUntil(1)
+	clock.Advance(59 * time.Minute)
+	select {
+	case <-runs:
+		t.Fatal("job ran before the interval elapsed")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	clock.Advance(time.Minute)
+	assert.Equal(t, start.Add(time.Hour), <-runs)
+
+	clock.BlockUntil(1)
+	cancel()
+	<-done
+}
+
+func TestRunNoOverlap(t *testing.T) {
+	clock := NewFakeClock(start)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	runs := make(chan time.Time)
+	go Run(ctx, clock, Every(time.Hour, 0), func(ctx context.Context) {
+		runs <- clock.Now()
+		// запуск длиннее интервала: следующий планируется от его окончания
+		clock.Advance(3 * time.Hour)
+	})
+
+	assert.Equal(t, start, <-runs)
+
+	clock.BlockUntil(1)
+	clock.Advance(time.Hour)
+	assert.Equal(t, start.Add(4*time.Hour), <-runs)
+}
+
+func TestRunCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	Run(ctx, NewFakeClock(start), Every(time.Hour, 0), func(ctx context.Context) {
+		t.Fatal("job ran after cancel")
+	})
+}
@@ -0,0 +1,39 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+func TestSetupStdout(t *testing.T) {
+	var out bytes.Buffer
+
+	shutdown, err := Setup(context.Background(), Config{ServiceName: "goapi-test", Exporter: ExporterStdout, SampleRatio: 1}, &out)
+	require.NoError(t, err)
+
+	ctx, span := otel.Tracer("test").Start(context.Background(), "test-span")
+	carrier := propagation.HeaderCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+	span.End()
+
+	assert.Contains(t, carrier.Get("traceparent"), span.SpanContext().TraceID().String())
+
+	require.NoError(t, shutdown(context.Background()))
+	assert.Contains(t, out.String(), "test-span")
+	assert.Contains(t, out.String(), "goapi-test")
+}
+
+func TestSetupExporters(t *testing.T) {
+	shutdown, err := Setup(context.Background(), Config{Exporter: ExporterNone}, nil)
+	require.NoError(t, err)
+	assert.NoError(t, shutdown(context.Background()))
+
+	_, err = Setup(context.Background(), Config{Exporter: "zipkin"}, nil)
+	assert.ErrorIs(t, err, ErrUnknownExporter)
+}
@@ -0,0 +1,184 @@
+package validate
+
+import (
+	"fmt"
+	"goapi/internal/lib/apperr"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+const (
+	MaxNameLength         = 255
+	MaxPasswordLength     = 72
+	MinPasswordLength     = 8
+	MaxProductCategoryies = 20
+	MaxProductBatch       = 500
+	MaxSKULength          = 64
+	MaxImportRows         = 10000
+	MaxURLLength          = 2048
+	MaxWebhookEventTypes  = 20
+	MinWebhookSecret      = 16
+	MaxSearchResults      = 100
+)
+
+// ErrValidation - общая ошибка валидации, нарушения перечисляются в Fields
+var ErrValidation = apperr.Validation("validation_failed", "request validation failed")
+
+// Правила, общие для обработчиков и сервисов. Используются в тегах validate по имени.
+var aliases = map[string]string{
+	"entity_id":      "required,gt=0",
+	"email_address":  fmt.Sprintf("required,email,max=%d", MaxNameLength),
+	"password":       fmt.Sprintf("required,max=%d", MaxPasswordLength),
+	"new_password":   fmt.Sprintf("required,min=%d,max=%d", MinPasswordLength, MaxPasswordLength),
+	"product_name":   fmt.Sprintf("required,max=%d,catalog_name", MaxNameLength),
+	"category_name":  fmt.Sprintf("required,max=%d,catalog_name", MaxNameLength),
+	"category_names": fmt.Sprintf("required,min=1,max=%d,unique,dive,required,max=%d,catalog_name", MaxProductCategoryies, MaxNameLength),
+	"category_list":  fmt.Sprintf("max=%d,unique,dive", MaxProductCategoryies),
+	"product_batch":  fmt.Sprintf("required,min=1,max=%d", MaxProductBatch),
+	"sku":            fmt.Sprintf("required,max=%d,sku_code", MaxSKULength),
+	"webhook_url":    fmt.Sprintf("required,max=%d,http_url", MaxURLLength),
+	"event_types":    fmt.Sprintf("required,min=1,max=%d,unique,dive,required,max=%d", MaxWebhookEventTypes, MaxNameLength),
+	"webhook_secret": fmt.Sprintf("omitempty,min=%d,max=%d", MinWebhookSecret, MaxNameLength),
+	"search_query":   fmt.Sprintf("required,max=%d", MaxNameLength),
+	"search_limit":   fmt.Sprintf("min=1,max=%d", MaxSearchResults),
+}
+
+// catalogNameRegexp - допустимые символы в названиях товаров и категорий
+var catalogNameRegexp = regexp.MustCompile(`^[\p{L}\p{N} \-_.,:;!?&'"()/+#%]+$`)
+
+// skuRegexp - артикул товара: латиница, цифры и разделители . _ -
+var skuRegexp = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+var v = newValidator()
+
+func newValidator() *validator.Validate {
+	res := validator.New()
+
+	res.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		if name == "" {
+			return field.Name
+		}
+
+		return name
+	})
+
+	_ = res.RegisterValidation("catalog_name", func(fl validator.FieldLevel) bool {
+		return catalogNameRegexp.MatchString(fl.Field().String())
+	})
+
+	_ = res.RegisterValidation("sku_code", func(fl validator.FieldLevel) bool {
+		return skuRegexp.MatchString(fl.Field().String())
+	})
+
+	for alias, tags := range aliases {
+		res.RegisterAlias(alias, tags)
+	}
+
+	return res
+}
+
+// Struct проверяет структуру по тегам validate и возвращает все нарушения сразу
+func Struct(s any) error {
+	err := v.Struct(s)
+	if err == nil {
+		return nil
+	}
+
+	errs, ok := err.(validator.ValidationErrors)
+	if !ok {
+		return err
+	}
+
+	fields := make([]apperr.FieldError, 0, len(errs))
+	for _, fe := range errs {
+		fields = append(fields, apperr.FieldError{
+			Field:   pointer(fe.Namespace()),
+			Code:    fe.ActualTag(),
+			Message: message(fe),
+		})
+	}
+
+	return ErrValidation.WithFields(fields...)
+}
+
+// pointer переводит путь валидатора (product.categoryies[1]) в JSON pointer (/categoryies/1)
+func pointer(namespace string) string {
+	parts := strings.Split(namespace, ".")[1:]
+
+	var b strings.Builder
+	for _, part := range parts {
+		name, rest, _ := strings.Cut(part, "[")
+		b.WriteString("/" + escape(name))
+
+		for rest != "" {
+			var idx string
+			idx, rest, _ = strings.Cut(rest, "]")
+			b.WriteString("/" + escape(idx))
+			rest = strings.TrimPrefix(rest, "[")
+		}
+	}
+
+	return b.String()
+}
+
+func escape(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
+
+func message(fe validator.FieldError) string {
+	switch fe.ActualTag() {
+	case "required", "required_without":
+		return "is required"
+	case "gt":
+		return "must be greater than " + fe.Param()
+	case "min":
+		return bound("at least", fe)
+	case "max":
+		return bound("at most", fe)
+	case "email":
+		return "must be a valid email address"
+	case "http_url":
+		return "must be an http or https URL"
+	case "unique":
+		return "must contain unique items"
+	case "catalog_name", "sku_code":
+		return "contains forbidden characters"
+	default:
+		return "failed on the '" + fe.ActualTag() + "' rule"
+	}
+}
+
+func bound(limit string, fe validator.FieldError) string {
+	switch fe.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return "must have " + limit + " " + fe.Param() + " items"
+	case reflect.String:
+		return "must have " + limit + " " + fe.Param() + " characters"
+	default:
+		return "must be " + limit + " " + fe.Param()
+	}
+}
+
+// IsRequired сообщает, делает ли тег validate (с учетом псевдонимов) поле обязательным
+func IsRequired(tag string) bool {
+	for _, t := range strings.Split(tag, ",") {
+		if t == "dive" {
+			return false
+		}
+		if t == "required" {
+			return true
+		}
+		if alias, ok := aliases[t]; ok && IsRequired(alias) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,97 @@
+package validate
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"goapi/internal/lib/apperr"
+)
+
+type testCategory struct {
+	ID   int64  `json:"id" validate:"gte=0"`
+	Name string `json:"name" validate:"required_without=ID,omitempty,category_name"`
+}
+
+type testProduct struct {
+	Name        string         `json:"name" validate:"product_name"`
+	Categoryies []string       `json:"categoryies" validate:"category_names"`
+	Links       []testCategory `json:"links" validate:"category_list"`
+}
+
+func TestStructOk(t *testing.T) {
+	err := Struct(testProduct{
+		Name:        "Cat food (5 kg)",
+		Categoryies: []string{"Pets", "Food"},
+		Links:       []testCategory{{ID: 1}, {Name: "Pets"}},
+	})
+	assert.NoError(t, err)
+}
+
+func TestStructViolations(t *testing.T) {
+	err := Struct(testProduct{
+		Name:        strings.Repeat("a", MaxNameLength+1),
+		Categoryies: []string{"Pets", "Food", "<script>"},
+		Links:       []testCategory{{}},
+	})
+
+	assert.ErrorIs(t, err, ErrValidation)
+
+	appErr, ok := apperr.As(err)
+	assert.True(t, ok)
+	assert.Equal(t, []apperr.FieldError{
+		{Field: "/name", Code: "max", Message: "must have at most 255 characters"},
+		{Field: "/categoryies/2", Code: "catalog_name", Message: "contains forbidden characters"},
+		{Field: "/links/0/name", Code: "required_without", Message: "is required"},
+	}, appErr.Fields)
+}
+
+func TestStructUniqueCategoryies(t *testing.T) {
+	err := Struct(testProduct{Name: "Product", Categoryies: []string{"Pets", "Pets"}})
+
+	appErr, ok := apperr.As(err)
+	assert.True(t, ok)
+	assert.Equal(t, []apperr.FieldError{
+		{Field: "/categoryies", Code: "unique", Message: "must contain unique items"},
+	}, appErr.Fields)
+}
+
+func TestStructCategoryCount(t *testing.T) {
+	categoryies := make([]string, MaxProductCategoryies+1)
+	for i := range categoryies {
+		categoryies[i] = "Category " + string(rune('A'+i))
+	}
+
+	err := Struct(testProduct{Name: "Product", Categoryies: categoryies})
+
+	appErr, ok := apperr.As(err)
+	assert.True(t, ok)
+	assert.Equal(t, []apperr.FieldError{
+		{Field: "/categoryies", Code: "max", Message: "must have at most 20 items"},
+	}, appErr.Fields)
+}
+
+func TestPointer(t *testing.T) {
+	assert.Equal(t, "/products/3/name", pointer("input.products[3].name"))
+	assert.Equal(t, "/a~1b", pointer("input.a/b"))
+}
+
+type testWebhook struct {
+	URL        string   `json:"url" validate:"webhook_url"`
+	EventTypes []string `json:"eventTypes" validate:"event_types"`
+	Secret     string   `json:"secret" validate:"webhook_secret"`
+}
+
+func TestStructWebhook(t *testing.T) {
+	assert.NoError(t, Struct(testWebhook{URL: "https://partner.example/hooks", EventTypes: []string{"product.created"}}))
+
+	err := Struct(testWebhook{URL: "ftp://partner.example", EventTypes: []string{}, Secret: "short"})
+
+	appErr, ok := apperr.As(err)
+	assert.True(t, ok)
+	assert.Equal(t, []apperr.FieldError{
+		{Field: "/url", Code: "http_url", Message: "must be an http or https URL"},
+		{Field: "/eventTypes", Code: "min", Message: "must have at least 1 items"},
+		{Field: "/secret", Code: "min", Message: "must have at least 16 characters"},
+	}, appErr.Fields)
+}
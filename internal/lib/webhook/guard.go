@@ -0,0 +1,110 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+var (
+	ErrForbiddenAddress = errors.New("webhook receiver address is not public")
+)
+
+// Guard не пускает доставки во внутреннюю сеть: на loopback, частные, link-local,
+// CGNAT, multicast и неуказанные адреса. При создании подписки проверяются все адреса ее хоста,
+// при отправке - адрес каждого соединения (см. Control), поэтому имя, которое
+// после проверки стало указывать во внутреннюю сеть, тоже не пройдет.
+type Guard struct {
+	resolver *net.Resolver
+}
+
+func NewGuard(resolver *net.Resolver) *Guard {
+	return &Guard{resolver: resolver}
+}
+
+// CheckURL разрешает хост адреса получателя и проверяет все его адреса
+func (g *Guard) CheckURL(ctx context.Context, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+
+	addrs, err := g.resolver.LookupNetIP(ctx, "ip", u.Hostname())
+	if err != nil {
+		return err
+	}
+
+	for _, addr := range addrs {
+		if !isPublic(addr) {
+			return fmt.Errorf("%w: %s", ErrForbiddenAddress, addr)
+		}
+	}
+
+	return nil
+}
+
+// Control - проверка адреса перед соединением для net.Dialer
+func Control(network, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return err
+	}
+
+	if !isPublic(addrPort.Addr()) {
+		return fmt.Errorf("%w: %s", ErrForbiddenAddress, addrPort.Addr())
+	}
+
+	return nil
+}
+
+// NewHTTPClient возвращает клиент доставок, который соединяется только с публичными адресами.
+// Прокси из окружения не используется: с ним проверялся бы адрес прокси, а не получателя.
+func NewHTTPClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: Control,
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+	}
+}
+
+// nonPublicPrefixes - внутренние диапазоны, которые не покрывают методы netip.Addr
+var nonPublicPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),     // "эта сеть"
+	netip.MustParsePrefix("100.64.0.0/10"), // CGNAT, провайдерская внутренняя сеть
+	netip.MustParsePrefix("198.18.0.0/15"), // стенды для тестов производительности
+}
+
+func isPublic(addr netip.Addr) bool {
+	addr = addr.Unmap()
+
+	if !addr.IsValid() ||
+		addr.IsLoopback() ||
+		addr.IsPrivate() ||
+		addr.IsLinkLocalUnicast() ||
+		addr.IsMulticast() ||
+		addr.IsUnspecified() {
+		return false
+	}
+
+	for _, prefix := range nonPublicPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,66 @@
+package webhook
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGuardCheckURL(t *testing.T) {
+	tests := []struct {
+		name          string
+		url           string
+		expectedError error
+	}{
+		{name: "Public", url: "https://93.184.216.34/hook"},
+		{name: "Public IPv6", url: "https://[2606:2800:220:1:248:1893:25c8:1946]/hook"},
+		{name: "Loopback", url: "http://127.0.0.1:8080/hook", expectedError: ErrForbiddenAddress},
+		{name: "Loopback IPv6", url: "http://[::1]/hook", expectedError: ErrForbiddenAddress},
+		{name: "Mapped Loopback", url: "http://[::ffff:127.0.0.1]/hook", expectedError: ErrForbiddenAddress},
+		{name: "Private", url: "http://10.0.0.5/hook", expectedError: ErrForbiddenAddress},
+		{name: "Private IPv6", url: "http://[fd00::1]/hook", expectedError: ErrForbiddenAddress},
+		{name: "Link Local", url: "http://169.254.169.254/latest/meta-data", expectedError: ErrForbiddenAddress},
+		{name: "Unspecified", url: "http://0.0.0.0/hook", expectedError: ErrForbiddenAddress},
+		{name: "This Network", url: "http://0.1.2.3/hook", expectedError: ErrForbiddenAddress},
+		{name: "CGNAT", url: "http://100.64.0.1/hook", expectedError: ErrForbiddenAddress},
+		{name: "CGNAT Upper", url: "http://100.127.255.254/hook", expectedError: ErrForbiddenAddress},
+		{name: "Above CGNAT", url: "https://100.128.0.1/hook"},
+		{name: "Benchmarking", url: "http://198.19.0.1/hook", expectedError: ErrForbiddenAddress},
+		{name: "Mapped CGNAT", url: "http://[::ffff:100.64.0.1]/hook", expectedError: ErrForbiddenAddress},
+		{name: "Global Multicast", url: "http://233.252.0.1/hook", expectedError: ErrForbiddenAddress},
+		{name: "Global Multicast IPv6", url: "http://[ff0e::1]/hook", expectedError: ErrForbiddenAddress},
+	}
+
+	guard := NewGuard(net.DefaultResolver)
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := guard.CheckURL(context.Background(), test.url)
+			if test.expectedError == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, test.expectedError)
+		})
+	}
+}
+
+func TestNewHTTPClientRefusesLoopback(t *testing.T) {
+	var called bool
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer receiver.Close()
+
+	client := NewClient(NewHTTPClient(time.Second))
+
+	resp, err := client.Send(context.Background(), Request{URL: receiver.URL, Secret: "secret", Body: []byte(`{}`)})
+	assert.ErrorIs(t, err, ErrForbiddenAddress)
+	assert.Zero(t, resp.Code)
+	assert.False(t, called)
+}
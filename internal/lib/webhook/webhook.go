@@ -0,0 +1,138 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Заголовки подписанной доставки. Подпись - HMAC-SHA256 от строки "<timestamp>.<тело>"
+// в виде "sha256=<hex>", метка времени - секунды Unix.
+const (
+	HeaderSignature = "X-Webhook-Signature"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderDelivery  = "X-Webhook-Delivery"
+
+	signaturePrefix = "sha256="
+	maxResponseBody = 64 << 10
+)
+
+var (
+	ErrMissingSignature = errors.New("webhook signature is missing")
+	ErrInvalidSignature = errors.New("webhook signature is invalid")
+	ErrExpiredTimestamp = errors.New("webhook timestamp is outside the tolerance")
+)
+
+// Sign подписывает тело доставки секретом подписки
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify проверяет подпись доставки на стороне получателя.
+// Метка времени должна отличаться от now не больше чем на tolerance, чтобы доставку нельзя было переиграть.
+func Verify(secret string, header http.Header, body []byte, tolerance time.Duration, now time.Time) error {
+	signature := header.Get(HeaderSignature)
+	if signature == "" || header.Get(HeaderTimestamp) == "" {
+		return ErrMissingSignature
+	}
+
+	timestamp, err := strconv.ParseInt(header.Get(HeaderTimestamp), 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+
+	if d := now.Sub(time.Unix(timestamp, 0)); d > tolerance || d < -tolerance {
+		return ErrExpiredTimestamp
+	}
+
+	if !hmac.Equal([]byte(signature), []byte(Sign(secret, timestamp, body))) {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
+
+// NewSecret создает случайный секрет подписки
+func NewSecret() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return "whsec_" + hex.EncodeToString(b), nil
+}
+
+// Request - одна доставка события
+type Request struct {
+	URL        string
+	Secret     string
+	DeliveryID int64
+	EventType  string
+	Body       []byte
+}
+
+// Response - результат попытки доставки. Code равен 0, если ответ не получен.
+type Response struct {
+	Code     int
+	Duration time.Duration
+}
+
+// Client отправляет подписанные доставки
+type Client struct {
+	http *http.Client
+	now  func() time.Time
+}
+
+func NewClient(client *http.Client) *Client {
+	return &Client{
+		http: client,
+		now:  time.Now,
+	}
+}
+
+// Send отправляет доставку. Ошибка возвращается и для ответа не из диапазона 2xx.
+func (c *Client) Send(ctx context.Context, r Request) (Response, error) {
+	start := c.now()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.URL, bytes.NewReader(r.Body))
+	if err != nil {
+		return Response{}, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, r.EventType)
+	req.Header.Set(HeaderDelivery, strconv.FormatInt(r.DeliveryID, 10))
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(start.Unix(), 10))
+	req.Header.Set(HeaderSignature, Sign(r.Secret, start.Unix(), r.Body))
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return Response{Duration: time.Since(start)}, err
+	}
+	defer resp.Body.Close()
+
+	// тело читается, чтобы соединение вернулось в пул
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxResponseBody))
+
+	res := Response{Code: resp.StatusCode, Duration: time.Since(start)}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return res, errors.New("receiver responded with " + strings.TrimSpace(resp.Status))
+	}
+
+	return res, nil
+}
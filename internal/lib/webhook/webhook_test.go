@@ -0,0 +1,76 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerify(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	body := []byte(`{"id":1}`)
+
+	signed := func(secret string, ts time.Time) http.Header {
+		h := http.Header{}
+		h.Set(HeaderTimestamp, strconv.FormatInt(ts.Unix(), 10))
+		h.Set(HeaderSignature, Sign(secret, ts.Unix(), body))
+		return h
+	}
+
+	tests := []struct {
+		name          string
+		header        http.Header
+		expectedError error
+	}{
+		{name: "Ok", header: signed("secret", now)},
+		{name: "Missing", header: http.Header{}, expectedError: ErrMissingSignature},
+		{name: "Wrong Secret", header: signed("other", now), expectedError: ErrInvalidSignature},
+		{name: "Expired", header: signed("secret", now.Add(-10000*time.Second)), expectedError: ErrExpiredTimestamp},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := Verify("secret", test.header, body, 5*time.Minute, now)
+			assert.ErrorIs(t, err, test.expectedError)
+		})
+	}
+}
+
+func TestClientSend(t *testing.T) {
+	var received http.Header
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received = r.Header.Clone()
+
+		if err := Verify("secret", r.Header, body, time.Minute, time.Now()); err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+
+	client := NewClient(receiver.Client())
+
+	resp, err := client.Send(context.Background(), Request{
+		URL:        receiver.URL,
+		Secret:     "secret",
+		DeliveryID: 42,
+		EventType:  "product.created",
+		Body:       []byte(`{"id":1}`),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, resp.Code)
+	assert.Equal(t, "42", received.Get(HeaderDelivery))
+	assert.Equal(t, "product.created", received.Get(HeaderEvent))
+
+	resp, err = client.Send(context.Background(), Request{URL: receiver.URL, Secret: "wrong", Body: []byte(`{}`)})
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+}
@@ -1,6 +1,13 @@
 package model
 
 type Category struct {
-	ID   int    `json:"id" db:"id"`
-	Name string `json:"name" db:"name" binding:"required" `
+	ID      int    `json:"id" db:"id" validate:"gte=0"`
+	Name    string `json:"name" db:"name" validate:"required_without=ID,omitempty,category_name"`
+	Version int64  `json:"version,omitempty" db:"version"`
+}
+
+// CategorySummary - категория с числом товаров в ней
+type CategorySummary struct {
+	Category
+	ProductCount int64 `json:"productCount" db:"product_count"`
 }
@@ -0,0 +1,39 @@
+package model
+
+type ImportStatus string
+
+const (
+	ImportCreated ImportStatus = "created"
+	ImportUpdated ImportStatus = "updated"
+	ImportFailed  ImportStatus = "failed"
+)
+
+// ImportRow - строка импортируемого каталога, товар ищется по артикулу
+type ImportRow struct {
+	Line        int      `json:"-"`
+	SKU         string   `json:"sku" validate:"sku"`
+	Name        string   `json:"name" validate:"product_name"`
+	Categoryies []string `json:"categoryies" validate:"category_names"`
+}
+
+// ImportResult - результат импорта одной строки. Renamed и Recategorized отмечают,
+// что обновление изменило имя или категории товара.
+type ImportResult struct {
+	Line          int
+	SKU           string
+	ID            int64
+	Status        ImportStatus
+	Renamed       bool
+	Recategorized bool
+	Err           error
+}
+
+// ImportReport - итог импорта файла
+type ImportReport struct {
+	DryRun  bool
+	Total   int
+	Created int
+	Updated int
+	Failed  int
+	Rows    []ImportResult
+}
@@ -0,0 +1,100 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type EventType string
+
+const (
+	EventProductCreated            EventType = "product.created"
+	EventProductRenamed            EventType = "product.renamed"
+	EventProductCategoryiesChanged EventType = "product.categoryies_changed"
+	EventProductDeleted            EventType = "product.deleted"
+	EventCategoryCreated           EventType = "category.created"
+	EventCategoryRenamed           EventType = "category.renamed"
+	EventCategoryDeleted           EventType = "category.deleted"
+)
+
+// EventTypes - все типы событий каталога
+var EventTypes = []EventType{
+	EventProductCreated,
+	EventProductRenamed,
+	EventProductCategoryiesChanged,
+	EventProductDeleted,
+	EventCategoryCreated,
+	EventCategoryRenamed,
+	EventCategoryDeleted,
+}
+
+// DomainEvent - типизированное событие изменения каталога
+type DomainEvent interface {
+	EventType() EventType
+	AggregateID() int64
+}
+
+type ProductCreated struct {
+	ProductID   int64      `json:"productID"`
+	Name        string     `json:"name"`
+	Categoryies []Category `json:"categoryies"`
+}
+
+// ProductRenamed и ProductDeleted несут категории товара на момент изменения,
+// чтобы получатели могли фильтровать события по категории
+type ProductRenamed struct {
+	ProductID   int64      `json:"productID"`
+	Name        string     `json:"name"`
+	Categoryies []Category `json:"categoryies,omitempty"`
+}
+
+type ProductCategoryiesChanged struct {
+	ProductID   int64      `json:"productID"`
+	Categoryies []Category `json:"categoryies"`
+}
+
+type ProductDeleted struct {
+	ProductID   int64      `json:"productID"`
+	Categoryies []Category `json:"categoryies,omitempty"`
+}
+
+type CategoryCreated struct {
+	CategoryID int64  `json:"categoryID"`
+	Name       string `json:"name"`
+}
+
+type CategoryRenamed struct {
+	CategoryID int64  `json:"categoryID"`
+	Name       string `json:"name"`
+}
+
+type CategoryDeleted struct {
+	CategoryID int64  `json:"categoryID"`
+	Name       string `json:"name,omitempty"`
+}
+
+func (e ProductCreated) EventType() EventType            { return EventProductCreated }
+func (e ProductRenamed) EventType() EventType            { return EventProductRenamed }
+func (e ProductCategoryiesChanged) EventType() EventType { return EventProductCategoryiesChanged }
+func (e ProductDeleted) EventType() EventType            { return EventProductDeleted }
+func (e CategoryCreated) EventType() EventType           { return EventCategoryCreated }
+func (e CategoryRenamed) EventType() EventType           { return EventCategoryRenamed }
+func (e CategoryDeleted) EventType() EventType           { return EventCategoryDeleted }
+
+func (e ProductCreated) AggregateID() int64            { return e.ProductID }
+func (e ProductRenamed) AggregateID() int64            { return e.ProductID }
+func (e ProductCategoryiesChanged) AggregateID() int64 { return e.ProductID }
+func (e ProductDeleted) AggregateID() int64            { return e.ProductID }
+func (e CategoryCreated) AggregateID() int64           { return e.CategoryID }
+func (e CategoryRenamed) AggregateID() int64           { return e.CategoryID }
+func (e CategoryDeleted) AggregateID() int64           { return e.CategoryID }
+
+// Event - событие из outbox в том виде, в котором оно доставляется получателям
+type Event struct {
+	ID          int64           `json:"id" db:"id"`
+	Type        EventType       `json:"type" db:"type"`
+	AggregateID int64           `json:"aggregateID" db:"aggregate_id"`
+	Payload     json.RawMessage `json:"payload" db:"payload"`
+	CreatedAt   time.Time       `json:"createdAt" db:"created_at"`
+	Attempts    int             `json:"-" db:"attempts"`
+}
@@ -0,0 +1,10 @@
+package model
+
+// IdempotentResponse - ответ, сохраненный под ключом идемпотентности.
+// Нулевой Status означает, что запрос с этим ключом еще выполняется
+type IdempotentResponse struct {
+	Fingerprint string
+	Status      int
+	Header      map[string]string
+	Body        []byte
+}
@@ -2,6 +2,8 @@ package model
 
 type Product struct {
 	ID          int        `json:"id" db:"id"`
-	Name        string     `json:"name" db:"name" binding:"required"`
-	Categoryies []Category `json:"categoryies" binding:"required"`
+	SKU         string     `json:"sku,omitempty" db:"sku" validate:"omitempty,sku"`
+	Name        string     `json:"name" db:"name" validate:"product_name"`
+	Version     int64      `json:"version,omitempty" db:"version"`
+	Categoryies []Category `json:"categoryies" validate:"category_list"`
 }
@@ -0,0 +1,36 @@
+package model
+
+type ProductOperationType string
+
+const (
+	ProductOperationCreate ProductOperationType = "create"
+	ProductOperationUpdate ProductOperationType = "update"
+	ProductOperationDelete ProductOperationType = "delete"
+)
+
+type ProductOperationStatus string
+
+const (
+	ProductOperationOK         ProductOperationStatus = "ok"
+	ProductOperationFailed     ProductOperationStatus = "failed"
+	ProductOperationRolledBack ProductOperationStatus = "rolled_back"
+	ProductOperationSkipped    ProductOperationStatus = "skipped"
+)
+
+// ProductOperation - одна операция пакетного изменения товаров
+type ProductOperation struct {
+	Op          ProductOperationType `json:"op" validate:"required,oneof=create update delete"`
+	ID          int64                `json:"id,omitempty" validate:"required_unless=Op create,omitempty,gt=0"`
+	Name        string               `json:"name,omitempty" validate:"required_if=Op create,omitempty,product_name"`
+	Categoryies []string             `json:"categoryies,omitempty" validate:"required_if=Op create,omitempty,category_names"`
+	Version     int64                `json:"version,omitempty" validate:"omitempty,gt=0"`
+}
+
+// ProductOperationResult - результат выполнения операции с ее индексом в пакете
+type ProductOperationResult struct {
+	Index  int
+	Op     ProductOperationType
+	ID     int64
+	Status ProductOperationStatus
+	Err    error
+}
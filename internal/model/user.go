@@ -1,7 +1,21 @@
 package model
 
+// Role - роль пользователя
+type Role string
+
+const (
+	RoleUser  Role = "user"
+	RoleAdmin Role = "admin"
+)
+
+// Valid проверяет, что роль известна
+func (r Role) Valid() bool {
+	return r == RoleUser || r == RoleAdmin
+}
+
 type User struct {
 	ID       int    `json:"id" db:"id"`
-	Email    string `json:"email" db:"email" binding:"required"`
-	PassHash []byte `json:"password" db:"passhash" binding:"required"`
+	Email    string `json:"email" db:"email" validate:"email_address"`
+	PassHash []byte `json:"password" db:"passhash" validate:"required"`
+	Role     Role   `json:"role" db:"role"`
 }
@@ -0,0 +1,71 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// WebhookAllEvents - подписка на все события каталога
+const WebhookAllEvents = "*"
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliverySucceeded WebhookDeliveryStatus = "succeeded"
+	WebhookDeliveryFailed    WebhookDeliveryStatus = "failed"
+)
+
+// Webhook - подписка партнера на события каталога.
+// Секрет отдается только при создании подписки.
+type Webhook struct {
+	ID         int64     `json:"id" db:"id"`
+	UserID     int64     `json:"-" db:"user_id"`
+	URL        string    `json:"url" db:"url"`
+	EventTypes []string  `json:"eventTypes" db:"-"`
+	Secret     string    `json:"secret,omitempty" db:"secret"`
+	Active     bool      `json:"active" db:"active"`
+	Version    int64     `json:"version" db:"version"`
+	CreatedAt  time.Time `json:"createdAt" db:"created_at"`
+}
+
+// WebhookUpdate - изменение подписки, nil-поля не меняются
+type WebhookUpdate struct {
+	URL        *string
+	EventTypes []string
+	Secret     *string
+	Active     *bool
+}
+
+// WebhookDelivery - доставка одного события одной подписке
+type WebhookDelivery struct {
+	ID            int64                 `json:"id" db:"id"`
+	WebhookID     int64                 `json:"webhookID" db:"webhook_id"`
+	EventID       int64                 `json:"eventID" db:"event_id"`
+	EventType     EventType             `json:"eventType" db:"event_type"`
+	Status        WebhookDeliveryStatus `json:"status" db:"status"`
+	Attempts      int                   `json:"attempts" db:"attempts"`
+	ResponseCode  *int                  `json:"responseCode,omitempty" db:"response_code"`
+	NextAttemptAt *time.Time            `json:"nextAttemptAt,omitempty" db:"next_attempt_at"`
+	CreatedAt     time.Time             `json:"createdAt" db:"created_at"`
+	Log           []WebhookAttempt      `json:"log,omitempty" db:"-"`
+}
+
+// WebhookAttempt - запись журнала доставки: одна попытка отправки
+type WebhookAttempt struct {
+	ResponseCode *int      `json:"responseCode,omitempty" db:"response_code"`
+	Error        string    `json:"error,omitempty" db:"error"`
+	DurationMs   int64     `json:"durationMs" db:"duration_ms"`
+	AttemptedAt  time.Time `json:"attemptedAt" db:"attempted_at"`
+}
+
+// WebhookDispatch - доставка, готовая к отправке: вместе с адресом, секретом и телом события
+type WebhookDispatch struct {
+	DeliveryID int64           `db:"id"`
+	WebhookID  int64           `db:"webhook_id"`
+	URL        string          `db:"url"`
+	Secret     string          `db:"secret"`
+	Attempts   int             `db:"attempts"`
+	Event      json.RawMessage `db:"event"`
+	EventType  EventType       `db:"event_type"`
+}
@@ -6,10 +6,11 @@ var (
 	ErrUserExist    = errors.New("user already exist")
 	ErrUserNotFound = errors.New("user not found")
 
-	ErrCategoryExist  = errors.New("category already exist")
-	ErrCategoryDelete = errors.New("error deleting category")
-	ErrUpdateCategory = errors.New("error updating category name")
-	ErrAllCategoryies = errors.New("error getting categories from database")
+	ErrCategoryExist    = errors.New("category already exist")
+	ErrCategoryDelete   = errors.New("error deleting category")
+	ErrUpdateCategory   = errors.New("error updating category name")
+	ErrAllCategoryies   = errors.New("error getting categories from database")
+	ErrCategoryNotFound = errors.New("category not found")
 
 	ErrSaveProduct           = errors.New("product is not saved")
 	ErrDeleteProduct         = errors.New("error deleting a product")
@@ -17,4 +18,12 @@ var (
 	ErrUpdateProduct         = errors.New("error updating product name")
 	ErrSaveProductCategory   = errors.New("error save product category")
 	ErrProductNotFound       = errors.New("product not found")
+	ErrGetProducts           = errors.New("error getting products from database")
+
+	ErrWebhookNotFound         = errors.New("webhook not found")
+	ErrWebhookDeliveryNotFound = errors.New("webhook delivery not found")
+
+	ErrIdempotencyLeaseLost = errors.New("idempotency key lease is lost")
+
+	ErrVersionMismatch = errors.New("version mismatch")
 )
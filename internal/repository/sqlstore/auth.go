@@ -1,9 +1,10 @@
-package postgres
+package sqlstore
 
 import (
 	"context"
 	"fmt"
 	"github.com/jmoiron/sqlx"
+	"goapi/internal/lib/logger/logctx"
 	"goapi/internal/model"
 	"goapi/internal/repository"
 	"log/slog"
@@ -18,17 +19,19 @@ type AuthRepository struct {
 	log *slog.Logger
 }
 
-func NewAuthPostgres(db *sqlx.DB, l *slog.Logger) *AuthRepository {
+func NewAuthRepository(db *sqlx.DB, l *slog.Logger) *AuthRepository {
 	return &AuthRepository{
 		db:  db,
 		log: l,
 	}
 }
 
-func (a *AuthRepository) SaveUser(ctx context.Context, email string, passHash []byte) (int64, error) {
+func (a *AuthRepository) SaveUser(ctx context.Context, email string, passHash []byte, role model.Role) (int64, error) {
 	const op = "AuthRepository.SaveUser"
+	ctx, end := startQuery(ctx, op)
+	defer end()
 
-	log := a.log.With(
+	log := logctx.From(ctx, a.log).With(
 		slog.String("op", op),
 		slog.String("email", email),
 	)
@@ -38,11 +41,11 @@ func (a *AuthRepository) SaveUser(ctx context.Context, email string, passHash []
 	var id int64
 
 	query := fmt.Sprintf(
-		"INSERT INTO %s (email, passHash) VALUES ($1, $2) RETURNING id",
+		"INSERT INTO %s (email, passHash, role) VALUES ($1, $2, $3) RETURNING id",
 		usersTable,
 	)
 
-	row := a.db.QueryRow(query, email, passHash)
+	row := a.db.QueryRow(query, email, passHash, role)
 	if err := row.Scan(&id); err != nil {
 		log.Error("error insert user in db")
 		return id, repository.ErrUserExist
@@ -55,8 +58,10 @@ func (a *AuthRepository) SaveUser(ctx context.Context, email string, passHash []
 
 func (a *AuthRepository) User(ctx context.Context, email string) (model.User, error) {
 	const op = "AuthRepository.User"
+	ctx, end := startQuery(ctx, op)
+	defer end()
 
-	log := a.log.With(
+	log := logctx.From(ctx, a.log).With(
 		slog.String("op", op),
 		slog.String("email", email),
 	)
@@ -66,7 +71,7 @@ func (a *AuthRepository) User(ctx context.Context, email string) (model.User, er
 	var user model.User
 
 	query := fmt.Sprintf(
-		"SELECT id, email, passHash FROM %s WHERE email=$1",
+		"SELECT id, email, passHash, role FROM %s WHERE email=$1",
 		usersTable,
 	)
 
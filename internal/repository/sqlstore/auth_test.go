@@ -0,0 +1,51 @@
+package sqlstore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"goapi/internal/model"
+	"goapi/internal/repository"
+)
+
+func TestSaveUser(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, db *sqlx.DB) {
+		authRepo := NewAuthRepository(db, testLogger())
+
+		userID, err := authRepo.SaveUser(context.Background(), "test@example.com", []byte("password_hash"), model.RoleUser)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), userID)
+
+		user, err := authRepo.User(context.Background(), "test@example.com")
+		require.NoError(t, err)
+		assert.Equal(t, int(userID), user.ID)
+		assert.Equal(t, []byte("password_hash"), user.PassHash)
+		assert.Equal(t, model.RoleUser, user.Role)
+	})
+}
+
+func TestSaveUserError(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, db *sqlx.DB) {
+		authRepo := NewAuthRepository(db, testLogger())
+
+		_, err := authRepo.SaveUser(context.Background(), "test@example.com", []byte("password_hash"), model.RoleUser)
+		require.NoError(t, err)
+
+		userID, err := authRepo.SaveUser(context.Background(), "test@example.com", []byte("other_hash"), model.RoleAdmin)
+		assert.Equal(t, repository.ErrUserExist, err)
+		assert.Equal(t, int64(0), userID)
+	})
+}
+
+func TestUserNotFound(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, db *sqlx.DB) {
+		authRepo := NewAuthRepository(db, testLogger())
+
+		user, err := authRepo.User(context.Background(), "test@example.com")
+		assert.Equal(t, repository.ErrUserNotFound, err)
+		assert.Equal(t, model.User{}, user)
+	})
+}
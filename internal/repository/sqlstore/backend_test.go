@@ -0,0 +1,483 @@
+package sqlstore
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"goapi/internal/model"
+	"goapi/internal/repository"
+)
+
+// postgresDSNEnv - строка подключения к Postgres для прогона сценариев. Без нее сценарии
+// выполняются только на SQLite, которому не нужны внешние сервисы; в CI она задана всегда.
+const postgresDSNEnv = "TEST_POSTGRES_DSN"
+
+// backend - база, на которой прогоняются общие сценарии репозиториев
+type backend struct {
+	name string
+	open func(t *testing.T) *sqlx.DB
+}
+
+func backends() []backend {
+	list := []backend{{name: DriverSQLite, open: openSQLite}}
+	if os.Getenv(postgresDSNEnv) != "" {
+		list = append(list, backend{name: DriverPostgres, open: openPostgres})
+	}
+
+	return list
+}
+
+// forEachBackend выполняет сценарий на каждой базе со схемой из встроенных миграций
+func forEachBackend(t *testing.T, fn func(t *testing.T, db *sqlx.DB)) {
+	forEachEmptyBackend(t, func(t *testing.T, db *sqlx.DB) {
+		require.NoError(t, NewMigrator(db, testLogger()).Up(context.Background()))
+		fn(t, db)
+	})
+}
+
+// forEachEmptyBackend выполняет сценарий на каждой пустой базе
+func forEachEmptyBackend(t *testing.T, fn func(t *testing.T, db *sqlx.DB)) {
+	for _, b := range backends() {
+		t.Run(b.name, func(t *testing.T) {
+			fn(t, b.open(t))
+		})
+	}
+}
+
+func openSQLite(t *testing.T) *sqlx.DB {
+	db, err := NewSQLiteDB(filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	return db
+}
+
+// openPostgres создает для теста отдельную схему и удаляет ее после теста
+func openPostgres(t *testing.T) *sqlx.DB {
+	dsn := os.Getenv(postgresDSNEnv)
+	schema := fmt.Sprintf("test_%d", time.Now().UnixNano())
+
+	admin, err := sqlx.Open(DriverPostgres, dsn)
+	require.NoError(t, err)
+	t.Cleanup(func() { admin.Close() })
+
+	_, err = admin.Exec("CREATE SCHEMA " + schema)
+	require.NoError(t, err)
+	t.Cleanup(func() { admin.Exec("DROP SCHEMA " + schema + " CASCADE") })
+
+	if strings.Contains(dsn, "://") {
+		sep := "?"
+		if strings.Contains(dsn, "?") {
+			sep = "&"
+		}
+		dsn += sep + "search_path=" + schema
+	} else {
+		dsn += " search_path=" + schema
+	}
+
+	db, err := sqlx.Open(DriverPostgres, dsn)
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	return db
+}
+
+func testLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
+}
+
+func TestBackendProducts(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, db *sqlx.DB) {
+		ctx := context.Background()
+		categoryRepo := NewCategoryRepository(db, testLogger())
+		productRepo := NewProductRepository(db, testLogger())
+
+		phones, err := categoryRepo.AddCategory(ctx, "Phones")
+		require.NoError(t, err)
+		sale, err := categoryRepo.AddCategory(ctx, "Sale")
+		require.NoError(t, err)
+		_, err = categoryRepo.AddCategory(ctx, "Empty")
+		require.NoError(t, err)
+
+		_, err = categoryRepo.AddCategory(ctx, "Phones")
+		assert.ErrorIs(t, err, repository.ErrCategoryExist)
+
+		phoneID, err := productRepo.AddProduct(ctx, "Phone 50%", []string{"Sale", "Phones"})
+		require.NoError(t, err)
+		caseID, err := productRepo.AddProduct(ctx, "Phone case", []string{"Phones"})
+		require.NoError(t, err)
+		_, err = productRepo.AddProduct(ctx, "Phone 500", nil)
+		require.NoError(t, err)
+
+		_, err = productRepo.AddProduct(ctx, "Tablet", []string{"Tablets"})
+		assert.ErrorIs(t, err, repository.ErrCategoryNotFound)
+
+		product, err := productRepo.GetProduct(ctx, phoneID)
+		require.NoError(t, err)
+		assert.Equal(t, "Phone 50%", product.Name)
+		assert.Equal(t, int64(1), product.Version)
+		assert.Equal(t, []string{"Phones", "Sale"}, categoryNames(product.Categoryies))
+
+		version, err := productRepo.UpdateProductName(ctx, caseID, "Phone cover", 1)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), version)
+
+		_, err = productRepo.UpdateProductName(ctx, caseID, "Phone sleeve", 1)
+		assert.ErrorIs(t, err, repository.ErrVersionMismatch)
+
+		version, err = productRepo.UpdateProductCategoryies(ctx, caseID, []model.Category{{ID: int(sale)}}, version)
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), version)
+
+		// операции пакета тоже проверяют версию
+		results, err := productRepo.BatchProducts(ctx, []model.ProductOperation{
+			{Op: model.ProductOperationUpdate, ID: caseID, Name: "Phone cover", Version: 1},
+			{Op: model.ProductOperationUpdate, ID: caseID, Name: "Phone cover", Version: version},
+		}, false)
+		require.NoError(t, err)
+		assert.ErrorIs(t, results[0].Err, repository.ErrVersionMismatch)
+		assert.Equal(t, model.ProductOperationOK, results[1].Status)
+
+		// спецсимволы LIKE ищутся буквально, регистр не важен
+		found, err := productRepo.SearchProducts(ctx, "PHONE 50%", 10)
+		require.NoError(t, err)
+		if assert.Len(t, found, 1) {
+			assert.Equal(t, int(phoneID), found[0].ID)
+		}
+
+		categoryies, err := productRepo.GetProductsCategoryies(ctx, []int64{phoneID, caseID})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Phones", "Sale"}, categoryNames(categoryies[phoneID]))
+		assert.Equal(t, []string{"Sale"}, categoryNames(categoryies[caseID]))
+
+		byID, err := productRepo.GetProducts(ctx, []int64{phoneID, caseID, 999})
+		require.NoError(t, err)
+		if assert.Len(t, byID, 2) {
+			assert.Equal(t, []string{"Phones", "Sale"}, categoryNames(byID[0].Categoryies))
+			assert.Equal(t, []string{"Sale"}, categoryNames(byID[1].Categoryies))
+		}
+
+		products, err := productRepo.GetCategoryiesProducts(ctx, []int64{phones, sale})
+		require.NoError(t, err)
+		assert.Len(t, products[phones], 1)
+		assert.Len(t, products[sale], 2)
+
+		saleProducts, err := productRepo.GetCategoryProducts(ctx, "Sale")
+		require.NoError(t, err)
+		assert.Len(t, saleProducts, 2)
+
+		all, err := productRepo.GetAllProducts(ctx)
+		require.NoError(t, err)
+		assert.Len(t, all, 3)
+
+		summary, err := categoryRepo.GetCategoryiesSummary(ctx)
+		require.NoError(t, err)
+		counts := make(map[string]int64, len(summary))
+		for _, s := range summary {
+			counts[s.Name] = s.ProductCount
+		}
+		assert.Equal(t, map[string]int64{"Empty": 0, "Phones": 1, "Sale": 2}, counts)
+
+		require.NoError(t, productRepo.DeleteProduct(ctx, phoneID, 1))
+		_, err = productRepo.GetProduct(ctx, phoneID)
+		assert.ErrorIs(t, err, repository.ErrProductNotFound)
+	})
+}
+
+func TestBackendCatalog(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, db *sqlx.DB) {
+		ctx := context.Background()
+		categoryRepo := NewCategoryRepository(db, testLogger())
+		productRepo := NewProductRepository(db, testLogger())
+
+		_, err := categoryRepo.AddCategory(ctx, "Phones")
+		require.NoError(t, err)
+		_, err = categoryRepo.AddCategory(ctx, "Sale")
+		require.NoError(t, err)
+
+		rows := []model.ImportRow{
+			{Line: 2, SKU: "SKU-1", Name: "Phone", Categoryies: []string{"Sale", "Phones"}},
+			{Line: 3, SKU: "SKU-2", Name: "Case"},
+			{Line: 4, SKU: "SKU-3", Name: "Tablet", Categoryies: []string{"Tablets"}},
+		}
+
+		results, err := productRepo.ImportProducts(ctx, rows, true)
+		require.NoError(t, err)
+		assert.Equal(t, []model.ImportStatus{model.ImportCreated, model.ImportCreated, model.ImportFailed}, importStatuses(results))
+
+		// пробный импорт ничего не сохраняет
+		all, err := productRepo.GetAllProducts(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, all)
+
+		_, err = productRepo.ImportProducts(ctx, rows[:2], false)
+		require.NoError(t, err)
+
+		results, err = productRepo.ImportProducts(ctx, []model.ImportRow{{Line: 2, SKU: "SKU-1", Name: "Phone X"}}, false)
+		require.NoError(t, err)
+		assert.Equal(t, []model.ImportStatus{model.ImportUpdated}, importStatuses(results))
+
+		var exported []model.Product
+		err = productRepo.ExportProducts(ctx, func(product model.Product) error {
+			exported = append(exported, product)
+			return nil
+		})
+		require.NoError(t, err)
+		if assert.Len(t, exported, 2) {
+			assert.Equal(t, "Phone X", exported[0].Name)
+			assert.Empty(t, exported[0].Categoryies)
+			assert.Equal(t, "SKU-2", exported[1].SKU)
+		}
+
+		_, err = productRepo.ImportProducts(ctx, []model.ImportRow{{Line: 2, SKU: "SKU-2", Name: "Case", Categoryies: []string{"Sale", "Phones"}}}, false)
+		require.NoError(t, err)
+
+		exported = nil
+		err = productRepo.ExportProducts(ctx, func(product model.Product) error {
+			exported = append(exported, product)
+			return nil
+		})
+		require.NoError(t, err)
+		if assert.Len(t, exported, 2) {
+			assert.Equal(t, []string{"Phones", "Sale"}, categoryNames(exported[1].Categoryies))
+		}
+	})
+}
+
+func TestBackendIdempotency(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, db *sqlx.DB) {
+		ctx := context.Background()
+		userID, err := NewAuthRepository(db, testLogger()).SaveUser(ctx, "user@example.com", []byte("hash"), model.RoleUser)
+		require.NoError(t, err)
+
+		repo := NewIdempotencyRepository(db, testLogger())
+
+		_, reserved, err := repo.ReserveIdempotencyKey(ctx, userID, "key", "fp", "owner", time.Hour)
+		require.NoError(t, err)
+		assert.True(t, reserved)
+
+		resp, reserved, err := repo.ReserveIdempotencyKey(ctx, userID, "key", "fp", "owner", time.Hour)
+		require.NoError(t, err)
+		assert.False(t, reserved)
+		assert.Equal(t, "fp", resp.Fingerprint)
+
+		saved := model.IdempotentResponse{Status: 201, Header: map[string]string{"Content-Type": "application/json"}, Body: []byte(`{"productID":1}`)}
+		require.NoError(t, repo.SaveIdempotentResponse(ctx, userID, "key", "owner", saved, time.Hour))
+
+		resp, _, err = repo.ReserveIdempotencyKey(ctx, userID, "key", "fp", "owner", time.Hour)
+		require.NoError(t, err)
+		assert.Equal(t, 201, resp.Status)
+		assert.Equal(t, saved.Header, resp.Header)
+		assert.Equal(t, saved.Body, resp.Body)
+
+		// сохраненный ответ живет ttl, а не аренду на время запроса - см. TestIdempotencyLeaseExpiresMidRequest
+		_, reserved, err = repo.ReserveIdempotencyKey(ctx, userID, "leased", "fp", "owner", time.Minute)
+		require.NoError(t, err)
+		require.True(t, reserved)
+		require.NoError(t, repo.SaveIdempotentResponse(ctx, userID, "leased", "owner", saved, time.Hour))
+
+		resp, reserved, err = repo.ReserveIdempotencyKey(ctx, userID, "leased", "fp", "retry", -time.Minute)
+		require.NoError(t, err)
+		assert.False(t, reserved)
+		assert.Equal(t, 201, resp.Status)
+
+		// истекший ключ резервируется заново и удаляется очисткой
+		_, reserved, err = repo.ReserveIdempotencyKey(ctx, userID, "expired", "fp", "owner", -time.Second)
+		require.NoError(t, err)
+		assert.True(t, reserved)
+
+		_, reserved, err = repo.ReserveIdempotencyKey(ctx, userID, "expired", "other", "owner", -time.Second)
+		require.NoError(t, err)
+		assert.True(t, reserved)
+
+		n, err := repo.DeleteExpiredIdempotencyKeys(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), n)
+	})
+}
+
+func TestBackendOutbox(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, db *sqlx.DB) {
+		ctx := context.Background()
+		repo := NewOutboxRepository(db, testLogger())
+		transactor := NewTransactor(db, testLogger())
+
+		err := transactor.WithinTx(ctx, func(ctx context.Context) error {
+			return repo.SaveEvents(ctx, model.ProductDeleted{ProductID: 1}, model.ProductDeleted{ProductID: 2})
+		})
+		require.NoError(t, err)
+
+		events, err := repo.PendingEvents(ctx, 10)
+		require.NoError(t, err)
+		require.Len(t, events, 2)
+		assert.Equal(t, model.EventProductDeleted, events[0].Type)
+		assert.Equal(t, int64(1), events[0].AggregateID)
+		assert.JSONEq(t, `{"productID":1}`, string(events[0].Payload))
+
+		require.NoError(t, repo.MarkEventDelivered(ctx, events[0].ID))
+		require.NoError(t, repo.RetryEvent(ctx, events[1].ID, time.Hour))
+
+		events, err = repo.PendingEvents(ctx, 10)
+		require.NoError(t, err)
+		assert.Empty(t, events)
+
+		// поток событий читает outbox независимо от доставки
+		last, err := repo.LastEventID(ctx)
+		require.NoError(t, err)
+
+		require.NoError(t, repo.SaveEvents(ctx, model.ProductDeleted{ProductID: 3}))
+
+		events, err = repo.EventsAfter(ctx, 0, 10)
+		require.NoError(t, err)
+		require.Len(t, events, 3)
+		assert.Equal(t, last, events[1].ID)
+
+		events, err = repo.EventsAfter(ctx, last, 10)
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		assert.Equal(t, int64(3), events[0].AggregateID)
+
+		events, err = repo.LatestEvents(ctx, 0, last, 1)
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		assert.Equal(t, last, events[0].ID)
+	})
+}
+
+func TestBackendWebhooks(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, db *sqlx.DB) {
+		ctx := context.Background()
+		userID, err := NewAuthRepository(db, testLogger()).SaveUser(ctx, "user@example.com", []byte("hash"), model.RoleUser)
+		require.NoError(t, err)
+
+		repo := NewWebhookRepository(db, testLogger())
+
+		all, err := repo.CreateWebhook(ctx, model.Webhook{UserID: userID, URL: "https://example.com/all", EventTypes: []string{model.WebhookAllEvents}, Secret: "s1"})
+		require.NoError(t, err)
+		assert.True(t, all.Active)
+		assert.Equal(t, []string{model.WebhookAllEvents}, all.EventTypes)
+
+		_, err = repo.CreateWebhook(ctx, model.Webhook{UserID: userID, URL: "https://example.com/created", EventTypes: []string{string(model.EventProductCreated)}, Secret: "s2"})
+		require.NoError(t, err)
+
+		event := model.Event{ID: 7, Type: model.EventProductDeleted, AggregateID: 1}
+		n, err := repo.EnqueueWebhookDeliveries(ctx, event)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), n)
+
+		n, err = repo.EnqueueWebhookDeliveries(ctx, event)
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), n)
+
+		due, err := repo.ClaimWebhookDeliveries(ctx, 10, time.Minute)
+		require.NoError(t, err)
+		require.Len(t, due, 1)
+		assert.Equal(t, all.ID, due[0].WebhookID)
+		assert.Equal(t, "s1", due[0].Secret)
+
+		// занятую доставку не получит другой доставщик, пока не истечет аренда
+		claimed, err := repo.ClaimWebhookDeliveries(ctx, 10, time.Minute)
+		require.NoError(t, err)
+		assert.Empty(t, claimed)
+
+		code := 500
+		next := time.Now().UTC().Add(time.Hour)
+		attempt := model.WebhookAttempt{ResponseCode: &code, Error: "server error", DurationMs: 12, AttemptedAt: time.Now().UTC()}
+		require.NoError(t, repo.RecordWebhookAttempt(ctx, due[0].DeliveryID, attempt, model.WebhookDeliveryPending, &next))
+
+		due, err = repo.ClaimWebhookDeliveries(ctx, 10, time.Minute)
+		require.NoError(t, err)
+		assert.Empty(t, due)
+
+		delivery, err := repo.GetWebhookDelivery(ctx, userID, all.ID, 1)
+		require.NoError(t, err)
+		assert.Equal(t, 1, delivery.Attempts)
+		if assert.Len(t, delivery.Log, 1) {
+			assert.Equal(t, "server error", delivery.Log[0].Error)
+		}
+
+		assert.ErrorIs(t, repo.RedeliverWebhook(ctx, userID+1, all.ID, delivery.ID), repository.ErrWebhookDeliveryNotFound)
+		require.NoError(t, repo.RedeliverWebhook(ctx, userID, all.ID, delivery.ID))
+
+		due, err = repo.ClaimWebhookDeliveries(ctx, 10, time.Minute)
+		require.NoError(t, err)
+		assert.Len(t, due, 1)
+	})
+}
+
+func TestBackendWebhookClaim(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, db *sqlx.DB) {
+		ctx := context.Background()
+		userID, err := NewAuthRepository(db, testLogger()).SaveUser(ctx, "user@example.com", []byte("hash"), model.RoleUser)
+		require.NoError(t, err)
+
+		repo := NewWebhookRepository(db, testLogger())
+		_, err = repo.CreateWebhook(ctx, model.Webhook{UserID: userID, URL: "https://example.com/all", EventTypes: []string{model.WebhookAllEvents}, Secret: "s1"})
+		require.NoError(t, err)
+
+		const deliveries = 30
+		for id := int64(1); id <= deliveries; id++ {
+			_, err := repo.EnqueueWebhookDeliveries(ctx, model.Event{ID: id, Type: model.EventProductCreated, AggregateID: id})
+			require.NoError(t, err)
+		}
+
+		// доставщики разбирают очередь одновременно, каждая доставка достается одному
+		claimed := make([][]int64, 3)
+		var wg sync.WaitGroup
+		for i := range claimed {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				for {
+					due, err := repo.ClaimWebhookDeliveries(ctx, 4, time.Minute)
+					if !assert.NoError(t, err) || len(due) == 0 {
+						return
+					}
+					for _, dispatch := range due {
+						claimed[i] = append(claimed[i], dispatch.DeliveryID)
+					}
+				}
+			}(i)
+		}
+		wg.Wait()
+
+		seen := make(map[int64]bool)
+		for _, ids := range claimed {
+			for _, id := range ids {
+				assert.False(t, seen[id], "delivery %d claimed twice", id)
+				seen[id] = true
+			}
+		}
+		assert.Len(t, seen, deliveries)
+	})
+}
+
+func categoryNames(categoryies []model.Category) []string {
+	names := make([]string, 0, len(categoryies))
+	for _, c := range categoryies {
+		names = append(names, c.Name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+func importStatuses(results []model.ImportResult) []model.ImportStatus {
+	statuses := make([]model.ImportStatus, 0, len(results))
+	for _, r := range results {
+		statuses = append(statuses, r.Status)
+	}
+
+	return statuses
+}
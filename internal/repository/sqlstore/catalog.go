@@ -0,0 +1,247 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"goapi/internal/lib/logger/logctx"
+	"goapi/internal/lib/logger/sl"
+	"goapi/internal/model"
+	"goapi/internal/repository"
+	"log/slog"
+	"strings"
+)
+
+// categoryiesSeparator разделяет имена категорий товара при экспорте, в именах он не встречается
+const categoryiesSeparator = "\x1f"
+
+// ExportProducts читает товары вместе с категориями и передает их в fn по одному,
+// не загружая весь каталог в память
+func (p *ProductRepository) ExportProducts(ctx context.Context, fn func(model.Product) error) error {
+	const op = "sqlstore.ExportProducts"
+	ctx, end := startQuery(ctx, op)
+	defer end()
+
+	log := logctx.From(ctx, p.log).With(
+		slog.String("op", op),
+	)
+
+	log.Info("exporting products from db")
+
+	query := fmt.Sprintf(`
+		SELECT p.id, COALESCE(p.sku, ''), p.name, string_agg(c.name, $1 ORDER BY c.name)
+		FROM %s p
+		LEFT JOIN %s pc ON pc.product_id = p.id
+		LEFT JOIN %s c ON c.id = pc.category_id
+		GROUP BY p.id
+		ORDER BY p.id
+	`, productsTable, productCategoryTable, categoryTable)
+
+	rows, err := p.db.QueryContext(ctx, query, categoryiesSeparator)
+	if err != nil {
+		log.Error("error getting products from database", sl.Err(err))
+		return fmt.Errorf("%s %w", op, repository.ErrGetProducts)
+	}
+	defer rows.Close()
+
+	count := 0
+	for rows.Next() {
+		var product model.Product
+		var categoryies sql.NullString
+
+		if err := rows.Scan(&product.ID, &product.SKU, &product.Name, &categoryies); err != nil {
+			log.Error("error scanning product", sl.Err(err))
+			return fmt.Errorf("%s %w", op, repository.ErrGetProducts)
+		}
+
+		product.Categoryies = []model.Category{}
+		if categoryies.Valid {
+			for _, name := range strings.Split(categoryies.String, categoryiesSeparator) {
+				product.Categoryies = append(product.Categoryies, model.Category{Name: name})
+			}
+		}
+
+		if err := fn(product); err != nil {
+			return fmt.Errorf("%s %w", op, err)
+		}
+		count++
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Error("error reading products", sl.Err(err))
+		return fmt.Errorf("%s %w", op, repository.ErrGetProducts)
+	}
+
+	log.Info("products exported from db", slog.Int("count", count))
+
+	return nil
+}
+
+// ImportProducts создает или обновляет товары по артикулу. Каждая строка изолирована
+// точкой сохранения, в режиме dryRun транзакция откатывается.
+func (p *ProductRepository) ImportProducts(
+	ctx context.Context,
+	rows []model.ImportRow,
+	dryRun bool,
+) ([]model.ImportResult, error) {
+	const op = "sqlstore.ImportProducts"
+	ctx, end := startQuery(ctx, op)
+	defer end()
+
+	log := logctx.From(ctx, p.log).With(
+		slog.String("op", op),
+		slog.Int("rows", len(rows)),
+		slog.Bool("dry_run", dryRun),
+	)
+
+	log.Info("importing products in db")
+
+	tx, err := beginTx(ctx, p.db)
+	if err != nil {
+		log.Error(ErrStartTransaction.Error())
+		return nil, fmt.Errorf("%s %w", op, ErrStartTransaction)
+	}
+	defer tx.Rollback()
+
+	results := make([]model.ImportResult, len(rows))
+	for i, row := range rows {
+		results[i] = model.ImportResult{Line: row.Line, SKU: row.SKU}
+
+		if _, err := tx.Exec("SAVEPOINT " + batchSavepoint); err != nil {
+			log.Error("error creating savepoint", sl.Err(err))
+			return nil, fmt.Errorf("%s %w", op, err)
+		}
+
+		res, err := p.upsertProduct(row, tx.Tx)
+		if err != nil {
+			log.Warn("row is not imported", slog.Int("line", row.Line), sl.Err(err))
+
+			results[i].Status = model.ImportFailed
+			results[i].Err = err
+
+			if _, err := tx.Exec("ROLLBACK TO SAVEPOINT " + batchSavepoint); err != nil {
+				log.Error("error rolling back to savepoint", sl.Err(err))
+				return nil, fmt.Errorf("%s %w", op, err)
+			}
+			continue
+		}
+
+		if _, err := tx.Exec("RELEASE SAVEPOINT " + batchSavepoint); err != nil {
+			log.Error("error releasing savepoint", sl.Err(err))
+			return nil, fmt.Errorf("%s %w", op, err)
+		}
+
+		res.Line, res.SKU = row.Line, row.SKU
+		results[i] = res
+	}
+
+	if dryRun {
+		log.Info("dry run, import is rolled back")
+		return results, nil
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error(ErrEndTransaction.Error())
+		return nil, fmt.Errorf("%s %w", op, ErrEndTransaction)
+	}
+
+	log.Info("products imported in db")
+
+	return results, nil
+}
+
+// upsertProduct создает или обновляет товар строки и сравнивает его с прежним состоянием
+func (p *ProductRepository) upsertProduct(row model.ImportRow, tx *sqlx.Tx) (model.ImportResult, error) {
+	res := model.ImportResult{ID: ErrProductID}
+
+	query := fmt.Sprintf(
+		"SELECT id FROM %s WHERE name = $1",
+		categoryTable,
+	)
+	categoryIDs, err := p.getCategoryiesIDs(query, row.Categoryies, tx)
+	if err != nil {
+		return res, err
+	}
+
+	var prev struct {
+		ID   int64  `db:"id"`
+		Name string `db:"name"`
+	}
+	query = fmt.Sprintf(
+		"SELECT id, name FROM %s WHERE sku = $1"+forUpdate(tx),
+		productsTable,
+	)
+	err = tx.Get(&prev, query, row.SKU)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return res, fmt.Errorf("%w: %w", repository.ErrSaveProduct, err)
+	}
+	found := err == nil
+
+	var prevCategoryIDs []int64
+	if found {
+		query = fmt.Sprintf(
+			"SELECT category_id FROM %s WHERE product_id = $1",
+			productCategoryTable,
+		)
+		if err := tx.Select(&prevCategoryIDs, query, prev.ID); err != nil {
+			return res, fmt.Errorf("%w: %w", repository.ErrSaveProduct, err)
+		}
+	}
+
+	// обновление увеличивает версию, поэтому первая версия только у строки, вставленной этой командой
+	query = fmt.Sprintf(
+		"INSERT INTO %s (sku, name) VALUES ($1, $2) ON CONFLICT (sku) DO UPDATE SET name = EXCLUDED.name, version = %s.version + 1 RETURNING id, version",
+		productsTable, productsTable,
+	)
+	var productID, version int64
+	if err := tx.QueryRow(query, row.SKU, row.Name).Scan(&productID, &version); err != nil {
+		return res, fmt.Errorf("%w: %w", repository.ErrSaveProduct, err)
+	}
+
+	query = fmt.Sprintf(
+		"DELETE FROM %s WHERE product_id = $1",
+		productCategoryTable,
+	)
+	if _, err := tx.Exec(query, productID); err != nil {
+		return res, fmt.Errorf("%w: %w", repository.ErrDeleteProductCategory, err)
+	}
+
+	query = fmt.Sprintf(
+		"INSERT INTO %s (product_id, category_id) VALUES ($1, $2)",
+		productCategoryTable,
+	)
+	if err := p.addProductCategory(query, categoryIDs, productID, tx); err != nil {
+		return res, fmt.Errorf("%w: %w", repository.ErrSaveProductCategory, err)
+	}
+
+	res.ID = productID
+	res.Status = model.ImportCreated
+	if version != 1 {
+		// товар, вставленный параллельно после чтения, считается измененным целиком
+		res.Status = model.ImportUpdated
+		res.Renamed = !found || prev.Name != row.Name
+		res.Recategorized = !found || !sameIDs(prevCategoryIDs, categoryIDs)
+	}
+
+	return res, nil
+}
+
+// sameIDs сравнивает наборы идентификаторов без учета порядка
+func sameIDs(a, b []int64) bool {
+	set := make(map[int64]bool, len(a))
+	for _, id := range a {
+		set[id] = true
+	}
+
+	other := make(map[int64]bool, len(b))
+	for _, id := range b {
+		if !set[id] {
+			return false
+		}
+		other[id] = true
+	}
+
+	return len(set) == len(other)
+}
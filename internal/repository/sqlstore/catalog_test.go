@@ -0,0 +1,122 @@
+package sqlstore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"goapi/internal/model"
+	"goapi/internal/repository"
+)
+
+// addCategoryies добавляет категории с именами names
+func addCategoryies(t *testing.T, db *sqlx.DB, names ...string) {
+	t.Helper()
+
+	categoryRepo := NewCategoryRepository(db, testLogger())
+	for _, name := range names {
+		_, err := categoryRepo.AddCategory(context.Background(), name)
+		require.NoError(t, err)
+	}
+}
+
+func TestExportProducts(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, db *sqlx.DB) {
+		ctx := context.Background()
+		addCategoryies(t, db, "Category1", "Category2")
+		productRepo := NewProductRepository(db, testLogger())
+
+		_, err := productRepo.ImportProducts(ctx, []model.ImportRow{
+			{Line: 2, SKU: "SKU-1", Name: "Product1", Categoryies: []string{"Category2", "Category1"}},
+		}, false)
+		require.NoError(t, err)
+		_, err = productRepo.AddProduct(ctx, "Product2", nil)
+		require.NoError(t, err)
+
+		var products []model.Product
+		err = productRepo.ExportProducts(ctx, func(product model.Product) error {
+			products = append(products, product)
+			return nil
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, []model.Product{
+			{ID: 1, SKU: "SKU-1", Name: "Product1", Categoryies: []model.Category{{Name: "Category1"}, {Name: "Category2"}}},
+			{ID: 2, Name: "Product2", Categoryies: []model.Category{}},
+		}, products)
+	})
+}
+
+func TestImportProducts(t *testing.T) {
+	tests := []struct {
+		name   string
+		dryRun bool
+	}{
+		{name: "Commit", dryRun: false},
+		{name: "Dry Run", dryRun: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			forEachBackend(t, func(t *testing.T, db *sqlx.DB) {
+				ctx := context.Background()
+				addCategoryies(t, db, "Category1")
+				productRepo := NewProductRepository(db, testLogger())
+
+				_, err := productRepo.ImportProducts(ctx, []model.ImportRow{{Line: 2, SKU: "SKU-1", Name: "Product"}}, false)
+				require.NoError(t, err)
+
+				rows := []model.ImportRow{
+					{Line: 2, SKU: "SKU-1", Name: "Product1", Categoryies: []string{"Category1"}},
+					{Line: 3, SKU: "SKU-2", Name: "Product2", Categoryies: []string{"Missing"}},
+				}
+
+				results, err := productRepo.ImportProducts(ctx, rows, test.dryRun)
+				require.NoError(t, err)
+				require.Len(t, results, 2)
+
+				assert.Equal(t, model.ImportUpdated, results[0].Status)
+				assert.Equal(t, int64(1), results[0].ID)
+				assert.Equal(t, 2, results[0].Line)
+				assert.True(t, results[0].Renamed)
+				assert.True(t, results[0].Recategorized)
+
+				assert.Equal(t, model.ImportFailed, results[1].Status)
+				assert.ErrorIs(t, results[1].Err, repository.ErrCategoryNotFound)
+
+				// ошибочная строка не сохраняется, пробный импорт не сохраняет ничего
+				expected := model.Product{ID: 1, SKU: "SKU-1", Name: "Product1", Version: 2, Categoryies: []model.Category{{ID: 1, Name: "Category1", Version: 1}}}
+				if test.dryRun {
+					expected = model.Product{ID: 1, SKU: "SKU-1", Name: "Product", Version: 1, Categoryies: []model.Category{}}
+				}
+
+				products, err := productRepo.GetAllProducts(ctx)
+				require.NoError(t, err)
+				assert.Equal(t, []model.Product{expected}, products)
+			})
+		})
+	}
+}
+
+func TestImportProductsUnchanged(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, db *sqlx.DB) {
+		ctx := context.Background()
+		addCategoryies(t, db, "Category1", "Category2")
+		productRepo := NewProductRepository(db, testLogger())
+
+		rows := []model.ImportRow{{Line: 2, SKU: "SKU-1", Name: "Product1", Categoryies: []string{"Category1", "Category2"}}}
+		_, err := productRepo.ImportProducts(ctx, rows, false)
+		require.NoError(t, err)
+
+		rows[0].Categoryies = []string{"Category2", "Category1"}
+		results, err := productRepo.ImportProducts(ctx, rows, false)
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+
+		assert.Equal(t, model.ImportUpdated, results[0].Status)
+		assert.False(t, results[0].Renamed)
+		assert.False(t, results[0].Recategorized)
+	})
+}
@@ -0,0 +1,251 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"goapi/internal/lib/logger/logctx"
+	"goapi/internal/lib/logger/sl"
+	"goapi/internal/model"
+	"goapi/internal/repository"
+	"log/slog"
+)
+
+const (
+	ErrCategoryID        = 0
+	categoryTable        = "categoryies"
+	productCategoryTable = "product_category"
+)
+
+type CategoryRepository struct {
+	db  *sqlx.DB
+	log *slog.Logger
+}
+
+func NewCategoryRepository(db *sqlx.DB, l *slog.Logger) *CategoryRepository {
+	return &CategoryRepository{
+		db:  db,
+		log: l,
+	}
+}
+
+func (c *CategoryRepository) AddCategory(ctx context.Context, name string) (int64, error) {
+	const op = "sqlstore.AddCategory"
+	ctx, end := startQuery(ctx, op)
+	defer end()
+
+	log := logctx.From(ctx, c.log).With(
+		slog.String("op", op),
+		slog.String("name", name),
+	)
+
+	log.Info("save product in db")
+
+	var id int64
+
+	query := fmt.Sprintf(
+		"INSERT INTO %s (name) VALUES ($1) RETURNING id",
+		categoryTable,
+	)
+
+	row := conn(ctx, c.db).QueryRowxContext(ctx, query, name)
+	if err := row.Scan(&id); err != nil {
+		log.Error("error insert category in db")
+		return id, repository.ErrCategoryExist
+	}
+
+	log.Info("product saved in db successfully")
+
+	return id, nil
+}
+
+func (c *CategoryRepository) DeleteCategory(ctx context.Context, id int64, version int64) error {
+	const op = "sqlstore.DeleteCategory"
+	ctx, end := startQuery(ctx, op)
+	defer end()
+
+	log := logctx.From(ctx, c.log).With(
+		slog.String("op", op),
+		slog.Int64("id", id),
+	)
+
+	log.Info("removing a category from the database")
+
+	tx, err := beginTx(ctx, c.db)
+	if err != nil {
+		log.Error(ErrStartTransaction.Error())
+		return fmt.Errorf("%s %w", op, ErrStartTransaction)
+	}
+	defer tx.Rollback()
+
+	// связи ссылаются на категорию, поэтому удаляются первыми. Если категория не удалена, откат их вернет
+	query := fmt.Sprintf(
+		"DELETE FROM %s WHERE category_id = $1",
+		productCategoryTable,
+	)
+	_, err = tx.Exec(query, id)
+	if err != nil {
+		log.Error("error deleting related product categories from the database\n")
+		return fmt.Errorf("%s %w", op, repository.ErrDeleteProductCategory)
+	}
+
+	query, args := withVersion(fmt.Sprintf(
+		"DELETE FROM %s WHERE id = $1",
+		categoryTable,
+	), []any{id}, version)
+	result, err := tx.Exec(query, args...)
+	if err != nil {
+		log.Error("error deleting category from database")
+		return fmt.Errorf("%s %w", op, repository.ErrCategoryDelete)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		log.Error("error getting number of affected rows")
+		return fmt.Errorf("%s %w", op, err)
+	}
+
+	if rowsAffected == 0 {
+		err := versionConflict(tx, categoryTable, id, repository.ErrCategoryNotFound)
+		log.Warn("category is not deleted", sl.Err(err))
+		return fmt.Errorf("%s %w", op, err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		log.Error("transaction commit error\n")
+		return fmt.Errorf("%s %w", op, ErrEndTransaction)
+	}
+
+	log.Info("category successfully deleted from the database")
+
+	return nil
+}
+
+// UpdateCategoryName переименовывает категорию и возвращает ее новую версию
+func (c *CategoryRepository) UpdateCategoryName(ctx context.Context, id int64, name string, version int64) (int64, error) {
+	const op = "sqlstore.UpdateCategoryName"
+	ctx, end := startQuery(ctx, op)
+	defer end()
+
+	log := logctx.From(ctx, c.log).With(
+		slog.String("op", op),
+		slog.Int64("id", id),
+		slog.String("name", name),
+	)
+
+	log.Info("updating the category name in the database")
+
+	query, args := withVersion(fmt.Sprintf(
+		"UPDATE %s SET name = $1, version = version + 1 WHERE id = $2",
+		categoryTable,
+	), []any{name, id}, version)
+
+	db := conn(ctx, c.db)
+
+	var newVersion int64
+	err := db.QueryRowxContext(ctx, query+" RETURNING version", args...).Scan(&newVersion)
+	if errors.Is(err, sql.ErrNoRows) {
+		err = versionConflict(db, categoryTable, id, repository.ErrCategoryNotFound)
+		log.Warn("category name is not updated", sl.Err(err))
+		return ErrCategoryID, fmt.Errorf("%s %w", op, err)
+	}
+	if err != nil {
+		log.Error("error updating category name in database")
+		return ErrCategoryID, fmt.Errorf("%s %w", op, repository.ErrUpdateCategory)
+	}
+
+	log.Info("category name successfully updated in database\n")
+
+	return newVersion, nil
+}
+
+// GetCategory возвращает категорию по идентификатору
+func (c *CategoryRepository) GetCategory(ctx context.Context, id int64) (model.Category, error) {
+	const op = "sqlstore.GetCategory"
+	ctx, end := startQuery(ctx, op)
+	defer end()
+
+	log := logctx.From(ctx, c.log).With(
+		slog.String("op", op),
+		slog.Int64("id", id),
+	)
+
+	log.Info("getting category from the database")
+
+	var category model.Category
+
+	query := fmt.Sprintf(
+		"SELECT id, name, version FROM %s WHERE id = $1",
+		categoryTable,
+	)
+	if err := sqlx.GetContext(ctx, conn(ctx, c.db), &category, query, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			log.Warn("category with specified ID not found")
+			return model.Category{}, fmt.Errorf("%s %w", op, repository.ErrCategoryNotFound)
+		}
+
+		log.Error("error getting category from database", sl.Err(err))
+		return model.Category{}, fmt.Errorf("%s %w", op, repository.ErrAllCategoryies)
+	}
+
+	log.Info("category successfully retrieved from database")
+
+	return category, nil
+}
+
+func (c *CategoryRepository) GetAllCategoryies(ctx context.Context) ([]model.Category, error) {
+	const op = "sqlstore.GetAllCategories"
+	ctx, end := startQuery(ctx, op)
+	defer end()
+
+	log := logctx.From(ctx, c.log).With(
+		slog.String("op", op),
+	)
+
+	log.Info("getting all categories from the database")
+
+	var categories []model.Category
+
+	query := fmt.Sprintf(
+		"SELECT * FROM %s",
+		categoryTable,
+	)
+	err := c.db.Select(&categories, query)
+	if err != nil {
+		log.Error("error getting categories from database\n")
+		return nil, fmt.Errorf("%s %w", op, repository.ErrAllCategoryies)
+	}
+
+	log.Info("categories successfully retrieved from database")
+
+	return categories, nil
+}
+
+// GetCategoryiesSummary возвращает все категории с числом товаров одним запросом
+func (c *CategoryRepository) GetCategoryiesSummary(ctx context.Context) ([]model.CategorySummary, error) {
+	const op = "sqlstore.GetCategoryiesSummary"
+	ctx, end := startQuery(ctx, op)
+	defer end()
+
+	log := logctx.From(ctx, c.log).With(
+		slog.String("op", op),
+	)
+
+	categoryies := []model.CategorySummary{}
+	query := fmt.Sprintf(`
+		SELECT c.id, c.name, c.version, COUNT(pc.product_id) AS product_count
+		FROM %s c
+		LEFT JOIN %s pc ON pc.category_id = c.id
+		GROUP BY c.id, c.name, c.version
+		ORDER BY c.name
+	`, categoryTable, productCategoryTable)
+	if err := c.db.SelectContext(ctx, &categoryies, query); err != nil {
+		log.Error("error getting categoryies summary from database", sl.Err(err))
+		return nil, fmt.Errorf("%s %w", op, repository.ErrAllCategoryies)
+	}
+
+	return categoryies, nil
+}
@@ -0,0 +1,160 @@
+package sqlstore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"goapi/internal/model"
+	"goapi/internal/repository"
+)
+
+func TestAddCategory(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, db *sqlx.DB) {
+		categoryRepo := NewCategoryRepository(db, testLogger())
+
+		categoryID, err := categoryRepo.AddCategory(context.Background(), "TestCategory")
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), categoryID)
+
+		category, err := categoryRepo.GetCategory(context.Background(), categoryID)
+		require.NoError(t, err)
+		assert.Equal(t, model.Category{ID: 1, Name: "TestCategory", Version: 1}, category)
+	})
+}
+
+func TestDeleteCategory(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, db *sqlx.DB) {
+		ctx := context.Background()
+		categoryRepo := NewCategoryRepository(db, testLogger())
+		productRepo := NewProductRepository(db, testLogger())
+
+		categoryID, err := categoryRepo.AddCategory(ctx, "TestCategory")
+		require.NoError(t, err)
+		productID, err := productRepo.AddProduct(ctx, "TestProduct", []string{"TestCategory"})
+		require.NoError(t, err)
+
+		// товары категории остаются, но без нее
+		require.NoError(t, categoryRepo.DeleteCategory(ctx, categoryID, 0))
+
+		_, err = categoryRepo.GetCategory(ctx, categoryID)
+		assert.ErrorIs(t, err, repository.ErrCategoryNotFound)
+
+		product, err := productRepo.GetProduct(ctx, productID)
+		require.NoError(t, err)
+		assert.Empty(t, product.Categoryies)
+
+		assert.ErrorIs(t, categoryRepo.DeleteCategory(ctx, categoryID, 0), repository.ErrCategoryNotFound)
+	})
+}
+
+func TestUpdateCategoryName(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, db *sqlx.DB) {
+		ctx := context.Background()
+		categoryRepo := NewCategoryRepository(db, testLogger())
+
+		categoryID, err := categoryRepo.AddCategory(ctx, "Category")
+		require.NoError(t, err)
+
+		version, err := categoryRepo.UpdateCategoryName(ctx, categoryID, "TestCategory", 1)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), version)
+
+		// без версии имя меняется безусловно
+		version, err = categoryRepo.UpdateCategoryName(ctx, categoryID, "Renamed", 0)
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), version)
+
+		category, err := categoryRepo.GetCategory(ctx, categoryID)
+		require.NoError(t, err)
+		assert.Equal(t, "Renamed", category.Name)
+	})
+}
+
+func TestUpdateCategoryNameVersionMismatch(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, db *sqlx.DB) {
+		ctx := context.Background()
+		categoryRepo := NewCategoryRepository(db, testLogger())
+
+		categoryID, err := categoryRepo.AddCategory(ctx, "Category")
+		require.NoError(t, err)
+
+		_, err = categoryRepo.UpdateCategoryName(ctx, categoryID, "TestCategory", 3)
+		assert.ErrorIs(t, err, repository.ErrVersionMismatch)
+
+		_, err = categoryRepo.UpdateCategoryName(ctx, categoryID+1, "TestCategory", 3)
+		assert.ErrorIs(t, err, repository.ErrCategoryNotFound)
+	})
+}
+
+func TestDeleteCategoryVersionMismatch(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, db *sqlx.DB) {
+		ctx := context.Background()
+		categoryRepo := NewCategoryRepository(db, testLogger())
+		productRepo := NewProductRepository(db, testLogger())
+
+		categoryID, err := categoryRepo.AddCategory(ctx, "TestCategory")
+		require.NoError(t, err)
+		productID, err := productRepo.AddProduct(ctx, "TestProduct", []string{"TestCategory"})
+		require.NoError(t, err)
+
+		// при конфликте версий транзакция откатывается вместе со связями товаров
+		err = categoryRepo.DeleteCategory(ctx, categoryID, 2)
+		assert.ErrorIs(t, err, repository.ErrVersionMismatch)
+
+		product, err := productRepo.GetProduct(ctx, productID)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"TestCategory"}, categoryNames(product.Categoryies))
+	})
+}
+
+func TestGetAllCategoryies(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, db *sqlx.DB) {
+		ctx := context.Background()
+		categoryRepo := NewCategoryRepository(db, testLogger())
+
+		categories, err := categoryRepo.GetAllCategoryies(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, categories)
+
+		for _, name := range []string{"Category1", "Category2"} {
+			_, err := categoryRepo.AddCategory(ctx, name)
+			require.NoError(t, err)
+		}
+
+		categories, err = categoryRepo.GetAllCategoryies(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []model.Category{
+			{ID: 1, Name: "Category1", Version: 1},
+			{ID: 2, Name: "Category2", Version: 1},
+		}, categories)
+	})
+}
+
+func TestGetCategoryiesSummary(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, db *sqlx.DB) {
+		ctx := context.Background()
+		categoryRepo := NewCategoryRepository(db, testLogger())
+		productRepo := NewProductRepository(db, testLogger())
+
+		for _, name := range []string{"Category1", "Category2"} {
+			_, err := categoryRepo.AddCategory(ctx, name)
+			require.NoError(t, err)
+		}
+		for _, name := range []string{"Product1", "Product2"} {
+			_, err := productRepo.AddProduct(ctx, name, []string{"Category1"})
+			require.NoError(t, err)
+		}
+		_, err := categoryRepo.UpdateCategoryName(ctx, 2, "Category2", 1)
+		require.NoError(t, err)
+
+		categoryies, err := categoryRepo.GetCategoryiesSummary(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []model.CategorySummary{
+			{Category: model.Category{ID: 1, Name: "Category1", Version: 1}, ProductCount: 2},
+			{Category: model.Category{ID: 2, Name: "Category2", Version: 2}, ProductCount: 0},
+		}, categoryies)
+	})
+}
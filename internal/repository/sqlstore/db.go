@@ -0,0 +1,96 @@
+package sqlstore
+
+import (
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+	_ "modernc.org/sqlite"
+	"net/url"
+	"os"
+	"path/filepath"
+)
+
+const (
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+
+	// sqliteBusyTimeout - сколько соединение ждет блокировку записи другого соединения, мс
+	sqliteBusyTimeout = 5000
+)
+
+type Config struct {
+	Driver   string
+	Host     string
+	Port     string
+	Username string
+	Password string
+	DBName   string
+	SSLMode  string
+	// Path - файл базы SQLite
+	Path string
+}
+
+// Open подключается к базе драйвером из конфига, по умолчанию - к Postgres
+func Open(cfg Config) (*sqlx.DB, error) {
+	switch cfg.Driver {
+	case DriverPostgres, "":
+		return NewPostgresDB(cfg)
+	case DriverSQLite:
+		return NewSQLiteDB(cfg.Path)
+	default:
+		return nil, fmt.Errorf("unknown db driver %q", cfg.Driver)
+	}
+}
+
+func NewPostgresDB(cfg Config) (*sqlx.DB, error) {
+	db, err := sqlx.Open(
+		"postgres",
+		fmt.Sprintf(
+			"host=%s port=%s user=%s dbname=%s password=%s sslmode=%s",
+			cfg.Host, cfg.Port, cfg.Username, cfg.DBName, cfg.Password, cfg.SSLMode,
+		),
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Ping()
+	if err != nil {
+		return nil, err
+	}
+
+	return db, nil
+}
+
+// NewSQLiteDB открывает файл базы SQLite, создавая каталог для него.
+// Транзакции сразу берут блокировку записи, а время хранится в формате,
+// который сравнивается как строка, поэтому запросы работают так же, как в Postgres.
+func NewSQLiteDB(path string) (*sqlx.DB, error) {
+	if path == "" {
+		return nil, fmt.Errorf("sqlite path is empty")
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+
+	params := url.Values{}
+	params.Add("_pragma", "foreign_keys(1)")
+	params.Add("_pragma", fmt.Sprintf("busy_timeout(%d)", sqliteBusyTimeout))
+	params.Add("_pragma", "journal_mode(WAL)")
+	params.Set("_txlock", "immediate")
+	params.Set("_time_format", "sqlite")
+
+	db, err := sqlx.Open(DriverSQLite, "file:"+path+"?"+params.Encode())
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Ping()
+	if err != nil {
+		return nil, err
+	}
+
+	return db, nil
+}
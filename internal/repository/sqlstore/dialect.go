@@ -0,0 +1,55 @@
+package sqlstore
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/lib/pq"
+	"time"
+)
+
+// now - текущее время для сроков в запросах. Время берет приложение, а не база,
+// поэтому запросы одинаковы для Postgres и SQLite.
+var now = func() time.Time {
+	return time.Now().UTC()
+}
+
+// driver - соединение или транзакция, по имени драйвера которых выбирается синтаксис запроса
+type driver interface {
+	DriverName() string
+}
+
+func isSQLite(db driver) bool {
+	return db.DriverName() == DriverSQLite
+}
+
+// forUpdate блокирует выбранные строки до конца транзакции. SQLite на время
+// транзакции записи блокирует всю базу, поэтому блокировка строк ему не нужна.
+func forUpdate(db driver) string {
+	if isSQLite(db) {
+		return ""
+	}
+
+	return " FOR UPDATE"
+}
+
+// skipLocked блокирует выбранные строки до конца транзакции и пропускает строки,
+// заблокированные другими транзакциями, чтобы конкуренты разбирали очередь, не мешая
+// друг другу. SQLite выполняет транзакции записи по одной, ему это не нужно.
+func skipLocked(db driver) string {
+	if isSQLite(db) {
+		return ""
+	}
+
+	return " FOR UPDATE SKIP LOCKED"
+}
+
+// inIDs - условие column IN ids одним параметром $n: массив в Postgres, JSON в SQLite.
+// Один параметр не упирается в ограничение числа параметров запроса.
+func inIDs(db driver, column string, n int, ids []int64) (string, any) {
+	if isSQLite(db) {
+		data, _ := json.Marshal(ids)
+		return fmt.Sprintf("%s IN (SELECT value FROM json_each($%d))", column, n), string(data)
+	}
+
+	return fmt.Sprintf("%s = ANY($%d)", column, n), pq.Int64Array(ids)
+}
@@ -0,0 +1,209 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"goapi/internal/lib/logger/logctx"
+	"goapi/internal/lib/logger/sl"
+	"goapi/internal/model"
+	"goapi/internal/repository"
+	"log/slog"
+	"time"
+)
+
+const (
+	idempotencyKeysTable = "idempotency_keys"
+)
+
+type IdempotencyRepository struct {
+	db  *sqlx.DB
+	log *slog.Logger
+}
+
+func NewIdempotencyRepository(db *sqlx.DB, l *slog.Logger) *IdempotencyRepository {
+	return &IdempotencyRepository{
+		db:  db,
+		log: l,
+	}
+}
+
+// ReserveIdempotencyKey занимает ключ за пользователем на время ttl - аренду на время выполнения запроса.
+// owner отличает эту аренду от следующих: сохранить ответ и освободить ключ может только она.
+// Если ключ уже занят и не истек, возвращается сохраненная запись и false.
+func (r *IdempotencyRepository) ReserveIdempotencyKey(
+	ctx context.Context,
+	userID int64,
+	key, fingerprint, owner string,
+	ttl time.Duration,
+) (model.IdempotentResponse, bool, error) {
+	const op = "IdempotencyRepository.ReserveIdempotencyKey"
+	ctx, end := startQuery(ctx, op)
+	defer end()
+
+	log := logctx.From(ctx, r.log).With(
+		slog.String("op", op),
+		slog.Int64("user_id", userID),
+	)
+
+	// истекший ключ переиспользуется, как будто его не было
+	query := fmt.Sprintf(
+		`INSERT INTO %[1]s (user_id, key, fingerprint, owner, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (user_id, key) DO UPDATE
+		SET fingerprint = EXCLUDED.fingerprint, owner = EXCLUDED.owner, status = NULL, headers = NULL, body = NULL, expires_at = EXCLUDED.expires_at
+		WHERE %[1]s.expires_at <= $6
+		RETURNING user_id`,
+		idempotencyKeysTable,
+	)
+
+	t := now()
+	var id int64
+	err := r.db.QueryRowContext(ctx, query, userID, key, fingerprint, owner, t.Add(ttl), t).Scan(&id)
+	if err == nil {
+		log.Info("idempotency key reserved")
+		return model.IdempotentResponse{}, true, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		log.Error("error reserve idempotency key", sl.Err(err))
+		return model.IdempotentResponse{}, false, err
+	}
+
+	query = fmt.Sprintf(
+		"SELECT fingerprint, COALESCE(status, 0), headers, body FROM %s WHERE user_id = $1 AND key = $2",
+		idempotencyKeysTable,
+	)
+
+	var (
+		resp    model.IdempotentResponse
+		headers []byte
+	)
+
+	err = r.db.QueryRowContext(ctx, query, userID, key).Scan(&resp.Fingerprint, &resp.Status, &headers, &resp.Body)
+	if err != nil {
+		// запись удалили между запросами - считаем, что ключ еще занят
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.IdempotentResponse{}, false, nil
+		}
+		log.Error("error get idempotency key", sl.Err(err))
+		return model.IdempotentResponse{}, false, err
+	}
+
+	if len(headers) > 0 {
+		if err := json.Unmarshal(headers, &resp.Header); err != nil {
+			log.Error("error decode stored headers", sl.Err(err))
+			return model.IdempotentResponse{}, false, err
+		}
+	}
+
+	return resp, false, nil
+}
+
+// SaveIdempotentResponse сохраняет ответ под ключом, занятым арендой owner, и хранит его ttl.
+// Если аренда истекла, ответ не сохраняется и возвращается repository.ErrIdempotencyLeaseLost:
+// ключ мог уже занять повтор запроса.
+func (r *IdempotencyRepository) SaveIdempotentResponse(
+	ctx context.Context,
+	userID int64,
+	key, owner string,
+	resp model.IdempotentResponse,
+	ttl time.Duration,
+) error {
+	const op = "IdempotencyRepository.SaveIdempotentResponse"
+	ctx, end := startQuery(ctx, op)
+	defer end()
+
+	log := logctx.From(ctx, r.log).With(
+		slog.String("op", op),
+		slog.Int64("user_id", userID),
+	)
+
+	headers, err := json.Marshal(resp.Header)
+	if err != nil {
+		log.Error("error encode headers", sl.Err(err))
+		return err
+	}
+
+	query := fmt.Sprintf(
+		`UPDATE %s SET status = $1, headers = $2, body = $3, expires_at = $4
+		WHERE user_id = $5 AND key = $6 AND owner = $7 AND status IS NULL AND expires_at > $8`,
+		idempotencyKeysTable,
+	)
+
+	t := now()
+	res, err := r.db.ExecContext(ctx, query, resp.Status, headers, resp.Body, t.Add(ttl), userID, key, owner, t)
+	if err != nil {
+		log.Error("error save idempotent response", sl.Err(err))
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		log.Warn("idempotency key lease is lost")
+		return repository.ErrIdempotencyLeaseLost
+	}
+
+	log.Info("idempotent response saved")
+
+	return nil
+}
+
+// DeleteIdempotencyKey освобождает ключ, занятый арендой owner, чтобы запрос можно было повторить.
+// Ключ, который уже занял другой запрос, не трогается.
+func (r *IdempotencyRepository) DeleteIdempotencyKey(ctx context.Context, userID int64, key, owner string) error {
+	const op = "IdempotencyRepository.DeleteIdempotencyKey"
+	ctx, end := startQuery(ctx, op)
+	defer end()
+
+	log := logctx.From(ctx, r.log).With(
+		slog.String("op", op),
+		slog.Int64("user_id", userID),
+	)
+
+	query := fmt.Sprintf(
+		"DELETE FROM %s WHERE user_id = $1 AND key = $2 AND owner = $3 AND status IS NULL",
+		idempotencyKeysTable,
+	)
+
+	if _, err := r.db.ExecContext(ctx, query, userID, key, owner); err != nil {
+		log.Error("error delete idempotency key", sl.Err(err))
+		return err
+	}
+
+	return nil
+}
+
+// DeleteExpiredIdempotencyKeys удаляет истекшие ключи и возвращает их количество
+func (r *IdempotencyRepository) DeleteExpiredIdempotencyKeys(ctx context.Context) (int64, error) {
+	const op = "IdempotencyRepository.DeleteExpiredIdempotencyKeys"
+	ctx, end := startQuery(ctx, op)
+	defer end()
+
+	log := logctx.From(ctx, r.log).With(
+		slog.String("op", op),
+	)
+
+	query := fmt.Sprintf(
+		"DELETE FROM %s WHERE expires_at <= $1",
+		idempotencyKeysTable,
+	)
+
+	res, err := r.db.ExecContext(ctx, query, now())
+	if err != nil {
+		log.Error("error delete expired idempotency keys", sl.Err(err))
+		return 0, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	return n, nil
+}
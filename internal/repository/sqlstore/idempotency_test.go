@@ -0,0 +1,167 @@
+package sqlstore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"goapi/internal/model"
+	"goapi/internal/repository"
+)
+
+func TestReserveIdempotencyKey(t *testing.T) {
+	saved := model.IdempotentResponse{
+		Status: 200,
+		Header: map[string]string{"Content-Type": "application/json"},
+		Body:   []byte(`{"productID":1}`),
+	}
+
+	tests := []struct {
+		name             string
+		prepare          func(t *testing.T, repo *IdempotencyRepository, userID int64)
+		expectedReserved bool
+		expectedResponse model.IdempotentResponse
+	}{
+		{
+			name:             "Reserved",
+			prepare:          func(t *testing.T, repo *IdempotencyRepository, userID int64) {},
+			expectedReserved: true,
+		},
+		{
+			name: "Completed",
+			prepare: func(t *testing.T, repo *IdempotencyRepository, userID int64) {
+				_, _, err := repo.ReserveIdempotencyKey(context.Background(), userID, "key", "fp", "owner", time.Minute)
+				require.NoError(t, err)
+				require.NoError(t, repo.SaveIdempotentResponse(context.Background(), userID, "key", "owner", saved, time.Hour))
+			},
+			expectedResponse: model.IdempotentResponse{
+				Fingerprint: "fp",
+				Status:      saved.Status,
+				Header:      saved.Header,
+				Body:        saved.Body,
+			},
+		},
+		{
+			name: "In Flight",
+			prepare: func(t *testing.T, repo *IdempotencyRepository, userID int64) {
+				_, _, err := repo.ReserveIdempotencyKey(context.Background(), userID, "key", "fp", "owner", time.Minute)
+				require.NoError(t, err)
+			},
+			expectedResponse: model.IdempotentResponse{Fingerprint: "fp"},
+		},
+		{
+			name: "Expired",
+			prepare: func(t *testing.T, repo *IdempotencyRepository, userID int64) {
+				_, _, err := repo.ReserveIdempotencyKey(context.Background(), userID, "key", "other", "owner", -time.Second)
+				require.NoError(t, err)
+			},
+			expectedReserved: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			forEachBackend(t, func(t *testing.T, db *sqlx.DB) {
+				userID, err := NewAuthRepository(db, testLogger()).SaveUser(context.Background(), "user@example.com", []byte("hash"), model.RoleUser)
+				require.NoError(t, err)
+
+				repo := NewIdempotencyRepository(db, testLogger())
+				test.prepare(t, repo, userID)
+
+				resp, reserved, err := repo.ReserveIdempotencyKey(context.Background(), userID, "key", "fp", "owner", time.Minute)
+				require.NoError(t, err)
+				assert.Equal(t, test.expectedReserved, reserved)
+				assert.Equal(t, test.expectedResponse, resp)
+			})
+		})
+	}
+}
+
+func TestSaveIdempotentResponse(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, db *sqlx.DB) {
+		ctx := context.Background()
+		userID, err := NewAuthRepository(db, testLogger()).SaveUser(ctx, "user@example.com", []byte("hash"), model.RoleUser)
+		require.NoError(t, err)
+
+		repo := NewIdempotencyRepository(db, testLogger())
+
+		_, _, err = repo.ReserveIdempotencyKey(ctx, userID, "key", "fp", "owner", time.Minute)
+		require.NoError(t, err)
+
+		err = repo.SaveIdempotentResponse(ctx, userID, "key", "owner", model.IdempotentResponse{
+			Status: 201,
+			Header: map[string]string{"ETag": `"1"`},
+			Body:   []byte("{}"),
+		}, time.Hour)
+		require.NoError(t, err)
+
+		resp, reserved, err := repo.ReserveIdempotencyKey(ctx, userID, "key", "fp", "owner", time.Minute)
+		require.NoError(t, err)
+		assert.False(t, reserved)
+		assert.Equal(t, model.IdempotentResponse{
+			Fingerprint: "fp",
+			Status:      201,
+			Header:      map[string]string{"ETag": `"1"`},
+			Body:        []byte("{}"),
+		}, resp)
+	})
+}
+
+func TestIdempotencyLeaseExpiresMidRequest(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, db *sqlx.DB) {
+		current := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+		now = func() time.Time { return current }
+		t.Cleanup(func() { now = func() time.Time { return time.Now().UTC() } })
+
+		ctx := context.Background()
+		userID, err := NewAuthRepository(db, testLogger()).SaveUser(ctx, "user@example.com", []byte("hash"), model.RoleUser)
+		require.NoError(t, err)
+
+		repo := NewIdempotencyRepository(db, testLogger())
+		saved := model.IdempotentResponse{Status: 201, Body: []byte(`{"productID":1}`)}
+
+		_, reserved, err := repo.ReserveIdempotencyKey(ctx, userID, "key", "fp", "first", time.Minute)
+		require.NoError(t, err)
+		require.True(t, reserved)
+
+		// запрос выполняется дольше аренды: ответ не сохраняется, даже если ключ никто не занял
+		current = current.Add(2 * time.Minute)
+		err = repo.SaveIdempotentResponse(ctx, userID, "key", "first", saved, time.Hour)
+		assert.ErrorIs(t, err, repository.ErrIdempotencyLeaseLost)
+
+		// повтор занимает истекший ключ, и первый запрос больше не может ни сохранить ответ, ни освободить ключ
+		_, reserved, err = repo.ReserveIdempotencyKey(ctx, userID, "key", "fp", "second", time.Minute)
+		require.NoError(t, err)
+		require.True(t, reserved)
+
+		err = repo.SaveIdempotentResponse(ctx, userID, "key", "first", saved, time.Hour)
+		assert.ErrorIs(t, err, repository.ErrIdempotencyLeaseLost)
+		require.NoError(t, repo.DeleteIdempotencyKey(ctx, userID, "key", "first"))
+
+		resp, reserved, err := repo.ReserveIdempotencyKey(ctx, userID, "key", "fp", "third", time.Minute)
+		require.NoError(t, err)
+		assert.False(t, reserved)
+		assert.Equal(t, model.IdempotentResponse{Fingerprint: "fp"}, resp)
+
+		require.NoError(t, repo.SaveIdempotentResponse(ctx, userID, "key", "second", saved, time.Hour))
+
+		// сохраненный ответ живет ttl, а не аренду
+		current = current.Add(30 * time.Minute)
+		resp, reserved, err = repo.ReserveIdempotencyKey(ctx, userID, "key", "fp", "fourth", time.Minute)
+		require.NoError(t, err)
+		assert.False(t, reserved)
+		assert.Equal(t, 201, resp.Status)
+
+		// сохраненный ответ нельзя удалить или перезаписать
+		require.NoError(t, repo.DeleteIdempotencyKey(ctx, userID, "key", "second"))
+		err = repo.SaveIdempotentResponse(ctx, userID, "key", "second", model.IdempotentResponse{Status: 500}, time.Hour)
+		assert.ErrorIs(t, err, repository.ErrIdempotencyLeaseLost)
+
+		resp, _, err = repo.ReserveIdempotencyKey(ctx, userID, "key", "fp", "fifth", time.Minute)
+		require.NoError(t, err)
+		assert.Equal(t, 201, resp.Status)
+	})
+}
@@ -0,0 +1,92 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"goapi/internal/lib/logger/logctx"
+	"goapi/internal/lib/logger/sl"
+	"log/slog"
+	"time"
+)
+
+const (
+	leasesTable = "leases"
+)
+
+// LeaseRepository хранит аренды: запись о том, какая реплика до какого времени
+// владеет именованной задачей. Сроки считаются по часам реплик, поэтому ttl
+// должен быть много больше расхождения часов между ними.
+type LeaseRepository struct {
+	db  *sqlx.DB
+	log *slog.Logger
+}
+
+func NewLeaseRepository(db *sqlx.DB, l *slog.Logger) *LeaseRepository {
+	return &LeaseRepository{
+		db:  db,
+		log: l,
+	}
+}
+
+// AcquireLease занимает аренду name за holder на ttl или продлевает ее, если holder уже владеет ею.
+// Возвращает false, если аренда занята другим и еще не истекла.
+func (r *LeaseRepository) AcquireLease(ctx context.Context, name, holder string, ttl time.Duration) (bool, error) {
+	const op = "LeaseRepository.AcquireLease"
+	ctx, end := startQuery(ctx, op)
+	defer end()
+
+	log := logctx.From(ctx, r.log).With(
+		slog.String("op", op),
+		slog.String("lease", name),
+	)
+
+	query := fmt.Sprintf(
+		`INSERT INTO %[1]s (name, holder, expires_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (name) DO UPDATE
+		SET holder = EXCLUDED.holder, expires_at = EXCLUDED.expires_at
+		WHERE %[1]s.holder = EXCLUDED.holder OR %[1]s.expires_at <= $4
+		RETURNING name`,
+		leasesTable,
+	)
+
+	t := now()
+	var leased string
+	err := r.db.QueryRowContext(ctx, query, name, holder, t.Add(ttl), t).Scan(&leased)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		log.Error("error acquire lease", sl.Err(err))
+		return false, err
+	}
+
+	return true, nil
+}
+
+// ReleaseLease освобождает аренду, если ею владеет holder, чтобы ее сразу могла занять другая реплика
+func (r *LeaseRepository) ReleaseLease(ctx context.Context, name, holder string) error {
+	const op = "LeaseRepository.ReleaseLease"
+	ctx, end := startQuery(ctx, op)
+	defer end()
+
+	log := logctx.From(ctx, r.log).With(
+		slog.String("op", op),
+		slog.String("lease", name),
+	)
+
+	query := fmt.Sprintf(
+		"DELETE FROM %s WHERE name = $1 AND holder = $2",
+		leasesTable,
+	)
+
+	if _, err := r.db.ExecContext(ctx, query, name, holder); err != nil {
+		log.Error("error release lease", sl.Err(err))
+		return err
+	}
+
+	return nil
+}
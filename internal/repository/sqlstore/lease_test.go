@@ -0,0 +1,53 @@
+package sqlstore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLeaseRepository(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, db *sqlx.DB) {
+		current := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+		now = func() time.Time { return current }
+		t.Cleanup(func() { now = func() time.Time { return time.Now().UTC() } })
+
+		ctx := context.Background()
+		repo := NewLeaseRepository(db, testLogger())
+
+		acquire := func(holder string) bool {
+			ok, err := repo.AcquireLease(ctx, "collector", holder, time.Minute)
+			require.NoError(t, err)
+			return ok
+		}
+
+		assert.True(t, acquire("a"))
+		assert.False(t, acquire("b"))
+
+		// владелец продлевает аренду, и она не истекает для других
+		current = current.Add(50 * time.Second)
+		assert.True(t, acquire("a"))
+		current = current.Add(50 * time.Second)
+		assert.False(t, acquire("b"))
+
+		// владелец перестал продлевать - аренду занимает другая реплика
+		current = current.Add(time.Minute)
+		assert.True(t, acquire("b"))
+		assert.False(t, acquire("a"))
+
+		// чужая аренда не освобождается, своя освобождается сразу
+		require.NoError(t, repo.ReleaseLease(ctx, "collector", "a"))
+		assert.False(t, acquire("a"))
+		require.NoError(t, repo.ReleaseLease(ctx, "collector", "b"))
+		assert.True(t, acquire("a"))
+
+		// аренды с разными именами независимы
+		ok, err := repo.AcquireLease(ctx, "other", "b", time.Minute)
+		require.NoError(t, err)
+		assert.True(t, ok)
+	})
+}
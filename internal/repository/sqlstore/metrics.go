@@ -0,0 +1,47 @@
+package sqlstore
+
+import (
+	"context"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.opentelemetry.io/otel"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("goapi/internal/repository/sqlstore")
+
+var queryDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: "goapi",
+	Subsystem: "db",
+	Name:      "query_duration_seconds",
+	Help:      "Duration of repository queries by name.",
+	Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+}, []string{"query"})
+
+// startQuery открывает спан запроса репозитория и замеряет его время. Параметры запроса
+// в спан не попадают, только имя операции.
+//
+//	ctx, end := startQuery(ctx, op)
+//	defer end()
+func startQuery(ctx context.Context, name string) (context.Context, func()) {
+	start := time.Now()
+	ctx, span := tracer.Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.DBOperationName(name)),
+	)
+
+	return ctx, func() {
+		queryDuration.WithLabelValues(name).Observe(time.Since(start).Seconds())
+		span.End()
+	}
+}
+
+// RegisterPoolMetrics публикует статистику пула подключений db под именем name
+func RegisterPoolMetrics(db *sqlx.DB, name string) error {
+	return prometheus.Register(collectors.NewDBStatsCollector(db.DB, name))
+}
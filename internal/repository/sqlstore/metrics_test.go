@@ -0,0 +1,55 @@
+package sqlstore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func querySamples(t *testing.T, name string) uint64 {
+	t.Helper()
+
+	var metric dto.Metric
+	require.NoError(t, queryDuration.WithLabelValues(name).(prometheus.Histogram).Write(&metric))
+	return metric.GetHistogram().GetSampleCount()
+}
+
+func TestQueryMetrics(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	forEachBackend(t, func(t *testing.T, db *sqlx.DB) {
+		authRepo := NewAuthRepository(db, testLogger())
+		ctx, parent := otel.Tracer("test").Start(context.Background(), "request")
+
+		before := querySamples(t, "AuthRepository.User")
+		_, err := authRepo.User(ctx, "nobody@example.com")
+		assert.Error(t, err)
+		parent.End()
+
+		assert.Equal(t, before+1, querySamples(t, "AuthRepository.User"))
+
+		spans := recorder.Ended()
+		query := spans[len(spans)-2]
+		assert.Equal(t, "AuthRepository.User", query.Name())
+		assert.Equal(t, parent.SpanContext().SpanID(), query.Parent().SpanID())
+		for _, attr := range query.Attributes() {
+			assert.NotContains(t, attr.Value.Emit(), "nobody@example.com", "query parameters must not leak into spans")
+		}
+	})
+}
+
+func TestRegisterPoolMetrics(t *testing.T) {
+	db := openSQLite(t)
+
+	require.NoError(t, RegisterPoolMetrics(db, "pool_test"))
+	assert.Error(t, RegisterPoolMetrics(db, "pool_test"), "pool is registered twice")
+}
@@ -0,0 +1,360 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"goapi/internal/lib/logger/sl"
+	"goapi/schema"
+	"io/fs"
+	"log/slog"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+const (
+	// migrationsTable совместима с утилитой migrate: базы, размеченные ею, продолжают мигрировать
+	migrationsTable = "schema_migrations"
+
+	// migrationLockKey - ключ advisory-блокировки Postgres, под которой реплики мигрируют по очереди
+	migrationLockKey = 7265812
+)
+
+var (
+	ErrDirtySchema      = errors.New("schema is dirty, fix the failed migration manually")
+	ErrUnknownMigration = errors.New("applied migration is not found")
+	ErrSchemaOutdated   = errors.New("schema version differs from the expected one")
+)
+
+var migrationFile = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+// Migration - встроенная миграция схемы
+type Migration struct {
+	Version int64
+	Name    string
+	Applied bool
+
+	up   string
+	down string
+}
+
+// MigrationStatus - версия схемы базы и список миграций
+type MigrationStatus struct {
+	Version    int64
+	Dirty      bool
+	Migrations []Migration
+}
+
+// Migrator применяет встроенные в бинарник миграции драйвера базы
+type Migrator struct {
+	db         *sqlx.DB
+	migrations fs.FS
+	dir        string
+	log        *slog.Logger
+}
+
+func NewMigrator(db *sqlx.DB, l *slog.Logger) *Migrator {
+	m := &Migrator{
+		db:         db,
+		migrations: schema.Postgres,
+		dir:        ".",
+		log:        l,
+	}
+	if isSQLite(db) {
+		m.migrations, m.dir = schema.SQLite, "sqlite"
+	}
+
+	return m
+}
+
+// Up применяет все непримененные миграции по порядку
+func (m *Migrator) Up(ctx context.Context) error {
+	const op = "sqlstore.MigrateUp"
+
+	log := m.log.With(
+		slog.String("op", op),
+	)
+
+	migrations, err := m.load()
+	if err != nil {
+		return fmt.Errorf("%s %w", op, err)
+	}
+
+	err = m.locked(ctx, func(conn *sqlx.Conn) error {
+		for {
+			current, err := currentVersion(ctx, conn)
+			if err != nil {
+				return err
+			}
+
+			i := sort.Search(len(migrations), func(i int) bool { return migrations[i].Version > current })
+			if i == len(migrations) {
+				return nil
+			}
+			if current != 0 && (i == 0 || migrations[i-1].Version != current) {
+				return fmt.Errorf("%w: version %d", ErrUnknownMigration, current)
+			}
+
+			next := migrations[i]
+			applied, err := apply(ctx, conn, current, next.up, next.Version)
+			if err != nil {
+				log.Error("failed to apply migration", slog.Int64("version", next.Version), sl.Err(err))
+				return fmt.Errorf("migration %d_%s: %w", next.Version, next.Name, err)
+			}
+			if applied {
+				log.Info("migration applied", slog.Int64("version", next.Version), slog.String("name", next.Name))
+			}
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("%s %w", op, err)
+	}
+
+	return nil
+}
+
+// Down откатывает steps последних миграций, при steps <= 0 - все
+func (m *Migrator) Down(ctx context.Context, steps int) error {
+	const op = "sqlstore.MigrateDown"
+
+	log := m.log.With(
+		slog.String("op", op),
+	)
+
+	migrations, err := m.load()
+	if err != nil {
+		return fmt.Errorf("%s %w", op, err)
+	}
+
+	err = m.locked(ctx, func(conn *sqlx.Conn) error {
+		for n := 0; steps <= 0 || n < steps; {
+			current, err := currentVersion(ctx, conn)
+			if err != nil {
+				return err
+			}
+			if current == 0 {
+				return nil
+			}
+
+			i := sort.Search(len(migrations), func(i int) bool { return migrations[i].Version >= current })
+			if i == len(migrations) || migrations[i].Version != current {
+				return fmt.Errorf("%w: version %d", ErrUnknownMigration, current)
+			}
+
+			var previous int64
+			if i > 0 {
+				previous = migrations[i-1].Version
+			}
+
+			applied, err := apply(ctx, conn, current, migrations[i].down, previous)
+			if err != nil {
+				log.Error("failed to revert migration", slog.Int64("version", current), sl.Err(err))
+				return fmt.Errorf("migration %d_%s: %w", current, migrations[i].Name, err)
+			}
+			if applied {
+				log.Info("migration reverted", slog.Int64("version", current), slog.String("name", migrations[i].Name))
+				n++
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("%s %w", op, err)
+	}
+
+	return nil
+}
+
+// Status возвращает версию схемы и отмечает примененные миграции
+func (m *Migrator) Status(ctx context.Context) (MigrationStatus, error) {
+	const op = "sqlstore.MigrationStatus"
+
+	migrations, err := m.load()
+	if err != nil {
+		return MigrationStatus{}, fmt.Errorf("%s %w", op, err)
+	}
+
+	if _, err := m.db.ExecContext(ctx, createMigrationsTable()); err != nil {
+		return MigrationStatus{}, fmt.Errorf("%s %w", op, err)
+	}
+
+	status := MigrationStatus{Migrations: migrations}
+	err = m.db.QueryRowContext(ctx, selectVersion()).Scan(&status.Version, &status.Dirty)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return MigrationStatus{}, fmt.Errorf("%s %w", op, err)
+	}
+
+	for i := range status.Migrations {
+		status.Migrations[i].Applied = status.Migrations[i].Version <= status.Version
+	}
+
+	return status, nil
+}
+
+// CheckVersion проверяет, что схема базы на версии последней встроенной миграции.
+// В отличие от Status, не создает таблицу миграций и подходит для проверки готовности.
+func (m *Migrator) CheckVersion(ctx context.Context) error {
+	const op = "sqlstore.CheckMigrationVersion"
+
+	migrations, err := m.load()
+	if err != nil {
+		return fmt.Errorf("%s %w", op, err)
+	}
+
+	var expected int64
+	if len(migrations) > 0 {
+		expected = migrations[len(migrations)-1].Version
+	}
+
+	current, err := currentVersion(ctx, m.db)
+	if err != nil {
+		return fmt.Errorf("%s %w", op, err)
+	}
+	if current != expected {
+		return fmt.Errorf("%s %w: version %d, expected %d", op, ErrSchemaOutdated, current, expected)
+	}
+
+	return nil
+}
+
+// locked выполняет fn на отдельном соединении. В Postgres соединение держит advisory-блокировку,
+// и реплики, запущенные одновременно, мигрируют по очереди. SQLite блокирует всю базу
+// на время транзакции записи, и каждая миграция и так выполняется в своей транзакции.
+func (m *Migrator) locked(ctx context.Context, fn func(conn *sqlx.Conn) error) error {
+	conn, err := m.db.Connx(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if !isSQLite(m.db) {
+		if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", migrationLockKey); err != nil {
+			return fmt.Errorf("acquire migration lock: %w", err)
+		}
+		defer conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", migrationLockKey)
+	}
+
+	if _, err := conn.ExecContext(ctx, createMigrationsTable()); err != nil {
+		return err
+	}
+
+	return fn(conn)
+}
+
+// load читает миграции из встроенных файлов и сортирует их по версии
+func (m *Migrator) load() ([]Migration, error) {
+	entries, err := fs.ReadDir(m.migrations, m.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, entry := range entries {
+		match := migrationFile.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("migration %s: %w", entry.Name(), err)
+		}
+
+		data, err := fs.ReadFile(m.migrations, path.Join(m.dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		}
+
+		if match[3] == "up" {
+			migration.up = string(data)
+		} else {
+			migration.down = string(data)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+
+	return migrations, nil
+}
+
+// apply выполняет query и переводит схему из версии from в версию to в одной транзакции.
+// Версия перечитывается в транзакции: если ее уже изменил другой процесс, apply ничего не делает.
+func apply(ctx context.Context, conn *sqlx.Conn, from int64, query string, to int64) (bool, error) {
+	tx, err := conn.BeginTxx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	current, err := currentVersion(ctx, tx)
+	if err != nil {
+		return false, err
+	}
+	if current != from {
+		return false, nil
+	}
+
+	if _, err := tx.ExecContext(ctx, query); err != nil {
+		return false, err
+	}
+
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf("DELETE FROM %s", migrationsTable)); err != nil {
+		return false, err
+	}
+	if to != 0 {
+		query := fmt.Sprintf("INSERT INTO %s (version, dirty) VALUES ($1, $2)", migrationsTable)
+		if _, err := tx.ExecContext(ctx, query, to, false); err != nil {
+			return false, err
+		}
+	}
+
+	return true, tx.Commit()
+}
+
+// currentVersion возвращает версию схемы, 0 - если миграций не было
+func currentVersion(ctx context.Context, q sqlx.QueryerContext) (int64, error) {
+	var version int64
+	var dirty bool
+	err := q.QueryRowxContext(ctx, selectVersion()).Scan(&version, &dirty)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	if dirty {
+		return 0, fmt.Errorf("%w: version %d", ErrDirtySchema, version)
+	}
+
+	return version, nil
+}
+
+func createMigrationsTable() string {
+	return fmt.Sprintf(
+		"CREATE TABLE IF NOT EXISTS %s (version BIGINT NOT NULL PRIMARY KEY, dirty BOOLEAN NOT NULL)",
+		migrationsTable,
+	)
+}
+
+func selectVersion() string {
+	return fmt.Sprintf(
+		"SELECT version, dirty FROM %s LIMIT 1",
+		migrationsTable,
+	)
+}
@@ -0,0 +1,97 @@
+package sqlstore
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrator(t *testing.T) {
+	forEachEmptyBackend(t, func(t *testing.T, db *sqlx.DB) {
+		ctx := context.Background()
+		migrator := NewMigrator(db, testLogger())
+
+		status, err := migrator.Status(ctx)
+		require.NoError(t, err)
+		require.NotEmpty(t, status.Migrations)
+		assert.Equal(t, int64(0), status.Version)
+		last := status.Migrations[len(status.Migrations)-1].Version
+		assert.ErrorIs(t, migrator.CheckVersion(ctx), ErrSchemaOutdated)
+
+		require.NoError(t, migrator.Up(ctx))
+		require.NoError(t, migrator.Up(ctx))
+		assert.NoError(t, migrator.CheckVersion(ctx))
+
+		status, err = migrator.Status(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, last, status.Version)
+		for _, migration := range status.Migrations {
+			assert.True(t, migration.Applied, migration.Name)
+		}
+
+		require.NoError(t, migrator.Down(ctx, 1))
+
+		status, err = migrator.Status(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, status.Migrations[len(status.Migrations)-2].Version, status.Version)
+		assert.False(t, status.Migrations[len(status.Migrations)-1].Applied)
+		assert.ErrorIs(t, migrator.CheckVersion(ctx), ErrSchemaOutdated)
+
+		// все down-скрипты откатывают схему полностью, и она снова применяется с нуля
+		require.NoError(t, migrator.Down(ctx, 0))
+
+		status, err = migrator.Status(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), status.Version)
+
+		require.NoError(t, migrator.Up(ctx))
+	})
+}
+
+func TestMigratorConcurrentReplicas(t *testing.T) {
+	forEachEmptyBackend(t, func(t *testing.T, db *sqlx.DB) {
+		ctx := context.Background()
+
+		var wg sync.WaitGroup
+		errs := make([]error, 3)
+		for i := range errs {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				errs[i] = NewMigrator(db, testLogger()).Up(ctx)
+			}(i)
+		}
+		wg.Wait()
+
+		for _, err := range errs {
+			assert.NoError(t, err)
+		}
+
+		status, err := NewMigrator(db, testLogger()).Status(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, status.Migrations[len(status.Migrations)-1].Version, status.Version)
+	})
+}
+
+func TestMigratorDirtySchema(t *testing.T) {
+	forEachEmptyBackend(t, func(t *testing.T, db *sqlx.DB) {
+		ctx := context.Background()
+		migrator := NewMigrator(db, testLogger())
+
+		// так утилита migrate помечает миграцию, упавшую на середине
+		_, err := db.Exec(createMigrationsTable())
+		require.NoError(t, err)
+		_, err = db.Exec("INSERT INTO schema_migrations (version, dirty) VALUES (1, TRUE)")
+		require.NoError(t, err)
+
+		assert.ErrorIs(t, migrator.Up(ctx), ErrDirtySchema)
+
+		status, err := migrator.Status(ctx)
+		require.NoError(t, err)
+		assert.True(t, status.Dirty)
+	})
+}
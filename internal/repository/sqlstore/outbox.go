@@ -0,0 +1,213 @@
+package sqlstore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"goapi/internal/lib/logger/logctx"
+	"goapi/internal/lib/logger/sl"
+	"goapi/internal/model"
+	"log/slog"
+	"slices"
+	"time"
+)
+
+const (
+	outboxTable = "outbox"
+)
+
+type OutboxRepository struct {
+	db  *sqlx.DB
+	log *slog.Logger
+}
+
+func NewOutboxRepository(db *sqlx.DB, l *slog.Logger) *OutboxRepository {
+	return &OutboxRepository{
+		db:  db,
+		log: l,
+	}
+}
+
+// SaveEvents записывает события в outbox. Вызванный внутри Transactor.WithinTx,
+// метод пишет в ту же транзакцию, что и изменение каталога.
+func (r *OutboxRepository) SaveEvents(ctx context.Context, events ...model.DomainEvent) error {
+	const op = "sqlstore.SaveEvents"
+	ctx, end := startQuery(ctx, op)
+	defer end()
+
+	log := logctx.From(ctx, r.log).With(
+		slog.String("op", op),
+	)
+
+	query := fmt.Sprintf(
+		"INSERT INTO %s (type, aggregate_id, payload) VALUES ($1, $2, $3)",
+		outboxTable,
+	)
+
+	db := conn(ctx, r.db)
+	for _, event := range events {
+		payload, err := json.Marshal(event)
+		if err != nil {
+			log.Error("error encode event", sl.Err(err))
+			return fmt.Errorf("%s %w", op, err)
+		}
+
+		if _, err := db.ExecContext(ctx, query, event.EventType(), event.AggregateID(), payload); err != nil {
+			log.Error("error save event", sl.Err(err))
+			return fmt.Errorf("%s %w", op, err)
+		}
+	}
+
+	return nil
+}
+
+// PendingEvents возвращает недоставленные события, время повтора которых наступило
+func (r *OutboxRepository) PendingEvents(ctx context.Context, limit int) ([]model.Event, error) {
+	const op = "sqlstore.PendingEvents"
+	ctx, end := startQuery(ctx, op)
+	defer end()
+
+	query := fmt.Sprintf(
+		`SELECT id, type, aggregate_id, payload, created_at, attempts FROM %s
+		WHERE delivered_at IS NULL AND next_attempt_at <= $1 ORDER BY id LIMIT $2`,
+		outboxTable,
+	)
+
+	var events []model.Event
+	if err := r.db.SelectContext(ctx, &events, query, now(), limit); err != nil {
+		r.log.Error("error get pending events", slog.String("op", op), sl.Err(err))
+		return nil, fmt.Errorf("%s %w", op, err)
+	}
+
+	return events, nil
+}
+
+// MarkEventDelivered отмечает событие доставленным
+func (r *OutboxRepository) MarkEventDelivered(ctx context.Context, id int64) error {
+	const op = "sqlstore.MarkEventDelivered"
+	ctx, end := startQuery(ctx, op)
+	defer end()
+
+	query := fmt.Sprintf(
+		"UPDATE %s SET delivered_at = $1 WHERE id = $2",
+		outboxTable,
+	)
+
+	if _, err := r.db.ExecContext(ctx, query, now(), id); err != nil {
+		r.log.Error("error mark event delivered", slog.String("op", op), sl.Err(err))
+		return fmt.Errorf("%s %w", op, err)
+	}
+
+	return nil
+}
+
+// RetryEvent откладывает повторную доставку события на delay
+func (r *OutboxRepository) RetryEvent(ctx context.Context, id int64, delay time.Duration) error {
+	const op = "sqlstore.RetryEvent"
+	ctx, end := startQuery(ctx, op)
+	defer end()
+
+	query := fmt.Sprintf(
+		"UPDATE %s SET attempts = attempts + 1, next_attempt_at = $1 WHERE id = $2",
+		outboxTable,
+	)
+
+	if _, err := r.db.ExecContext(ctx, query, now().Add(delay), id); err != nil {
+		r.log.Error("error postpone event", slog.String("op", op), sl.Err(err))
+		return fmt.Errorf("%s %w", op, err)
+	}
+
+	return nil
+}
+
+// LastEventID возвращает идентификатор последнего события outbox, 0 - если событий нет
+func (r *OutboxRepository) LastEventID(ctx context.Context) (int64, error) {
+	const op = "sqlstore.LastEventID"
+	ctx, end := startQuery(ctx, op)
+	defer end()
+
+	query := fmt.Sprintf(
+		"SELECT COALESCE(MAX(id), 0) FROM %s",
+		outboxTable,
+	)
+
+	var id int64
+	if err := r.db.GetContext(ctx, &id, query); err != nil {
+		r.log.Error("error get last event id", slog.String("op", op), sl.Err(err))
+		return 0, fmt.Errorf("%s %w", op, err)
+	}
+
+	return id, nil
+}
+
+// EventsAfter возвращает до limit событий с идентификатором больше afterID по возрастанию,
+// независимо от того, доставил ли их relay
+func (r *OutboxRepository) EventsAfter(ctx context.Context, afterID int64, limit int) ([]model.Event, error) {
+	const op = "sqlstore.EventsAfter"
+	ctx, end := startQuery(ctx, op)
+	defer end()
+
+	query := fmt.Sprintf(
+		`SELECT id, type, aggregate_id, payload, created_at, attempts FROM %s
+		WHERE id > $1 ORDER BY id LIMIT $2`,
+		outboxTable,
+	)
+
+	var events []model.Event
+	if err := r.db.SelectContext(ctx, &events, query, afterID, limit); err != nil {
+		r.log.Error("error get events", slog.String("op", op), sl.Err(err))
+		return nil, fmt.Errorf("%s %w", op, err)
+	}
+
+	return events, nil
+}
+
+// LatestEvents возвращает последние limit событий с идентификатором в (afterID, untilID]
+// по возрастанию
+func (r *OutboxRepository) LatestEvents(ctx context.Context, afterID, untilID int64, limit int) ([]model.Event, error) {
+	const op = "sqlstore.LatestEvents"
+	ctx, end := startQuery(ctx, op)
+	defer end()
+
+	query := fmt.Sprintf(
+		`SELECT id, type, aggregate_id, payload, created_at, attempts FROM %s
+		WHERE id > $1 AND id <= $2 ORDER BY id DESC LIMIT $3`,
+		outboxTable,
+	)
+
+	var events []model.Event
+	if err := r.db.SelectContext(ctx, &events, query, afterID, untilID, limit); err != nil {
+		r.log.Error("error get latest events", slog.String("op", op), sl.Err(err))
+		return nil, fmt.Errorf("%s %w", op, err)
+	}
+
+	slices.Reverse(events)
+
+	return events, nil
+}
+
+// DeleteDeliveredEvents удаляет события, доставленные больше retention назад, и возвращает их количество
+func (r *OutboxRepository) DeleteDeliveredEvents(ctx context.Context, retention time.Duration) (int64, error) {
+	const op = "sqlstore.DeleteDeliveredEvents"
+	ctx, end := startQuery(ctx, op)
+	defer end()
+
+	query := fmt.Sprintf(
+		"DELETE FROM %s WHERE delivered_at IS NOT NULL AND delivered_at <= $1",
+		outboxTable,
+	)
+
+	res, err := r.db.ExecContext(ctx, query, now().Add(-retention))
+	if err != nil {
+		r.log.Error("error delete delivered events", slog.String("op", op), sl.Err(err))
+		return 0, fmt.Errorf("%s %w", op, err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s %w", op, err)
+	}
+
+	return n, nil
+}
@@ -0,0 +1,164 @@
+package sqlstore
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"goapi/internal/model"
+	"goapi/internal/repository"
+)
+
+func TestWithinTxSavesEventsWithChange(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, db *sqlx.DB) {
+		ctx := context.Background()
+		transactor := NewTransactor(db, testLogger())
+		categoryRepo := NewCategoryRepository(db, testLogger())
+		outboxRepo := NewOutboxRepository(db, testLogger())
+
+		errAborted := errors.New("aborted")
+		for _, name := range []string{"Category1", "Category2"} {
+			err := transactor.WithinTx(ctx, func(ctx context.Context) error {
+				id, err := categoryRepo.AddCategory(ctx, name)
+				if err != nil {
+					return err
+				}
+
+				if err := outboxRepo.SaveEvents(ctx, model.CategoryCreated{CategoryID: id, Name: name}); err != nil {
+					return err
+				}
+				if name == "Category2" {
+					return errAborted
+				}
+
+				return nil
+			})
+			if name == "Category2" {
+				assert.ErrorIs(t, err, errAborted)
+			} else {
+				assert.NoError(t, err)
+			}
+		}
+
+		// откаченное изменение не оставляет события
+		categoryies, err := categoryRepo.GetAllCategoryies(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Category1"}, categoryNames(categoryies))
+
+		events, err := outboxRepo.PendingEvents(ctx, 10)
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		assert.Equal(t, model.EventCategoryCreated, events[0].Type)
+		assert.JSONEq(t, `{"categoryID":1,"name":"Category1"}`, string(events[0].Payload))
+	})
+}
+
+func TestWithinTxNestedRollback(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, db *sqlx.DB) {
+		ctx := context.Background()
+		transactor := NewTransactor(db, testLogger())
+		categoryRepo := NewCategoryRepository(db, testLogger())
+		productRepo := NewProductRepository(db, testLogger())
+
+		_, err := categoryRepo.AddCategory(ctx, "Category1")
+		require.NoError(t, err)
+		productID, err := productRepo.AddProduct(ctx, "Product1", []string{"Category1"})
+		require.NoError(t, err)
+
+		// ошибка метода откатывает только его точку сохранения: связи товара не удалены,
+		// а изменения до вызова фиксируются вместе с транзакцией
+		err = transactor.WithinTx(ctx, func(ctx context.Context) error {
+			if _, err := categoryRepo.AddCategory(ctx, "Category2"); err != nil {
+				return err
+			}
+
+			err := productRepo.DeleteProduct(ctx, productID, 5)
+			assert.ErrorIs(t, err, repository.ErrVersionMismatch)
+
+			return nil
+		})
+		require.NoError(t, err)
+
+		product, err := productRepo.GetProduct(ctx, productID)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Category1"}, categoryNames(product.Categoryies))
+
+		// ошибку из fn WithinTx откатывает целиком
+		err = transactor.WithinTx(ctx, func(ctx context.Context) error {
+			if err := productRepo.DeleteProduct(ctx, productID, 0); err != nil {
+				return err
+			}
+
+			_, err := categoryRepo.AddCategory(ctx, "Category1")
+			return err
+		})
+		assert.ErrorIs(t, err, repository.ErrCategoryExist)
+
+		_, err = productRepo.GetProduct(ctx, productID)
+		assert.NoError(t, err)
+
+		categoryies, err := categoryRepo.GetAllCategoryies(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Category1", "Category2"}, categoryNames(categoryies))
+	})
+}
+
+func TestPendingEvents(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, db *sqlx.DB) {
+		ctx := context.Background()
+		outboxRepo := NewOutboxRepository(db, testLogger())
+
+		require.NoError(t, outboxRepo.SaveEvents(ctx,
+			model.ProductDeleted{ProductID: 5},
+			model.ProductDeleted{ProductID: 6},
+			model.ProductDeleted{ProductID: 7},
+		))
+
+		// отложенное событие не возвращается до времени повтора
+		require.NoError(t, outboxRepo.RetryEvent(ctx, 2, time.Hour))
+
+		events, err := outboxRepo.PendingEvents(ctx, 1)
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		assert.Equal(t, model.EventProductDeleted, events[0].Type)
+		assert.Equal(t, int64(5), events[0].AggregateID)
+
+		events, err = outboxRepo.PendingEvents(ctx, 10)
+		require.NoError(t, err)
+		require.Len(t, events, 2)
+		assert.Equal(t, int64(7), events[1].AggregateID)
+		assert.Equal(t, 0, events[1].Attempts)
+	})
+}
+
+func TestDeleteDeliveredEvents(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, db *sqlx.DB) {
+		ctx := context.Background()
+		outboxRepo := NewOutboxRepository(db, testLogger())
+
+		require.NoError(t, outboxRepo.SaveEvents(ctx,
+			model.ProductDeleted{ProductID: 5},
+			model.ProductDeleted{ProductID: 6},
+		))
+		require.NoError(t, outboxRepo.MarkEventDelivered(ctx, 1))
+
+		// событие доставлено только что и еще хранится
+		n, err := outboxRepo.DeleteDeliveredEvents(ctx, time.Hour)
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), n)
+
+		n, err = outboxRepo.DeleteDeliveredEvents(ctx, -time.Minute)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), n)
+
+		// недоставленное событие не удаляется
+		events, err := outboxRepo.EventsAfter(ctx, 0, 10)
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		assert.Equal(t, int64(6), events[0].AggregateID)
+	})
+}
@@ -0,0 +1,908 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"goapi/internal/lib/logger/logctx"
+	"goapi/internal/lib/logger/sl"
+	"goapi/internal/model"
+	"goapi/internal/repository"
+	"log/slog"
+	"strings"
+)
+
+const (
+	productsTable  = "products"
+	ErrProductID   = 0
+	batchSavepoint = "batch_item"
+)
+
+var (
+	ErrStartTransaction = errors.New("error starting transaction")
+	ErrEndTransaction   = errors.New("error committing transaction")
+)
+
+type ProductRepository struct {
+	db  *sqlx.DB
+	log *slog.Logger
+}
+
+func NewProductRepository(db *sqlx.DB, l *slog.Logger) *ProductRepository {
+	return &ProductRepository{
+		db:  db,
+		log: l,
+	}
+}
+
+func (p *ProductRepository) AddProduct(
+	ctx context.Context,
+	name string,
+	categoryies []string,
+) (int64, error) {
+	const op = "sqlstore.AddProduct"
+	ctx, end := startQuery(ctx, op)
+	defer end()
+
+	log := logctx.From(ctx, p.log).With(
+		slog.String("op", op),
+		slog.String("name", name),
+	)
+
+	log.Info("save product in db")
+
+	tx, err := beginTx(ctx, p.db)
+	if err != nil {
+		log.Error(ErrStartTransaction.Error())
+		return ErrProductID, fmt.Errorf("%s %w", op, ErrStartTransaction)
+	}
+	defer tx.Rollback()
+
+	productID, err := p.addProduct(name, categoryies, tx.Tx)
+	if err != nil {
+		log.Error("error saving product", sl.Err(err))
+		return ErrProductID, fmt.Errorf("%s %w: %w", op, repository.ErrSaveProduct, err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		log.Error(ErrEndTransaction.Error())
+		return ErrProductID, fmt.Errorf("%s %w", op, ErrEndTransaction)
+	}
+
+	log.Info("product is saved in db successfully")
+
+	return productID, nil
+}
+
+func (p *ProductRepository) DeleteProduct(
+	ctx context.Context,
+	id int64,
+	version int64,
+) error {
+	const op = "sqlstore.DeleteProduct"
+	ctx, end := startQuery(ctx, op)
+	defer end()
+
+	log := logctx.From(ctx, p.log).With(
+		slog.String("op", op),
+		slog.Int64("id", id),
+	)
+
+	log.Info("removing a product from the database")
+
+	tx, err := beginTx(ctx, p.db)
+	if err != nil {
+		log.Error(ErrStartTransaction.Error())
+		return fmt.Errorf("%s %w", op, ErrStartTransaction)
+	}
+	defer tx.Rollback()
+
+	// связи ссылаются на товар, поэтому удаляются первыми. Если товар не удален, откат их вернет
+	query := fmt.Sprintf(
+		"DELETE FROM %s WHERE product_id = $1",
+		productCategoryTable,
+	)
+	_, err = tx.Exec(query, id)
+	if err != nil {
+		log.Error("error deleting product-category links from the database")
+		return fmt.Errorf("%s %w", op, repository.ErrDeleteProductCategory)
+	}
+
+	query, args := withVersion(fmt.Sprintf(
+		"DELETE FROM %s WHERE id = $1",
+		productsTable,
+	), []any{id}, version)
+	result, err := tx.Exec(query, args...)
+	if err != nil {
+		log.Error("error deleting a product from the database")
+		return fmt.Errorf("%s %w", op, repository.ErrDeleteProduct)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		log.Error("error getting number of affected rows")
+		return fmt.Errorf("%s %w", op, err)
+	}
+
+	if rowsAffected == 0 {
+		err := versionConflict(tx, productsTable, id, repository.ErrProductNotFound)
+		log.Warn("product is not deleted", sl.Err(err))
+		return fmt.Errorf("%s %w", op, err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		log.Error(ErrEndTransaction.Error())
+		return fmt.Errorf("%s %w", op, ErrEndTransaction)
+	}
+
+	log.Info("the product was successfully removed from the database")
+
+	return nil
+}
+
+// UpdateProductName переименовывает товар и возвращает его новую версию
+func (p *ProductRepository) UpdateProductName(
+	ctx context.Context,
+	id int64,
+	name string,
+	version int64,
+) (int64, error) {
+	const op = "sqlstore.UpdateProductName"
+	ctx, end := startQuery(ctx, op)
+	defer end()
+
+	log := logctx.From(ctx, p.log).With(
+		slog.String("op", op),
+		slog.Int64("id", id),
+		slog.String("name", name),
+	)
+
+	log.Info("updating the product name in the database")
+
+	query, args := withVersion(fmt.Sprintf(
+		"UPDATE %s SET name = $1, version = version + 1 WHERE id = $2",
+		productsTable,
+	), []any{name, id}, version)
+
+	db := conn(ctx, p.db)
+
+	var newVersion int64
+	err := db.QueryRowxContext(ctx, query+" RETURNING version", args...).Scan(&newVersion)
+	if errors.Is(err, sql.ErrNoRows) {
+		err = versionConflict(db, productsTable, id, repository.ErrProductNotFound)
+		log.Warn("product name is not updated", sl.Err(err))
+		return ErrProductID, fmt.Errorf("%s %w", op, err)
+	}
+	if err != nil {
+		log.Error("error updating product name in database\n")
+		return ErrProductID, fmt.Errorf("%s %w", op, repository.ErrUpdateProduct)
+	}
+
+	log.Info("product name was successfully updated in the database")
+
+	return newVersion, nil
+}
+
+// UpdateProductCategoryies заменяет категории товара и возвращает его новую версию
+func (p *ProductRepository) UpdateProductCategoryies(
+	ctx context.Context,
+	id int64,
+	categoryies []model.Category,
+	version int64,
+) (int64, error) {
+	const op = "sqlstore.UpdateProductCategoryies"
+	ctx, end := startQuery(ctx, op)
+	defer end()
+
+	log := logctx.From(ctx, p.log).With(
+		slog.String("op", op),
+		slog.Int64("id", id),
+	)
+
+	log.Info("updating product categories in the database")
+
+	tx, err := beginTx(ctx, p.db)
+	if err != nil {
+		log.Error(ErrStartTransaction.Error())
+		return ErrProductID, fmt.Errorf("%s %w", op, ErrStartTransaction)
+	}
+	defer tx.Rollback()
+
+	query, args := withVersion(fmt.Sprintf(
+		"UPDATE %s SET version = version + 1 WHERE id = $1",
+		productsTable,
+	), []any{id}, version)
+
+	var newVersion int64
+	err = tx.QueryRow(query+" RETURNING version", args...).Scan(&newVersion)
+	if errors.Is(err, sql.ErrNoRows) {
+		err = versionConflict(tx, productsTable, id, repository.ErrProductNotFound)
+		log.Warn("product categories are not updated", sl.Err(err))
+		return ErrProductID, fmt.Errorf("%s %w", op, err)
+	}
+	if err != nil {
+		log.Error("error updating product version in database", sl.Err(err))
+		return ErrProductID, fmt.Errorf("%s %w", op, repository.ErrUpdateProduct)
+	}
+
+	categoryIDs, err := p.resolveCategoryies(categoryies, tx.Tx)
+	if err != nil {
+		log.Warn("product categories are not resolved", sl.Err(err))
+		return ErrProductID, fmt.Errorf("%s %w", op, err)
+	}
+
+	query = fmt.Sprintf(
+		"DELETE FROM %s WHERE product_id = $1",
+		productCategoryTable,
+	)
+	_, err = tx.Exec(query, id)
+	if err != nil {
+		log.Error("error deleting product-category links from the database\n")
+		return ErrProductID, fmt.Errorf("%s %w", op, repository.ErrDeleteProductCategory)
+	}
+
+	query = fmt.Sprintf(
+		"INSERT INTO %s (product_id, category_id) VALUES ($1, $2)",
+		productCategoryTable,
+	)
+	if err := p.addProductCategory(query, categoryIDs, id, tx.Tx); err != nil {
+		log.Error("error adding a new product-category link to the database\n")
+		return ErrProductID, fmt.Errorf("%s %w", op, repository.ErrSaveProductCategory)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		log.Error(ErrEndTransaction.Error())
+		return ErrProductID, fmt.Errorf("%s %w", op, ErrEndTransaction)
+	}
+
+	log.Info("product categories have been successfully updated in the database\n")
+
+	return newVersion, nil
+}
+
+func (p *ProductRepository) GetAllProducts(ctx context.Context) ([]model.Product, error) {
+	const op = "sqlstore.GetAllProducts"
+	ctx, end := startQuery(ctx, op)
+	defer end()
+
+	log := logctx.From(ctx, p.log).With(
+		slog.String("op", op),
+	)
+
+	log.Info("getting all products from the database\n")
+
+	var products []model.Product
+	query := fmt.Sprintf(
+		"SELECT id, name, COALESCE(sku, '') AS sku, version FROM %s",
+		productsTable,
+	)
+	err := p.db.SelectContext(ctx, &products, query)
+	if err != nil {
+		log.Error("error getting products from database\n")
+		return nil, fmt.Errorf("%s %w", op, repository.ErrGetProducts)
+	}
+
+	if err := p.withCategoryies(ctx, products); err != nil {
+		log.Error("error getting products categoryies from database", sl.Err(err))
+		return nil, fmt.Errorf("%s %w", op, repository.ErrGetProducts)
+	}
+
+	log.Info("products successfully retrieved from database")
+
+	return products, nil
+}
+
+func (p *ProductRepository) GetCategoryProducts(ctx context.Context, category string) ([]model.Product, error) {
+	const op = "sqlstore.GetCategoryProducts"
+	ctx, end := startQuery(ctx, op)
+	defer end()
+
+	log := logctx.From(ctx, p.log).With(
+		slog.String("op", op),
+		slog.String("category", category),
+	)
+
+	log.Info("getting products by category from db")
+
+	var products []model.Product
+	query := fmt.Sprintf(`
+		SELECT p.id, p.name, COALESCE(p.sku, '') AS sku, p.version
+		FROM %s p
+		INNER JOIN %s pc ON p.id = pc.product_id
+		INNER JOIN %s c ON pc.category_id = c.id
+		WHERE c.name = $1
+	`, productsTable, productCategoryTable, categoryTable)
+
+	if err := p.db.SelectContext(ctx, &products, query, category); err != nil {
+		log.Error("failed to get products by category from db")
+		return nil, fmt.Errorf("%s %w", op, repository.ErrGetProducts)
+	}
+
+	if err := p.withCategoryies(ctx, products); err != nil {
+		log.Error("failed to get products categoryies from db", sl.Err(err))
+		return nil, fmt.Errorf("%s %w", op, repository.ErrGetProducts)
+	}
+
+	log.Info("products by category retrieved from db")
+
+	return products, nil
+}
+
+// SearchProducts ищет товары по подстроке в названии без учета регистра
+func (p *ProductRepository) SearchProducts(ctx context.Context, query string, limit int) ([]model.Product, error) {
+	const op = "sqlstore.SearchProducts"
+	ctx, end := startQuery(ctx, op)
+	defer end()
+
+	log := logctx.From(ctx, p.log).With(
+		slog.String("op", op),
+		slog.String("query", query),
+	)
+
+	products := []model.Product{}
+	q := fmt.Sprintf(
+		`SELECT id, name, COALESCE(sku, '') AS sku, version FROM %s
+		WHERE LOWER(name) LIKE '%%' || LOWER($1) || '%%' ESCAPE '\' ORDER BY name, id LIMIT $2`,
+		productsTable,
+	)
+	if err := p.db.SelectContext(ctx, &products, q, escapeLike(query), limit); err != nil {
+		log.Error("failed to search products in db", sl.Err(err))
+		return nil, fmt.Errorf("%s %w", op, repository.ErrGetProducts)
+	}
+
+	return products, nil
+}
+
+// GetProductsCategoryies возвращает категории товаров одним запросом
+func (p *ProductRepository) GetProductsCategoryies(ctx context.Context, productIDs []int64) (map[int64][]model.Category, error) {
+	const op = "sqlstore.GetProductsCategoryies"
+	ctx, end := startQuery(ctx, op)
+	defer end()
+
+	categoryies, err := productsCategoryies(ctx, p.db, productIDs)
+	if err != nil {
+		p.log.Error("failed to get products categoryies from db", slog.String("op", op), sl.Err(err))
+		return nil, fmt.Errorf("%s %w", op, repository.ErrGetProducts)
+	}
+
+	return categoryies, nil
+}
+
+// GetProducts возвращает товары с категориями по идентификаторам: один запрос за товарами
+// и один за их категориями. Несуществующие идентификаторы пропускаются.
+func (p *ProductRepository) GetProducts(ctx context.Context, ids []int64) ([]model.Product, error) {
+	const op = "sqlstore.GetProducts"
+	ctx, end := startQuery(ctx, op)
+	defer end()
+
+	log := logctx.From(ctx, p.log).With(
+		slog.String("op", op),
+		slog.Int("ids", len(ids)),
+	)
+
+	products := []model.Product{}
+	db := conn(ctx, p.db)
+	where, arg := inIDs(db, "id", 1, ids)
+	query := fmt.Sprintf(
+		"SELECT id, name, COALESCE(sku, '') AS sku, version FROM %s WHERE %s ORDER BY id",
+		productsTable, where,
+	)
+	if err := sqlx.SelectContext(ctx, db, &products, query, arg); err != nil {
+		log.Error("failed to get products from db", sl.Err(err))
+		return nil, fmt.Errorf("%s %w", op, repository.ErrGetProducts)
+	}
+	if len(products) == 0 {
+		return products, nil
+	}
+
+	found := make([]int64, 0, len(products))
+	for _, product := range products {
+		found = append(found, int64(product.ID))
+	}
+
+	categoryies, err := productsCategoryies(ctx, db, found)
+	if err != nil {
+		log.Error("failed to get products categoryies from db", sl.Err(err))
+		return nil, fmt.Errorf("%s %w", op, repository.ErrGetProducts)
+	}
+
+	for i := range products {
+		products[i].Categoryies = categoryies[int64(products[i].ID)]
+		if products[i].Categoryies == nil {
+			products[i].Categoryies = []model.Category{}
+		}
+	}
+
+	return products, nil
+}
+
+// withCategoryies заполняет категории товаров вторым запросом по всем идентификаторам сразу.
+// У товара без категорий остается пустой список, а не nil.
+func (p *ProductRepository) withCategoryies(ctx context.Context, products []model.Product) error {
+	if len(products) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, 0, len(products))
+	for _, product := range products {
+		ids = append(ids, int64(product.ID))
+	}
+
+	categoryies, err := productsCategoryies(ctx, p.db, ids)
+	if err != nil {
+		return err
+	}
+
+	for i := range products {
+		products[i].Categoryies = categoryies[int64(products[i].ID)]
+		if products[i].Categoryies == nil {
+			products[i].Categoryies = []model.Category{}
+		}
+	}
+
+	return nil
+}
+
+func productsCategoryies(ctx context.Context, q sqlx.ExtContext, productIDs []int64) (map[int64][]model.Category, error) {
+	var rows []struct {
+		ProductID int64 `db:"product_id"`
+		model.Category
+	}
+	where, ids := inIDs(q, "pc.product_id", 1, productIDs)
+	query := fmt.Sprintf(`
+		SELECT pc.product_id, c.id, c.name, c.version
+		FROM %s c
+		INNER JOIN %s pc ON pc.category_id = c.id
+		WHERE %s
+		ORDER BY c.name
+	`, categoryTable, productCategoryTable, where)
+	if err := sqlx.SelectContext(ctx, q, &rows, query, ids); err != nil {
+		return nil, err
+	}
+
+	categoryies := make(map[int64][]model.Category, len(productIDs))
+	for _, row := range rows {
+		categoryies[row.ProductID] = append(categoryies[row.ProductID], row.Category)
+	}
+
+	return categoryies, nil
+}
+
+// GetCategoryiesProducts возвращает товары категорий одним запросом
+func (p *ProductRepository) GetCategoryiesProducts(ctx context.Context, categoryIDs []int64) (map[int64][]model.Product, error) {
+	const op = "sqlstore.GetCategoryiesProducts"
+	ctx, end := startQuery(ctx, op)
+	defer end()
+
+	var rows []struct {
+		CategoryID int64 `db:"category_id"`
+		model.Product
+	}
+	where, ids := inIDs(p.db, "pc.category_id", 1, categoryIDs)
+	query := fmt.Sprintf(`
+		SELECT pc.category_id, p.id, p.name, COALESCE(p.sku, '') AS sku, p.version
+		FROM %s p
+		INNER JOIN %s pc ON pc.product_id = p.id
+		WHERE %s
+		ORDER BY p.name, p.id
+	`, productsTable, productCategoryTable, where)
+	if err := p.db.SelectContext(ctx, &rows, query, ids); err != nil {
+		p.log.Error("failed to get categoryies products from db", slog.String("op", op), sl.Err(err))
+		return nil, fmt.Errorf("%s %w", op, repository.ErrGetProducts)
+	}
+
+	products := make(map[int64][]model.Product, len(categoryIDs))
+	for _, row := range rows {
+		products[row.CategoryID] = append(products[row.CategoryID], row.Product)
+	}
+
+	return products, nil
+}
+
+// escapeLike экранирует спецсимволы шаблона LIKE
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+// GetProduct возвращает товар вместе с категориями
+func (p *ProductRepository) GetProduct(ctx context.Context, id int64) (model.Product, error) {
+	const op = "sqlstore.GetProduct"
+	ctx, end := startQuery(ctx, op)
+	defer end()
+
+	log := logctx.From(ctx, p.log).With(
+		slog.String("op", op),
+		slog.Int64("id", id),
+	)
+
+	log.Info("getting product from db")
+
+	var product model.Product
+	query := fmt.Sprintf(
+		"SELECT id, name, COALESCE(sku, '') AS sku, version FROM %s WHERE id = $1",
+		productsTable,
+	)
+	db := conn(ctx, p.db)
+	if err := sqlx.GetContext(ctx, db, &product, query, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			log.Warn("no product found with the specified ID")
+			return model.Product{}, fmt.Errorf("%s %w", op, repository.ErrProductNotFound)
+		}
+
+		log.Error("failed to get product from db", sl.Err(err))
+		return model.Product{}, fmt.Errorf("%s %w", op, repository.ErrGetProducts)
+	}
+
+	query = fmt.Sprintf(`
+		SELECT c.id, c.name, c.version
+		FROM %s c
+		INNER JOIN %s pc ON pc.category_id = c.id
+		WHERE pc.product_id = $1
+		ORDER BY c.name
+	`, categoryTable, productCategoryTable)
+	if err := sqlx.SelectContext(ctx, db, &product.Categoryies, query, id); err != nil {
+		log.Error("failed to get product categories from db", sl.Err(err))
+		return model.Product{}, fmt.Errorf("%s %w", op, repository.ErrGetProducts)
+	}
+
+	log.Info("product retrieved from db")
+
+	return product, nil
+}
+
+// AddProducts сохраняет товары одной транзакцией и возвращает их идентификаторы
+func (p *ProductRepository) AddProducts(ctx context.Context, products []model.Product) ([]int64, error) {
+	const op = "sqlstore.AddProducts"
+	ctx, end := startQuery(ctx, op)
+	defer end()
+
+	log := logctx.From(ctx, p.log).With(
+		slog.String("op", op),
+	)
+
+	log.Info("add products from db")
+
+	tx, err := beginTx(ctx, p.db)
+	if err != nil {
+		log.Error(ErrStartTransaction.Error())
+		return nil, fmt.Errorf("%s %w", op, ErrStartTransaction)
+	}
+	defer tx.Rollback()
+
+	ids := make([]int64, 0, len(products))
+	for _, product := range products {
+		id, err := p.addProduct(product.Name, getNamesCategoryies(product.Categoryies), tx.Tx)
+		if err != nil {
+			log.Error("error saving product", sl.Err(err))
+			return nil, fmt.Errorf("%s %w: %w", op, repository.ErrSaveProduct, err)
+		}
+		ids = append(ids, id)
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error(ErrEndTransaction.Error())
+		return nil, fmt.Errorf("%s %w", op, ErrEndTransaction)
+	}
+
+	log.Info("products added from db")
+
+	return ids, nil
+}
+
+// BatchProducts выполняет операции пакета в одной транзакции. В режиме atomic первая ошибка
+// откатывает весь пакет, иначе каждая операция изолирована точкой сохранения.
+func (p *ProductRepository) BatchProducts(
+	ctx context.Context,
+	operations []model.ProductOperation,
+	atomic bool,
+) ([]model.ProductOperationResult, error) {
+	const op = "sqlstore.BatchProducts"
+	ctx, end := startQuery(ctx, op)
+	defer end()
+
+	log := logctx.From(ctx, p.log).With(
+		slog.String("op", op),
+		slog.Int("operations", len(operations)),
+		slog.Bool("atomic", atomic),
+	)
+
+	log.Info("applying batch of product operations in db")
+
+	results := make([]model.ProductOperationResult, len(operations))
+	for i, operation := range operations {
+		results[i] = model.ProductOperationResult{
+			Index:  i,
+			Op:     operation.Op,
+			ID:     operation.ID,
+			Status: model.ProductOperationSkipped,
+		}
+	}
+
+	tx, err := beginTx(ctx, p.db)
+	if err != nil {
+		log.Error(ErrStartTransaction.Error())
+		return nil, fmt.Errorf("%s %w", op, ErrStartTransaction)
+	}
+	defer tx.Rollback()
+
+	for i, operation := range operations {
+		if !atomic {
+			if _, err := tx.Exec("SAVEPOINT " + batchSavepoint); err != nil {
+				log.Error("error creating savepoint", sl.Err(err))
+				return nil, fmt.Errorf("%s %w", op, err)
+			}
+		}
+
+		id, err := p.applyProductOperation(operation, tx.Tx)
+		if err != nil {
+			log.Warn("product operation failed", slog.Int("index", i), sl.Err(err))
+
+			results[i].Status = model.ProductOperationFailed
+			results[i].Err = err
+
+			if atomic {
+				for j := 0; j < i; j++ {
+					results[j].Status = model.ProductOperationRolledBack
+				}
+				return results, nil
+			}
+
+			if _, err := tx.Exec("ROLLBACK TO SAVEPOINT " + batchSavepoint); err != nil {
+				log.Error("error rolling back to savepoint", sl.Err(err))
+				return nil, fmt.Errorf("%s %w", op, err)
+			}
+			continue
+		}
+
+		if !atomic {
+			if _, err := tx.Exec("RELEASE SAVEPOINT " + batchSavepoint); err != nil {
+				log.Error("error releasing savepoint", sl.Err(err))
+				return nil, fmt.Errorf("%s %w", op, err)
+			}
+		}
+
+		results[i].ID = id
+		results[i].Status = model.ProductOperationOK
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error(ErrEndTransaction.Error())
+		return nil, fmt.Errorf("%s %w", op, ErrEndTransaction)
+	}
+
+	log.Info("batch of product operations applied in db")
+
+	return results, nil
+}
+
+func (p *ProductRepository) applyProductOperation(operation model.ProductOperation, tx *sqlx.Tx) (int64, error) {
+	switch operation.Op {
+	case model.ProductOperationCreate:
+		return p.addProduct(operation.Name, operation.Categoryies, tx)
+	case model.ProductOperationUpdate:
+		return operation.ID, p.updateProduct(operation, tx)
+	case model.ProductOperationDelete:
+		return operation.ID, p.deleteProduct(operation.ID, operation.Version, tx)
+	default:
+		return ErrProductID, fmt.Errorf("unknown product operation %q", operation.Op)
+	}
+}
+
+// lockProduct блокирует строку товара до конца транзакции и проверяет, что товар существует
+// и его версия совпадает с ненулевой version
+func (p *ProductRepository) lockProduct(id, version int64, tx *sqlx.Tx) error {
+	query := fmt.Sprintf(
+		"SELECT version FROM %s WHERE id = $1%s",
+		productsTable, forUpdate(tx),
+	)
+
+	var current int64
+	if err := tx.Get(&current, query, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return repository.ErrProductNotFound
+		}
+		return err
+	}
+
+	if version != 0 && version != current {
+		return repository.ErrVersionMismatch
+	}
+
+	return nil
+}
+
+func (p *ProductRepository) updateProduct(operation model.ProductOperation, tx *sqlx.Tx) error {
+	if err := p.lockProduct(operation.ID, operation.Version, tx); err != nil {
+		return err
+	}
+
+	var categoryIDs []int64
+	if len(operation.Categoryies) > 0 {
+		query := fmt.Sprintf(
+			"SELECT id FROM %s WHERE name = $1",
+			categoryTable,
+		)
+		ids, err := p.getCategoryiesIDs(query, operation.Categoryies, tx)
+		if err != nil {
+			return err
+		}
+		categoryIDs = ids
+	}
+
+	query := fmt.Sprintf(
+		"UPDATE %s SET name = COALESCE(NULLIF($1, ''), name), version = version + 1 WHERE id = $2",
+		productsTable,
+	)
+	if _, err := tx.Exec(query, operation.Name, operation.ID); err != nil {
+		return fmt.Errorf("%w: %w", repository.ErrUpdateProduct, err)
+	}
+
+	if len(operation.Categoryies) == 0 {
+		return nil
+	}
+
+	query = fmt.Sprintf(
+		"DELETE FROM %s WHERE product_id = $1",
+		productCategoryTable,
+	)
+	if _, err := tx.Exec(query, operation.ID); err != nil {
+		return fmt.Errorf("%w: %w", repository.ErrDeleteProductCategory, err)
+	}
+
+	query = fmt.Sprintf(
+		"INSERT INTO %s (product_id, category_id) VALUES ($1, $2)",
+		productCategoryTable,
+	)
+	if err := p.addProductCategory(query, categoryIDs, operation.ID, tx); err != nil {
+		return fmt.Errorf("%w: %w", repository.ErrSaveProductCategory, err)
+	}
+
+	return nil
+}
+
+func (p *ProductRepository) deleteProduct(id, version int64, tx *sqlx.Tx) error {
+	if err := p.lockProduct(id, version, tx); err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf(
+		"DELETE FROM %s WHERE product_id = $1",
+		productCategoryTable,
+	)
+	if _, err := tx.Exec(query, id); err != nil {
+		return fmt.Errorf("%w: %w", repository.ErrDeleteProductCategory, err)
+	}
+
+	query = fmt.Sprintf(
+		"DELETE FROM %s WHERE id = $1",
+		productsTable,
+	)
+	if _, err := tx.Exec(query, id); err != nil {
+		return fmt.Errorf("%w: %w", repository.ErrDeleteProduct, err)
+	}
+
+	return nil
+}
+
+func (p *ProductRepository) getCategoryiesIDs(query string, categoryies []string, tx *sqlx.Tx) ([]int64, error) {
+	var categoryIDs []int64
+
+	for _, categoryName := range categoryies {
+		var categoryID int64
+		err := tx.Get(&categoryID, query, categoryName)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				p.log.Error("category does not exist in the database", slog.String("category", categoryName))
+				return []int64{}, fmt.Errorf("category %s: %w", categoryName, repository.ErrCategoryNotFound)
+			}
+
+			p.log.Error("error checking category in the database")
+			return []int64{}, fmt.Errorf("%w", err)
+		}
+		categoryIDs = append(categoryIDs, categoryID)
+	}
+	return categoryIDs, nil
+}
+
+// resolveCategoryies возвращает идентификаторы категорий, заданных идентификатором или только именем
+func (p *ProductRepository) resolveCategoryies(categoryies []model.Category, tx *sqlx.Tx) ([]int64, error) {
+	query := fmt.Sprintf(
+		"SELECT id FROM %s WHERE id = $1",
+		categoryTable,
+	)
+
+	var names []string
+	var categoryIDs []int64
+	for _, category := range categoryies {
+		if category.ID == 0 {
+			names = append(names, category.Name)
+			continue
+		}
+
+		var categoryID int64
+		if err := tx.Get(&categoryID, query, category.ID); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, fmt.Errorf("category %d: %w", category.ID, repository.ErrCategoryNotFound)
+			}
+			return nil, err
+		}
+		categoryIDs = append(categoryIDs, categoryID)
+	}
+
+	query = fmt.Sprintf(
+		"SELECT id FROM %s WHERE name = $1",
+		categoryTable,
+	)
+	ids, err := p.getCategoryiesIDs(query, names, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	// категория может быть задана и идентификатором, и именем - связь с ней одна
+	seen := make(map[int64]bool, len(categoryIDs)+len(ids))
+	resolved := make([]int64, 0, len(categoryIDs)+len(ids))
+	for _, id := range append(categoryIDs, ids...) {
+		if !seen[id] {
+			seen[id] = true
+			resolved = append(resolved, id)
+		}
+	}
+
+	return resolved, nil
+}
+
+func (p *ProductRepository) addProductCategory(query string, categoryIDs []int64, productID int64, tx *sqlx.Tx) error {
+	for _, categoryID := range categoryIDs {
+		_, err := tx.Exec(query, productID, categoryID)
+		if err != nil {
+			p.log.Error("error inserting product-category relationship into the database")
+			return fmt.Errorf("%w", err)
+		}
+	}
+	return nil
+}
+
+func (p *ProductRepository) addProduct(name string, categoryies []string, tx *sqlx.Tx) (int64, error) {
+	query := fmt.Sprintf(
+		"SELECT id FROM %s WHERE name = $1",
+		categoryTable,
+	)
+	categoryIDs, err := p.getCategoryiesIDs(query, categoryies, tx)
+	if err != nil {
+		return ErrProductID, err
+	}
+
+	query = fmt.Sprintf(
+		"INSERT INTO %s (name) VALUES ($1) RETURNING id",
+		productsTable,
+	)
+	var productID int64
+	err = tx.Get(&productID, query, name)
+	if err != nil {
+		p.log.Error("error inserting product into the database")
+		return ErrProductID, err
+	}
+
+	query = fmt.Sprintf(
+		"INSERT INTO %s (product_id, category_id) VALUES ($1, $2)",
+		productCategoryTable,
+	)
+	err = p.addProductCategory(query, categoryIDs, productID, tx)
+	if err != nil {
+		return ErrProductID, err
+	}
+	return productID, nil
+}
+
+func getNamesCategoryies(categoryies []model.Category) []string {
+	res := make([]string, 0, len(categoryies))
+	for _, category := range categoryies {
+		res = append(res, category.Name)
+	}
+	return res
+}
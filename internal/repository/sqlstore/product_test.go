@@ -0,0 +1,312 @@
+package sqlstore
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"goapi/internal/model"
+	"goapi/internal/repository"
+)
+
+// addProducts добавляет товары в категории categoryies и возвращает их идентификаторы
+func addProducts(t *testing.T, db *sqlx.DB, categoryies []string, names ...string) []int64 {
+	t.Helper()
+
+	productRepo := NewProductRepository(db, testLogger())
+	ids := make([]int64, 0, len(names))
+	for _, name := range names {
+		id, err := productRepo.AddProduct(context.Background(), name, categoryies)
+		require.NoError(t, err)
+		ids = append(ids, id)
+	}
+
+	return ids
+}
+
+func TestDeleteProduct(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, db *sqlx.DB) {
+		ctx := context.Background()
+		addCategoryies(t, db, "Category1")
+		ids := addProducts(t, db, []string{"Category1"}, "Product1", "Product2")
+		productRepo := NewProductRepository(db, testLogger())
+
+		require.NoError(t, productRepo.DeleteProduct(ctx, ids[0], 0))
+
+		_, err := productRepo.GetProduct(ctx, ids[0])
+		assert.ErrorIs(t, err, repository.ErrProductNotFound)
+
+		products, err := productRepo.GetCategoryProducts(ctx, "Category1")
+		require.NoError(t, err)
+		if assert.Len(t, products, 1) {
+			assert.Equal(t, int(ids[1]), products[0].ID)
+		}
+
+		assert.ErrorIs(t, productRepo.DeleteProduct(ctx, ids[0], 0), repository.ErrProductNotFound)
+	})
+}
+
+func TestUpdateProductName(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, db *sqlx.DB) {
+		ctx := context.Background()
+		ids := addProducts(t, db, nil, "Product")
+		productRepo := NewProductRepository(db, testLogger())
+
+		// без версии имя меняется безусловно
+		version, err := productRepo.UpdateProductName(ctx, ids[0], "TestProduct", 0)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), version)
+
+		product, err := productRepo.GetProduct(ctx, ids[0])
+		require.NoError(t, err)
+		assert.Equal(t, "TestProduct", product.Name)
+	})
+}
+
+func TestUpdateProductCategoryies(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, db *sqlx.DB) {
+		ctx := context.Background()
+		addCategoryies(t, db, "Category1", "Category2", "Category3")
+		ids := addProducts(t, db, []string{"Category3"}, "Product")
+		productRepo := NewProductRepository(db, testLogger())
+
+		version, err := productRepo.UpdateProductCategoryies(ctx, ids[0], []model.Category{{ID: 1}, {ID: 2}}, 1)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), version)
+
+		product, err := productRepo.GetProduct(ctx, ids[0])
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Category1", "Category2"}, categoryNames(product.Categoryies))
+
+		_, err = productRepo.UpdateProductCategoryies(ctx, ids[0], []model.Category{{ID: 3}}, 1)
+		assert.ErrorIs(t, err, repository.ErrVersionMismatch)
+
+		// категория может быть задана только именем, повтор той же категории не дублирует связь
+		version, err = productRepo.UpdateProductCategoryies(ctx, ids[0], []model.Category{{Name: "Category3"}, {ID: 3}, {ID: 1}}, 2)
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), version)
+
+		product, err = productRepo.GetProduct(ctx, ids[0])
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"Category1", "Category3"}, categoryNames(product.Categoryies))
+
+		for _, category := range []model.Category{{Name: "Missing"}, {ID: 42}} {
+			_, err = productRepo.UpdateProductCategoryies(ctx, ids[0], []model.Category{category}, 3)
+			assert.ErrorIs(t, err, repository.ErrCategoryNotFound)
+		}
+	})
+}
+
+func TestGetAllProducts(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, db *sqlx.DB) {
+		addCategoryies(t, db, "Category1", "Category2")
+		addProducts(t, db, []string{"Category1", "Category2"}, "Product1")
+		addProducts(t, db, nil, "Product2")
+		productRepo := NewProductRepository(db, testLogger())
+
+		products, err := productRepo.GetAllProducts(context.Background())
+		require.NoError(t, err)
+		sort.Slice(products, func(i, j int) bool { return products[i].ID < products[j].ID })
+		assert.Equal(t, []model.Product{
+			{ID: 1, Name: "Product1", Version: 1, Categoryies: []model.Category{{ID: 1, Name: "Category1", Version: 1}, {ID: 2, Name: "Category2", Version: 1}}},
+			{ID: 2, Name: "Product2", Version: 1, Categoryies: []model.Category{}},
+		}, products)
+	})
+}
+
+func TestGetCategoryProducts(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, db *sqlx.DB) {
+		addCategoryies(t, db, "TestCategory", "Other")
+		addProducts(t, db, []string{"TestCategory"}, "Product1", "Product2")
+		addProducts(t, db, []string{"Other"}, "Product3")
+		productRepo := NewProductRepository(db, testLogger())
+
+		products, err := productRepo.GetCategoryProducts(context.Background(), "TestCategory")
+		require.NoError(t, err)
+		sort.Slice(products, func(i, j int) bool { return products[i].ID < products[j].ID })
+		category := []model.Category{{ID: 1, Name: "TestCategory", Version: 1}}
+		assert.Equal(t, []model.Product{
+			{ID: 1, Name: "Product1", Version: 1, Categoryies: category},
+			{ID: 2, Name: "Product2", Version: 1, Categoryies: category},
+		}, products)
+	})
+}
+
+func TestUpdateProductNameNotFound(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, db *sqlx.DB) {
+		productRepo := NewProductRepository(db, testLogger())
+
+		version, err := productRepo.UpdateProductName(context.Background(), 1, "TestProduct", 1)
+		assert.ErrorIs(t, err, repository.ErrProductNotFound)
+		assert.Equal(t, int64(ErrProductID), version)
+	})
+}
+
+func TestBatchProductsBestEffort(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, db *sqlx.DB) {
+		ctx := context.Background()
+		addCategoryies(t, db, "Category")
+		productRepo := NewProductRepository(db, testLogger())
+
+		operations := []model.ProductOperation{
+			{Op: model.ProductOperationCreate, Name: "Product", Categoryies: []string{"Category"}},
+			{Op: model.ProductOperationDelete, ID: 7},
+		}
+
+		results, err := productRepo.BatchProducts(ctx, operations, false)
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+
+		assert.Equal(t, model.ProductOperationOK, results[0].Status)
+		assert.Equal(t, int64(1), results[0].ID)
+
+		assert.Equal(t, model.ProductOperationFailed, results[1].Status)
+		assert.ErrorIs(t, results[1].Err, repository.ErrProductNotFound)
+
+		// неудачная операция не отменяет удачную
+		product, err := productRepo.GetProduct(ctx, 1)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Category"}, categoryNames(product.Categoryies))
+	})
+}
+
+func TestBatchProductsAtomic(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, db *sqlx.DB) {
+		ctx := context.Background()
+		ids := addProducts(t, db, nil, "Product1", "Product2", "Product3")
+		productRepo := NewProductRepository(db, testLogger())
+
+		operations := []model.ProductOperation{
+			{Op: model.ProductOperationUpdate, ID: ids[0], Name: "Renamed"},
+			{Op: model.ProductOperationUpdate, ID: ids[1], Categoryies: []string{"Missing"}},
+			{Op: model.ProductOperationDelete, ID: ids[2]},
+		}
+
+		results, err := productRepo.BatchProducts(ctx, operations, true)
+		require.NoError(t, err)
+		require.Len(t, results, 3)
+
+		assert.Equal(t, model.ProductOperationRolledBack, results[0].Status)
+		assert.Equal(t, model.ProductOperationFailed, results[1].Status)
+		assert.ErrorIs(t, results[1].Err, repository.ErrCategoryNotFound)
+		assert.Equal(t, model.ProductOperationSkipped, results[2].Status)
+
+		// пакет откатывается целиком
+		product, err := productRepo.GetProduct(ctx, ids[0])
+		require.NoError(t, err)
+		assert.Equal(t, "Product1", product.Name)
+		assert.Equal(t, int64(1), product.Version)
+
+		_, err = productRepo.GetProduct(ctx, ids[2])
+		assert.NoError(t, err)
+	})
+}
+
+func TestUpdateProductNameVersionMismatch(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, db *sqlx.DB) {
+		ctx := context.Background()
+		ids := addProducts(t, db, nil, "Product")
+		productRepo := NewProductRepository(db, testLogger())
+
+		_, err := productRepo.UpdateProductName(ctx, ids[0], "Renamed", 1)
+		require.NoError(t, err)
+
+		_, err = productRepo.UpdateProductName(ctx, ids[0], "TestProduct", 1)
+		assert.ErrorIs(t, err, repository.ErrVersionMismatch)
+	})
+}
+
+func TestGetProduct(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, db *sqlx.DB) {
+		ctx := context.Background()
+		addCategoryies(t, db, "Other", "Category1")
+		productRepo := NewProductRepository(db, testLogger())
+
+		// товар с SKU создается импортом, повторный импорт увеличивает версию
+		for _, name := range []string{"Product", "Product1", "Product1"} {
+			_, err := productRepo.ImportProducts(ctx, []model.ImportRow{{Line: 2, SKU: "SKU-1", Name: name, Categoryies: []string{"Category1"}}}, false)
+			require.NoError(t, err)
+		}
+
+		product, err := productRepo.GetProduct(ctx, 1)
+		require.NoError(t, err)
+		assert.Equal(t, model.Product{
+			ID:          1,
+			SKU:         "SKU-1",
+			Name:        "Product1",
+			Version:     3,
+			Categoryies: []model.Category{{ID: 2, Name: "Category1", Version: 1}},
+		}, product)
+	})
+}
+
+func TestSearchProducts(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, db *sqlx.DB) {
+		addProducts(t, db, nil, "Phone 500", "Phone 50%", "Phone 50% case", "Tablet")
+		productRepo := NewProductRepository(db, testLogger())
+
+		// спецсимволы LIKE в запросе ищутся буквально
+		products, err := productRepo.SearchProducts(context.Background(), "50%", 1)
+		require.NoError(t, err)
+		assert.Equal(t, []model.Product{{ID: 2, Name: "Phone 50%", Version: 1}}, products)
+
+		products, err = productRepo.SearchProducts(context.Background(), "phone", 5)
+		require.NoError(t, err)
+		assert.Len(t, products, 3)
+	})
+}
+
+func TestGetProductsCategoryies(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, db *sqlx.DB) {
+		addCategoryies(t, db, "Category1", "Category2")
+		addProducts(t, db, []string{"Category1", "Category2"}, "Product1")
+		addProducts(t, db, []string{"Category1"}, "Product2")
+		addProducts(t, db, nil, "Product3")
+		productRepo := NewProductRepository(db, testLogger())
+
+		categoryies, err := productRepo.GetProductsCategoryies(context.Background(), []int64{1, 2, 3})
+		require.NoError(t, err)
+		assert.Equal(t, map[int64][]model.Category{
+			1: {{ID: 1, Name: "Category1", Version: 1}, {ID: 2, Name: "Category2", Version: 1}},
+			2: {{ID: 1, Name: "Category1", Version: 1}},
+		}, categoryies)
+	})
+}
+
+func TestGetProducts(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, db *sqlx.DB) {
+		ctx := context.Background()
+		addCategoryies(t, db, "Category1")
+		addProducts(t, db, []string{"Category1"}, "Product1")
+		addProducts(t, db, nil, "Product2")
+		productRepo := NewProductRepository(db, testLogger())
+
+		_, err := productRepo.UpdateProductName(ctx, 1, "Product1", 1)
+		require.NoError(t, err)
+
+		products, err := productRepo.GetProducts(ctx, []int64{2, 1, 3})
+		require.NoError(t, err)
+		assert.Equal(t, []model.Product{
+			{ID: 1, Name: "Product1", Version: 2, Categoryies: []model.Category{{ID: 1, Name: "Category1", Version: 1}}},
+			{ID: 2, Name: "Product2", Version: 1, Categoryies: []model.Category{}},
+		}, products)
+	})
+}
+
+func TestGetCategoryiesProducts(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, db *sqlx.DB) {
+		addCategoryies(t, db, "Category1", "Category2", "Category3")
+		addProducts(t, db, []string{"Category1", "Category2"}, "Product10")
+		productRepo := NewProductRepository(db, testLogger())
+
+		products, err := productRepo.GetCategoryiesProducts(context.Background(), []int64{1, 2, 3})
+		require.NoError(t, err)
+		assert.Equal(t, map[int64][]model.Product{
+			1: {{ID: 1, Name: "Product10", Version: 1}},
+			2: {{ID: 1, Name: "Product10", Version: 1}},
+		}, products)
+	})
+}
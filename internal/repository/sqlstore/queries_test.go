@@ -0,0 +1,247 @@
+package sqlstore
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"goapi/internal/model"
+)
+
+// TestBackendRepositoryQueries вызывает каждый метод репозиториев на схеме из встроенных миграций:
+// опечатка в имени таблицы или колонки, расхождение запроса и миграций видны без ручного прогона.
+// Новый метод репозитория без проверки здесь роняет тест.
+func TestBackendRepositoryQueries(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, db *sqlx.DB) {
+		ctx := context.Background()
+		authRepo := NewAuthRepository(db, testLogger())
+		categoryRepo := NewCategoryRepository(db, testLogger())
+		productRepo := NewProductRepository(db, testLogger())
+		idempotencyRepo := NewIdempotencyRepository(db, testLogger())
+		outboxRepo := NewOutboxRepository(db, testLogger())
+		webhookRepo := NewWebhookRepository(db, testLogger())
+
+		var userID, categoryID, productID, webhookID, deliveryID int64
+		var events []model.Event
+		url := "https://example.com/hook"
+		code := 200
+
+		checks := []struct {
+			method string
+			call   func() error
+		}{
+			{"AuthRepository.SaveUser", func() (err error) {
+				userID, err = authRepo.SaveUser(ctx, "user@example.com", []byte("hash"), model.RoleUser)
+				return err
+			}},
+			{"AuthRepository.User", func() error {
+				_, err := authRepo.User(ctx, "user@example.com")
+				return err
+			}},
+
+			{"CategoryRepository.AddCategory", func() (err error) {
+				categoryID, err = categoryRepo.AddCategory(ctx, "Phones")
+				return err
+			}},
+			{"CategoryRepository.GetCategory", func() error {
+				_, err := categoryRepo.GetCategory(ctx, categoryID)
+				return err
+			}},
+			{"CategoryRepository.GetAllCategoryies", func() error {
+				_, err := categoryRepo.GetAllCategoryies(ctx)
+				return err
+			}},
+
+			{"ProductRepository.AddProduct", func() (err error) {
+				productID, err = productRepo.AddProduct(ctx, "Phone", []string{"Phones"})
+				return err
+			}},
+			{"ProductRepository.AddProducts", func() error {
+				_, err := productRepo.AddProducts(ctx, []model.Product{{Name: "Case", Categoryies: []model.Category{{Name: "Phones"}}}})
+				return err
+			}},
+			{"ProductRepository.GetProduct", func() error {
+				_, err := productRepo.GetProduct(ctx, productID)
+				return err
+			}},
+			{"ProductRepository.GetProducts", func() error {
+				_, err := productRepo.GetProducts(ctx, []int64{productID})
+				return err
+			}},
+			{"ProductRepository.GetAllProducts", func() error {
+				_, err := productRepo.GetAllProducts(ctx)
+				return err
+			}},
+			{"ProductRepository.GetCategoryProducts", func() error {
+				_, err := productRepo.GetCategoryProducts(ctx, "Phones")
+				return err
+			}},
+			{"ProductRepository.SearchProducts", func() error {
+				_, err := productRepo.SearchProducts(ctx, "pho", 10)
+				return err
+			}},
+			{"ProductRepository.GetProductsCategoryies", func() error {
+				_, err := productRepo.GetProductsCategoryies(ctx, []int64{productID})
+				return err
+			}},
+			{"ProductRepository.GetCategoryiesProducts", func() error {
+				_, err := productRepo.GetCategoryiesProducts(ctx, []int64{categoryID})
+				return err
+			}},
+			{"ProductRepository.UpdateProductName", func() error {
+				_, err := productRepo.UpdateProductName(ctx, productID, "Phone X", 1)
+				return err
+			}},
+			{"ProductRepository.UpdateProductCategoryies", func() error {
+				_, err := productRepo.UpdateProductCategoryies(ctx, productID, []model.Category{{ID: int(categoryID)}}, 2)
+				return err
+			}},
+			{"ProductRepository.BatchProducts", func() error {
+				results, err := productRepo.BatchProducts(ctx, []model.ProductOperation{
+					{Op: model.ProductOperationCreate, Name: "Charger", Categoryies: []string{"Phones"}},
+					{Op: model.ProductOperationUpdate, ID: productID, Name: "Phone Y"},
+				}, true)
+				for _, result := range results {
+					assert.NoError(t, result.Err)
+				}
+				return err
+			}},
+			{"ProductRepository.ImportProducts", func() error {
+				results, err := productRepo.ImportProducts(ctx, []model.ImportRow{{Line: 2, SKU: "SKU-1", Name: "Cable", Categoryies: []string{"Phones"}}}, false)
+				for _, result := range results {
+					assert.NoError(t, result.Err)
+				}
+				return err
+			}},
+			{"ProductRepository.ExportProducts", func() error {
+				return productRepo.ExportProducts(ctx, func(model.Product) error { return nil })
+			}},
+
+			{"CategoryRepository.GetCategoryiesSummary", func() error {
+				_, err := categoryRepo.GetCategoryiesSummary(ctx)
+				return err
+			}},
+			{"CategoryRepository.UpdateCategoryName", func() error {
+				_, err := categoryRepo.UpdateCategoryName(ctx, categoryID, "Smartphones", 1)
+				return err
+			}},
+
+			{"IdempotencyRepository.ReserveIdempotencyKey", func() error {
+				_, _, err := idempotencyRepo.ReserveIdempotencyKey(ctx, userID, "key", "fp", "owner", time.Hour)
+				return err
+			}},
+			{"IdempotencyRepository.SaveIdempotentResponse", func() error {
+				return idempotencyRepo.SaveIdempotentResponse(ctx, userID, "key", "owner", model.IdempotentResponse{Status: 200, Body: []byte("{}")}, time.Hour)
+			}},
+			{"IdempotencyRepository.DeleteIdempotencyKey", func() error {
+				return idempotencyRepo.DeleteIdempotencyKey(ctx, userID, "key", "owner")
+			}},
+			{"IdempotencyRepository.DeleteExpiredIdempotencyKeys", func() error {
+				_, err := idempotencyRepo.DeleteExpiredIdempotencyKeys(ctx)
+				return err
+			}},
+
+			{"OutboxRepository.SaveEvents", func() error {
+				return outboxRepo.SaveEvents(ctx, model.ProductDeleted{ProductID: productID})
+			}},
+			{"OutboxRepository.PendingEvents", func() (err error) {
+				events, err = outboxRepo.PendingEvents(ctx, 10)
+				require.NotEmpty(t, events)
+				return err
+			}},
+			{"OutboxRepository.RetryEvent", func() error {
+				return outboxRepo.RetryEvent(ctx, events[0].ID, time.Minute)
+			}},
+			{"OutboxRepository.MarkEventDelivered", func() error {
+				return outboxRepo.MarkEventDelivered(ctx, events[0].ID)
+			}},
+			{"OutboxRepository.LastEventID", func() error {
+				_, err := outboxRepo.LastEventID(ctx)
+				return err
+			}},
+			{"OutboxRepository.EventsAfter", func() error {
+				_, err := outboxRepo.EventsAfter(ctx, 0, 10)
+				return err
+			}},
+			{"OutboxRepository.LatestEvents", func() error {
+				_, err := outboxRepo.LatestEvents(ctx, 0, events[0].ID, 10)
+				return err
+			}},
+			{"OutboxRepository.DeleteDeliveredEvents", func() error {
+				_, err := outboxRepo.DeleteDeliveredEvents(ctx, time.Hour)
+				return err
+			}},
+
+			{"WebhookRepository.CreateWebhook", func() error {
+				webhook, err := webhookRepo.CreateWebhook(ctx, model.Webhook{UserID: userID, URL: url, EventTypes: []string{model.WebhookAllEvents}, Secret: "secret"})
+				webhookID = webhook.ID
+				return err
+			}},
+			{"WebhookRepository.ListWebhooks", func() error {
+				_, err := webhookRepo.ListWebhooks(ctx, userID)
+				return err
+			}},
+			{"WebhookRepository.GetWebhook", func() error {
+				_, err := webhookRepo.GetWebhook(ctx, userID, webhookID)
+				return err
+			}},
+			{"WebhookRepository.UpdateWebhook", func() error {
+				_, err := webhookRepo.UpdateWebhook(ctx, userID, webhookID, model.WebhookUpdate{URL: &url}, 1)
+				return err
+			}},
+			{"WebhookRepository.EnqueueWebhookDeliveries", func() error {
+				_, err := webhookRepo.EnqueueWebhookDeliveries(ctx, events[0])
+				return err
+			}},
+			{"WebhookRepository.ClaimWebhookDeliveries", func() error {
+				due, err := webhookRepo.ClaimWebhookDeliveries(ctx, 10, time.Minute)
+				require.NotEmpty(t, due)
+				deliveryID = due[0].DeliveryID
+				return err
+			}},
+			{"WebhookRepository.RecordWebhookAttempt", func() error {
+				attempt := model.WebhookAttempt{ResponseCode: &code, DurationMs: 5, AttemptedAt: time.Now()}
+				return webhookRepo.RecordWebhookAttempt(ctx, deliveryID, attempt, model.WebhookDeliverySucceeded, nil)
+			}},
+			{"WebhookRepository.ListWebhookDeliveries", func() error {
+				_, err := webhookRepo.ListWebhookDeliveries(ctx, userID, webhookID, 10)
+				return err
+			}},
+			{"WebhookRepository.GetWebhookDelivery", func() error {
+				_, err := webhookRepo.GetWebhookDelivery(ctx, userID, webhookID, deliveryID)
+				return err
+			}},
+			{"WebhookRepository.RedeliverWebhook", func() error {
+				return webhookRepo.RedeliverWebhook(ctx, userID, webhookID, deliveryID)
+			}},
+			{"WebhookRepository.DeleteWebhook", func() error {
+				return webhookRepo.DeleteWebhook(ctx, userID, webhookID, 2)
+			}},
+
+			{"ProductRepository.DeleteProduct", func() error {
+				return productRepo.DeleteProduct(ctx, productID, 0)
+			}},
+			{"CategoryRepository.DeleteCategory", func() error {
+				return categoryRepo.DeleteCategory(ctx, categoryID, 0)
+			}},
+		}
+
+		checked := make(map[string]bool, len(checks))
+		for _, check := range checks {
+			require.NoError(t, check.call(), check.method)
+			checked[check.method] = true
+		}
+
+		for _, repo := range []any{authRepo, categoryRepo, productRepo, idempotencyRepo, outboxRepo, webhookRepo} {
+			typ := reflect.TypeOf(repo)
+			for i := 0; i < typ.NumMethod(); i++ {
+				method := typ.Elem().Name() + "." + typ.Method(i).Name
+				assert.True(t, checked[method], "%s is not checked against the schema", method)
+			}
+		}
+	})
+}
@@ -0,0 +1,132 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"goapi/internal/lib/logger/logctx"
+	"goapi/internal/lib/logger/sl"
+	"log/slog"
+)
+
+const (
+	scopeSavepoint = "tx_scope"
+)
+
+type txKey struct{}
+
+// Transactor выполняет несколько вызовов репозиториев в одной транзакции:
+// репозитории, вызванные с контекстом из WithinTx, работают в его транзакции
+type Transactor struct {
+	db  *sqlx.DB
+	log *slog.Logger
+}
+
+func NewTransactor(db *sqlx.DB, l *slog.Logger) *Transactor {
+	return &Transactor{
+		db:  db,
+		log: l,
+	}
+}
+
+// WithinTx вызывает fn в транзакции и фиксирует ее, если fn не вернула ошибку.
+// Если ctx уже содержит транзакцию, fn выполняется в ней.
+func (t *Transactor) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	const op = "sqlstore.WithinTx"
+
+	if _, ok := ctx.Value(txKey{}).(*sqlx.Tx); ok {
+		return fn(ctx)
+	}
+
+	log := logctx.From(ctx, t.log).With(
+		slog.String("op", op),
+	)
+
+	tx, err := t.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error(ErrStartTransaction.Error(), sl.Err(err))
+		return fmt.Errorf("%s %w", op, ErrStartTransaction)
+	}
+	defer tx.Rollback()
+
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error(ErrEndTransaction.Error(), sl.Err(err))
+		return fmt.Errorf("%s %w", op, ErrEndTransaction)
+	}
+
+	return nil
+}
+
+// txScope - транзакция одного метода репозитория. Внутри транзакции из ctx
+// она оформляется точкой сохранения: откат метода не затрагивает внешнюю транзакцию,
+// а фиксирует изменения владелец внешней транзакции.
+type txScope struct {
+	*sqlx.Tx
+	nested bool
+	done   bool
+}
+
+// beginTx продолжает транзакцию из ctx или начинает новую
+func beginTx(ctx context.Context, db *sqlx.DB) (*txScope, error) {
+	if tx, ok := ctx.Value(txKey{}).(*sqlx.Tx); ok {
+		if _, err := tx.Exec("SAVEPOINT " + scopeSavepoint); err != nil {
+			return nil, err
+		}
+		return &txScope{Tx: tx, nested: true}, nil
+	}
+
+	tx, err := db.Beginx()
+	if err != nil {
+		return nil, err
+	}
+
+	return &txScope{Tx: tx}, nil
+}
+
+func (t *txScope) Commit() error {
+	if t.done {
+		return sql.ErrTxDone
+	}
+	t.done = true
+
+	if t.nested {
+		_, err := t.Exec("RELEASE SAVEPOINT " + scopeSavepoint)
+		return err
+	}
+
+	return t.Tx.Commit()
+}
+
+func (t *txScope) Rollback() error {
+	if t.done {
+		return sql.ErrTxDone
+	}
+	t.done = true
+
+	if t.nested {
+		_, err := t.Exec("ROLLBACK TO SAVEPOINT " + scopeSavepoint)
+		return err
+	}
+
+	return t.Tx.Rollback()
+}
+
+// executor - запросы без явной транзакции: *sqlx.DB или *sqlx.Tx
+type executor interface {
+	sqlx.Ext
+	sqlx.ExtContext
+}
+
+// conn возвращает транзакцию из ctx, а без нее - пул соединений
+func conn(ctx context.Context, db *sqlx.DB) executor {
+	if tx, ok := ctx.Value(txKey{}).(*sqlx.Tx); ok {
+		return tx
+	}
+
+	return db
+}
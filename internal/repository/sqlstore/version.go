@@ -0,0 +1,39 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"goapi/internal/repository"
+)
+
+// withVersion добавляет к условию запроса проверку версии строки.
+// Нулевая версия означает изменение без проверки; сервисы ее не пропускают, версию требуют у клиента.
+func withVersion(query string, args []any, version int64) (string, []any) {
+	if version == 0 {
+		return query, args
+	}
+
+	args = append(args, version)
+	return fmt.Sprintf("%s AND version = $%d", query, len(args)), args
+}
+
+// versionConflict выясняет, почему условное изменение не затронуло строку:
+// строки нет или ее версия уже другая
+func versionConflict(q sqlx.Queryer, table string, id int64, notFound error) error {
+	query := fmt.Sprintf(
+		"SELECT version FROM %s WHERE id = $1",
+		table,
+	)
+
+	var version int64
+	if err := sqlx.Get(q, &version, query, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return notFound
+		}
+		return err
+	}
+
+	return repository.ErrVersionMismatch
+}
@@ -0,0 +1,499 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"goapi/internal/lib/logger/logctx"
+	"goapi/internal/lib/logger/sl"
+	"goapi/internal/model"
+	"goapi/internal/repository"
+	"log/slog"
+	"slices"
+	"time"
+)
+
+const (
+	webhooksTable          = "webhooks"
+	webhookDeliveriesTable = "webhook_deliveries"
+	webhookAttemptsTable   = "webhook_delivery_attempts"
+
+	webhookColumns = "id, user_id, url, event_types, secret, active, version, created_at"
+)
+
+type WebhookRepository struct {
+	db  *sqlx.DB
+	log *slog.Logger
+}
+
+func NewWebhookRepository(db *sqlx.DB, l *slog.Logger) *WebhookRepository {
+	return &WebhookRepository{
+		db:  db,
+		log: l,
+	}
+}
+
+// webhookRow - строка подписки, типы событий хранятся массивом Postgres,
+// в SQLite - тем же литералом массива в текстовой колонке
+type webhookRow struct {
+	model.Webhook
+	EventTypes pq.StringArray `db:"event_types"`
+}
+
+func (r webhookRow) webhook() model.Webhook {
+	w := r.Webhook
+	w.EventTypes = r.EventTypes
+	return w
+}
+
+func (r *WebhookRepository) CreateWebhook(ctx context.Context, webhook model.Webhook) (model.Webhook, error) {
+	const op = "sqlstore.CreateWebhook"
+	ctx, end := startQuery(ctx, op)
+	defer end()
+
+	log := logctx.From(ctx, r.log).With(
+		slog.String("op", op),
+		slog.Int64("user_id", webhook.UserID),
+	)
+
+	query := fmt.Sprintf(
+		"INSERT INTO %s (user_id, url, event_types, secret) VALUES ($1, $2, $3, $4) RETURNING %s",
+		webhooksTable, webhookColumns,
+	)
+
+	var row webhookRow
+	err := r.db.GetContext(ctx, &row, query, webhook.UserID, webhook.URL, pq.StringArray(webhook.EventTypes), webhook.Secret)
+	if err != nil {
+		log.Error("error insert webhook", sl.Err(err))
+		return model.Webhook{}, fmt.Errorf("%s %w", op, err)
+	}
+
+	log.Info("webhook saved in db")
+
+	return row.webhook(), nil
+}
+
+func (r *WebhookRepository) ListWebhooks(ctx context.Context, userID int64) ([]model.Webhook, error) {
+	const op = "sqlstore.ListWebhooks"
+	ctx, end := startQuery(ctx, op)
+	defer end()
+
+	query := fmt.Sprintf(
+		"SELECT %s FROM %s WHERE user_id = $1 ORDER BY id",
+		webhookColumns, webhooksTable,
+	)
+
+	var rows []webhookRow
+	if err := r.db.SelectContext(ctx, &rows, query, userID); err != nil {
+		r.log.Error("error select webhooks", slog.String("op", op), sl.Err(err))
+		return nil, fmt.Errorf("%s %w", op, err)
+	}
+
+	webhooks := make([]model.Webhook, 0, len(rows))
+	for _, row := range rows {
+		webhooks = append(webhooks, row.webhook())
+	}
+
+	return webhooks, nil
+}
+
+func (r *WebhookRepository) GetWebhook(ctx context.Context, userID, id int64) (model.Webhook, error) {
+	const op = "sqlstore.GetWebhook"
+	ctx, end := startQuery(ctx, op)
+	defer end()
+
+	query := fmt.Sprintf(
+		"SELECT %s FROM %s WHERE id = $1 AND user_id = $2",
+		webhookColumns, webhooksTable,
+	)
+
+	var row webhookRow
+	if err := r.db.GetContext(ctx, &row, query, id, userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Webhook{}, fmt.Errorf("%s %w", op, repository.ErrWebhookNotFound)
+		}
+		r.log.Error("error select webhook", slog.String("op", op), sl.Err(err))
+		return model.Webhook{}, fmt.Errorf("%s %w", op, err)
+	}
+
+	return row.webhook(), nil
+}
+
+// UpdateWebhook меняет заданные поля подписки и возвращает ее новое состояние
+func (r *WebhookRepository) UpdateWebhook(
+	ctx context.Context,
+	userID, id int64,
+	update model.WebhookUpdate,
+	version int64,
+) (model.Webhook, error) {
+	const op = "sqlstore.UpdateWebhook"
+	ctx, end := startQuery(ctx, op)
+	defer end()
+
+	log := logctx.From(ctx, r.log).With(
+		slog.String("op", op),
+		slog.Int64("id", id),
+	)
+
+	query, args := withVersion(fmt.Sprintf(
+		`UPDATE %s SET url = COALESCE($1, url), event_types = COALESCE($2, event_types),
+		secret = COALESCE($3, secret), active = COALESCE($4, active), version = version + 1
+		WHERE id = $5 AND user_id = $6`,
+		webhooksTable,
+	), []any{update.URL, pq.StringArray(update.EventTypes), update.Secret, update.Active, id, userID}, version)
+
+	var row webhookRow
+	err := r.db.GetContext(ctx, &row, query+" RETURNING "+webhookColumns, args...)
+	if errors.Is(err, sql.ErrNoRows) {
+		err = r.webhookConflict(ctx, userID, id)
+		log.Warn("webhook is not updated", sl.Err(err))
+		return model.Webhook{}, fmt.Errorf("%s %w", op, err)
+	}
+	if err != nil {
+		log.Error("error update webhook", sl.Err(err))
+		return model.Webhook{}, fmt.Errorf("%s %w", op, err)
+	}
+
+	return row.webhook(), nil
+}
+
+func (r *WebhookRepository) DeleteWebhook(ctx context.Context, userID, id int64, version int64) error {
+	const op = "sqlstore.DeleteWebhook"
+	ctx, end := startQuery(ctx, op)
+	defer end()
+
+	log := logctx.From(ctx, r.log).With(
+		slog.String("op", op),
+		slog.Int64("id", id),
+	)
+
+	query, args := withVersion(fmt.Sprintf(
+		"DELETE FROM %s WHERE id = $1 AND user_id = $2",
+		webhooksTable,
+	), []any{id, userID}, version)
+
+	res, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		log.Error("error delete webhook", sl.Err(err))
+		return fmt.Errorf("%s %w", op, err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s %w", op, err)
+	}
+
+	if n == 0 {
+		err := r.webhookConflict(ctx, userID, id)
+		log.Warn("webhook is not deleted", sl.Err(err))
+		return fmt.Errorf("%s %w", op, err)
+	}
+
+	return nil
+}
+
+// webhookConflict выясняет, почему условное изменение подписки не затронуло строку
+func (r *WebhookRepository) webhookConflict(ctx context.Context, userID, id int64) error {
+	query := fmt.Sprintf(
+		"SELECT version FROM %s WHERE id = $1 AND user_id = $2",
+		webhooksTable,
+	)
+
+	var version int64
+	if err := r.db.GetContext(ctx, &version, query, id, userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return repository.ErrWebhookNotFound
+		}
+		return err
+	}
+
+	return repository.ErrVersionMismatch
+}
+
+// ListWebhookDeliveries возвращает последние доставки подписки, новые первыми
+func (r *WebhookRepository) ListWebhookDeliveries(ctx context.Context, userID, webhookID int64, limit int) ([]model.WebhookDelivery, error) {
+	const op = "sqlstore.ListWebhookDeliveries"
+	ctx, end := startQuery(ctx, op)
+	defer end()
+
+	query := fmt.Sprintf(
+		`SELECT d.id, d.webhook_id, d.event_id, d.event_type, d.status, d.attempts, d.response_code, d.next_attempt_at, d.created_at
+		FROM %s d JOIN %s w ON w.id = d.webhook_id
+		WHERE d.webhook_id = $1 AND w.user_id = $2 ORDER BY d.id DESC LIMIT $3`,
+		webhookDeliveriesTable, webhooksTable,
+	)
+
+	deliveries := []model.WebhookDelivery{}
+	if err := r.db.SelectContext(ctx, &deliveries, query, webhookID, userID, limit); err != nil {
+		r.log.Error("error select webhook deliveries", slog.String("op", op), sl.Err(err))
+		return nil, fmt.Errorf("%s %w", op, err)
+	}
+
+	return deliveries, nil
+}
+
+// GetWebhookDelivery возвращает доставку вместе с журналом попыток
+func (r *WebhookRepository) GetWebhookDelivery(ctx context.Context, userID, webhookID, id int64) (model.WebhookDelivery, error) {
+	const op = "sqlstore.GetWebhookDelivery"
+	ctx, end := startQuery(ctx, op)
+	defer end()
+
+	log := logctx.From(ctx, r.log).With(
+		slog.String("op", op),
+		slog.Int64("id", id),
+	)
+
+	query := fmt.Sprintf(
+		`SELECT d.id, d.webhook_id, d.event_id, d.event_type, d.status, d.attempts, d.response_code, d.next_attempt_at, d.created_at
+		FROM %s d JOIN %s w ON w.id = d.webhook_id
+		WHERE d.id = $1 AND d.webhook_id = $2 AND w.user_id = $3`,
+		webhookDeliveriesTable, webhooksTable,
+	)
+
+	var delivery model.WebhookDelivery
+	if err := r.db.GetContext(ctx, &delivery, query, id, webhookID, userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.WebhookDelivery{}, fmt.Errorf("%s %w", op, repository.ErrWebhookDeliveryNotFound)
+		}
+		log.Error("error select webhook delivery", sl.Err(err))
+		return model.WebhookDelivery{}, fmt.Errorf("%s %w", op, err)
+	}
+
+	query = fmt.Sprintf(
+		"SELECT response_code, error, duration_ms, attempted_at FROM %s WHERE delivery_id = $1 ORDER BY id",
+		webhookAttemptsTable,
+	)
+
+	if err := r.db.SelectContext(ctx, &delivery.Log, query, id); err != nil {
+		log.Error("error select webhook delivery attempts", sl.Err(err))
+		return model.WebhookDelivery{}, fmt.Errorf("%s %w", op, err)
+	}
+
+	return delivery, nil
+}
+
+// RedeliverWebhook ставит доставку в очередь на немедленную отправку
+func (r *WebhookRepository) RedeliverWebhook(ctx context.Context, userID, webhookID, id int64) error {
+	const op = "sqlstore.RedeliverWebhook"
+	ctx, end := startQuery(ctx, op)
+	defer end()
+
+	query := fmt.Sprintf(
+		`UPDATE %s SET status = $1, next_attempt_at = $2
+		WHERE id = $3 AND webhook_id = $4 AND webhook_id IN (SELECT id FROM %s WHERE user_id = $5)`,
+		webhookDeliveriesTable, webhooksTable,
+	)
+
+	res, err := r.db.ExecContext(ctx, query, model.WebhookDeliveryPending, now(), id, webhookID, userID)
+	if err != nil {
+		r.log.Error("error redeliver webhook", slog.String("op", op), sl.Err(err))
+		return fmt.Errorf("%s %w", op, err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s %w", op, err)
+	}
+
+	if n == 0 {
+		return fmt.Errorf("%s %w", op, repository.ErrWebhookDeliveryNotFound)
+	}
+
+	return nil
+}
+
+// EnqueueWebhookDeliveries создает доставки события всем активным подпискам на его тип.
+// Повторная постановка того же события ничего не меняет.
+func (r *WebhookRepository) EnqueueWebhookDeliveries(ctx context.Context, event model.Event) (int64, error) {
+	const op = "sqlstore.EnqueueWebhookDeliveries"
+	ctx, end := startQuery(ctx, op)
+	defer end()
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		return 0, fmt.Errorf("%s %w", op, err)
+	}
+
+	log := logctx.From(ctx, r.log).With(
+		slog.String("op", op),
+		slog.Int64("event_id", event.ID),
+	)
+
+	query := fmt.Sprintf(
+		"SELECT id, event_types FROM %s WHERE active ORDER BY id",
+		webhooksTable,
+	)
+
+	var subscriptions []struct {
+		ID         int64          `db:"id"`
+		EventTypes pq.StringArray `db:"event_types"`
+	}
+	if err := r.db.SelectContext(ctx, &subscriptions, query); err != nil {
+		log.Error("error select active webhooks", sl.Err(err))
+		return 0, fmt.Errorf("%s %w", op, err)
+	}
+
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		log.Error(ErrStartTransaction.Error())
+		return 0, fmt.Errorf("%s %w", op, ErrStartTransaction)
+	}
+	defer tx.Rollback()
+
+	query = fmt.Sprintf(
+		`INSERT INTO %s (webhook_id, event_id, event_type, event) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (webhook_id, event_id) DO NOTHING`,
+		webhookDeliveriesTable,
+	)
+
+	var enqueued int64
+	for _, subscription := range subscriptions {
+		if !slices.Contains(subscription.EventTypes, string(event.Type)) && !slices.Contains(subscription.EventTypes, model.WebhookAllEvents) {
+			continue
+		}
+
+		res, err := tx.Exec(query, subscription.ID, event.ID, event.Type, body)
+		if err != nil {
+			log.Error("error enqueue webhook delivery", sl.Err(err))
+			return 0, fmt.Errorf("%s %w", op, err)
+		}
+
+		n, err := res.RowsAffected()
+		if err != nil {
+			return 0, fmt.Errorf("%s %w", op, err)
+		}
+		enqueued += n
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error(ErrEndTransaction.Error())
+		return 0, fmt.Errorf("%s %w", op, ErrEndTransaction)
+	}
+
+	return enqueued, nil
+}
+
+// ClaimWebhookDeliveries занимает доставки, время отправки которых наступило, и возвращает их.
+// Занятые доставки откладываются на lease: за это время их не займет другой доставщик,
+// а если доставщик упал, не записав попытку, доставка снова станет доступна после lease.
+// В Postgres доставщики пропускают строки, которые занимает другой, SQLite выполняет
+// транзакции записи по одной.
+func (r *WebhookRepository) ClaimWebhookDeliveries(ctx context.Context, limit int, lease time.Duration) ([]model.WebhookDispatch, error) {
+	const op = "sqlstore.ClaimWebhookDeliveries"
+	ctx, end := startQuery(ctx, op)
+	defer end()
+
+	log := logctx.From(ctx, r.log).With(
+		slog.String("op", op),
+	)
+
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		log.Error(ErrStartTransaction.Error())
+		return nil, fmt.Errorf("%s %w", op, ErrStartTransaction)
+	}
+	defer tx.Rollback()
+
+	query := fmt.Sprintf(
+		`UPDATE %[1]s SET next_attempt_at = $1
+		WHERE id IN (
+			SELECT id FROM %[1]s
+			WHERE status = $2 AND next_attempt_at <= $3 AND webhook_id IN (SELECT id FROM %[2]s WHERE active)
+			ORDER BY id LIMIT $4%[3]s
+		)
+		RETURNING id`,
+		webhookDeliveriesTable, webhooksTable, skipLocked(tx),
+	)
+
+	t := now()
+	var ids []int64
+	if err := tx.Select(&ids, query, t.Add(lease), model.WebhookDeliveryPending, t, limit); err != nil {
+		log.Error("error claim webhook deliveries", sl.Err(err))
+		return nil, fmt.Errorf("%s %w", op, err)
+	}
+
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	where, arg := inIDs(tx, "d.id", 1, ids)
+	query = fmt.Sprintf(
+		`SELECT d.id, d.webhook_id, w.url, w.secret, d.attempts, d.event, d.event_type
+		FROM %s d JOIN %s w ON w.id = d.webhook_id
+		WHERE %s ORDER BY d.id`,
+		webhookDeliveriesTable, webhooksTable, where,
+	)
+
+	var dispatches []model.WebhookDispatch
+	if err := tx.Select(&dispatches, query, arg); err != nil {
+		log.Error("error select claimed webhook deliveries", sl.Err(err))
+		return nil, fmt.Errorf("%s %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error(ErrEndTransaction.Error())
+		return nil, fmt.Errorf("%s %w", op, ErrEndTransaction)
+	}
+
+	return dispatches, nil
+}
+
+// RecordWebhookAttempt пишет попытку в журнал и переводит доставку в статус status.
+// nextAttemptAt задает время повтора для статуса pending.
+func (r *WebhookRepository) RecordWebhookAttempt(
+	ctx context.Context,
+	deliveryID int64,
+	attempt model.WebhookAttempt,
+	status model.WebhookDeliveryStatus,
+	nextAttemptAt *time.Time,
+) error {
+	const op = "sqlstore.RecordWebhookAttempt"
+	ctx, end := startQuery(ctx, op)
+	defer end()
+
+	log := logctx.From(ctx, r.log).With(
+		slog.String("op", op),
+		slog.Int64("delivery_id", deliveryID),
+	)
+
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		log.Error(ErrStartTransaction.Error())
+		return fmt.Errorf("%s %w", op, ErrStartTransaction)
+	}
+	defer tx.Rollback()
+
+	query := fmt.Sprintf(
+		"INSERT INTO %s (delivery_id, response_code, error, duration_ms, attempted_at) VALUES ($1, $2, $3, $4, $5)",
+		webhookAttemptsTable,
+	)
+	// SQLite сравнивает время как строки, поэтому все время хранится в UTC
+	if _, err := tx.Exec(query, deliveryID, attempt.ResponseCode, attempt.Error, attempt.DurationMs, attempt.AttemptedAt.UTC()); err != nil {
+		log.Error("error insert webhook attempt", sl.Err(err))
+		return fmt.Errorf("%s %w", op, err)
+	}
+
+	query = fmt.Sprintf(
+		"UPDATE %s SET status = $1, attempts = attempts + 1, response_code = $2, next_attempt_at = $3 WHERE id = $4",
+		webhookDeliveriesTable,
+	)
+	if nextAttemptAt != nil {
+		next := nextAttemptAt.UTC()
+		nextAttemptAt = &next
+	}
+	if _, err := tx.Exec(query, status, attempt.ResponseCode, nextAttemptAt, deliveryID); err != nil {
+		log.Error("error update webhook delivery", sl.Err(err))
+		return fmt.Errorf("%s %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error(ErrEndTransaction.Error())
+		return fmt.Errorf("%s %w", op, ErrEndTransaction)
+	}
+
+	return nil
+}
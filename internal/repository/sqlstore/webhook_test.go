@@ -0,0 +1,151 @@
+package sqlstore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"goapi/internal/model"
+	"goapi/internal/repository"
+)
+
+// createWebhooks создает пользователя и по подписке на каждый набор типов событий
+func createWebhooks(t *testing.T, db *sqlx.DB, eventTypes ...[]string) (int64, []model.Webhook) {
+	t.Helper()
+
+	ctx := context.Background()
+	userID, err := NewAuthRepository(db, testLogger()).SaveUser(ctx, "user@example.com", []byte("hash"), model.RoleUser)
+	require.NoError(t, err)
+
+	repo := NewWebhookRepository(db, testLogger())
+	webhooks := make([]model.Webhook, 0, len(eventTypes))
+	for _, types := range eventTypes {
+		webhook, err := repo.CreateWebhook(ctx, model.Webhook{UserID: userID, URL: "https://example.com", EventTypes: types, Secret: "secret"})
+		require.NoError(t, err)
+		webhooks = append(webhooks, webhook)
+	}
+
+	return userID, webhooks
+}
+
+func TestUpdateWebhook(t *testing.T) {
+	active := false
+
+	tests := []struct {
+		name          string
+		id            func(webhook model.Webhook) int64
+		version       int64
+		expectedError error
+	}{
+		{
+			name:    "OK",
+			id:      func(webhook model.Webhook) int64 { return webhook.ID },
+			version: 1,
+		},
+		{
+			name:          "Version Mismatch",
+			id:            func(webhook model.Webhook) int64 { return webhook.ID },
+			version:       3,
+			expectedError: repository.ErrVersionMismatch,
+		},
+		{
+			name:          "Not Found",
+			id:            func(webhook model.Webhook) int64 { return webhook.ID + 1 },
+			version:       1,
+			expectedError: repository.ErrWebhookNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			forEachBackend(t, func(t *testing.T, db *sqlx.DB) {
+				userID, webhooks := createWebhooks(t, db, []string{string(model.EventProductCreated)})
+				repo := NewWebhookRepository(db, testLogger())
+
+				webhook, err := repo.UpdateWebhook(context.Background(), userID, test.id(webhooks[0]), model.WebhookUpdate{Active: &active}, test.version)
+				if test.expectedError != nil {
+					assert.ErrorIs(t, err, test.expectedError)
+					return
+				}
+
+				require.NoError(t, err)
+				assert.Equal(t, []string{"product.created"}, webhook.EventTypes)
+				assert.False(t, webhook.Active)
+				assert.Equal(t, int64(2), webhook.Version)
+			})
+		})
+	}
+}
+
+func TestEnqueueWebhookDeliveries(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, db *sqlx.DB) {
+		ctx := context.Background()
+		userID, webhooks := createWebhooks(t, db,
+			[]string{string(model.EventProductDeleted)},
+			[]string{string(model.EventProductCreated)},
+			[]string{model.WebhookAllEvents},
+			[]string{string(model.EventProductCreated), model.WebhookAllEvents},
+		)
+		repo := NewWebhookRepository(db, testLogger())
+
+		// неактивная подписка не получает события
+		active := false
+		_, err := repo.UpdateWebhook(ctx, userID, webhooks[3].ID, model.WebhookUpdate{Active: &active}, 1)
+		require.NoError(t, err)
+
+		event := model.Event{ID: 7, Type: model.EventProductDeleted}
+		n, err := repo.EnqueueWebhookDeliveries(ctx, event)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), n)
+
+		// подписка 2 не получает событие, повторная постановка ничего не добавляет
+		n, err = repo.EnqueueWebhookDeliveries(ctx, event)
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), n)
+
+		for i, expected := range []int{1, 0, 1, 0} {
+			deliveries, err := repo.ListWebhookDeliveries(ctx, userID, webhooks[i].ID, 10)
+			require.NoError(t, err)
+			assert.Len(t, deliveries, expected, "webhook %d", i)
+		}
+	})
+}
+
+func TestRecordWebhookAttempt(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, db *sqlx.DB) {
+		ctx := context.Background()
+		userID, webhooks := createWebhooks(t, db, []string{model.WebhookAllEvents})
+		repo := NewWebhookRepository(db, testLogger())
+
+		_, err := repo.EnqueueWebhookDeliveries(ctx, model.Event{ID: 7, Type: model.EventProductDeleted})
+		require.NoError(t, err)
+
+		code := 500
+		now := time.Now().UTC().Truncate(time.Second)
+		next := now.Add(time.Minute)
+
+		err = repo.RecordWebhookAttempt(ctx, 1, model.WebhookAttempt{
+			ResponseCode: &code,
+			DurationMs:   12,
+			AttemptedAt:  now,
+		}, model.WebhookDeliveryPending, &next)
+		require.NoError(t, err)
+
+		delivery, err := repo.GetWebhookDelivery(ctx, userID, webhooks[0].ID, 1)
+		require.NoError(t, err)
+		assert.Equal(t, model.WebhookDeliveryPending, delivery.Status)
+		assert.Equal(t, 1, delivery.Attempts)
+		assert.Equal(t, &code, delivery.ResponseCode)
+		if assert.NotNil(t, delivery.NextAttemptAt) {
+			assert.True(t, next.Equal(*delivery.NextAttemptAt), "next attempt at %s", delivery.NextAttemptAt)
+		}
+		if assert.Len(t, delivery.Log, 1) {
+			assert.Equal(t, &code, delivery.Log[0].ResponseCode)
+			assert.Equal(t, int64(12), delivery.Log[0].DurationMs)
+			assert.True(t, now.Equal(delivery.Log[0].AttemptedAt))
+		}
+	})
+}
@@ -0,0 +1,21 @@
+package service
+
+import (
+	"context"
+	"goapi/internal/lib/apperr"
+	"goapi/internal/lib/authctx"
+)
+
+// ErrAdminRequired - действие доступно только администратору: импорт каталога,
+// удаление товаров и категорий, управление вебхуками
+var ErrAdminRequired = apperr.Forbidden("admin_required", "admin role is required")
+
+// requireAdmin проверяет роль пользователя запроса. Без пользователя в контексте доступ
+// запрещен: транспорты кладут его после проверки токена, команды оператора - явно
+func requireAdmin(ctx context.Context) error {
+	if !authctx.IsAdmin(ctx) {
+		return ErrAdminRequired
+	}
+
+	return nil
+}
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"goapi/internal/lib/apperr"
+	"goapi/internal/lib/authctx"
+	"goapi/internal/lib/catalog"
+	"goapi/internal/model"
+)
+
+var (
+	adminCtx = authctx.With(context.Background(), authctx.User{ID: 1, Role: model.RoleAdmin})
+	userCtx  = authctx.With(context.Background(), authctx.User{ID: 2, Role: model.RoleUser})
+)
+
+func TestAdminRequired(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	// до проверки роли сервисы не обращаются к хранилищу, поэтому зависимости не нужны
+	products := NewProductService(nil, nil, nil, nil, nil, nil, nil, logger)
+	categoryies := NewCategoryService(nil, nil, nil, nil, nil, nil, logger)
+	catalogs := NewCatalogService(nil, nil, nil, nil, logger)
+	webhooks := NewWebhookService(nil, nil, nil, nil, logger)
+
+	calls := map[string]func(ctx context.Context) error{
+		"DeleteProduct": func(ctx context.Context) error {
+			return products.DeleteProduct(ctx, 1, 1)
+		},
+		"DeleteCategory": func(ctx context.Context) error {
+			return categoryies.DeleteCategory(ctx, 1, 1)
+		},
+		"ImportProducts": func(ctx context.Context) error {
+			_, err := catalogs.ImportProducts(ctx, strings.NewReader(""), catalog.FormatCSV, false)
+			return err
+		},
+		"CreateWebhook": func(ctx context.Context) error {
+			_, err := webhooks.CreateWebhook(ctx, 2, "https://example.com/hook", []string{model.WebhookAllEvents}, "")
+			return err
+		},
+		"ListWebhooks": func(ctx context.Context) error {
+			_, err := webhooks.ListWebhooks(ctx, 2)
+			return err
+		},
+		"RedeliverWebhook": func(ctx context.Context) error {
+			return webhooks.RedeliverWebhook(ctx, 2, 1, 1)
+		},
+	}
+
+	for name, call := range calls {
+		t.Run(name, func(t *testing.T) {
+			err := call(userCtx)
+			assert.ErrorIs(t, err, ErrAdminRequired)
+			assert.Equal(t, apperr.KindForbidden, apperr.KindOf(err))
+
+			// без пользователя в контексте доступ тоже запрещен
+			assert.ErrorIs(t, call(context.Background()), ErrAdminRequired)
+		})
+	}
+}
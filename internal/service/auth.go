@@ -4,7 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"goapi/internal/lib/jwt"
+	"goapi/internal/lib/apperr"
+	"goapi/internal/lib/logger/logctx"
+	"goapi/internal/lib/logger/sl"
+	"goapi/internal/lib/validate"
 	"goapi/internal/model"
 	"goapi/internal/repository"
 	"golang.org/x/crypto/bcrypt"
@@ -19,32 +22,41 @@ const (
 )
 
 var (
-	ErrorInvalidCredentials = errors.New("invalid credential")
-	ErrorUserExist          = errors.New("user already exist")
-	ErrPasswordIsEmpty      = errors.New("password is empty")
-	ErrEmailIsEmpty         = errors.New("email is empty")
-	ErrFailedToSaveUser     = errors.New("failed to save user")
+	ErrorInvalidCredentials = apperr.Unauthorized("invalid_credentials", "invalid credential")
+	ErrorUserExist          = apperr.Conflict("user_already_exists", "user already exist")
+	ErrPasswordIsEmpty      = apperr.InvalidField("password_empty", "/password", "password is empty")
+	ErrEmailIsEmpty         = apperr.InvalidField("email_empty", "/email", "email is empty")
+	ErrFailedToSaveUser     = apperr.Internal("user_not_saved", "failed to save user")
+	ErrInvalidRole          = apperr.InvalidField("invalid_role", "/role", "role must be user or admin")
+	ErrUserNotFound         = apperr.NotFound("user_not_found", "user not found")
 )
 
 type AuthService struct {
 	usrSaver    UserSaver
 	usrProvider UserProvider
+	tokens      TokenIssuer
 	log         *slog.Logger
 	tokenTTL    time.Duration
 }
 
 type UserSaver interface {
-	SaveUser(ctx context.Context, email string, passHash []byte) (int64, error)
+	SaveUser(ctx context.Context, email string, passHash []byte, role model.Role) (int64, error)
 }
 
 type UserProvider interface {
 	User(ctx context.Context, email string) (model.User, error)
 }
 
-func NewAuthService(us UserSaver, up UserProvider, l *slog.Logger, tokenTTL time.Duration) *AuthService {
+// TokenIssuer выпускает токены доступа
+type TokenIssuer interface {
+	NewToken(user model.User, duration time.Duration) (string, error)
+}
+
+func NewAuthService(us UserSaver, up UserProvider, ti TokenIssuer, l *slog.Logger, tokenTTL time.Duration) *AuthService {
 	return &AuthService{
 		usrSaver:    us,
 		usrProvider: up,
+		tokens:      ti,
 		log:         l,
 		tokenTTL:    tokenTTL,
 	}
@@ -52,8 +64,10 @@ func NewAuthService(us UserSaver, up UserProvider, l *slog.Logger, tokenTTL time
 
 func (s *AuthService) Login(ctx context.Context, email, password string) (string, error) {
 	const op = "postgres.Login"
+	ctx, span := tracer.Start(ctx, "AuthService.Login")
+	defer span.End()
 
-	log := s.log.With(
+	log := logctx.From(ctx, s.log).With(
 		slog.String("op", op),
 		slog.String("email", email),
 	)
@@ -61,66 +75,91 @@ func (s *AuthService) Login(ctx context.Context, email, password string) (string
 	log.Info("logging user")
 
 	if err := s.validate(email, password); err != nil {
-		log.Error("data is invalid ", err)
+		log.Error("data is invalid", sl.Err(err))
 		return "", fmt.Errorf("data is invalid: %w", err)
 	}
 
+	if err := validate.Struct(signInInput{Email: email, Password: password}); err != nil {
+		log.Error("data is invalid", sl.Err(err))
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
 	user, err := s.usrProvider.User(ctx, email)
 	if err != nil {
 		if errors.Is(err, repository.ErrUserNotFound) {
-			s.log.Warn("user not found", err)
+			log.Warn("user not found", sl.Err(err))
 			return "", fmt.Errorf("%s: %w", op, ErrorInvalidCredentials)
 		}
 
-		s.log.Warn("failed to get user", err)
+		log.Error("failed to get user", sl.Err(err))
 		return "", fmt.Errorf("%s: %w", op, err)
 	}
 
 	if err := bcrypt.CompareHashAndPassword(user.PassHash, []byte(password)); err != nil {
-		s.log.Warn("pass", string(user.PassHash), password)
-		s.log.Warn("invalid credential", err)
+		log.Warn("invalid credential", sl.Err(err))
 		return "", fmt.Errorf("%s: %w", op, ErrorInvalidCredentials)
 	}
 
 	log.Info("user is login successfully")
 
-	token, err := jwt.NewToken(user, s.tokenTTL)
+	token, err := s.tokens.NewToken(user, s.tokenTTL)
 	if err != nil {
-		s.log.Warn("failed to generate token", err)
+		log.Error("failed to generate token", sl.Err(err))
 		return "", fmt.Errorf("%s: %w", op, err)
 	}
 
 	return token, nil
 }
 
+// Register регистрирует пользователя с ролью user
 func (s *AuthService) Register(ctx context.Context, email, password string) (userID int64, err error) {
+	return s.CreateUser(ctx, email, password, model.RoleUser)
+}
+
+// CreateUser создает пользователя с ролью role. Через API регистрируются только пользователи,
+// администраторов заводит команда user create.
+func (s *AuthService) CreateUser(ctx context.Context, email, password string, role model.Role) (userID int64, err error) {
 	const op = "postgres.RegisterNewUser"
+	ctx, span := tracer.Start(ctx, "AuthService.CreateUser")
+	defer span.End()
 
-	log := s.log.With(
+	log := logctx.From(ctx, s.log).With(
 		slog.String("op", op),
 		slog.String("email", email),
+		slog.String("role", string(role)),
 	)
 
 	log.Info("registering user")
 
+	if !role.Valid() {
+		log.Warn("unknown role")
+		return ErrUserID, fmt.Errorf("%s: %w", op, ErrInvalidRole)
+	}
+
 	if err := s.validate(email, password); err != nil {
-		log.Error("data is invalid ", err)
+		log.Error("data is invalid", sl.Err(err))
 		return ErrUserID, fmt.Errorf("data is invalid: %w", err)
 	}
 
+	if err := validate.Struct(signUpInput{Email: email, Password: password}); err != nil {
+		log.Error("data is invalid", sl.Err(err))
+		return ErrUserID, fmt.Errorf("%s: %w", op, err)
+	}
+
 	passHash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 	if err != nil {
-		if errors.Is(err, repository.ErrUserExist) {
-			s.log.Warn("app id not found", err)
-			return ErrUserID, fmt.Errorf("%s: %w", op, ErrorUserExist)
-		}
-		log.Error("failed to get password hash: ", err)
+		log.Error("failed to get password hash", sl.Err(err))
 		return ErrUserID, fmt.Errorf("%s %w", op, err)
 	}
 
-	id, err := s.usrSaver.SaveUser(ctx, email, passHash)
+	id, err := s.usrSaver.SaveUser(ctx, email, passHash, role)
 	if err != nil {
-		log.Error("failed to save user: ", err)
+		if errors.Is(err, repository.ErrUserExist) {
+			log.Warn("user already exist", sl.Err(err))
+			return ErrUserID, fmt.Errorf("%s: %w", op, ErrorUserExist)
+		}
+
+		log.Error("failed to save user", sl.Err(err))
 		return ErrUserID, fmt.Errorf("%s %w", op, ErrFailedToSaveUser)
 	}
 
@@ -129,6 +168,44 @@ func (s *AuthService) Register(ctx context.Context, email, password string) (use
 	return id, nil
 }
 
+// IssueToken выпускает токен пользователя без пароля, для отладки из командной строки.
+// Нулевой ttl означает срок жизни токена из конфига.
+func (s *AuthService) IssueToken(ctx context.Context, email string, ttl time.Duration) (string, error) {
+	const op = "auth.IssueToken"
+	ctx, span := tracer.Start(ctx, "AuthService.IssueToken")
+	defer span.End()
+
+	log := logctx.From(ctx, s.log).With(
+		slog.String("op", op),
+		slog.String("email", email),
+	)
+
+	user, err := s.usrProvider.User(ctx, email)
+	if err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			log.Warn("user not found", sl.Err(err))
+			return "", fmt.Errorf("%s: %w", op, ErrUserNotFound)
+		}
+
+		log.Error("failed to get user", sl.Err(err))
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	if ttl <= 0 {
+		ttl = s.tokenTTL
+	}
+
+	token, err := s.tokens.NewToken(user, ttl)
+	if err != nil {
+		log.Error("failed to generate token", sl.Err(err))
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("token issued", slog.Duration("ttl", ttl))
+
+	return token, nil
+}
+
 func (s *AuthService) validate(email, password string) error {
 	if email == "" {
 		return ErrEmailIsEmpty
@@ -2,7 +2,9 @@ package service
 
 import (
 	"context"
+	"goapi/internal/lib/jwt"
 	"goapi/internal/model"
+	"goapi/internal/repository"
 	"log/slog"
 	"os"
 	"testing"
@@ -13,6 +15,8 @@ import (
 	mock_service "goapi/internal/service/mock"
 )
 
+var testSigner = jwt.NewSigner("test-signing-key")
+
 func TestLoginOk(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -20,7 +24,7 @@ func TestLoginOk(t *testing.T) {
 	mockUserProvider := mock_service.NewMockUserProvider(ctrl)
 	mockLogger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
 
-	authService := NewAuthService(nil, mockUserProvider, mockLogger, time.Minute)
+	authService := NewAuthService(nil, mockUserProvider, testSigner, mockLogger, time.Minute)
 
 	testEmail := "test@example.com"
 	testPassword := "1"
@@ -39,7 +43,7 @@ func TestLoginFailed(t *testing.T) {
 	mockUserProvider := mock_service.NewMockUserProvider(ctrl)
 	mockLogger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
 
-	authService := NewAuthService(nil, mockUserProvider, mockLogger, time.Minute)
+	authService := NewAuthService(nil, mockUserProvider, testSigner, mockLogger, time.Minute)
 
 	testEmail := "test@example.com"
 	testPassword := "0"
@@ -58,13 +62,80 @@ func TestRegisterOk(t *testing.T) {
 	mockUserSaver := mock_service.NewMockUserSaver(ctrl)
 	mockLogger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
 
-	authService := NewAuthService(mockUserSaver, nil, mockLogger, time.Minute)
+	authService := NewAuthService(mockUserSaver, nil, testSigner, mockLogger, time.Minute)
 
 	testEmail := "test@example.com"
 	testPassword := "password"
-	mockUserSaver.EXPECT().SaveUser(gomock.Any(), testEmail, gomock.Any()).Return(int64(1), nil)
+	mockUserSaver.EXPECT().SaveUser(gomock.Any(), testEmail, gomock.Any(), model.RoleUser).Return(int64(1), nil)
 
 	userID, err := authService.Register(context.Background(), testEmail, testPassword)
 	assert.NoError(t, err)
 	assert.NotEqual(t, ErrUserID, userID)
 }
+
+func TestCreateUser(t *testing.T) {
+	tests := []struct {
+		name        string
+		role        model.Role
+		saves       bool
+		expectedErr error
+	}{
+		{name: "Admin", role: model.RoleAdmin, saves: true},
+		{name: "User", role: model.RoleUser, saves: true},
+		{name: "Unknown Role", role: "root", expectedErr: ErrInvalidRole},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockUserSaver := mock_service.NewMockUserSaver(ctrl)
+			mockLogger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+			authService := NewAuthService(mockUserSaver, nil, testSigner, mockLogger, time.Minute)
+
+			if test.saves {
+				mockUserSaver.EXPECT().SaveUser(gomock.Any(), "admin@example.com", gomock.Any(), test.role).Return(int64(7), nil)
+			}
+
+			userID, err := authService.CreateUser(context.Background(), "admin@example.com", "password", test.role)
+			assert.ErrorIs(t, err, test.expectedErr)
+			if test.expectedErr == nil {
+				assert.Equal(t, int64(7), userID)
+			}
+		})
+	}
+}
+
+func TestIssueToken(t *testing.T) {
+	tests := []struct {
+		name        string
+		userErr     error
+		expectedErr error
+	}{
+		{name: "Ok"},
+		{name: "User Not Found", userErr: repository.ErrUserNotFound, expectedErr: ErrUserNotFound},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockUserProvider := mock_service.NewMockUserProvider(ctrl)
+			mockLogger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+			authService := NewAuthService(nil, mockUserProvider, testSigner, mockLogger, time.Minute)
+
+			mockUser := model.User{ID: 1, Email: "admin@example.com", Role: model.RoleAdmin}
+			mockUserProvider.EXPECT().User(gomock.Any(), "admin@example.com").Return(mockUser, test.userErr)
+
+			token, err := authService.IssueToken(context.Background(), "admin@example.com", 0)
+			assert.ErrorIs(t, err, test.expectedErr)
+			if test.expectedErr == nil {
+				assert.NotEmpty(t, token)
+			}
+		})
+	}
+}
@@ -0,0 +1,260 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"goapi/internal/lib/apperr"
+	"goapi/internal/lib/catalog"
+	"goapi/internal/lib/logger/logctx"
+	"goapi/internal/lib/logger/sl"
+	"goapi/internal/lib/validate"
+	"goapi/internal/model"
+	"goapi/internal/repository"
+	"io"
+	"log/slog"
+)
+
+//go:generate mockgen -source=catalog.go -destination=mock/catalog_mock.go
+
+var (
+	ErrImportDuplicateSKU = apperr.InvalidField("duplicate_sku", "/sku", "sku is repeated in the file")
+	ErrImportTooLarge     = apperr.InvalidField("import_too_large", "/file", fmt.Sprintf("file must contain at most %d rows", validate.MaxImportRows))
+)
+
+type CatalogService struct {
+	exporter ExporterCatalog
+	importer ImporterCatalog
+	tx       Transactor
+	events   EventSaver
+	log      *slog.Logger
+}
+
+type ExporterCatalog interface {
+	ExportProducts(ctx context.Context, fn func(model.Product) error) error
+}
+
+type ImporterCatalog interface {
+	ImportProducts(ctx context.Context, rows []model.ImportRow, dryRun bool) ([]model.ImportResult, error)
+}
+
+func NewCatalogService(
+	e ExporterCatalog,
+	i ImporterCatalog,
+	tx Transactor,
+	ev EventSaver,
+	l *slog.Logger,
+) *CatalogService {
+	return &CatalogService{
+		exporter: e,
+		importer: i,
+		tx:       tx,
+		events:   ev,
+		log:      l,
+	}
+}
+
+// ExportProducts записывает весь каталог в w в формате format
+func (s *CatalogService) ExportProducts(ctx context.Context, w io.Writer, format catalog.Format) error {
+	const op = "catalog.ExportProducts"
+	ctx, span := tracer.Start(ctx, "CatalogService.ExportProducts")
+	defer span.End()
+
+	log := logctx.From(ctx, s.log).With(
+		slog.String("op", op),
+		slog.String("format", string(format)),
+	)
+
+	log.Info("export products")
+
+	enc := catalog.NewEncoder(format, w)
+	if err := s.exporter.ExportProducts(ctx, enc.Encode); err != nil {
+		log.Error("products didnt exported", sl.Err(err))
+		return fmt.Errorf("%s %w", op, err)
+	}
+
+	if err := enc.Flush(); err != nil {
+		log.Error("products didnt flushed", sl.Err(err))
+		return fmt.Errorf("%s %w", op, err)
+	}
+
+	log.Info("products exported")
+
+	return nil
+}
+
+// ImportProducts создает или обновляет товары из файла по артикулу. Строки проверяются
+// теми же правилами, что и в AddProduct; ошибки строк возвращаются в отчете.
+func (s *CatalogService) ImportProducts(
+	ctx context.Context,
+	r io.Reader,
+	format catalog.Format,
+	dryRun bool,
+) (model.ImportReport, error) {
+	const op = "catalog.ImportProducts"
+	ctx, span := tracer.Start(ctx, "CatalogService.ImportProducts")
+	defer span.End()
+
+	log := logctx.From(ctx, s.log).With(
+		slog.String("op", op),
+		slog.String("format", string(format)),
+		slog.Bool("dry_run", dryRun),
+	)
+
+	log.Info("import products")
+
+	if err := requireAdmin(ctx); err != nil {
+		log.Warn("access denied", sl.Err(err))
+		return model.ImportReport{}, fmt.Errorf("%s %w", op, err)
+	}
+
+	report := model.ImportReport{DryRun: dryRun}
+
+	var valid []model.ImportRow
+	var indexes []int
+	skus := make(map[string]struct{})
+
+	dec := catalog.NewDecoder(format, r)
+	for {
+		row, err := dec.Decode()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		var rowErr *catalog.RowError
+		if errors.As(err, &rowErr) {
+			report.Rows = append(report.Rows, model.ImportResult{
+				Line:   rowErr.Line,
+				Status: model.ImportFailed,
+				Err:    rowErr.Err,
+			})
+			continue
+		}
+		if err != nil {
+			log.Error("file is invalid", sl.Err(err))
+			return model.ImportReport{}, fmt.Errorf("%s %w", op, err)
+		}
+
+		if len(report.Rows) == validate.MaxImportRows {
+			log.Warn("file is too large")
+			return model.ImportReport{}, fmt.Errorf("%s %w", op, ErrImportTooLarge)
+		}
+
+		result := model.ImportResult{Line: row.Line, SKU: row.SKU}
+		if err := validateImportRow(row, skus); err != nil {
+			result.Status = model.ImportFailed
+			result.Err = err
+			report.Rows = append(report.Rows, result)
+			continue
+		}
+
+		valid = append(valid, row)
+		indexes = append(indexes, len(report.Rows))
+		report.Rows = append(report.Rows, result)
+	}
+
+	if len(valid) > 0 {
+		imported, err := s.importRows(ctx, valid, dryRun)
+		if err != nil {
+			log.Error("products didnt imported", sl.Err(err))
+			return model.ImportReport{}, fmt.Errorf("%s %w", op, err)
+		}
+
+		for k, res := range imported {
+			i := indexes[k]
+			report.Rows[i].ID = res.ID
+			report.Rows[i].Status = res.Status
+			report.Rows[i].Err = importRowError(res.Err)
+		}
+	}
+
+	report.Total = len(report.Rows)
+	for _, res := range report.Rows {
+		switch res.Status {
+		case model.ImportCreated:
+			report.Created++
+		case model.ImportUpdated:
+			report.Updated++
+		case model.ImportFailed:
+			report.Failed++
+		}
+	}
+
+	log.Info("products imported",
+		slog.Int("created", report.Created),
+		slog.Int("updated", report.Updated),
+		slog.Int("failed", report.Failed),
+	)
+
+	return report, nil
+}
+
+// importRows сохраняет строки вместе с событиями по ним в одной транзакции.
+// Пробный импорт откатывается хранилищем, поэтому событий не пишет.
+func (s *CatalogService) importRows(ctx context.Context, rows []model.ImportRow, dryRun bool) ([]model.ImportResult, error) {
+	if dryRun {
+		return s.importer.ImportProducts(ctx, rows, true)
+	}
+
+	var imported []model.ImportResult
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		results, err := s.importer.ImportProducts(ctx, rows, false)
+		if err != nil {
+			return err
+		}
+
+		imported = results
+		return s.events.SaveEvents(ctx, importEvents(rows, results)...)
+	})
+
+	return imported, err
+}
+
+// importEvents собирает события по созданным и измененным строкам импорта
+func importEvents(rows []model.ImportRow, results []model.ImportResult) []model.DomainEvent {
+	var events []model.DomainEvent
+
+	for i, res := range results {
+		row := rows[i]
+		categoryies := categoryiesByName(row.Categoryies)
+
+		switch res.Status {
+		case model.ImportCreated:
+			events = append(events, model.ProductCreated{ProductID: res.ID, Name: row.Name, Categoryies: categoryies})
+		case model.ImportUpdated:
+			if res.Renamed {
+				events = append(events, model.ProductRenamed{ProductID: res.ID, Name: row.Name, Categoryies: categoryies})
+			}
+			if res.Recategorized {
+				events = append(events, model.ProductCategoryiesChanged{ProductID: res.ID, Categoryies: categoryies})
+			}
+		}
+	}
+
+	return events
+}
+
+func validateImportRow(row model.ImportRow, skus map[string]struct{}) error {
+	if err := validate.Struct(row); err != nil {
+		return err
+	}
+
+	if _, ok := skus[row.SKU]; ok {
+		return ErrImportDuplicateSKU
+	}
+	skus[row.SKU] = struct{}{}
+
+	return nil
+}
+
+// importRowError переводит ошибку хранилища по строке импорта в доменную ошибку
+func importRowError(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, repository.ErrCategoryNotFound):
+		return ErrProductCategoryNotFound
+	default:
+		return err
+	}
+}
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"goapi/internal/lib/catalog"
+	"goapi/internal/lib/validate"
+	"goapi/internal/model"
+	"goapi/internal/repository"
+	mock_service "goapi/internal/service/mock"
+	"log/slog"
+	"os"
+)
+
+func TestExportProducts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockExporter := mock_service.NewMockExporterCatalog(ctrl)
+	mockLogger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	mockExporter.EXPECT().ExportProducts(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(model.Product) error) error {
+			return fn(model.Product{ID: 1, SKU: "SKU-1", Name: "Product1", Categoryies: []model.Category{{Name: "Category1"}}})
+		})
+
+	catalogService := NewCatalogService(mockExporter, nil, nil, nil, mockLogger)
+
+	var buf bytes.Buffer
+	err := catalogService.ExportProducts(context.Background(), &buf, catalog.FormatJSONL)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"id":1,"sku":"SKU-1","name":"Product1","categoryies":["Category1"]}`+"\n", buf.String())
+}
+
+func TestImportProducts(t *testing.T) {
+	type mockBehavior func(r *mock_service.MockImporterCatalog)
+
+	tests := []struct {
+		name             string
+		input            string
+		dryRun           bool
+		mockBehavior     mockBehavior
+		expectedError    error
+		expectedStatuses []model.ImportStatus
+		expectedErrors   []error
+	}{
+		{
+			name:  "Ok",
+			input: "sku,name,categoryies\nSKU-1,Product1,Category1\nSKU-2,Product2,Category1|Category2\n",
+			mockBehavior: func(r *mock_service.MockImporterCatalog) {
+				r.EXPECT().ImportProducts(gomock.Any(), []model.ImportRow{
+					{Line: 2, SKU: "SKU-1", Name: "Product1", Categoryies: []string{"Category1"}},
+					{Line: 3, SKU: "SKU-2", Name: "Product2", Categoryies: []string{"Category1", "Category2"}},
+				}, false).Return([]model.ImportResult{
+					{Line: 2, SKU: "SKU-1", ID: 1, Status: model.ImportCreated},
+					{Line: 3, SKU: "SKU-2", ID: 2, Status: model.ImportUpdated},
+				}, nil)
+			},
+			expectedStatuses: []model.ImportStatus{model.ImportCreated, model.ImportUpdated},
+			expectedErrors:   []error{nil, nil},
+		},
+		{
+			name:   "Row errors",
+			input:  "sku,name,categoryies\nSKU-1,Product1,Category1\n,Product2,Category1\nSKU-1,Product3,Category1\nSKU-4,Product4,Missing\n",
+			dryRun: true,
+			mockBehavior: func(r *mock_service.MockImporterCatalog) {
+				r.EXPECT().ImportProducts(gomock.Any(), gomock.Len(2), true).Return([]model.ImportResult{
+					{Line: 2, SKU: "SKU-1", ID: 1, Status: model.ImportCreated},
+					{Line: 5, SKU: "SKU-4", Status: model.ImportFailed, Err: repository.ErrCategoryNotFound},
+				}, nil)
+			},
+			expectedStatuses: []model.ImportStatus{
+				model.ImportCreated,
+				model.ImportFailed,
+				model.ImportFailed,
+				model.ImportFailed,
+			},
+			expectedErrors: []error{nil, validate.ErrValidation, ErrImportDuplicateSKU, ErrProductCategoryNotFound},
+		},
+		{
+			name:          "Malformed header",
+			input:         "name\nProduct1\n",
+			mockBehavior:  func(r *mock_service.MockImporterCatalog) {},
+			expectedError: catalog.ErrMalformedHeader,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockImporter := mock_service.NewMockImporterCatalog(ctrl)
+			mockLogger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+			mockTx, mockEvents := passThroughTx(ctrl)
+
+			test.mockBehavior(mockImporter)
+
+			catalogService := NewCatalogService(nil, mockImporter, mockTx, mockEvents, mockLogger)
+
+			report, err := catalogService.ImportProducts(adminCtx, strings.NewReader(test.input), catalog.FormatCSV, test.dryRun)
+			if test.expectedError != nil {
+				assert.ErrorIs(t, err, test.expectedError)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.dryRun, report.DryRun)
+			assert.Equal(t, len(test.expectedStatuses), report.Total)
+			assert.Len(t, report.Rows, len(test.expectedStatuses))
+			for i, res := range report.Rows {
+				assert.Equal(t, test.expectedStatuses[i], res.Status)
+				if test.expectedErrors[i] == nil {
+					assert.NoError(t, res.Err)
+				} else {
+					assert.ErrorIs(t, res.Err, test.expectedErrors[i])
+				}
+			}
+		})
+	}
+}
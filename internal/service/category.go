@@ -4,6 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"goapi/internal/lib/apperr"
+	"goapi/internal/lib/logger/logctx"
+	"goapi/internal/lib/logger/sl"
+	"goapi/internal/lib/validate"
 	"goapi/internal/model"
 	"goapi/internal/repository"
 	"log/slog"
@@ -17,9 +21,14 @@ const (
 //go:generate mockgen -source=category.go -destination=mock/category_mock.go
 
 var (
-	ErrCategoryNameIsEmpty = errors.New("category name is empty")
-	ErrCategoryIDIsEmpty   = errors.New("category id is empty")
-	ErrCategoryUnknownTag  = errors.New("unknown tag get all products")
+	ErrCategoryNameIsEmpty = apperr.InvalidField("category_name_empty", "/name", "category name is empty")
+	ErrCategoryIDIsEmpty   = apperr.InvalidField("category_id_empty", "/id", "category id is empty")
+	ErrCategoryUnknownTag  = apperr.InvalidField("unknown_tag", "/tag", "unknown tag get all products")
+	ErrCategoryExist       = apperr.Conflict("category_already_exists", "category already exist")
+	ErrCategoryNotFound    = apperr.NotFound("category_not_found", "category not found")
+
+	ErrCategoryVersionMismatch = apperr.PreconditionFailed("category_version_mismatch", "category was modified by another request")
+	ErrCategoryVersionRequired = apperr.PreconditionRequired("category_version_required", "category version is required")
 )
 
 type CategoryService struct {
@@ -27,6 +36,8 @@ type CategoryService struct {
 	deleter DeleterCategory
 	updater UpdaterCategory
 	getter  GetterCategory
+	tx      Transactor
+	events  EventSaver
 	log     *slog.Logger
 }
 
@@ -35,15 +46,17 @@ type AdderCategory interface {
 }
 
 type DeleterCategory interface {
-	DeleteCategory(ctx context.Context, id int64) error
+	DeleteCategory(ctx context.Context, id int64, version int64) error
 }
 
 type UpdaterCategory interface {
-	UpdateCategoryName(ctx context.Context, id int64, name string) (int64, error)
+	UpdateCategoryName(ctx context.Context, id int64, name string, version int64) (int64, error)
 }
 
 type GetterCategory interface {
+	GetCategory(ctx context.Context, id int64) (model.Category, error)
 	GetAllCategoryies(ctx context.Context) ([]model.Category, error)
+	GetCategoryiesSummary(ctx context.Context) ([]model.CategorySummary, error)
 }
 
 func NewCategoryService(
@@ -51,6 +64,8 @@ func NewCategoryService(
 	d DeleterCategory,
 	u UpdaterCategory,
 	g GetterCategory,
+	tx Transactor,
+	e EventSaver,
 	l *slog.Logger,
 ) *CategoryService {
 	return &CategoryService{
@@ -58,14 +73,18 @@ func NewCategoryService(
 		deleter: d,
 		updater: u,
 		getter:  g,
+		tx:      tx,
+		events:  e,
 		log:     l,
 	}
 }
 
 func (s *CategoryService) AddCategory(ctx context.Context, name string) (int64, error) {
 	const op = "category.AddCategory"
+	ctx, span := tracer.Start(ctx, "CategoryService.AddCategory")
+	defer span.End()
 
-	log := s.log.With(
+	log := logctx.From(ctx, s.log).With(
 		slog.String("op", op),
 		slog.String("name", name),
 	)
@@ -73,18 +92,32 @@ func (s *CategoryService) AddCategory(ctx context.Context, name string) (int64,
 	log.Info("add category")
 
 	if name == "" {
-		log.Info("name is empty", ErrCategoryNameIsEmpty)
+		log.Info("name is empty", sl.Err(ErrCategoryNameIsEmpty))
 		return ErrCategoryId, fmt.Errorf("%s %w", op, ErrCategoryNameIsEmpty)
 	}
 
-	categoryID, err := s.adder.AddCategory(ctx, name)
+	if err := validate.Struct(categoryInput{Name: name}); err != nil {
+		log.Error("data is invalid", sl.Err(err))
+		return ErrCategoryId, fmt.Errorf("%s %w", op, err)
+	}
+
+	var categoryID int64
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		id, err := s.adder.AddCategory(ctx, name)
+		if err != nil {
+			return err
+		}
+
+		categoryID = id
+		return s.events.SaveEvents(ctx, model.CategoryCreated{CategoryID: id, Name: name})
+	})
 	if err != nil {
 		if errors.Is(err, repository.ErrCategoryExist) {
-			s.log.Warn("category already exist", err)
-			return ErrCategoryId, fmt.Errorf("%s: %w", op, ErrorInvalidCredentials)
+			log.Warn("category already exist", sl.Err(err))
+			return ErrCategoryId, fmt.Errorf("%s %w", op, ErrCategoryExist)
 		}
 
-		log.Info("category didnt added")
+		log.Error("category didnt added", sl.Err(err))
 		return ErrCategoryId, fmt.Errorf("%s %w", op, err)
 	}
 
@@ -92,33 +125,59 @@ func (s *CategoryService) AddCategory(ctx context.Context, name string) (int64,
 
 	return categoryID, nil
 }
-func (s *CategoryService) DeleteCategory(ctx context.Context, id int64) error {
+
+// DeleteCategory удаляет категорию. Версия должна совпадать с текущей версией категории.
+func (s *CategoryService) DeleteCategory(ctx context.Context, id int64, version int64) error {
 	const op = "category.DeleteCategory"
+	ctx, span := tracer.Start(ctx, "CategoryService.DeleteCategory")
+	defer span.End()
 
-	log := s.log.With(
+	log := logctx.From(ctx, s.log).With(
 		slog.String("op", op),
 		slog.Int64("id", id),
 	)
 
 	log.Info("delete category")
 
+	if err := requireAdmin(ctx); err != nil {
+		log.Warn("access denied", sl.Err(err))
+		return fmt.Errorf("%s %w", op, err)
+	}
+
 	if id <= 0 {
-		log.Info("id is empty", ErrCategoryIDIsEmpty)
+		log.Info("id is empty", sl.Err(ErrCategoryIDIsEmpty))
 		return fmt.Errorf("%s %w", op, ErrCategoryIDIsEmpty)
 	}
 
-	err := s.deleter.DeleteCategory(ctx, id)
+	if version <= 0 {
+		log.Warn("version is empty", sl.Err(ErrCategoryVersionRequired))
+		return fmt.Errorf("%s %w", op, ErrCategoryVersionRequired)
+	}
+
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		category, err := s.getter.GetCategory(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if err := s.deleter.DeleteCategory(ctx, id, version); err != nil {
+			return err
+		}
+
+		return s.events.SaveEvents(ctx, model.CategoryDeleted{CategoryID: id, Name: category.Name})
+	})
 	if err != nil {
-		if errors.Is(err, repository.ErrCategoryDelete) {
-			s.log.Warn("user not found", err)
-			return fmt.Errorf("%s: %w", op, ErrorInvalidCredentials)
+		if errors.Is(err, repository.ErrCategoryNotFound) {
+			log.Warn("category not exist", sl.Err(err))
+			return fmt.Errorf("%s %w", op, ErrCategoryNotFound)
 		}
-		if errors.Is(err, repository.ErrDeleteProductCategory) {
-			s.log.Warn("category not exist", err)
-			return fmt.Errorf("%s: %w", op, ErrorInvalidCredentials)
+
+		if errors.Is(err, repository.ErrVersionMismatch) {
+			log.Warn("category version mismatch", sl.Err(err))
+			return fmt.Errorf("%s %w", op, ErrCategoryVersionMismatch)
 		}
 
-		log.Info("category didnt deleted")
+		log.Error("category didnt deleted", sl.Err(err))
 		return fmt.Errorf("%s %w", op, err)
 	}
 
@@ -127,10 +186,14 @@ func (s *CategoryService) DeleteCategory(ctx context.Context, id int64) error {
 	return nil
 }
 
-func (s *CategoryService) EditCategory(ctx context.Context, id int64, name string) (int64, error) {
+// EditCategory переименовывает категорию и возвращает ее новую версию.
+// Версия должна совпадать с текущей версией категории.
+func (s *CategoryService) EditCategory(ctx context.Context, id int64, name string, version int64) (int64, error) {
 	const op = "category.DeleteCategory"
+	ctx, span := tracer.Start(ctx, "CategoryService.EditCategory")
+	defer span.End()
 
-	log := s.log.With(
+	log := logctx.From(ctx, s.log).With(
 		slog.String("op", op),
 		slog.Int64("id", id),
 	)
@@ -138,34 +201,94 @@ func (s *CategoryService) EditCategory(ctx context.Context, id int64, name strin
 	log.Info("delete category")
 
 	if id <= 0 {
-		log.Info("id is empty", ErrCategoryIDIsEmpty)
+		log.Info("id is empty", sl.Err(ErrCategoryIDIsEmpty))
 		return ErrCategoryId, fmt.Errorf("%s %w", op, ErrCategoryIDIsEmpty)
 	}
 
+	if version <= 0 {
+		log.Warn("version is empty", sl.Err(ErrCategoryVersionRequired))
+		return ErrCategoryId, fmt.Errorf("%s %w", op, ErrCategoryVersionRequired)
+	}
+
 	if name == "" {
-		log.Info("name is empty", ErrCategoryNameIsEmpty)
+		log.Info("name is empty", sl.Err(ErrCategoryNameIsEmpty))
 		return ErrCategoryId, fmt.Errorf("%s %w", op, ErrCategoryNameIsEmpty)
 	}
 
-	categoryID, err := s.updater.UpdateCategoryName(ctx, id, name)
+	if err := validate.Struct(categoryNameInput{ID: id, Name: name}); err != nil {
+		log.Error("data is invalid", sl.Err(err))
+		return ErrCategoryId, fmt.Errorf("%s %w", op, err)
+	}
+
+	var newVersion int64
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		v, err := s.updater.UpdateCategoryName(ctx, id, name, version)
+		if err != nil {
+			return err
+		}
+
+		newVersion = v
+		return s.events.SaveEvents(ctx, model.CategoryRenamed{CategoryID: id, Name: name})
+	})
 	if err != nil {
-		if errors.Is(err, repository.ErrUpdateCategory) {
-			s.log.Warn("categoryies not updated", err)
-			return ErrCategoryId, fmt.Errorf("%s: %w", op, ErrorInvalidCredentials)
+		if errors.Is(err, repository.ErrCategoryNotFound) {
+			log.Warn("category not exist", sl.Err(err))
+			return ErrCategoryId, fmt.Errorf("%s %w", op, ErrCategoryNotFound)
+		}
+
+		if errors.Is(err, repository.ErrVersionMismatch) {
+			log.Warn("category version mismatch", sl.Err(err))
+			return ErrCategoryId, fmt.Errorf("%s %w", op, ErrCategoryVersionMismatch)
 		}
 
-		log.Info("category didnt deleted")
+		log.Error("category didnt edited", sl.Err(err))
 		return ErrCategoryId, fmt.Errorf("%s %w", op, err)
 	}
 
 	log.Info("category is deleted")
 
-	return categoryID, nil
+	return newVersion, nil
+}
+
+// GetCategory возвращает категорию с текущей версией
+func (s *CategoryService) GetCategory(ctx context.Context, id int64) (model.Category, error) {
+	const op = "category.GetCategory"
+	ctx, span := tracer.Start(ctx, "CategoryService.GetCategory")
+	defer span.End()
+
+	log := logctx.From(ctx, s.log).With(
+		slog.String("op", op),
+		slog.Int64("id", id),
+	)
+
+	log.Info("get category")
+
+	if id <= 0 {
+		log.Info("id is empty", sl.Err(ErrCategoryIDIsEmpty))
+		return model.Category{}, fmt.Errorf("%s %w", op, ErrCategoryIDIsEmpty)
+	}
+
+	category, err := s.getter.GetCategory(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrCategoryNotFound) {
+			log.Warn("category not exist", sl.Err(err))
+			return model.Category{}, fmt.Errorf("%s %w", op, ErrCategoryNotFound)
+		}
+
+		log.Error("category didnt get", sl.Err(err))
+		return model.Category{}, fmt.Errorf("%s %w", op, err)
+	}
+
+	log.Info("category is getter")
+
+	return category, nil
 }
 func (s *CategoryService) GetAllCategoryies(ctx context.Context, tag string) ([]model.Category, error) {
 	const op = "category.GetAllCategoryies"
+	ctx, span := tracer.Start(ctx, "CategoryService.GetAllCategoryies")
+	defer span.End()
 
-	log := s.log.With(
+	log := logctx.From(ctx, s.log).With(
 		slog.String("op", op),
 		slog.String("tag", tag),
 	)
@@ -173,18 +296,13 @@ func (s *CategoryService) GetAllCategoryies(ctx context.Context, tag string) ([]
 	log.Info("get all categoryies")
 
 	if tag != TagGetAllCategoryies {
-		log.Error("categoryies didnt get", ErrCategoryUnknownTag)
+		log.Error("categoryies didnt get", sl.Err(ErrCategoryUnknownTag))
 		return []model.Category{}, fmt.Errorf("%s %w", op, ErrCategoryUnknownTag)
 	}
 
 	categoryies, err := s.getter.GetAllCategoryies(ctx)
 	if err != nil {
-		if errors.Is(err, repository.ErrAllCategoryies) {
-			s.log.Warn("categoryies empty", err)
-			return []model.Category{}, fmt.Errorf("%s: %w", op, ErrorInvalidCredentials)
-		}
-
-		log.Error("categoryies didnt get", err)
+		log.Error("categoryies didnt get", sl.Err(err))
 		return []model.Category{}, fmt.Errorf("%s %w", op, err)
 	}
 
@@ -192,3 +310,24 @@ func (s *CategoryService) GetAllCategoryies(ctx context.Context, tag string) ([]
 
 	return categoryies, nil
 }
+
+// GetCategoryiesSummary возвращает категории с числом товаров в каждой
+func (s *CategoryService) GetCategoryiesSummary(ctx context.Context) ([]model.CategorySummary, error) {
+	const op = "category.GetCategoryiesSummary"
+	ctx, span := tracer.Start(ctx, "CategoryService.GetCategoryiesSummary")
+	defer span.End()
+
+	log := logctx.From(ctx, s.log).With(
+		slog.String("op", op),
+	)
+
+	log.Info("get categoryies summary")
+
+	categoryies, err := s.getter.GetCategoryiesSummary(ctx)
+	if err != nil {
+		log.Error("categoryies summary didnt get", sl.Err(err))
+		return nil, fmt.Errorf("%s %w", op, err)
+	}
+
+	return categoryies, nil
+}
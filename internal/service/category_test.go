@@ -4,6 +4,7 @@ import (
 	"context"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
+	"goapi/internal/repository"
 	mock_service "goapi/internal/service/mock"
 	"log/slog"
 	"os"
@@ -17,7 +18,8 @@ func TestCategoryService_AddCategory(t *testing.T) {
 	mockAdder := mock_service.NewMockAdderCategory(ctrl)
 	mockLogger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
 
-	categoryService := NewCategoryService(mockAdder, nil, nil, nil, mockLogger)
+	mockTx, mockEvents := passThroughTx(ctrl)
+	categoryService := NewCategoryService(mockAdder, nil, nil, nil, mockTx, mockEvents, mockLogger)
 
 	testName := "Test Category"
 	expectedID := int64(1)
@@ -36,13 +38,14 @@ func TestCategoryService_DeleteCategory(t *testing.T) {
 	mockDeleter := mock_service.NewMockDeleterCategory(ctrl)
 	mockLogger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
 
-	categoryService := NewCategoryService(nil, mockDeleter, nil, nil, mockLogger)
+	mockTx, mockEvents := passThroughTx(ctrl)
+	categoryService := NewCategoryService(nil, mockDeleter, nil, knownCategoryies(ctrl), mockTx, mockEvents, mockLogger)
 
 	testID := int64(1)
 
-	mockDeleter.EXPECT().DeleteCategory(gomock.Any(), testID).Return(nil)
+	mockDeleter.EXPECT().DeleteCategory(gomock.Any(), testID, int64(1)).Return(nil)
 
-	err := categoryService.DeleteCategory(context.Background(), testID)
+	err := categoryService.DeleteCategory(adminCtx, testID, 1)
 	assert.NoError(t, err)
 }
 
@@ -53,15 +56,16 @@ func TestCategoryService_EditCategory(t *testing.T) {
 	mockUpdater := mock_service.NewMockUpdaterCategory(ctrl)
 	mockLogger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
 
-	categoryService := NewCategoryService(nil, nil, mockUpdater, nil, mockLogger)
+	mockTx, mockEvents := passThroughTx(ctrl)
+	categoryService := NewCategoryService(nil, nil, mockUpdater, nil, mockTx, mockEvents, mockLogger)
 
 	testID := int64(1)
 	testName := "Updated Category"
 	expectedID := int64(1)
 
-	mockUpdater.EXPECT().UpdateCategoryName(gomock.Any(), testID, testName).Return(expectedID, nil)
+	mockUpdater.EXPECT().UpdateCategoryName(gomock.Any(), testID, testName, int64(1)).Return(expectedID, nil)
 
-	categoryID, err := categoryService.EditCategory(context.Background(), testID, testName)
+	categoryID, err := categoryService.EditCategory(context.Background(), testID, testName, 1)
 	assert.NoError(t, err)
 	assert.Equal(t, expectedID, categoryID)
 }
@@ -73,15 +77,32 @@ func TestFailedCategoryService_EditCategory(t *testing.T) {
 	mockUpdater := mock_service.NewMockUpdaterCategory(ctrl)
 	mockLogger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
 
-	categoryService := NewCategoryService(nil, nil, mockUpdater, nil, mockLogger)
+	mockTx, mockEvents := passThroughTx(ctrl)
+	categoryService := NewCategoryService(nil, nil, mockUpdater, nil, mockTx, mockEvents, mockLogger)
 
 	testID := int64(1)
 	testName := "Updated Category"
 	expectedID := int64(1)
 
-	mockUpdater.EXPECT().UpdateCategoryName(gomock.Any(), testID, testName).Return(expectedID-1, nil)
+	mockUpdater.EXPECT().UpdateCategoryName(gomock.Any(), testID, testName, int64(1)).Return(expectedID-1, nil)
 
-	categoryID, err := categoryService.EditCategory(context.Background(), testID, testName)
+	categoryID, err := categoryService.EditCategory(context.Background(), testID, testName, 1)
 	assert.NoError(t, err)
 	assert.NotEqual(t, expectedID, categoryID)
 }
+
+func TestCategoryService_DeleteCategoryVersionMismatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDeleter := mock_service.NewMockDeleterCategory(ctrl)
+	mockLogger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	mockTx, mockEvents := passThroughTx(ctrl)
+	categoryService := NewCategoryService(nil, mockDeleter, nil, knownCategoryies(ctrl), mockTx, mockEvents, mockLogger)
+
+	mockDeleter.EXPECT().DeleteCategory(gomock.Any(), int64(1), int64(4)).Return(repository.ErrVersionMismatch)
+
+	err := categoryService.DeleteCategory(adminCtx, 1, 4)
+	assert.ErrorIs(t, err, ErrCategoryVersionMismatch)
+}
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+	"goapi/internal/model"
+)
+
+//go:generate mockgen -source=events.go -destination=mock/events_mock.go
+
+// Transactor выполняет fn в одной транзакции хранилища
+type Transactor interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// EventSaver записывает события в outbox в транзакции из ctx
+type EventSaver interface {
+	SaveEvents(ctx context.Context, events ...model.DomainEvent) error
+}
+
+// batchEvents собирает события по успешно примененным операциям пакета.
+// categoryies - категории изменяемых товаров до применения пакета.
+func batchEvents(
+	operations []model.ProductOperation,
+	results []model.ProductOperationResult,
+	categoryies map[int64][]model.Category,
+) []model.DomainEvent {
+	var events []model.DomainEvent
+
+	for i, res := range results {
+		if res.Status != model.ProductOperationOK {
+			continue
+		}
+
+		operation := operations[i]
+		switch operation.Op {
+		case model.ProductOperationCreate:
+			events = append(events, model.ProductCreated{ProductID: res.ID, Name: operation.Name, Categoryies: categoryiesByName(operation.Categoryies)})
+		case model.ProductOperationUpdate:
+			if operation.Name != "" {
+				events = append(events, model.ProductRenamed{ProductID: res.ID, Name: operation.Name, Categoryies: categoryies[res.ID]})
+			}
+			if len(operation.Categoryies) > 0 {
+				events = append(events, model.ProductCategoryiesChanged{ProductID: res.ID, Categoryies: categoryiesByName(operation.Categoryies)})
+			}
+		case model.ProductOperationDelete:
+			events = append(events, model.ProductDeleted{ProductID: res.ID, Categoryies: categoryies[res.ID]})
+		}
+	}
+
+	return events
+}
+
+func categoryiesByName(names []string) []model.Category {
+	categoryies := make([]model.Category, 0, len(names))
+	for _, name := range names {
+		categoryies = append(categoryies, model.Category{Name: name})
+	}
+
+	return categoryies
+}
@@ -0,0 +1,223 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"goapi/internal/lib/catalog"
+	"goapi/internal/model"
+	mock_service "goapi/internal/service/mock"
+	"log/slog"
+	"os"
+)
+
+// passThroughTx возвращает транзакцию, которая просто вызывает fn, и outbox, принимающий любые события
+func passThroughTx(ctrl *gomock.Controller) (*mock_service.MockTransactor, *mock_service.MockEventSaver) {
+	mockTx := mock_service.NewMockTransactor(ctrl)
+	mockTx.EXPECT().WithinTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(ctx context.Context) error) error {
+			return fn(ctx)
+		}).AnyTimes()
+
+	mockEvents := mock_service.NewMockEventSaver(ctrl)
+	mockEvents.EXPECT().SaveEvents(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+
+	return mockTx, mockEvents
+}
+
+// knownProducts возвращает хранилище, в котором есть любой запрошенный товар
+func knownProducts(ctrl *gomock.Controller) *mock_service.MockGetterProduct {
+	mockGetter := mock_service.NewMockGetterProduct(ctrl)
+	mockGetter.EXPECT().GetProduct(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, id int64) (model.Product, error) {
+			return model.Product{ID: int(id), Categoryies: []model.Category{{ID: 1, Name: "Category1"}}}, nil
+		}).AnyTimes()
+	mockGetter.EXPECT().GetProducts(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, ids []int64) ([]model.Product, error) {
+			products := make([]model.Product, 0, len(ids))
+			for _, id := range ids {
+				products = append(products, model.Product{ID: int(id), Categoryies: []model.Category{{ID: 1, Name: "Category1"}}})
+			}
+			return products, nil
+		}).AnyTimes()
+
+	return mockGetter
+}
+
+// knownCategoryies возвращает хранилище, в котором есть любая запрошенная категория
+func knownCategoryies(ctrl *gomock.Controller) *mock_service.MockGetterCategory {
+	mockGetter := mock_service.NewMockGetterCategory(ctrl)
+	mockGetter.EXPECT().GetCategory(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, id int64) (model.Category, error) {
+			return model.Category{ID: int(id), Name: "Category1"}, nil
+		}).AnyTimes()
+
+	return mockGetter
+}
+
+func TestAddProductSavesEvent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAdder := mock_service.NewMockAdderProduct(ctrl)
+	mockTx, _ := passThroughTx(ctrl)
+	mockEvents := mock_service.NewMockEventSaver(ctrl)
+	mockLogger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	mockAdder.EXPECT().AddProduct(gomock.Any(), "Product1", []string{"Category1"}).Return(int64(7), nil)
+	mockEvents.EXPECT().SaveEvents(gomock.Any(), model.ProductCreated{
+		ProductID:   7,
+		Name:        "Product1",
+		Categoryies: []model.Category{{Name: "Category1"}},
+	}).Return(nil)
+
+	productService := NewProductService(mockAdder, nil, nil, nil, nil, mockTx, mockEvents, mockLogger)
+
+	id, err := productService.AddProduct(context.Background(), "Product1", []string{"Category1"})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), id)
+}
+
+func TestDeleteProductSavesCategoryies(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDeleter := mock_service.NewMockDeleterProduct(ctrl)
+	mockTx, _ := passThroughTx(ctrl)
+	mockEvents := mock_service.NewMockEventSaver(ctrl)
+	mockLogger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	mockDeleter.EXPECT().DeleteProduct(gomock.Any(), int64(3), int64(1)).Return(nil)
+	mockEvents.EXPECT().SaveEvents(gomock.Any(), model.ProductDeleted{
+		ProductID:   3,
+		Categoryies: []model.Category{{ID: 1, Name: "Category1"}},
+	}).Return(nil)
+
+	productService := NewProductService(nil, mockDeleter, nil, knownProducts(ctrl), nil, mockTx, mockEvents, mockLogger)
+
+	assert.NoError(t, productService.DeleteProduct(adminCtx, 3, 1))
+}
+
+func TestEditProductCategorySavesResolvedCategoryies(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUpdater := mock_service.NewMockUpdaterProduct(ctrl)
+	mockTx, _ := passThroughTx(ctrl)
+	mockEvents := mock_service.NewMockEventSaver(ctrl)
+	mockLogger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	categoryies := []model.Category{{Name: "Category1"}}
+	mockUpdater.EXPECT().UpdateProductCategoryies(gomock.Any(), int64(3), categoryies, int64(1)).Return(int64(2), nil)
+	mockEvents.EXPECT().SaveEvents(gomock.Any(), model.ProductCategoryiesChanged{
+		ProductID:   3,
+		Categoryies: []model.Category{{ID: 1, Name: "Category1"}},
+	}).Return(nil)
+
+	productService := NewProductService(nil, nil, mockUpdater, knownProducts(ctrl), nil, mockTx, mockEvents, mockLogger)
+
+	version, err := productService.EditProductCategory(context.Background(), 3, categoryies, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), version)
+}
+
+func TestDeleteCategoryEventError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDeleter := mock_service.NewMockDeleterCategory(ctrl)
+	mockTx, _ := passThroughTx(ctrl)
+	mockEvents := mock_service.NewMockEventSaver(ctrl)
+	mockLogger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	outboxErr := errors.New("outbox is unavailable")
+
+	mockDeleter.EXPECT().DeleteCategory(gomock.Any(), int64(1), int64(1)).Return(nil)
+	mockEvents.EXPECT().SaveEvents(gomock.Any(), model.CategoryDeleted{CategoryID: 1, Name: "Category1"}).Return(outboxErr)
+
+	categoryService := NewCategoryService(nil, mockDeleter, nil, knownCategoryies(ctrl), mockTx, mockEvents, mockLogger)
+
+	err := categoryService.DeleteCategory(adminCtx, 1, 1)
+	assert.ErrorIs(t, err, outboxErr)
+}
+
+func TestImportProductsSavesEvents(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockImporter := mock_service.NewMockImporterCatalog(ctrl)
+	mockTx, _ := passThroughTx(ctrl)
+	mockEvents := mock_service.NewMockEventSaver(ctrl)
+	mockLogger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	mockImporter.EXPECT().ImportProducts(gomock.Any(), gomock.Len(4), false).Return([]model.ImportResult{
+		{Line: 2, SKU: "SKU-1", ID: 1, Status: model.ImportCreated},
+		{Line: 3, SKU: "SKU-2", ID: 2, Status: model.ImportUpdated, Renamed: true},
+		{Line: 4, SKU: "SKU-3", ID: 3, Status: model.ImportUpdated, Recategorized: true},
+		{Line: 5, SKU: "SKU-4", ID: 4, Status: model.ImportUpdated},
+	}, nil)
+	mockEvents.EXPECT().SaveEvents(gomock.Any(),
+		model.ProductCreated{ProductID: 1, Name: "Product1", Categoryies: []model.Category{{Name: "Category1"}}},
+		model.ProductRenamed{ProductID: 2, Name: "Product2", Categoryies: []model.Category{{Name: "Category1"}}},
+		model.ProductCategoryiesChanged{ProductID: 3, Categoryies: []model.Category{{Name: "Category2"}}},
+	).Return(nil)
+
+	catalogService := NewCatalogService(nil, mockImporter, mockTx, mockEvents, mockLogger)
+
+	input := "sku,name,categoryies\nSKU-1,Product1,Category1\nSKU-2,Product2,Category1\nSKU-3,Product3,Category2\nSKU-4,Product4,Category1\n"
+	_, err := catalogService.ImportProducts(adminCtx, strings.NewReader(input), catalog.FormatCSV, false)
+	assert.NoError(t, err)
+}
+
+func TestImportProductsDryRunSavesNoEvents(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockImporter := mock_service.NewMockImporterCatalog(ctrl)
+	mockTx := mock_service.NewMockTransactor(ctrl)
+	mockEvents := mock_service.NewMockEventSaver(ctrl)
+	mockLogger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	mockImporter.EXPECT().ImportProducts(gomock.Any(), gomock.Len(1), true).Return([]model.ImportResult{
+		{Line: 2, SKU: "SKU-1", ID: 1, Status: model.ImportCreated},
+	}, nil)
+
+	catalogService := NewCatalogService(nil, mockImporter, mockTx, mockEvents, mockLogger)
+
+	_, err := catalogService.ImportProducts(adminCtx, strings.NewReader("sku,name,categoryies\nSKU-1,Product1,Category1\n"), catalog.FormatCSV, true)
+	assert.NoError(t, err)
+}
+
+func TestBatchEvents(t *testing.T) {
+	operations := []model.ProductOperation{
+		{Op: model.ProductOperationCreate, Name: "Product1", Categoryies: []string{"Category1"}},
+		{Op: model.ProductOperationUpdate, ID: 2, Name: "Product2"},
+		{Op: model.ProductOperationUpdate, ID: 3, Categoryies: []string{"Category2"}},
+		{Op: model.ProductOperationDelete, ID: 4},
+		{Op: model.ProductOperationDelete, ID: 5},
+	}
+	results := []model.ProductOperationResult{
+		{ID: 1, Status: model.ProductOperationOK},
+		{ID: 2, Status: model.ProductOperationOK},
+		{ID: 3, Status: model.ProductOperationOK},
+		{ID: 4, Status: model.ProductOperationOK},
+		{ID: 5, Status: model.ProductOperationFailed},
+	}
+
+	expected := []model.DomainEvent{
+		model.ProductCreated{ProductID: 1, Name: "Product1", Categoryies: []model.Category{{Name: "Category1"}}},
+		model.ProductRenamed{ProductID: 2, Name: "Product2", Categoryies: []model.Category{{ID: 7, Name: "Category7"}}},
+		model.ProductCategoryiesChanged{ProductID: 3, Categoryies: []model.Category{{Name: "Category2"}}},
+		model.ProductDeleted{ProductID: 4},
+	}
+
+	categoryies := map[int64][]model.Category{
+		2: {{ID: 7, Name: "Category7"}},
+	}
+
+	assert.Equal(t, expected, batchEvents(operations, results, categoryies))
+}
@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"goapi/internal/lib/apperr"
+	"goapi/internal/lib/logger/logctx"
+	"goapi/internal/lib/logger/sl"
+	"goapi/internal/model"
+	"goapi/internal/repository"
+	"log/slog"
+	"time"
+)
+
+//go:generate mockgen -source=idempotency.go -destination=mock/idempotency_mock.go
+
+var (
+	ErrIdempotencyKeyInFlight = apperr.Conflict("idempotency_key_in_flight", "request with this idempotency key is still in progress")
+	ErrIdempotencyKeyReused   = apperr.Conflict("idempotency_key_reused", "idempotency key was already used for a different request")
+	ErrIdempotencyKeyFailed   = apperr.Internal("idempotency_key_failed", "failed to check idempotency key")
+	ErrIdempotencyLeaseLost   = apperr.Conflict("idempotency_lease_lost", "request outlived its idempotency key lease")
+)
+
+type IdempotencyService struct {
+	reserver IdempotencyReserver
+	saver    IdempotencySaver
+	remover  IdempotencyRemover
+	log      *slog.Logger
+	ttl      time.Duration
+	lease    time.Duration
+}
+
+type IdempotencyReserver interface {
+	ReserveIdempotencyKey(ctx context.Context, userID int64, key, fingerprint, owner string, ttl time.Duration) (model.IdempotentResponse, bool, error)
+}
+
+type IdempotencySaver interface {
+	SaveIdempotentResponse(ctx context.Context, userID int64, key, owner string, resp model.IdempotentResponse, ttl time.Duration) error
+}
+
+type IdempotencyRemover interface {
+	DeleteIdempotencyKey(ctx context.Context, userID int64, key, owner string) error
+	DeleteExpiredIdempotencyKeys(ctx context.Context) (int64, error)
+}
+
+func NewIdempotencyService(
+	r IdempotencyReserver,
+	s IdempotencySaver,
+	rm IdempotencyRemover,
+	l *slog.Logger,
+	ttl time.Duration,
+	lease time.Duration,
+) *IdempotencyService {
+	return &IdempotencyService{
+		reserver: r,
+		saver:    s,
+		remover:  rm,
+		log:      l,
+		ttl:      ttl,
+		lease:    lease,
+	}
+}
+
+// Begin занимает ключ под запрос с отпечатком fingerprint на время lease: если реплика
+// упадет посреди запроса, ключ освободится через lease, а не через ttl.
+// Возвращает владельца аренды, которого нужно передать в Complete или Release.
+// Если запрос с этим ключом уже выполнен, возвращается сохраненный ответ,
+// если выполняется - ErrIdempotencyKeyInFlight.
+func (s *IdempotencyService) Begin(ctx context.Context, userID int64, key, fingerprint string) (string, *model.IdempotentResponse, error) {
+	const op = "idempotency.Begin"
+	ctx, span := tracer.Start(ctx, "IdempotencyService.Begin")
+	defer span.End()
+
+	log := logctx.From(ctx, s.log).With(
+		slog.String("op", op),
+		slog.Int64("user_id", userID),
+	)
+
+	owner := newLeaseOwner()
+
+	resp, reserved, err := s.reserver.ReserveIdempotencyKey(ctx, userID, key, fingerprint, owner, s.lease)
+	if err != nil {
+		log.Error("error reserve idempotency key", sl.Err(err))
+		return "", nil, fmt.Errorf("%s %w", op, ErrIdempotencyKeyFailed)
+	}
+
+	if reserved {
+		return owner, nil, nil
+	}
+
+	if resp.Status == 0 {
+		log.Warn("idempotency key in flight")
+		return "", nil, fmt.Errorf("%s %w", op, ErrIdempotencyKeyInFlight)
+	}
+
+	if resp.Fingerprint != fingerprint {
+		log.Warn("idempotency key reused with another request")
+		return "", nil, fmt.Errorf("%s %w", op, ErrIdempotencyKeyReused)
+	}
+
+	log.Info("replay idempotent response")
+
+	return "", &resp, nil
+}
+
+// Complete сохраняет ответ на запрос, занявший ключ арендой owner, и продлевает ключ на ttl.
+// Если запрос выполнялся дольше аренды, ответ не сохраняется и возвращается ErrIdempotencyLeaseLost.
+func (s *IdempotencyService) Complete(ctx context.Context, userID int64, key, owner string, resp model.IdempotentResponse) error {
+	const op = "idempotency.Complete"
+	ctx, span := tracer.Start(ctx, "IdempotencyService.Complete")
+	defer span.End()
+
+	err := s.saver.SaveIdempotentResponse(ctx, userID, key, owner, resp, s.ttl)
+	if errors.Is(err, repository.ErrIdempotencyLeaseLost) {
+		s.log.Warn("idempotency key lease is lost", slog.String("op", op), sl.Err(err))
+		return fmt.Errorf("%s %w", op, ErrIdempotencyLeaseLost)
+	}
+	if err != nil {
+		s.log.Error("error save idempotent response", slog.String("op", op), sl.Err(err))
+		return fmt.Errorf("%s %w", op, ErrIdempotencyKeyFailed)
+	}
+
+	return nil
+}
+
+// Release освобождает ключ, занятый арендой owner, если ответ сохранять нельзя (например, при внутренней ошибке)
+func (s *IdempotencyService) Release(ctx context.Context, userID int64, key, owner string) error {
+	const op = "idempotency.Release"
+	ctx, span := tracer.Start(ctx, "IdempotencyService.Release")
+	defer span.End()
+
+	if err := s.remover.DeleteIdempotencyKey(ctx, userID, key, owner); err != nil {
+		s.log.Error("error release idempotency key", slog.String("op", op), sl.Err(err))
+		return fmt.Errorf("%s %w", op, ErrIdempotencyKeyFailed)
+	}
+
+	return nil
+}
+
+// Purge раз в interval удаляет истекшие ключи, пока не отменен ctx
+func (s *IdempotencyService) Purge(ctx context.Context, interval time.Duration) {
+	const op = "idempotency.Purge"
+
+	log := logctx.From(ctx, s.log).With(
+		slog.String("op", op),
+	)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			n, err := s.remover.DeleteExpiredIdempotencyKeys(ctx)
+			if err != nil {
+				log.Error("error purge idempotency keys", sl.Err(err))
+				continue
+			}
+
+			log.Debug("expired idempotency keys purged", slog.Int64("count", n))
+		}
+	}
+}
+
+// newLeaseOwner возвращает случайный идентификатор аренды ключа
+func newLeaseOwner() string {
+	owner := make([]byte, 16)
+	_, _ = rand.Read(owner)
+
+	return hex.EncodeToString(owner)
+}
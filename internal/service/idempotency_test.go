@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"goapi/internal/model"
+	"goapi/internal/repository"
+	mock_service "goapi/internal/service/mock"
+	"log/slog"
+	"os"
+)
+
+func TestIdempotencyBegin(t *testing.T) {
+	type mockBehavior func(r *mock_service.MockIdempotencyReserver)
+
+	stored := model.IdempotentResponse{Fingerprint: "fp", Status: 200, Body: []byte(`{"productID":1}`)}
+
+	tests := []struct {
+		name             string
+		fingerprint      string
+		mockBehavior     mockBehavior
+		expectedResponse *model.IdempotentResponse
+		expectedError    error
+	}{
+		{
+			name:        "Reserved",
+			fingerprint: "fp",
+			mockBehavior: func(r *mock_service.MockIdempotencyReserver) {
+				r.EXPECT().ReserveIdempotencyKey(gomock.Any(), int64(1), "key", "fp", gomock.Any(), time.Minute).
+					Return(model.IdempotentResponse{}, true, nil)
+			},
+		},
+		{
+			name:        "Replay",
+			fingerprint: "fp",
+			mockBehavior: func(r *mock_service.MockIdempotencyReserver) {
+				r.EXPECT().ReserveIdempotencyKey(gomock.Any(), int64(1), "key", "fp", gomock.Any(), time.Minute).
+					Return(stored, false, nil)
+			},
+			expectedResponse: &stored,
+		},
+		{
+			name:        "In Flight",
+			fingerprint: "fp",
+			mockBehavior: func(r *mock_service.MockIdempotencyReserver) {
+				r.EXPECT().ReserveIdempotencyKey(gomock.Any(), int64(1), "key", "fp", gomock.Any(), time.Minute).
+					Return(model.IdempotentResponse{Fingerprint: "fp"}, false, nil)
+			},
+			expectedError: ErrIdempotencyKeyInFlight,
+		},
+		{
+			name:        "Reused",
+			fingerprint: "other",
+			mockBehavior: func(r *mock_service.MockIdempotencyReserver) {
+				r.EXPECT().ReserveIdempotencyKey(gomock.Any(), int64(1), "key", "other", gomock.Any(), time.Minute).
+					Return(stored, false, nil)
+			},
+			expectedError: ErrIdempotencyKeyReused,
+		},
+		{
+			name:        "Repository Error",
+			fingerprint: "fp",
+			mockBehavior: func(r *mock_service.MockIdempotencyReserver) {
+				r.EXPECT().ReserveIdempotencyKey(gomock.Any(), int64(1), "key", "fp", gomock.Any(), time.Minute).
+					Return(model.IdempotentResponse{}, false, errors.New("connection refused"))
+			},
+			expectedError: ErrIdempotencyKeyFailed,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockReserver := mock_service.NewMockIdempotencyReserver(ctrl)
+			mockLogger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+			test.mockBehavior(mockReserver)
+
+			idempotencyService := NewIdempotencyService(mockReserver, nil, nil, mockLogger, time.Hour, time.Minute)
+
+			owner, resp, err := idempotencyService.Begin(context.Background(), 1, "key", test.fingerprint)
+			assert.ErrorIs(t, err, test.expectedError)
+			assert.Equal(t, test.expectedResponse, resp)
+			// владелец есть только у аренды, занятой этим вызовом
+			assert.Equal(t, test.name == "Reserved", owner != "")
+		})
+	}
+}
+
+func TestIdempotencyCompleteExtendsKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSaver := mock_service.NewMockIdempotencySaver(ctrl)
+	mockLogger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	resp := model.IdempotentResponse{Fingerprint: "fp", Status: 200}
+	mockSaver.EXPECT().SaveIdempotentResponse(gomock.Any(), int64(1), "key", "owner", resp, time.Hour).Return(nil)
+
+	idempotencyService := NewIdempotencyService(nil, mockSaver, nil, mockLogger, time.Hour, time.Minute)
+
+	assert.NoError(t, idempotencyService.Complete(context.Background(), 1, "key", "owner", resp))
+}
+
+func TestIdempotencyCompleteLeaseLost(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSaver := mock_service.NewMockIdempotencySaver(ctrl)
+	mockLogger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	resp := model.IdempotentResponse{Fingerprint: "fp", Status: 200}
+	mockSaver.EXPECT().SaveIdempotentResponse(gomock.Any(), int64(1), "key", "owner", resp, time.Hour).
+		Return(fmt.Errorf("save: %w", repository.ErrIdempotencyLeaseLost))
+
+	idempotencyService := NewIdempotencyService(nil, mockSaver, nil, mockLogger, time.Hour, time.Minute)
+
+	err := idempotencyService.Complete(context.Background(), 1, "key", "owner", resp)
+	assert.ErrorIs(t, err, ErrIdempotencyLeaseLost)
+}
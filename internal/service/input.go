@@ -0,0 +1,69 @@
+package service
+
+import "goapi/internal/model"
+
+// Входные данные сервисов. Правила те же, что и у запросов обработчиков,
+// поэтому вызовы не через HTTP (например, сборщик товаров) проверяются одинаково.
+
+type signInInput struct {
+	Email    string `json:"email" validate:"email_address"`
+	Password string `json:"password" validate:"password"`
+}
+
+type signUpInput struct {
+	Email    string `json:"email" validate:"email_address"`
+	Password string `json:"password" validate:"new_password"`
+}
+
+type productInput struct {
+	Name        string   `json:"name" validate:"product_name"`
+	Categoryies []string `json:"categoryies" validate:"category_names"`
+}
+
+type productNameInput struct {
+	ID   int64  `json:"id" validate:"entity_id"`
+	Name string `json:"name" validate:"product_name"`
+}
+
+type productCategoryiesInput struct {
+	ID          int64            `json:"id" validate:"entity_id"`
+	Categoryies []model.Category `json:"categoryies" validate:"required,min=1,category_list"`
+}
+
+type categoryProductsInput struct {
+	Category string `json:"category" validate:"category_name"`
+}
+
+type productsInput struct {
+	Products []model.Product `json:"products" validate:"required,dive"`
+}
+
+type categoryInput struct {
+	Name string `json:"name" validate:"category_name"`
+}
+
+type categoryNameInput struct {
+	ID   int64  `json:"id" validate:"entity_id"`
+	Name string `json:"name" validate:"category_name"`
+}
+
+type productSearchInput struct {
+	Query string `json:"query" validate:"search_query"`
+	Limit int    `json:"limit" validate:"search_limit"`
+}
+
+type productBatchInput struct {
+	Operations []model.ProductOperation `json:"operations" validate:"product_batch"`
+}
+
+type webhookInput struct {
+	URL        string   `json:"url" validate:"webhook_url"`
+	EventTypes []string `json:"eventTypes" validate:"event_types"`
+	Secret     string   `json:"secret" validate:"webhook_secret"`
+}
+
+type webhookUpdateInput struct {
+	URL        *string  `json:"url" validate:"omitempty,webhook_url"`
+	EventTypes []string `json:"eventTypes" validate:"omitempty,event_types"`
+	Secret     *string  `json:"secret" validate:"omitempty,webhook_secret"`
+}
@@ -8,6 +8,7 @@ import (
 	context "context"
 	model "goapi/internal/model"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 )
@@ -36,18 +37,18 @@ func (m *MockUserSaver) EXPECT() *MockUserSaverMockRecorder {
 }
 
 // SaveUser mocks base method.
-func (m *MockUserSaver) SaveUser(ctx context.Context, email string, passHash []byte) (int64, error) {
+func (m *MockUserSaver) SaveUser(ctx context.Context, email string, passHash []byte, role model.Role) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SaveUser", ctx, email, passHash)
+	ret := m.ctrl.Call(m, "SaveUser", ctx, email, passHash, role)
 	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // SaveUser indicates an expected call of SaveUser.
-func (mr *MockUserSaverMockRecorder) SaveUser(ctx, email, passHash interface{}) *gomock.Call {
+func (mr *MockUserSaverMockRecorder) SaveUser(ctx, email, passHash, role interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveUser", reflect.TypeOf((*MockUserSaver)(nil).SaveUser), ctx, email, passHash)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveUser", reflect.TypeOf((*MockUserSaver)(nil).SaveUser), ctx, email, passHash, role)
 }
 
 // MockUserProvider is a mock of UserProvider interface.
@@ -87,3 +88,41 @@ func (mr *MockUserProviderMockRecorder) User(ctx, email interface{}) *gomock.Cal
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "User", reflect.TypeOf((*MockUserProvider)(nil).User), ctx, email)
 }
+
+// MockTokenIssuer is a mock of TokenIssuer interface.
+type MockTokenIssuer struct {
+	ctrl     *gomock.Controller
+	recorder *MockTokenIssuerMockRecorder
+}
+
+// MockTokenIssuerMockRecorder is the mock recorder for MockTokenIssuer.
+type MockTokenIssuerMockRecorder struct {
+	mock *MockTokenIssuer
+}
+
+// NewMockTokenIssuer creates a new mock instance.
+func NewMockTokenIssuer(ctrl *gomock.Controller) *MockTokenIssuer {
+	mock := &MockTokenIssuer{ctrl: ctrl}
+	mock.recorder = &MockTokenIssuerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTokenIssuer) EXPECT() *MockTokenIssuerMockRecorder {
+	return m.recorder
+}
+
+// NewToken mocks base method.
+func (m *MockTokenIssuer) NewToken(user model.User, duration time.Duration) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NewToken", user, duration)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NewToken indicates an expected call of NewToken.
+func (mr *MockTokenIssuerMockRecorder) NewToken(user, duration interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewToken", reflect.TypeOf((*MockTokenIssuer)(nil).NewToken), user, duration)
+}
@@ -0,0 +1,88 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: catalog.go
+
+// Package mock_service is a generated GoMock package.
+package mock_service
+
+import (
+	context "context"
+	model "goapi/internal/model"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockExporterCatalog is a mock of ExporterCatalog interface.
+type MockExporterCatalog struct {
+	ctrl     *gomock.Controller
+	recorder *MockExporterCatalogMockRecorder
+}
+
+// MockExporterCatalogMockRecorder is the mock recorder for MockExporterCatalog.
+type MockExporterCatalogMockRecorder struct {
+	mock *MockExporterCatalog
+}
+
+// NewMockExporterCatalog creates a new mock instance.
+func NewMockExporterCatalog(ctrl *gomock.Controller) *MockExporterCatalog {
+	mock := &MockExporterCatalog{ctrl: ctrl}
+	mock.recorder = &MockExporterCatalogMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExporterCatalog) EXPECT() *MockExporterCatalogMockRecorder {
+	return m.recorder
+}
+
+// ExportProducts mocks base method.
+func (m *MockExporterCatalog) ExportProducts(ctx context.Context, fn func(model.Product) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportProducts", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExportProducts indicates an expected call of ExportProducts.
+func (mr *MockExporterCatalogMockRecorder) ExportProducts(ctx, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportProducts", reflect.TypeOf((*MockExporterCatalog)(nil).ExportProducts), ctx, fn)
+}
+
+// MockImporterCatalog is a mock of ImporterCatalog interface.
+type MockImporterCatalog struct {
+	ctrl     *gomock.Controller
+	recorder *MockImporterCatalogMockRecorder
+}
+
+// MockImporterCatalogMockRecorder is the mock recorder for MockImporterCatalog.
+type MockImporterCatalogMockRecorder struct {
+	mock *MockImporterCatalog
+}
+
+// NewMockImporterCatalog creates a new mock instance.
+func NewMockImporterCatalog(ctrl *gomock.Controller) *MockImporterCatalog {
+	mock := &MockImporterCatalog{ctrl: ctrl}
+	mock.recorder = &MockImporterCatalogMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockImporterCatalog) EXPECT() *MockImporterCatalogMockRecorder {
+	return m.recorder
+}
+
+// ImportProducts mocks base method.
+func (m *MockImporterCatalog) ImportProducts(ctx context.Context, rows []model.ImportRow, dryRun bool) ([]model.ImportResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportProducts", ctx, rows, dryRun)
+	ret0, _ := ret[0].([]model.ImportResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportProducts indicates an expected call of ImportProducts.
+func (mr *MockImporterCatalogMockRecorder) ImportProducts(ctx, rows, dryRun interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportProducts", reflect.TypeOf((*MockImporterCatalog)(nil).ImportProducts), ctx, rows, dryRun)
+}
@@ -74,17 +74,17 @@ func (m *MockDeleterCategory) EXPECT() *MockDeleterCategoryMockRecorder {
 }
 
 // DeleteCategory mocks base method.
-func (m *MockDeleterCategory) DeleteCategory(ctx context.Context, id int64) error {
+func (m *MockDeleterCategory) DeleteCategory(ctx context.Context, id, version int64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteCategory", ctx, id)
+	ret := m.ctrl.Call(m, "DeleteCategory", ctx, id, version)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteCategory indicates an expected call of DeleteCategory.
-func (mr *MockDeleterCategoryMockRecorder) DeleteCategory(ctx, id interface{}) *gomock.Call {
+func (mr *MockDeleterCategoryMockRecorder) DeleteCategory(ctx, id, version interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCategory", reflect.TypeOf((*MockDeleterCategory)(nil).DeleteCategory), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCategory", reflect.TypeOf((*MockDeleterCategory)(nil).DeleteCategory), ctx, id, version)
 }
 
 // MockUpdaterCategory is a mock of UpdaterCategory interface.
@@ -111,18 +111,18 @@ func (m *MockUpdaterCategory) EXPECT() *MockUpdaterCategoryMockRecorder {
 }
 
 // UpdateCategoryName mocks base method.
-func (m *MockUpdaterCategory) UpdateCategoryName(ctx context.Context, id int64, name string) (int64, error) {
+func (m *MockUpdaterCategory) UpdateCategoryName(ctx context.Context, id int64, name string, version int64) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateCategoryName", ctx, id, name)
+	ret := m.ctrl.Call(m, "UpdateCategoryName", ctx, id, name, version)
 	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // UpdateCategoryName indicates an expected call of UpdateCategoryName.
-func (mr *MockUpdaterCategoryMockRecorder) UpdateCategoryName(ctx, id, name interface{}) *gomock.Call {
+func (mr *MockUpdaterCategoryMockRecorder) UpdateCategoryName(ctx, id, name, version interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCategoryName", reflect.TypeOf((*MockUpdaterCategory)(nil).UpdateCategoryName), ctx, id, name)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCategoryName", reflect.TypeOf((*MockUpdaterCategory)(nil).UpdateCategoryName), ctx, id, name, version)
 }
 
 // MockGetterCategory is a mock of GetterCategory interface.
@@ -162,3 +162,33 @@ func (mr *MockGetterCategoryMockRecorder) GetAllCategoryies(ctx interface{}) *go
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllCategoryies", reflect.TypeOf((*MockGetterCategory)(nil).GetAllCategoryies), ctx)
 }
+
+// GetCategory mocks base method.
+func (m *MockGetterCategory) GetCategory(ctx context.Context, id int64) (model.Category, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCategory", ctx, id)
+	ret0, _ := ret[0].(model.Category)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCategory indicates an expected call of GetCategory.
+func (mr *MockGetterCategoryMockRecorder) GetCategory(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCategory", reflect.TypeOf((*MockGetterCategory)(nil).GetCategory), ctx, id)
+}
+
+// GetCategoryiesSummary mocks base method.
+func (m *MockGetterCategory) GetCategoryiesSummary(ctx context.Context) ([]model.CategorySummary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCategoryiesSummary", ctx)
+	ret0, _ := ret[0].([]model.CategorySummary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCategoryiesSummary indicates an expected call of GetCategoryiesSummary.
+func (mr *MockGetterCategoryMockRecorder) GetCategoryiesSummary(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCategoryiesSummary", reflect.TypeOf((*MockGetterCategory)(nil).GetCategoryiesSummary), ctx)
+}
@@ -0,0 +1,142 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: idempotency.go
+
+// Package mock_service is a generated GoMock package.
+package mock_service
+
+import (
+	context "context"
+	model "goapi/internal/model"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockIdempotencyReserver is a mock of IdempotencyReserver interface.
+type MockIdempotencyReserver struct {
+	ctrl     *gomock.Controller
+	recorder *MockIdempotencyReserverMockRecorder
+}
+
+// MockIdempotencyReserverMockRecorder is the mock recorder for MockIdempotencyReserver.
+type MockIdempotencyReserverMockRecorder struct {
+	mock *MockIdempotencyReserver
+}
+
+// NewMockIdempotencyReserver creates a new mock instance.
+func NewMockIdempotencyReserver(ctrl *gomock.Controller) *MockIdempotencyReserver {
+	mock := &MockIdempotencyReserver{ctrl: ctrl}
+	mock.recorder = &MockIdempotencyReserverMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIdempotencyReserver) EXPECT() *MockIdempotencyReserverMockRecorder {
+	return m.recorder
+}
+
+// ReserveIdempotencyKey mocks base method.
+func (m *MockIdempotencyReserver) ReserveIdempotencyKey(ctx context.Context, userID int64, key, fingerprint, owner string, ttl time.Duration) (model.IdempotentResponse, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReserveIdempotencyKey", ctx, userID, key, fingerprint, owner, ttl)
+	ret0, _ := ret[0].(model.IdempotentResponse)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ReserveIdempotencyKey indicates an expected call of ReserveIdempotencyKey.
+func (mr *MockIdempotencyReserverMockRecorder) ReserveIdempotencyKey(ctx, userID, key, fingerprint, owner, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReserveIdempotencyKey", reflect.TypeOf((*MockIdempotencyReserver)(nil).ReserveIdempotencyKey), ctx, userID, key, fingerprint, owner, ttl)
+}
+
+// MockIdempotencySaver is a mock of IdempotencySaver interface.
+type MockIdempotencySaver struct {
+	ctrl     *gomock.Controller
+	recorder *MockIdempotencySaverMockRecorder
+}
+
+// MockIdempotencySaverMockRecorder is the mock recorder for MockIdempotencySaver.
+type MockIdempotencySaverMockRecorder struct {
+	mock *MockIdempotencySaver
+}
+
+// NewMockIdempotencySaver creates a new mock instance.
+func NewMockIdempotencySaver(ctrl *gomock.Controller) *MockIdempotencySaver {
+	mock := &MockIdempotencySaver{ctrl: ctrl}
+	mock.recorder = &MockIdempotencySaverMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIdempotencySaver) EXPECT() *MockIdempotencySaverMockRecorder {
+	return m.recorder
+}
+
+// SaveIdempotentResponse mocks base method.
+func (m *MockIdempotencySaver) SaveIdempotentResponse(ctx context.Context, userID int64, key, owner string, resp model.IdempotentResponse, ttl time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveIdempotentResponse", ctx, userID, key, owner, resp, ttl)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveIdempotentResponse indicates an expected call of SaveIdempotentResponse.
+func (mr *MockIdempotencySaverMockRecorder) SaveIdempotentResponse(ctx, userID, key, owner, resp, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveIdempotentResponse", reflect.TypeOf((*MockIdempotencySaver)(nil).SaveIdempotentResponse), ctx, userID, key, owner, resp, ttl)
+}
+
+// MockIdempotencyRemover is a mock of IdempotencyRemover interface.
+type MockIdempotencyRemover struct {
+	ctrl     *gomock.Controller
+	recorder *MockIdempotencyRemoverMockRecorder
+}
+
+// MockIdempotencyRemoverMockRecorder is the mock recorder for MockIdempotencyRemover.
+type MockIdempotencyRemoverMockRecorder struct {
+	mock *MockIdempotencyRemover
+}
+
+// NewMockIdempotencyRemover creates a new mock instance.
+func NewMockIdempotencyRemover(ctrl *gomock.Controller) *MockIdempotencyRemover {
+	mock := &MockIdempotencyRemover{ctrl: ctrl}
+	mock.recorder = &MockIdempotencyRemoverMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIdempotencyRemover) EXPECT() *MockIdempotencyRemoverMockRecorder {
+	return m.recorder
+}
+
+// DeleteExpiredIdempotencyKeys mocks base method.
+func (m *MockIdempotencyRemover) DeleteExpiredIdempotencyKeys(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredIdempotencyKeys", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpiredIdempotencyKeys indicates an expected call of DeleteExpiredIdempotencyKeys.
+func (mr *MockIdempotencyRemoverMockRecorder) DeleteExpiredIdempotencyKeys(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredIdempotencyKeys", reflect.TypeOf((*MockIdempotencyRemover)(nil).DeleteExpiredIdempotencyKeys), ctx)
+}
+
+// DeleteIdempotencyKey mocks base method.
+func (m *MockIdempotencyRemover) DeleteIdempotencyKey(ctx context.Context, userID int64, key, owner string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteIdempotencyKey", ctx, userID, key, owner)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteIdempotencyKey indicates an expected call of DeleteIdempotencyKey.
+func (mr *MockIdempotencyRemoverMockRecorder) DeleteIdempotencyKey(ctx, userID, key, owner interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteIdempotencyKey", reflect.TypeOf((*MockIdempotencyRemover)(nil).DeleteIdempotencyKey), ctx, userID, key, owner)
+}
@@ -51,11 +51,12 @@ func (mr *MockAdderProductMockRecorder) AddProduct(ctx, name, categoryies interf
 }
 
 // AddProducts mocks base method.
-func (m *MockAdderProduct) AddProducts(ctx context.Context, products []model.Product) error {
+func (m *MockAdderProduct) AddProducts(ctx context.Context, products []model.Product) ([]int64, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "AddProducts", ctx, products)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // AddProducts indicates an expected call of AddProducts.
@@ -88,17 +89,17 @@ func (m *MockDeleterProduct) EXPECT() *MockDeleterProductMockRecorder {
 }
 
 // DeleteProduct mocks base method.
-func (m *MockDeleterProduct) DeleteProduct(ctx context.Context, id int64) error {
+func (m *MockDeleterProduct) DeleteProduct(ctx context.Context, id, version int64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteProduct", ctx, id)
+	ret := m.ctrl.Call(m, "DeleteProduct", ctx, id, version)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteProduct indicates an expected call of DeleteProduct.
-func (mr *MockDeleterProductMockRecorder) DeleteProduct(ctx, id interface{}) *gomock.Call {
+func (mr *MockDeleterProductMockRecorder) DeleteProduct(ctx, id, version interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteProduct", reflect.TypeOf((*MockDeleterProduct)(nil).DeleteProduct), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteProduct", reflect.TypeOf((*MockDeleterProduct)(nil).DeleteProduct), ctx, id, version)
 }
 
 // MockUpdaterProduct is a mock of UpdaterProduct interface.
@@ -125,33 +126,33 @@ func (m *MockUpdaterProduct) EXPECT() *MockUpdaterProductMockRecorder {
 }
 
 // UpdateProductCategoryies mocks base method.
-func (m *MockUpdaterProduct) UpdateProductCategoryies(ctx context.Context, id int64, categoryies []model.Category) (int64, error) {
+func (m *MockUpdaterProduct) UpdateProductCategoryies(ctx context.Context, id int64, categoryies []model.Category, version int64) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateProductCategoryies", ctx, id, categoryies)
+	ret := m.ctrl.Call(m, "UpdateProductCategoryies", ctx, id, categoryies, version)
 	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // UpdateProductCategoryies indicates an expected call of UpdateProductCategoryies.
-func (mr *MockUpdaterProductMockRecorder) UpdateProductCategoryies(ctx, id, categoryies interface{}) *gomock.Call {
+func (mr *MockUpdaterProductMockRecorder) UpdateProductCategoryies(ctx, id, categoryies, version interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProductCategoryies", reflect.TypeOf((*MockUpdaterProduct)(nil).UpdateProductCategoryies), ctx, id, categoryies)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProductCategoryies", reflect.TypeOf((*MockUpdaterProduct)(nil).UpdateProductCategoryies), ctx, id, categoryies, version)
 }
 
 // UpdateProductName mocks base method.
-func (m *MockUpdaterProduct) UpdateProductName(ctx context.Context, id int64, name string) (int64, error) {
+func (m *MockUpdaterProduct) UpdateProductName(ctx context.Context, id int64, name string, version int64) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateProductName", ctx, id, name)
+	ret := m.ctrl.Call(m, "UpdateProductName", ctx, id, name, version)
 	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // UpdateProductName indicates an expected call of UpdateProductName.
-func (mr *MockUpdaterProductMockRecorder) UpdateProductName(ctx, id, name interface{}) *gomock.Call {
+func (mr *MockUpdaterProductMockRecorder) UpdateProductName(ctx, id, name, version interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProductName", reflect.TypeOf((*MockUpdaterProduct)(nil).UpdateProductName), ctx, id, name)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProductName", reflect.TypeOf((*MockUpdaterProduct)(nil).UpdateProductName), ctx, id, name, version)
 }
 
 // MockGetterProduct is a mock of GetterProduct interface.
@@ -206,3 +207,116 @@ func (mr *MockGetterProductMockRecorder) GetCategoryProducts(ctx, category inter
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCategoryProducts", reflect.TypeOf((*MockGetterProduct)(nil).GetCategoryProducts), ctx, category)
 }
+
+// GetCategoryiesProducts mocks base method.
+func (m *MockGetterProduct) GetCategoryiesProducts(ctx context.Context, categoryIDs []int64) (map[int64][]model.Product, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCategoryiesProducts", ctx, categoryIDs)
+	ret0, _ := ret[0].(map[int64][]model.Product)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCategoryiesProducts indicates an expected call of GetCategoryiesProducts.
+func (mr *MockGetterProductMockRecorder) GetCategoryiesProducts(ctx, categoryIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCategoryiesProducts", reflect.TypeOf((*MockGetterProduct)(nil).GetCategoryiesProducts), ctx, categoryIDs)
+}
+
+// GetProduct mocks base method.
+func (m *MockGetterProduct) GetProduct(ctx context.Context, id int64) (model.Product, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProduct", ctx, id)
+	ret0, _ := ret[0].(model.Product)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProduct indicates an expected call of GetProduct.
+func (mr *MockGetterProductMockRecorder) GetProduct(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProduct", reflect.TypeOf((*MockGetterProduct)(nil).GetProduct), ctx, id)
+}
+
+// GetProducts mocks base method.
+func (m *MockGetterProduct) GetProducts(ctx context.Context, ids []int64) ([]model.Product, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProducts", ctx, ids)
+	ret0, _ := ret[0].([]model.Product)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProducts indicates an expected call of GetProducts.
+func (mr *MockGetterProductMockRecorder) GetProducts(ctx, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProducts", reflect.TypeOf((*MockGetterProduct)(nil).GetProducts), ctx, ids)
+}
+
+// GetProductsCategoryies mocks base method.
+func (m *MockGetterProduct) GetProductsCategoryies(ctx context.Context, productIDs []int64) (map[int64][]model.Category, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProductsCategoryies", ctx, productIDs)
+	ret0, _ := ret[0].(map[int64][]model.Category)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProductsCategoryies indicates an expected call of GetProductsCategoryies.
+func (mr *MockGetterProductMockRecorder) GetProductsCategoryies(ctx, productIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProductsCategoryies", reflect.TypeOf((*MockGetterProduct)(nil).GetProductsCategoryies), ctx, productIDs)
+}
+
+// SearchProducts mocks base method.
+func (m *MockGetterProduct) SearchProducts(ctx context.Context, query string, limit int) ([]model.Product, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchProducts", ctx, query, limit)
+	ret0, _ := ret[0].([]model.Product)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchProducts indicates an expected call of SearchProducts.
+func (mr *MockGetterProductMockRecorder) SearchProducts(ctx, query, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchProducts", reflect.TypeOf((*MockGetterProduct)(nil).SearchProducts), ctx, query, limit)
+}
+
+// MockBatcherProduct is a mock of BatcherProduct interface.
+type MockBatcherProduct struct {
+	ctrl     *gomock.Controller
+	recorder *MockBatcherProductMockRecorder
+}
+
+// MockBatcherProductMockRecorder is the mock recorder for MockBatcherProduct.
+type MockBatcherProductMockRecorder struct {
+	mock *MockBatcherProduct
+}
+
+// NewMockBatcherProduct creates a new mock instance.
+func NewMockBatcherProduct(ctrl *gomock.Controller) *MockBatcherProduct {
+	mock := &MockBatcherProduct{ctrl: ctrl}
+	mock.recorder = &MockBatcherProductMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBatcherProduct) EXPECT() *MockBatcherProductMockRecorder {
+	return m.recorder
+}
+
+// BatchProducts mocks base method.
+func (m *MockBatcherProduct) BatchProducts(ctx context.Context, operations []model.ProductOperation, atomic bool) ([]model.ProductOperationResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BatchProducts", ctx, operations, atomic)
+	ret0, _ := ret[0].([]model.ProductOperationResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BatchProducts indicates an expected call of BatchProducts.
+func (mr *MockBatcherProductMockRecorder) BatchProducts(ctx, operations, atomic interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchProducts", reflect.TypeOf((*MockBatcherProduct)(nil).BatchProducts), ctx, operations, atomic)
+}
@@ -0,0 +1,237 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: webhook.go
+
+// Package mock_service is a generated GoMock package.
+package mock_service
+
+import (
+	context "context"
+	model "goapi/internal/model"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockWebhookSaver is a mock of WebhookSaver interface.
+type MockWebhookSaver struct {
+	ctrl     *gomock.Controller
+	recorder *MockWebhookSaverMockRecorder
+}
+
+// MockWebhookSaverMockRecorder is the mock recorder for MockWebhookSaver.
+type MockWebhookSaverMockRecorder struct {
+	mock *MockWebhookSaver
+}
+
+// NewMockWebhookSaver creates a new mock instance.
+func NewMockWebhookSaver(ctrl *gomock.Controller) *MockWebhookSaver {
+	mock := &MockWebhookSaver{ctrl: ctrl}
+	mock.recorder = &MockWebhookSaverMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWebhookSaver) EXPECT() *MockWebhookSaverMockRecorder {
+	return m.recorder
+}
+
+// CreateWebhook mocks base method.
+func (m *MockWebhookSaver) CreateWebhook(ctx context.Context, webhook model.Webhook) (model.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhook", ctx, webhook)
+	ret0, _ := ret[0].(model.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWebhook indicates an expected call of CreateWebhook.
+func (mr *MockWebhookSaverMockRecorder) CreateWebhook(ctx, webhook interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhook", reflect.TypeOf((*MockWebhookSaver)(nil).CreateWebhook), ctx, webhook)
+}
+
+// MockWebhookProvider is a mock of WebhookProvider interface.
+type MockWebhookProvider struct {
+	ctrl     *gomock.Controller
+	recorder *MockWebhookProviderMockRecorder
+}
+
+// MockWebhookProviderMockRecorder is the mock recorder for MockWebhookProvider.
+type MockWebhookProviderMockRecorder struct {
+	mock *MockWebhookProvider
+}
+
+// NewMockWebhookProvider creates a new mock instance.
+func NewMockWebhookProvider(ctrl *gomock.Controller) *MockWebhookProvider {
+	mock := &MockWebhookProvider{ctrl: ctrl}
+	mock.recorder = &MockWebhookProviderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWebhookProvider) EXPECT() *MockWebhookProviderMockRecorder {
+	return m.recorder
+}
+
+// GetWebhook mocks base method.
+func (m *MockWebhookProvider) GetWebhook(ctx context.Context, userID, id int64) (model.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhook", ctx, userID, id)
+	ret0, _ := ret[0].(model.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhook indicates an expected call of GetWebhook.
+func (mr *MockWebhookProviderMockRecorder) GetWebhook(ctx, userID, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhook", reflect.TypeOf((*MockWebhookProvider)(nil).GetWebhook), ctx, userID, id)
+}
+
+// GetWebhookDelivery mocks base method.
+func (m *MockWebhookProvider) GetWebhookDelivery(ctx context.Context, userID, webhookID, id int64) (model.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhookDelivery", ctx, userID, webhookID, id)
+	ret0, _ := ret[0].(model.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhookDelivery indicates an expected call of GetWebhookDelivery.
+func (mr *MockWebhookProviderMockRecorder) GetWebhookDelivery(ctx, userID, webhookID, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookDelivery", reflect.TypeOf((*MockWebhookProvider)(nil).GetWebhookDelivery), ctx, userID, webhookID, id)
+}
+
+// ListWebhookDeliveries mocks base method.
+func (m *MockWebhookProvider) ListWebhookDeliveries(ctx context.Context, userID, webhookID int64, limit int) ([]model.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWebhookDeliveries", ctx, userID, webhookID, limit)
+	ret0, _ := ret[0].([]model.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWebhookDeliveries indicates an expected call of ListWebhookDeliveries.
+func (mr *MockWebhookProviderMockRecorder) ListWebhookDeliveries(ctx, userID, webhookID, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebhookDeliveries", reflect.TypeOf((*MockWebhookProvider)(nil).ListWebhookDeliveries), ctx, userID, webhookID, limit)
+}
+
+// ListWebhooks mocks base method.
+func (m *MockWebhookProvider) ListWebhooks(ctx context.Context, userID int64) ([]model.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWebhooks", ctx, userID)
+	ret0, _ := ret[0].([]model.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWebhooks indicates an expected call of ListWebhooks.
+func (mr *MockWebhookProviderMockRecorder) ListWebhooks(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebhooks", reflect.TypeOf((*MockWebhookProvider)(nil).ListWebhooks), ctx, userID)
+}
+
+// MockWebhookUpdater is a mock of WebhookUpdater interface.
+type MockWebhookUpdater struct {
+	ctrl     *gomock.Controller
+	recorder *MockWebhookUpdaterMockRecorder
+}
+
+// MockWebhookUpdaterMockRecorder is the mock recorder for MockWebhookUpdater.
+type MockWebhookUpdaterMockRecorder struct {
+	mock *MockWebhookUpdater
+}
+
+// NewMockWebhookUpdater creates a new mock instance.
+func NewMockWebhookUpdater(ctrl *gomock.Controller) *MockWebhookUpdater {
+	mock := &MockWebhookUpdater{ctrl: ctrl}
+	mock.recorder = &MockWebhookUpdaterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWebhookUpdater) EXPECT() *MockWebhookUpdaterMockRecorder {
+	return m.recorder
+}
+
+// DeleteWebhook mocks base method.
+func (m *MockWebhookUpdater) DeleteWebhook(ctx context.Context, userID, id, version int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhook", ctx, userID, id, version)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWebhook indicates an expected call of DeleteWebhook.
+func (mr *MockWebhookUpdaterMockRecorder) DeleteWebhook(ctx, userID, id, version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhook", reflect.TypeOf((*MockWebhookUpdater)(nil).DeleteWebhook), ctx, userID, id, version)
+}
+
+// RedeliverWebhook mocks base method.
+func (m *MockWebhookUpdater) RedeliverWebhook(ctx context.Context, userID, webhookID, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RedeliverWebhook", ctx, userID, webhookID, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RedeliverWebhook indicates an expected call of RedeliverWebhook.
+func (mr *MockWebhookUpdaterMockRecorder) RedeliverWebhook(ctx, userID, webhookID, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RedeliverWebhook", reflect.TypeOf((*MockWebhookUpdater)(nil).RedeliverWebhook), ctx, userID, webhookID, id)
+}
+
+// UpdateWebhook mocks base method.
+func (m *MockWebhookUpdater) UpdateWebhook(ctx context.Context, userID, id int64, update model.WebhookUpdate, version int64) (model.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWebhook", ctx, userID, id, update, version)
+	ret0, _ := ret[0].(model.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateWebhook indicates an expected call of UpdateWebhook.
+func (mr *MockWebhookUpdaterMockRecorder) UpdateWebhook(ctx, userID, id, update, version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWebhook", reflect.TypeOf((*MockWebhookUpdater)(nil).UpdateWebhook), ctx, userID, id, update, version)
+}
+
+// MockWebhookURLChecker is a mock of WebhookURLChecker interface.
+type MockWebhookURLChecker struct {
+	ctrl     *gomock.Controller
+	recorder *MockWebhookURLCheckerMockRecorder
+}
+
+// MockWebhookURLCheckerMockRecorder is the mock recorder for MockWebhookURLChecker.
+type MockWebhookURLCheckerMockRecorder struct {
+	mock *MockWebhookURLChecker
+}
+
+// NewMockWebhookURLChecker creates a new mock instance.
+func NewMockWebhookURLChecker(ctrl *gomock.Controller) *MockWebhookURLChecker {
+	mock := &MockWebhookURLChecker{ctrl: ctrl}
+	mock.recorder = &MockWebhookURLCheckerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWebhookURLChecker) EXPECT() *MockWebhookURLCheckerMockRecorder {
+	return m.recorder
+}
+
+// CheckURL mocks base method.
+func (m *MockWebhookURLChecker) CheckURL(ctx context.Context, url string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckURL", ctx, url)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CheckURL indicates an expected call of CheckURL.
+func (mr *MockWebhookURLCheckerMockRecorder) CheckURL(ctx, url interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckURL", reflect.TypeOf((*MockWebhookURLChecker)(nil).CheckURL), ctx, url)
+}
@@ -4,6 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"goapi/internal/lib/apperr"
+	"goapi/internal/lib/logger/logctx"
+	"goapi/internal/lib/logger/sl"
+	"goapi/internal/lib/validate"
 	"goapi/internal/model"
 	"goapi/internal/repository"
 	"log/slog"
@@ -14,14 +18,21 @@ import (
 const (
 	ErrProductId      = -1
 	TagGetAllProducts = "get all products"
+
+	DefaultSearchResults = 20
 )
 
 var (
-	ErrProductNameIsEmpty = errors.New("product name is empty")
-	ErrProductIDIsEmpty   = errors.New("product id is empty")
-	ErrCategoryiesEmpty   = errors.New("product categoryies is empty")
-	ErrProductsEmpty      = errors.New("products is empty")
-	ErrProductUnknownTag  = errors.New("unknown tag get all products")
+	ErrProductNameIsEmpty      = apperr.InvalidField("product_name_empty", "/name", "product name is empty")
+	ErrProductIDIsEmpty        = apperr.InvalidField("product_id_empty", "/id", "product id is empty")
+	ErrCategoryiesEmpty        = apperr.InvalidField("product_categoryies_empty", "/categoryies", "product categoryies is empty")
+	ErrProductsEmpty           = apperr.Validation("products_empty", "products is empty")
+	ErrProductUnknownTag       = apperr.InvalidField("unknown_tag", "/tag", "unknown tag get all products")
+	ErrProductNotFound         = apperr.NotFound("product_not_found", "product not found")
+	ErrProductCategoryNotFound = apperr.InvalidField("product_category_not_found", "/categoryies", "product category not found")
+	ErrProductOperationEmpty   = apperr.InvalidField("product_operation_empty", "/name", "update operation must change name or categoryies")
+	ErrProductVersionMismatch  = apperr.PreconditionFailed("product_version_mismatch", "product was modified by another request")
+	ErrProductVersionRequired  = apperr.PreconditionRequired("product_version_required", "product version is required")
 )
 
 type ProductService struct {
@@ -29,26 +40,38 @@ type ProductService struct {
 	deleter DeleterProduct
 	updater UpdaterProduct
 	getter  GetterProduct
+	batcher BatcherProduct
+	tx      Transactor
+	events  EventSaver
 	log     *slog.Logger
 }
 
 type AdderProduct interface {
 	AddProduct(ctx context.Context, name string, categoryies []string) (int64, error)
-	AddProducts(ctx context.Context, products []model.Product) error
+	AddProducts(ctx context.Context, products []model.Product) ([]int64, error)
 }
 
 type DeleterProduct interface {
-	DeleteProduct(ctx context.Context, id int64) error
+	DeleteProduct(ctx context.Context, id int64, version int64) error
 }
 
 type UpdaterProduct interface {
-	UpdateProductName(ctx context.Context, id int64, name string) (int64, error)
-	UpdateProductCategoryies(ctx context.Context, id int64, categoryies []model.Category) (int64, error)
+	UpdateProductName(ctx context.Context, id int64, name string, version int64) (int64, error)
+	UpdateProductCategoryies(ctx context.Context, id int64, categoryies []model.Category, version int64) (int64, error)
 }
 
 type GetterProduct interface {
+	GetProduct(ctx context.Context, id int64) (model.Product, error)
+	GetProducts(ctx context.Context, ids []int64) ([]model.Product, error)
 	GetAllProducts(ctx context.Context) ([]model.Product, error)
 	GetCategoryProducts(ctx context.Context, category string) ([]model.Product, error)
+	SearchProducts(ctx context.Context, query string, limit int) ([]model.Product, error)
+	GetProductsCategoryies(ctx context.Context, productIDs []int64) (map[int64][]model.Category, error)
+	GetCategoryiesProducts(ctx context.Context, categoryIDs []int64) (map[int64][]model.Product, error)
+}
+
+type BatcherProduct interface {
+	BatchProducts(ctx context.Context, operations []model.ProductOperation, atomic bool) ([]model.ProductOperationResult, error)
 }
 
 func NewProductService(
@@ -56,6 +79,9 @@ func NewProductService(
 	d DeleterProduct,
 	u UpdaterProduct,
 	g GetterProduct,
+	b BatcherProduct,
+	tx Transactor,
+	e EventSaver,
 	l *slog.Logger,
 ) *ProductService {
 	return &ProductService{
@@ -63,14 +89,19 @@ func NewProductService(
 		deleter: d,
 		updater: u,
 		getter:  g,
+		batcher: b,
+		tx:      tx,
+		events:  e,
 		log:     l,
 	}
 }
 
 func (s *ProductService) AddProduct(ctx context.Context, name string, categoryies []string) (int64, error) {
 	const op = "product.AddProduct"
+	ctx, span := tracer.Start(ctx, "ProductService.AddProduct")
+	defer span.End()
 
-	log := s.log.With(
+	log := logctx.From(ctx, s.log).With(
 		slog.String("op", op),
 		slog.String("name", name),
 	)
@@ -78,23 +109,41 @@ func (s *ProductService) AddProduct(ctx context.Context, name string, categoryie
 	log.Info("add product")
 
 	if name == "" {
-		log.Error("data is invalid: ", ErrProductNameIsEmpty)
+		log.Error("data is invalid", sl.Err(ErrProductNameIsEmpty))
 		return ErrProductId, fmt.Errorf("%s %w", op, ErrProductNameIsEmpty)
 	}
 
 	if len(categoryies) == 0 {
-		log.Error("data is invalid: ", ErrCategoryiesEmpty)
+		log.Error("data is invalid", sl.Err(ErrCategoryiesEmpty))
 		return ErrProductId, fmt.Errorf("%s %w", op, ErrCategoryiesEmpty)
 	}
 
-	productID, err := s.adder.AddProduct(ctx, name, categoryies)
+	if err := validate.Struct(productInput{Name: name, Categoryies: categoryies}); err != nil {
+		log.Error("data is invalid", sl.Err(err))
+		return ErrProductId, fmt.Errorf("%s %w", op, err)
+	}
+
+	var productID int64
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		id, err := s.adder.AddProduct(ctx, name, categoryies)
+		if err != nil {
+			return err
+		}
+
+		productID = id
+		return s.events.SaveEvents(ctx, model.ProductCreated{
+			ProductID:   id,
+			Name:        name,
+			Categoryies: categoryiesByName(categoryies),
+		})
+	})
 	if err != nil {
-		if errors.Is(err, repository.ErrSaveProduct) {
-			s.log.Warn("product isnt saved", err)
-			return ErrProductId, fmt.Errorf("%s %w", op, ErrorInvalidCredentials)
+		if errors.Is(err, repository.ErrCategoryNotFound) {
+			log.Warn("product category not found", sl.Err(err))
+			return ErrProductId, fmt.Errorf("%s %w", op, ErrProductCategoryNotFound)
 		}
 
-		log.Error("product dont saved", err)
+		log.Error("product dont saved", sl.Err(err))
 		return ErrProductId, fmt.Errorf("%s %w", op, err)
 	}
 
@@ -103,33 +152,58 @@ func (s *ProductService) AddProduct(ctx context.Context, name string, categoryie
 	return productID, nil
 }
 
-func (s *ProductService) DeleteProduct(ctx context.Context, id int64) error {
+// DeleteProduct удаляет товар. Версия должна совпадать с текущей версией товара.
+func (s *ProductService) DeleteProduct(ctx context.Context, id int64, version int64) error {
 	const op = "product.DeleteProduct"
+	ctx, span := tracer.Start(ctx, "ProductService.DeleteProduct")
+	defer span.End()
 
-	log := s.log.With(
+	log := logctx.From(ctx, s.log).With(
 		slog.String("op", op),
 		slog.Int64("id", id),
 	)
 
 	log.Info("delete product")
 
+	if err := requireAdmin(ctx); err != nil {
+		log.Warn("access denied", sl.Err(err))
+		return fmt.Errorf("%s %w", op, err)
+	}
+
 	if id <= 0 {
-		log.Error("data is invalid: ", ErrProductIDIsEmpty)
+		log.Error("data is invalid", sl.Err(ErrProductIDIsEmpty))
 		return fmt.Errorf("%s %w", op, ErrProductIDIsEmpty)
 	}
 
-	err := s.deleter.DeleteProduct(ctx, id)
+	if version <= 0 {
+		log.Warn("version is empty", sl.Err(ErrProductVersionRequired))
+		return fmt.Errorf("%s %w", op, ErrProductVersionRequired)
+	}
+
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		product, err := s.getter.GetProduct(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if err := s.deleter.DeleteProduct(ctx, id, version); err != nil {
+			return err
+		}
+
+		return s.events.SaveEvents(ctx, model.ProductDeleted{ProductID: id, Categoryies: product.Categoryies})
+	})
 	if err != nil {
-		if errors.Is(err, repository.ErrDeleteProduct) {
-			s.log.Warn("product isnt deleted", err)
-			return fmt.Errorf("%s %w", op, ErrorInvalidCredentials)
+		if errors.Is(err, repository.ErrProductNotFound) {
+			log.Warn("product not found", sl.Err(err))
+			return fmt.Errorf("%s %w", op, ErrProductNotFound)
 		}
-		if errors.Is(err, repository.ErrDeleteProductCategory) {
-			s.log.Warn("product isnt deleted", err)
-			return fmt.Errorf("%s %w", op, ErrorInvalidCredentials)
+
+		if errors.Is(err, repository.ErrVersionMismatch) {
+			log.Warn("product version mismatch", sl.Err(err))
+			return fmt.Errorf("%s %w", op, ErrProductVersionMismatch)
 		}
 
-		log.Error("product didnt deleted", err)
+		log.Error("product didnt deleted", sl.Err(err))
 		return fmt.Errorf("%s %w", op, err)
 	}
 
@@ -138,10 +212,14 @@ func (s *ProductService) DeleteProduct(ctx context.Context, id int64) error {
 	return nil
 }
 
-func (s *ProductService) EditProductName(ctx context.Context, id int64, name string) (int64, error) {
+// EditProductName переименовывает товар и возвращает его новую версию.
+// Версия должна совпадать с текущей версией товара.
+func (s *ProductService) EditProductName(ctx context.Context, id int64, name string, version int64) (int64, error) {
 	const op = "product.EditProductName"
+	ctx, span := tracer.Start(ctx, "ProductService.EditProductName")
+	defer span.End()
 
-	log := s.log.With(
+	log := logctx.From(ctx, s.log).With(
 		slog.String("op", op),
 		slog.Int64("id", id),
 	)
@@ -149,35 +227,68 @@ func (s *ProductService) EditProductName(ctx context.Context, id int64, name str
 	log.Info("edit product name")
 
 	if id <= 0 {
-		log.Error("data is invalid: ", ErrProductIDIsEmpty)
+		log.Error("data is invalid", sl.Err(ErrProductIDIsEmpty))
 		return ErrProductId, fmt.Errorf("%s %w", op, ErrProductIDIsEmpty)
 	}
 
+	if version <= 0 {
+		log.Warn("version is empty", sl.Err(ErrProductVersionRequired))
+		return ErrProductId, fmt.Errorf("%s %w", op, ErrProductVersionRequired)
+	}
+
 	if name == "" {
-		log.Error("data is invalid: ", ErrProductNameIsEmpty)
+		log.Error("data is invalid", sl.Err(ErrProductNameIsEmpty))
 		return ErrProductId, fmt.Errorf("%s %w", op, ErrProductNameIsEmpty)
 	}
 
-	productID, err := s.updater.UpdateProductName(ctx, id, name)
+	if err := validate.Struct(productNameInput{ID: id, Name: name}); err != nil {
+		log.Error("data is invalid", sl.Err(err))
+		return ErrProductId, fmt.Errorf("%s %w", op, err)
+	}
+
+	var newVersion int64
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		product, err := s.getter.GetProduct(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		v, err := s.updater.UpdateProductName(ctx, id, name, version)
+		if err != nil {
+			return err
+		}
+
+		newVersion = v
+		return s.events.SaveEvents(ctx, model.ProductRenamed{ProductID: id, Name: name, Categoryies: product.Categoryies})
+	})
 	if err != nil {
-		if errors.Is(err, repository.ErrUpdateProduct) {
-			s.log.Warn("product isnt edited", err)
-			return ErrProductId, fmt.Errorf("%s %w", op, ErrorInvalidCredentials)
+		if errors.Is(err, repository.ErrProductNotFound) {
+			log.Warn("product not found", sl.Err(err))
+			return ErrProductId, fmt.Errorf("%s %w", op, ErrProductNotFound)
 		}
 
-		log.Error("product name didnt edited", err)
+		if errors.Is(err, repository.ErrVersionMismatch) {
+			log.Warn("product version mismatch", sl.Err(err))
+			return ErrProductId, fmt.Errorf("%s %w", op, ErrProductVersionMismatch)
+		}
+
+		log.Error("product name didnt edited", sl.Err(err))
 		return ErrProductId, fmt.Errorf("%s %w", op, err)
 	}
 
 	log.Info("product is edited")
 
-	return productID, nil
+	return newVersion, nil
 }
 
-func (s *ProductService) EditProductCategory(ctx context.Context, id int64, categoryies []model.Category) (int64, error) {
+// EditProductCategory заменяет категории товара и возвращает его новую версию.
+// Версия должна совпадать с текущей версией товара.
+func (s *ProductService) EditProductCategory(ctx context.Context, id int64, categoryies []model.Category, version int64) (int64, error) {
 	const op = "product.EditProductCategoryies"
+	ctx, span := tracer.Start(ctx, "ProductService.EditProductCategory")
+	defer span.End()
 
-	log := s.log.With(
+	log := logctx.From(ctx, s.log).With(
 		slog.String("op", op),
 		slog.Int64("id", id),
 	)
@@ -185,39 +296,176 @@ func (s *ProductService) EditProductCategory(ctx context.Context, id int64, cate
 	log.Info("edit product categoryies")
 
 	if id <= 0 {
-		log.Error("data is invalid: ", ErrProductIDIsEmpty)
+		log.Error("data is invalid", sl.Err(ErrProductIDIsEmpty))
 		return ErrProductId, fmt.Errorf("%s %w", op, ErrProductIDIsEmpty)
 	}
 
+	if version <= 0 {
+		log.Warn("version is empty", sl.Err(ErrProductVersionRequired))
+		return ErrProductId, fmt.Errorf("%s %w", op, ErrProductVersionRequired)
+	}
+
 	if len(categoryies) == 0 {
-		log.Error("data is invalid: ", ErrCategoryiesEmpty)
+		log.Error("data is invalid", sl.Err(ErrCategoryiesEmpty))
 		return ErrProductId, fmt.Errorf("%s %w", op, ErrCategoryiesEmpty)
 	}
 
-	productID, err := s.updater.UpdateProductCategoryies(ctx, id, categoryies)
+	if err := validate.Struct(productCategoryiesInput{ID: id, Categoryies: categoryies}); err != nil {
+		log.Error("data is invalid", sl.Err(err))
+		return ErrProductId, fmt.Errorf("%s %w", op, err)
+	}
+
+	var newVersion int64
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		v, err := s.updater.UpdateProductCategoryies(ctx, id, categoryies, version)
+		if err != nil {
+			return err
+		}
+
+		newVersion = v
+
+		// в событие попадают категории с идентификатором и именем, даже если запрос задал только одно из них
+		product, err := s.getter.GetProduct(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		return s.events.SaveEvents(ctx, model.ProductCategoryiesChanged{ProductID: id, Categoryies: product.Categoryies})
+	})
 	if err != nil {
-		if errors.Is(err, repository.ErrDeleteProductCategory) {
-			s.log.Warn("product isnt edited", err)
-			return ErrProductId, fmt.Errorf("%s %w", op, ErrorInvalidCredentials)
+		if errors.Is(err, repository.ErrSaveProductCategory) || errors.Is(err, repository.ErrCategoryNotFound) {
+			log.Warn("product category not saved", sl.Err(err))
+			return ErrProductId, fmt.Errorf("%s %w", op, ErrProductCategoryNotFound)
 		}
-		if errors.Is(err, repository.ErrSaveProductCategory) {
-			s.log.Warn("product isnt edited", err)
-			return ErrProductId, fmt.Errorf("%s %w", op, ErrorInvalidCredentials)
+
+		if errors.Is(err, repository.ErrProductNotFound) {
+			log.Warn("product not found", sl.Err(err))
+			return ErrProductId, fmt.Errorf("%s %w", op, ErrProductNotFound)
+		}
+
+		if errors.Is(err, repository.ErrVersionMismatch) {
+			log.Warn("product version mismatch", sl.Err(err))
+			return ErrProductId, fmt.Errorf("%s %w", op, ErrProductVersionMismatch)
 		}
 
-		log.Error("product categoryies didnt edited", err)
+		log.Error("product categoryies didnt edited", sl.Err(err))
 		return ErrProductId, fmt.Errorf("%s %w", op, err)
 	}
 
 	log.Info("product is edited")
 
-	return productID, nil
+	return newVersion, nil
+}
+
+// GetProduct возвращает товар с категориями и текущей версией
+func (s *ProductService) GetProduct(ctx context.Context, id int64) (model.Product, error) {
+	const op = "product.GetProduct"
+	ctx, span := tracer.Start(ctx, "ProductService.GetProduct")
+	defer span.End()
+
+	log := logctx.From(ctx, s.log).With(
+		slog.String("op", op),
+		slog.Int64("id", id),
+	)
+
+	log.Info("get product")
+
+	if id <= 0 {
+		log.Error("data is invalid", sl.Err(ErrProductIDIsEmpty))
+		return model.Product{}, fmt.Errorf("%s %w", op, ErrProductIDIsEmpty)
+	}
+
+	product, err := s.getter.GetProduct(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrProductNotFound) {
+			log.Warn("product not found", sl.Err(err))
+			return model.Product{}, fmt.Errorf("%s %w", op, ErrProductNotFound)
+		}
+
+		log.Error("product didnt get", sl.Err(err))
+		return model.Product{}, fmt.Errorf("%s %w", op, err)
+	}
+
+	log.Info("product is getter")
+
+	return product, nil
+}
+
+// SearchProducts ищет товары по подстроке в названии. Нулевой limit означает значение по умолчанию.
+func (s *ProductService) SearchProducts(ctx context.Context, query string, limit int) ([]model.Product, error) {
+	const op = "product.SearchProducts"
+	ctx, span := tracer.Start(ctx, "ProductService.SearchProducts")
+	defer span.End()
+
+	log := logctx.From(ctx, s.log).With(
+		slog.String("op", op),
+		slog.String("query", query),
+	)
+
+	log.Info("search products")
+
+	if limit == 0 {
+		limit = DefaultSearchResults
+	}
+
+	if err := validate.Struct(productSearchInput{Query: query, Limit: limit}); err != nil {
+		log.Warn("data is invalid", sl.Err(err))
+		return nil, fmt.Errorf("%s %w", op, err)
+	}
+
+	products, err := s.getter.SearchProducts(ctx, query, limit)
+	if err != nil {
+		log.Error("products didnt search", sl.Err(err))
+		return nil, fmt.Errorf("%s %w", op, err)
+	}
+
+	return products, nil
+}
+
+// GetProductsCategoryies возвращает категории нескольких товаров за один запрос к хранилищу
+func (s *ProductService) GetProductsCategoryies(ctx context.Context, productIDs []int64) (map[int64][]model.Category, error) {
+	const op = "product.GetProductsCategoryies"
+	ctx, span := tracer.Start(ctx, "ProductService.GetProductsCategoryies")
+	defer span.End()
+
+	if len(productIDs) == 0 {
+		return map[int64][]model.Category{}, nil
+	}
+
+	categoryies, err := s.getter.GetProductsCategoryies(ctx, productIDs)
+	if err != nil {
+		s.log.Error("categoryies didnt get", slog.String("op", op), sl.Err(err))
+		return nil, fmt.Errorf("%s %w", op, err)
+	}
+
+	return categoryies, nil
+}
+
+// GetCategoryiesProducts возвращает товары нескольких категорий за один запрос к хранилищу
+func (s *ProductService) GetCategoryiesProducts(ctx context.Context, categoryIDs []int64) (map[int64][]model.Product, error) {
+	const op = "product.GetCategoryiesProducts"
+	ctx, span := tracer.Start(ctx, "ProductService.GetCategoryiesProducts")
+	defer span.End()
+
+	if len(categoryIDs) == 0 {
+		return map[int64][]model.Product{}, nil
+	}
+
+	products, err := s.getter.GetCategoryiesProducts(ctx, categoryIDs)
+	if err != nil {
+		s.log.Error("products didnt get", slog.String("op", op), sl.Err(err))
+		return nil, fmt.Errorf("%s %w", op, err)
+	}
+
+	return products, nil
 }
 
 func (s *ProductService) GetAllProducts(ctx context.Context, tag string) ([]model.Product, error) {
 	const op = "product.GetAllProducts"
+	ctx, span := tracer.Start(ctx, "ProductService.GetAllProducts")
+	defer span.End()
 
-	log := s.log.With(
+	log := logctx.From(ctx, s.log).With(
 		slog.String("op", op),
 		slog.String("tag", tag),
 	)
@@ -225,18 +473,13 @@ func (s *ProductService) GetAllProducts(ctx context.Context, tag string) ([]mode
 	log.Info("get all product")
 
 	if tag != TagGetAllProducts {
-		log.Error("products didnt get", ErrProductUnknownTag)
+		log.Error("products didnt get", sl.Err(ErrProductUnknownTag))
 		return []model.Product{}, fmt.Errorf("%s %w", op, ErrProductUnknownTag)
 	}
 
 	products, err := s.getter.GetAllProducts(ctx)
 	if err != nil {
-		if errors.Is(err, repository.ErrProductNotFound) {
-			s.log.Warn("products empty", err)
-			return []model.Product{}, fmt.Errorf("%s %w", op, ErrorInvalidCredentials)
-		}
-
-		log.Error("products didnt get", err)
+		log.Error("products didnt get", sl.Err(err))
 		return []model.Product{}, fmt.Errorf("%s %w", op, err)
 	}
 
@@ -247,8 +490,10 @@ func (s *ProductService) GetAllProducts(ctx context.Context, tag string) ([]mode
 
 func (s *ProductService) GetCategoryProducts(ctx context.Context, category string) ([]model.Product, error) {
 	const op = "product.GetAllProducts"
+	ctx, span := tracer.Start(ctx, "ProductService.GetCategoryProducts")
+	defer span.End()
 
-	log := s.log.With(
+	log := logctx.From(ctx, s.log).With(
 		slog.String("op", op),
 		slog.String("category", category),
 	)
@@ -256,18 +501,18 @@ func (s *ProductService) GetCategoryProducts(ctx context.Context, category strin
 	log.Info("get category product")
 
 	if category == "" {
-		log.Error("data is invalid: ", ErrCategoryiesEmpty)
+		log.Error("data is invalid", sl.Err(ErrCategoryiesEmpty))
 		return []model.Product{}, fmt.Errorf("%s %w", op, ErrCategoryiesEmpty)
 	}
 
+	if err := validate.Struct(categoryProductsInput{Category: category}); err != nil {
+		log.Error("data is invalid", sl.Err(err))
+		return []model.Product{}, fmt.Errorf("%s %w", op, err)
+	}
+
 	products, err := s.getter.GetCategoryProducts(ctx, category)
 	if err != nil {
-		if errors.Is(err, repository.ErrProductNotFound) {
-			s.log.Warn("products empty", err)
-			return []model.Product{}, fmt.Errorf("%s: %w", op, ErrorInvalidCredentials)
-		}
-
-		log.Error("products didnt get", err)
+		log.Error("products didnt get", sl.Err(err))
 		return []model.Product{}, fmt.Errorf("%s %w", op, err)
 	}
 
@@ -278,26 +523,49 @@ func (s *ProductService) GetCategoryProducts(ctx context.Context, category strin
 
 func (s *ProductService) AddProducts(ctx context.Context, products []model.Product) error {
 	const op = "postgres.AddProducts"
+	ctx, span := tracer.Start(ctx, "ProductService.AddProducts")
+	defer span.End()
 
-	log := s.log.With(
+	log := logctx.From(ctx, s.log).With(
 		slog.String("op", op),
 	)
 
 	log.Info("add products")
 
 	if len(products) == 0 {
-		log.Error("data is invalid: ", ErrProductsEmpty)
+		log.Error("data is invalid", sl.Err(ErrProductsEmpty))
 		return fmt.Errorf("%s %w", op, ErrProductsEmpty)
 	}
 
-	err := s.adder.AddProducts(ctx, products)
+	if err := validate.Struct(productsInput{Products: products}); err != nil {
+		log.Error("data is invalid", sl.Err(err))
+		return fmt.Errorf("%s %w", op, err)
+	}
+
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		ids, err := s.adder.AddProducts(ctx, products)
+		if err != nil {
+			return err
+		}
+
+		events := make([]model.DomainEvent, 0, len(ids))
+		for i, id := range ids {
+			events = append(events, model.ProductCreated{
+				ProductID:   id,
+				Name:        products[i].Name,
+				Categoryies: products[i].Categoryies,
+			})
+		}
+
+		return s.events.SaveEvents(ctx, events...)
+	})
 	if err != nil {
-		if errors.Is(err, repository.ErrSaveProduct) {
-			s.log.Warn("products empty", err)
-			return fmt.Errorf("%s: %w", op, ErrorInvalidCredentials)
+		if errors.Is(err, repository.ErrCategoryNotFound) {
+			log.Warn("product category not found", sl.Err(err))
+			return fmt.Errorf("%s %w", op, ErrProductCategoryNotFound)
 		}
 
-		log.Error("products didnt added", err)
+		log.Error("products didnt added", sl.Err(err))
 		return fmt.Errorf("%s %w", op, err)
 	}
 
@@ -305,3 +573,157 @@ func (s *ProductService) AddProducts(ctx context.Context, products []model.Produ
 
 	return nil
 }
+
+// BatchProducts выполняет пакет операций с товарами и возвращает результат по каждой операции.
+// Ошибки отдельных операций не прерывают вызов и возвращаются в результатах.
+func (s *ProductService) BatchProducts(
+	ctx context.Context,
+	operations []model.ProductOperation,
+	atomic bool,
+) ([]model.ProductOperationResult, error) {
+	const op = "product.BatchProducts"
+	ctx, span := tracer.Start(ctx, "ProductService.BatchProducts")
+	defer span.End()
+
+	log := logctx.From(ctx, s.log).With(
+		slog.String("op", op),
+		slog.Int("operations", len(operations)),
+		slog.Bool("atomic", atomic),
+	)
+
+	log.Info("batch products")
+
+	if err := validate.Struct(productBatchInput{Operations: operations}); err != nil {
+		log.Error("data is invalid", sl.Err(err))
+		return nil, fmt.Errorf("%s %w", op, err)
+	}
+
+	results := make([]model.ProductOperationResult, len(operations))
+	valid := make([]model.ProductOperation, 0, len(operations))
+	indexes := make([]int, 0, len(operations))
+	invalid := false
+
+	for i, operation := range operations {
+		results[i] = model.ProductOperationResult{
+			Index:  i,
+			Op:     operation.Op,
+			ID:     operation.ID,
+			Status: model.ProductOperationSkipped,
+		}
+
+		if err := validateProductOperation(ctx, operation); err != nil {
+			results[i].Status = model.ProductOperationFailed
+			results[i].Err = err
+			invalid = true
+			continue
+		}
+
+		valid = append(valid, operation)
+		indexes = append(indexes, i)
+	}
+
+	if len(valid) == 0 || (atomic && invalid) {
+		log.Warn("batch contains invalid operations")
+		return results, nil
+	}
+
+	var applied []model.ProductOperationResult
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		categoryies, err := s.productCategoryies(ctx, valid)
+		if err != nil {
+			return err
+		}
+
+		res, err := s.batcher.BatchProducts(ctx, valid, atomic)
+		if err != nil {
+			return err
+		}
+
+		applied = res
+		return s.events.SaveEvents(ctx, batchEvents(valid, res, categoryies)...)
+	})
+	if err != nil {
+		log.Error("batch didnt applied", sl.Err(err))
+		return nil, fmt.Errorf("%s %w", op, err)
+	}
+
+	for k, res := range applied {
+		i := indexes[k]
+		results[i].ID = res.ID
+		results[i].Status = res.Status
+		results[i].Err = productOperationError(res.Err)
+	}
+
+	log.Info("batch is applied")
+
+	return results, nil
+}
+
+func validateProductOperation(ctx context.Context, operation model.ProductOperation) error {
+	if err := validate.Struct(operation); err != nil {
+		return err
+	}
+
+	if operation.Op != model.ProductOperationCreate && operation.Version == 0 {
+		return ErrProductVersionRequired
+	}
+
+	if operation.Op == model.ProductOperationDelete {
+		if err := requireAdmin(ctx); err != nil {
+			return err
+		}
+	}
+
+	if operation.Op == model.ProductOperationUpdate && operation.Name == "" && len(operation.Categoryies) == 0 {
+		return ErrProductOperationEmpty
+	}
+
+	return nil
+}
+
+// productOperationError переводит ошибку хранилища по операции пакета в доменную ошибку
+func productOperationError(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, repository.ErrProductNotFound):
+		return ErrProductNotFound
+	case errors.Is(err, repository.ErrCategoryNotFound):
+		return ErrProductCategoryNotFound
+	case errors.Is(err, repository.ErrVersionMismatch):
+		return ErrProductVersionMismatch
+	default:
+		return err
+	}
+}
+
+// productCategoryies возвращает текущие категории товаров, которые переименовывают или удаляют операции пакета.
+// Товары загружаются одним запросом на весь пакет.
+func (s *ProductService) productCategoryies(ctx context.Context, operations []model.ProductOperation) (map[int64][]model.Category, error) {
+	ids := make([]int64, 0, len(operations))
+	seen := make(map[int64]bool, len(operations))
+	for _, operation := range operations {
+		if operation.Op == model.ProductOperationCreate || seen[operation.ID] {
+			continue
+		}
+
+		seen[operation.ID] = true
+		ids = append(ids, operation.ID)
+	}
+
+	categoryies := make(map[int64][]model.Category, len(ids))
+	if len(ids) == 0 {
+		return categoryies, nil
+	}
+
+	products, err := s.getter.GetProducts(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, product := range products {
+		categoryies[int64(product.ID)] = product.Categoryies
+	}
+
+	return categoryies, nil
+}
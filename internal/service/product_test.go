@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
+	"goapi/internal/lib/apperr"
+	"goapi/internal/lib/validate"
 	"goapi/internal/model"
 	"goapi/internal/repository"
 	mock_service "goapi/internal/service/mock"
@@ -82,7 +84,22 @@ func TestAddProduct(t *testing.T) {
 				).Return(int64(-1), repository.ErrSaveProduct)
 			},
 			expectedError: fmt.Errorf("%s %w",
-				"product.AddProduct", ErrorInvalidCredentials),
+				"product.AddProduct", repository.ErrSaveProduct),
+			expectedID: ErrProductId,
+		},
+		{
+			name:            "Category Not Found",
+			inputName:       "Test Product",
+			inputCategories: []string{"Category1", "Category2"},
+			mockBehavior: func(r *mock_service.MockAdderProduct, name string, categories []string) {
+				r.EXPECT().AddProduct(
+					gomock.Any(),
+					name,
+					categories,
+				).Return(int64(-1), fmt.Errorf("%w: %w", repository.ErrSaveProduct, repository.ErrCategoryNotFound))
+			},
+			expectedError: fmt.Errorf("%s %w",
+				"product.AddProduct", ErrProductCategoryNotFound),
 			expectedID: ErrProductId,
 		},
 	}
@@ -99,11 +116,15 @@ func TestAddProduct(t *testing.T) {
 
 			test.mockBehavior(mockAdder, test.inputName, test.inputCategories)
 
+			mockTx, mockEvents := passThroughTx(ctrl)
 			productService := NewProductService(
 				mockAdder,
 				nil,
 				nil,
 				nil,
+				nil,
+				mockTx,
+				mockEvents,
 				mockLogger,
 			)
 
@@ -132,7 +153,7 @@ func TestDeleteProduct(t *testing.T) {
 			name:    "Ok",
 			inputID: 1,
 			mockBehavior: func(r *mock_service.MockDeleterProduct, id int64) {
-				r.EXPECT().DeleteProduct(gomock.Any(), id).Return(nil)
+				r.EXPECT().DeleteProduct(gomock.Any(), id, int64(1)).Return(nil)
 			},
 			expectedError: nil,
 		},
@@ -146,7 +167,7 @@ func TestDeleteProduct(t *testing.T) {
 			name:    "Service Error",
 			inputID: 1,
 			mockBehavior: func(r *mock_service.MockDeleterProduct, id int64) {
-				r.EXPECT().DeleteProduct(gomock.Any(), id).Return(errors.New("something error"))
+				r.EXPECT().DeleteProduct(gomock.Any(), id, int64(1)).Return(errors.New("something error"))
 			},
 			expectedError: fmt.Errorf("%s %w", "product.DeleteProduct", errors.New("something error")),
 		},
@@ -154,17 +175,25 @@ func TestDeleteProduct(t *testing.T) {
 			name:    "Error Delete Product",
 			inputID: 1,
 			mockBehavior: func(r *mock_service.MockDeleterProduct, id int64) {
-				r.EXPECT().DeleteProduct(gomock.Any(), id).Return(repository.ErrDeleteProduct)
+				r.EXPECT().DeleteProduct(gomock.Any(), id, int64(1)).Return(repository.ErrDeleteProduct)
 			},
-			expectedError: fmt.Errorf("%s %w", "product.DeleteProduct", ErrorInvalidCredentials),
+			expectedError: fmt.Errorf("%s %w", "product.DeleteProduct", repository.ErrDeleteProduct),
 		},
 		{
 			name:    "Error Delete Product Category",
 			inputID: 1,
 			mockBehavior: func(r *mock_service.MockDeleterProduct, id int64) {
-				r.EXPECT().DeleteProduct(gomock.Any(), id).Return(repository.ErrDeleteProductCategory)
+				r.EXPECT().DeleteProduct(gomock.Any(), id, int64(1)).Return(repository.ErrDeleteProductCategory)
+			},
+			expectedError: fmt.Errorf("%s %w", "product.DeleteProduct", repository.ErrDeleteProductCategory),
+		},
+		{
+			name:    "Product Not Found",
+			inputID: 1,
+			mockBehavior: func(r *mock_service.MockDeleterProduct, id int64) {
+				r.EXPECT().DeleteProduct(gomock.Any(), id, int64(1)).Return(repository.ErrProductNotFound)
 			},
-			expectedError: fmt.Errorf("%s %w", "product.DeleteProduct", ErrorInvalidCredentials),
+			expectedError: fmt.Errorf("%s %w", "product.DeleteProduct", ErrProductNotFound),
 		},
 	}
 
@@ -178,9 +207,10 @@ func TestDeleteProduct(t *testing.T) {
 
 			test.mockBehavior(mockDeleter, test.inputID)
 
-			productService := NewProductService(nil, mockDeleter, nil, nil, mockLogger)
+			mockTx, mockEvents := passThroughTx(ctrl)
+			productService := NewProductService(nil, mockDeleter, nil, knownProducts(ctrl), nil, mockTx, mockEvents, mockLogger)
 
-			err := productService.DeleteProduct(context.Background(), test.inputID)
+			err := productService.DeleteProduct(adminCtx, test.inputID, 1)
 
 			assert.Equal(t, test.expectedError, err)
 		})
@@ -203,7 +233,7 @@ func TestEditProductName(t *testing.T) {
 			inputID:   1,
 			inputName: "Test",
 			mockBehavior: func(r *mock_service.MockUpdaterProduct, id int64, name string) {
-				r.EXPECT().UpdateProductName(gomock.Any(), id, name).Return(int64(1), nil)
+				r.EXPECT().UpdateProductName(gomock.Any(), id, name, int64(1)).Return(int64(1), nil)
 			},
 			expectedID:    1,
 			expectedError: nil,
@@ -229,7 +259,7 @@ func TestEditProductName(t *testing.T) {
 			inputID:   1,
 			inputName: "Test",
 			mockBehavior: func(r *mock_service.MockUpdaterProduct, id int64, name string) {
-				r.EXPECT().UpdateProductName(gomock.Any(), id, name).Return(int64(ErrProductId), errors.New("something error"))
+				r.EXPECT().UpdateProductName(gomock.Any(), id, name, int64(1)).Return(int64(ErrProductId), errors.New("something error"))
 			},
 			expectedID:    ErrProductId,
 			expectedError: fmt.Errorf("%s %w", "product.EditProductName", errors.New("something error")),
@@ -239,10 +269,20 @@ func TestEditProductName(t *testing.T) {
 			inputID:   1,
 			inputName: "Test",
 			mockBehavior: func(r *mock_service.MockUpdaterProduct, id int64, name string) {
-				r.EXPECT().UpdateProductName(gomock.Any(), id, name).Return(int64(ErrProductId), repository.ErrUpdateProduct)
+				r.EXPECT().UpdateProductName(gomock.Any(), id, name, int64(1)).Return(int64(ErrProductId), repository.ErrUpdateProduct)
 			},
 			expectedID:    ErrProductId,
-			expectedError: fmt.Errorf("%s %w", "product.EditProductName", ErrorInvalidCredentials),
+			expectedError: fmt.Errorf("%s %w", "product.EditProductName", repository.ErrUpdateProduct),
+		},
+		{
+			name:      "Product Not Found",
+			inputID:   1,
+			inputName: "Test",
+			mockBehavior: func(r *mock_service.MockUpdaterProduct, id int64, name string) {
+				r.EXPECT().UpdateProductName(gomock.Any(), id, name, int64(1)).Return(int64(ErrProductId), repository.ErrProductNotFound)
+			},
+			expectedID:    ErrProductId,
+			expectedError: fmt.Errorf("%s %w", "product.EditProductName", ErrProductNotFound),
 		},
 	}
 
@@ -256,9 +296,10 @@ func TestEditProductName(t *testing.T) {
 
 			test.mockBehavior(mockUpdater, test.inputID, test.inputName)
 
-			productService := NewProductService(nil, nil, mockUpdater, nil, mockLogger)
+			mockTx, mockEvents := passThroughTx(ctrl)
+			productService := NewProductService(nil, nil, mockUpdater, knownProducts(ctrl), nil, mockTx, mockEvents, mockLogger)
 
-			id, err := productService.EditProductName(context.Background(), test.inputID, test.inputName)
+			id, err := productService.EditProductName(context.Background(), test.inputID, test.inputName, 1)
 
 			assert.Equal(t, test.expectedError, err)
 			assert.Equal(t, test.expectedID, id)
@@ -282,7 +323,7 @@ func TestEditProductCategoryies(t *testing.T) {
 			inputID:       1,
 			inputCategory: []model.Category{model.Category{Name: "Category1"}, model.Category{Name: "Category2"}},
 			mockBehavior: func(r *mock_service.MockUpdaterProduct, id int64, category []model.Category) {
-				r.EXPECT().UpdateProductCategoryies(gomock.Any(), id, category).Return(int64(1), nil)
+				r.EXPECT().UpdateProductCategoryies(gomock.Any(), id, category, int64(1)).Return(int64(1), nil)
 			},
 			expectedID:    1,
 			expectedError: nil,
@@ -308,7 +349,7 @@ func TestEditProductCategoryies(t *testing.T) {
 			inputID:       1,
 			inputCategory: []model.Category{model.Category{Name: "Category1"}, model.Category{Name: "Category2"}},
 			mockBehavior: func(r *mock_service.MockUpdaterProduct, id int64, category []model.Category) {
-				r.EXPECT().UpdateProductCategoryies(gomock.Any(), id, category).Return(int64(-1), errors.New("something error"))
+				r.EXPECT().UpdateProductCategoryies(gomock.Any(), id, category, int64(1)).Return(int64(-1), errors.New("something error"))
 			},
 			expectedID:    ErrProductId,
 			expectedError: fmt.Errorf("%s %w", "product.EditProductCategoryies", errors.New("something error")),
@@ -318,20 +359,20 @@ func TestEditProductCategoryies(t *testing.T) {
 			inputID:       1,
 			inputCategory: []model.Category{model.Category{Name: "Category1"}, model.Category{Name: "Category2"}},
 			mockBehavior: func(r *mock_service.MockUpdaterProduct, id int64, category []model.Category) {
-				r.EXPECT().UpdateProductCategoryies(gomock.Any(), id, category).Return(int64(1), repository.ErrDeleteProductCategory)
+				r.EXPECT().UpdateProductCategoryies(gomock.Any(), id, category, int64(1)).Return(int64(1), repository.ErrDeleteProductCategory)
 			},
 			expectedID:    ErrProductId,
-			expectedError: fmt.Errorf("%s %w", "product.EditProductCategoryies", ErrorInvalidCredentials),
+			expectedError: fmt.Errorf("%s %w", "product.EditProductCategoryies", repository.ErrDeleteProductCategory),
 		},
 		{
 			name:          "Error Save Product Categoryies",
 			inputID:       1,
 			inputCategory: []model.Category{model.Category{Name: "Category1"}, model.Category{Name: "Category2"}},
 			mockBehavior: func(r *mock_service.MockUpdaterProduct, id int64, category []model.Category) {
-				r.EXPECT().UpdateProductCategoryies(gomock.Any(), id, category).Return(int64(1), repository.ErrSaveProductCategory)
+				r.EXPECT().UpdateProductCategoryies(gomock.Any(), id, category, int64(1)).Return(int64(1), repository.ErrSaveProductCategory)
 			},
 			expectedID:    ErrProductId,
-			expectedError: fmt.Errorf("%s %w", "product.EditProductCategoryies", ErrorInvalidCredentials),
+			expectedError: fmt.Errorf("%s %w", "product.EditProductCategoryies", ErrProductCategoryNotFound),
 		},
 	}
 
@@ -345,9 +386,10 @@ func TestEditProductCategoryies(t *testing.T) {
 
 			test.mockBehavior(mockUpdater, test.inputID, test.inputCategory)
 
-			productService := NewProductService(nil, nil, mockUpdater, nil, mockLogger)
+			mockTx, mockEvents := passThroughTx(ctrl)
+			productService := NewProductService(nil, nil, mockUpdater, knownProducts(ctrl), nil, mockTx, mockEvents, mockLogger)
 
-			id, err := productService.EditProductCategory(context.Background(), test.inputID, test.inputCategory)
+			id, err := productService.EditProductCategory(context.Background(), test.inputID, test.inputCategory, 1)
 
 			assert.Equal(t, test.expectedError, err)
 			assert.Equal(t, test.expectedID, id)
@@ -382,13 +424,13 @@ func TestGetAllProducts(t *testing.T) {
 			expectedError:    fmt.Errorf("%s %w", "product.GetAllProducts", ErrProductUnknownTag),
 		},
 		{
-			name: "Product Not Found",
+			name: "Get Products Error",
 			tag:  TagGetAllProducts,
 			mockBehavior: func(r *mock_service.MockGetterProduct) {
-				r.EXPECT().GetAllProducts(gomock.Any()).Return([]model.Product{}, repository.ErrProductNotFound)
+				r.EXPECT().GetAllProducts(gomock.Any()).Return([]model.Product{}, repository.ErrGetProducts)
 			},
 			expectedProducts: []model.Product{},
-			expectedError:    fmt.Errorf("%s %w", "product.GetAllProducts", ErrorInvalidCredentials),
+			expectedError:    fmt.Errorf("%s %w", "product.GetAllProducts", repository.ErrGetProducts),
 		},
 		{
 			name: "Service Error",
@@ -411,7 +453,8 @@ func TestGetAllProducts(t *testing.T) {
 
 			test.mockBehavior(mockGetter)
 
-			productService := NewProductService(nil, nil, nil, mockGetter, mockLogger)
+			mockTx, mockEvents := passThroughTx(ctrl)
+			productService := NewProductService(nil, nil, nil, mockGetter, nil, mockTx, mockEvents, mockLogger)
 
 			products, err := productService.GetAllProducts(context.Background(), test.tag)
 
@@ -428,15 +471,310 @@ func TestAddProducts(t *testing.T) {
 	mockAdder := mock_service.NewMockAdderProduct(ctrl)
 	mockLogger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
 
-	productService := NewProductService(mockAdder, nil, nil, nil, mockLogger)
+	mockTx, mockEvents := passThroughTx(ctrl)
+	productService := NewProductService(mockAdder, nil, nil, nil, nil, mockTx, mockEvents, mockLogger)
 
 	testProducts := []model.Product{
 		{ID: 1, Name: "Product 1", Categoryies: []model.Category{}},
 		{ID: 2, Name: "Product 2", Categoryies: []model.Category{}},
 	}
 
-	mockAdder.EXPECT().AddProducts(gomock.Any(), testProducts).Return(nil)
+	mockAdder.EXPECT().AddProducts(gomock.Any(), testProducts).Return([]int64{1, 2}, nil)
 
 	err := productService.AddProducts(context.Background(), testProducts)
 	assert.NoError(t, err)
 }
+
+func TestAddProductsValidation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAdder := mock_service.NewMockAdderProduct(ctrl)
+	mockLogger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	mockTx, mockEvents := passThroughTx(ctrl)
+	productService := NewProductService(mockAdder, nil, nil, nil, nil, mockTx, mockEvents, mockLogger)
+
+	testProducts := []model.Product{
+		{Name: "Product 1"},
+		{Name: ""},
+	}
+
+	err := productService.AddProducts(context.Background(), testProducts)
+	assert.ErrorIs(t, err, validate.ErrValidation)
+
+	appErr, ok := apperr.As(err)
+	assert.True(t, ok)
+	assert.Equal(t, "/products/1/name", appErr.Fields[0].Field)
+}
+
+func TestBatchProductsLoadsProductsOnce(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockGetter := mock_service.NewMockGetterProduct(ctrl)
+	mockBatcher := mock_service.NewMockBatcherProduct(ctrl)
+	mockTx, _ := passThroughTx(ctrl)
+	mockEvents := mock_service.NewMockEventSaver(ctrl)
+	mockLogger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	operations := []model.ProductOperation{
+		{Op: model.ProductOperationCreate, Name: "Product", Categoryies: []string{"Category"}},
+		{Op: model.ProductOperationUpdate, ID: 2, Name: "Product2", Version: 1},
+		{Op: model.ProductOperationDelete, ID: 2, Version: 2},
+		{Op: model.ProductOperationDelete, ID: 3, Version: 1},
+	}
+	categoryies := []model.Category{{ID: 1, Name: "Category1"}}
+
+	mockGetter.EXPECT().GetProducts(gomock.Any(), []int64{2, 3}).Return([]model.Product{{ID: 2, Categoryies: categoryies}}, nil)
+	mockBatcher.EXPECT().BatchProducts(gomock.Any(), operations, true).Return([]model.ProductOperationResult{
+		{Index: 0, ID: 1, Status: model.ProductOperationOK},
+		{Index: 1, ID: 2, Status: model.ProductOperationOK},
+		{Index: 2, ID: 2, Status: model.ProductOperationOK},
+		{Index: 3, ID: 3, Status: model.ProductOperationFailed, Err: repository.ErrProductNotFound},
+	}, nil)
+	mockEvents.EXPECT().SaveEvents(gomock.Any(),
+		model.ProductCreated{ProductID: 1, Name: "Product", Categoryies: []model.Category{{Name: "Category"}}},
+		model.ProductRenamed{ProductID: 2, Name: "Product2", Categoryies: categoryies},
+		model.ProductDeleted{ProductID: 2, Categoryies: categoryies},
+	).Return(nil)
+
+	productService := NewProductService(nil, nil, nil, mockGetter, mockBatcher, mockTx, mockEvents, mockLogger)
+
+	_, err := productService.BatchProducts(adminCtx, operations, true)
+	assert.NoError(t, err)
+}
+
+func TestBatchProducts(t *testing.T) {
+	type mockBehavior func(r *mock_service.MockBatcherProduct)
+
+	create := model.ProductOperation{Op: model.ProductOperationCreate, Name: "Product", Categoryies: []string{"Category"}}
+	remove := model.ProductOperation{Op: model.ProductOperationDelete, ID: 2, Version: 1}
+	emptyUpdate := model.ProductOperation{Op: model.ProductOperationUpdate, ID: 3, Version: 1}
+	unversioned := model.ProductOperation{Op: model.ProductOperationDelete, ID: 4}
+
+	tests := []struct {
+		name             string
+		inputCtx         context.Context
+		inputOperations  []model.ProductOperation
+		inputAtomic      bool
+		mockBehavior     mockBehavior
+		expectedError    error
+		expectedStatuses []model.ProductOperationStatus
+		expectedErrors   []error
+	}{
+		{
+			name:            "Ok",
+			inputOperations: []model.ProductOperation{create, remove},
+			inputAtomic:     true,
+			mockBehavior: func(r *mock_service.MockBatcherProduct) {
+				r.EXPECT().BatchProducts(gomock.Any(), []model.ProductOperation{create, remove}, true).Return(
+					[]model.ProductOperationResult{
+						{Index: 0, Op: create.Op, ID: 1, Status: model.ProductOperationOK},
+						{Index: 1, Op: remove.Op, ID: 2, Status: model.ProductOperationOK},
+					}, nil)
+			},
+			expectedStatuses: []model.ProductOperationStatus{model.ProductOperationOK, model.ProductOperationOK},
+			expectedErrors:   []error{nil, nil},
+		},
+		{
+			name:             "Empty batch",
+			inputOperations:  nil,
+			mockBehavior:     func(r *mock_service.MockBatcherProduct) {},
+			expectedError:    validate.ErrValidation,
+			expectedStatuses: nil,
+		},
+		{
+			name:            "Atomic with invalid operation",
+			inputOperations: []model.ProductOperation{create, emptyUpdate},
+			inputAtomic:     true,
+			mockBehavior:    func(r *mock_service.MockBatcherProduct) {},
+			expectedStatuses: []model.ProductOperationStatus{
+				model.ProductOperationSkipped,
+				model.ProductOperationFailed,
+			},
+			expectedErrors: []error{nil, ErrProductOperationEmpty},
+		},
+		{
+			name:            "Best effort skips invalid operation",
+			inputOperations: []model.ProductOperation{emptyUpdate, remove},
+			inputAtomic:     false,
+			mockBehavior: func(r *mock_service.MockBatcherProduct) {
+				r.EXPECT().BatchProducts(gomock.Any(), []model.ProductOperation{remove}, false).Return(
+					[]model.ProductOperationResult{
+						{Index: 0, Op: remove.Op, ID: 2, Status: model.ProductOperationFailed, Err: repository.ErrProductNotFound},
+					}, nil)
+			},
+			expectedStatuses: []model.ProductOperationStatus{
+				model.ProductOperationFailed,
+				model.ProductOperationFailed,
+			},
+			expectedErrors: []error{ErrProductOperationEmpty, ErrProductNotFound},
+		},
+		{
+			name:            "Missing version",
+			inputOperations: []model.ProductOperation{create, unversioned},
+			inputAtomic:     true,
+			mockBehavior:    func(r *mock_service.MockBatcherProduct) {},
+			expectedStatuses: []model.ProductOperationStatus{
+				model.ProductOperationSkipped,
+				model.ProductOperationFailed,
+			},
+			expectedErrors: []error{nil, ErrProductVersionRequired},
+		},
+		{
+			name:            "Delete without admin role",
+			inputCtx:        userCtx,
+			inputOperations: []model.ProductOperation{create, remove},
+			inputAtomic:     false,
+			mockBehavior: func(r *mock_service.MockBatcherProduct) {
+				r.EXPECT().BatchProducts(gomock.Any(), []model.ProductOperation{create}, false).Return(
+					[]model.ProductOperationResult{
+						{Index: 0, Op: create.Op, ID: 1, Status: model.ProductOperationOK},
+					}, nil)
+			},
+			expectedStatuses: []model.ProductOperationStatus{model.ProductOperationOK, model.ProductOperationFailed},
+			expectedErrors:   []error{nil, ErrAdminRequired},
+		},
+		{
+			name:            "Repository error",
+			inputOperations: []model.ProductOperation{remove},
+			inputAtomic:     true,
+			mockBehavior: func(r *mock_service.MockBatcherProduct) {
+				r.EXPECT().BatchProducts(gomock.Any(), []model.ProductOperation{remove}, true).Return(
+					nil, repository.ErrDeleteProduct)
+			},
+			expectedError: repository.ErrDeleteProduct,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockBatcher := mock_service.NewMockBatcherProduct(ctrl)
+			mockLogger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+			test.mockBehavior(mockBatcher)
+
+			mockTx, mockEvents := passThroughTx(ctrl)
+			productService := NewProductService(nil, nil, nil, knownProducts(ctrl), mockBatcher, mockTx, mockEvents, mockLogger)
+
+			ctx := adminCtx
+			if test.inputCtx != nil {
+				ctx = test.inputCtx
+			}
+
+			results, err := productService.BatchProducts(ctx, test.inputOperations, test.inputAtomic)
+			if test.expectedError != nil {
+				assert.ErrorIs(t, err, test.expectedError)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Len(t, results, len(test.expectedStatuses))
+			for i, res := range results {
+				assert.Equal(t, i, res.Index)
+				assert.Equal(t, test.expectedStatuses[i], res.Status)
+				if test.expectedErrors[i] == nil {
+					assert.NoError(t, res.Err)
+				} else {
+					assert.ErrorIs(t, res.Err, test.expectedErrors[i])
+				}
+			}
+		})
+	}
+}
+
+func TestEditProductNameVersionMismatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUpdater := mock_service.NewMockUpdaterProduct(ctrl)
+	mockLogger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	mockUpdater.EXPECT().UpdateProductName(gomock.Any(), int64(1), "Test", int64(2)).
+		Return(int64(ErrProductId), repository.ErrVersionMismatch)
+
+	mockTx, mockEvents := passThroughTx(ctrl)
+	productService := NewProductService(nil, nil, mockUpdater, knownProducts(ctrl), nil, mockTx, mockEvents, mockLogger)
+
+	_, err := productService.EditProductName(context.Background(), 1, "Test", 2)
+	assert.ErrorIs(t, err, ErrProductVersionMismatch)
+	assert.Equal(t, apperr.KindPreconditionFailed, apperr.KindOf(err))
+}
+
+func TestVersionRequired(t *testing.T) {
+	mockLogger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	// без версии сервисы не обращаются к хранилищу, поэтому зависимости не нужны
+	productService := NewProductService(nil, nil, nil, nil, nil, nil, nil, mockLogger)
+	categoryService := NewCategoryService(nil, nil, nil, nil, nil, nil, mockLogger)
+
+	err := productService.DeleteProduct(adminCtx, 1, 0)
+	assert.ErrorIs(t, err, ErrProductVersionRequired)
+	assert.Equal(t, apperr.KindPreconditionRequired, apperr.KindOf(err))
+
+	_, err = productService.EditProductName(context.Background(), 1, "Test", 0)
+	assert.ErrorIs(t, err, ErrProductVersionRequired)
+
+	_, err = productService.EditProductCategory(context.Background(), 1, []model.Category{{ID: 1, Name: "Category1"}}, 0)
+	assert.ErrorIs(t, err, ErrProductVersionRequired)
+
+	err = categoryService.DeleteCategory(adminCtx, 1, 0)
+	assert.ErrorIs(t, err, ErrCategoryVersionRequired)
+
+	_, err = categoryService.EditCategory(context.Background(), 1, "Category", 0)
+	assert.ErrorIs(t, err, ErrCategoryVersionRequired)
+}
+
+func TestGetProduct(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockGetter := mock_service.NewMockGetterProduct(ctrl)
+	mockLogger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	expected := model.Product{ID: 1, Name: "Test", Version: 3}
+	mockGetter.EXPECT().GetProduct(gomock.Any(), int64(1)).Return(expected, nil)
+	mockGetter.EXPECT().GetProduct(gomock.Any(), int64(2)).Return(model.Product{}, repository.ErrProductNotFound)
+
+	mockTx, mockEvents := passThroughTx(ctrl)
+	productService := NewProductService(nil, nil, nil, mockGetter, nil, mockTx, mockEvents, mockLogger)
+
+	product, err := productService.GetProduct(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, product)
+
+	_, err = productService.GetProduct(context.Background(), 2)
+	assert.ErrorIs(t, err, ErrProductNotFound)
+}
+
+func TestSearchProducts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockGetter := mock_service.NewMockGetterProduct(ctrl)
+	mockLogger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	expected := []model.Product{{ID: 1, Name: "Phone"}}
+	mockGetter.EXPECT().SearchProducts(gomock.Any(), "phone", DefaultSearchResults).Return(expected, nil)
+
+	mockTx, mockEvents := passThroughTx(ctrl)
+	productService := NewProductService(nil, nil, nil, mockGetter, nil, mockTx, mockEvents, mockLogger)
+
+	products, err := productService.SearchProducts(context.Background(), "phone", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, products)
+
+	_, err = productService.SearchProducts(context.Background(), "", 0)
+	assert.ErrorIs(t, err, validate.ErrValidation)
+
+	_, err = productService.SearchProducts(context.Background(), "phone", validate.MaxSearchResults+1)
+	assert.ErrorIs(t, err, validate.ErrValidation)
+
+	categoryies, err := productService.GetProductsCategoryies(context.Background(), nil)
+	assert.NoError(t, err)
+	assert.Empty(t, categoryies)
+}
@@ -0,0 +1,6 @@
+package service
+
+import "go.opentelemetry.io/otel"
+
+// tracer открывает спаны методов сервисов, вложенные в спан запроса
+var tracer = otel.Tracer("goapi/internal/service")
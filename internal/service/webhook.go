@@ -0,0 +1,369 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"goapi/internal/lib/apperr"
+	"goapi/internal/lib/logger/logctx"
+	"goapi/internal/lib/logger/sl"
+	"goapi/internal/lib/validate"
+	"goapi/internal/lib/webhook"
+	"goapi/internal/model"
+	"goapi/internal/repository"
+	"log/slog"
+	"slices"
+)
+
+//go:generate mockgen -source=webhook.go -destination=mock/webhook_mock.go
+
+const (
+	DefaultWebhookDeliveries = 50
+	MaxWebhookDeliveries     = 200
+)
+
+var (
+	ErrWebhookNotFound         = apperr.NotFound("webhook_not_found", "webhook not found")
+	ErrWebhookDeliveryNotFound = apperr.NotFound("webhook_delivery_not_found", "webhook delivery not found")
+	ErrWebhookVersionMismatch  = apperr.PreconditionFailed("webhook_version_mismatch", "webhook was modified by another request")
+	ErrWebhookUnknownEvent     = apperr.InvalidField("unknown_event_type", "/eventTypes", "unknown event type")
+	ErrWebhookUpdateEmpty      = apperr.Validation("webhook_update_empty", "update must change at least one field")
+	ErrWebhookSecret           = apperr.Internal("webhook_secret_failed", "failed to generate webhook secret")
+	ErrWebhookURLForbidden     = apperr.InvalidField("webhook_url_forbidden", "/url", "url must resolve to public addresses only")
+	ErrWebhookURLUnresolved    = apperr.InvalidField("webhook_url_unresolved", "/url", "url host cannot be resolved")
+)
+
+type WebhookService struct {
+	saver    WebhookSaver
+	provider WebhookProvider
+	updater  WebhookUpdater
+	checker  WebhookURLChecker
+	log      *slog.Logger
+}
+
+type WebhookSaver interface {
+	CreateWebhook(ctx context.Context, webhook model.Webhook) (model.Webhook, error)
+}
+
+type WebhookProvider interface {
+	ListWebhooks(ctx context.Context, userID int64) ([]model.Webhook, error)
+	GetWebhook(ctx context.Context, userID, id int64) (model.Webhook, error)
+	ListWebhookDeliveries(ctx context.Context, userID, webhookID int64, limit int) ([]model.WebhookDelivery, error)
+	GetWebhookDelivery(ctx context.Context, userID, webhookID, id int64) (model.WebhookDelivery, error)
+}
+
+type WebhookUpdater interface {
+	UpdateWebhook(ctx context.Context, userID, id int64, update model.WebhookUpdate, version int64) (model.Webhook, error)
+	DeleteWebhook(ctx context.Context, userID, id int64, version int64) error
+	RedeliverWebhook(ctx context.Context, userID, webhookID, id int64) error
+}
+
+// WebhookURLChecker проверяет, что адрес получателя не ведет во внутреннюю сеть
+type WebhookURLChecker interface {
+	CheckURL(ctx context.Context, url string) error
+}
+
+func NewWebhookService(
+	s WebhookSaver,
+	p WebhookProvider,
+	u WebhookUpdater,
+	c WebhookURLChecker,
+	l *slog.Logger,
+) *WebhookService {
+	return &WebhookService{
+		saver:    s,
+		provider: p,
+		updater:  u,
+		checker:  c,
+		log:      l,
+	}
+}
+
+// CreateWebhook создает подписку. Пустой секрет генерируется, секрет возвращается только здесь.
+func (s *WebhookService) CreateWebhook(ctx context.Context, userID int64, url string, eventTypes []string, secret string) (model.Webhook, error) {
+	const op = "webhook.CreateWebhook"
+	ctx, span := tracer.Start(ctx, "WebhookService.CreateWebhook")
+	defer span.End()
+
+	log := logctx.From(ctx, s.log).With(
+		slog.String("op", op),
+		slog.Int64("user_id", userID),
+	)
+
+	log.Info("create webhook")
+
+	if err := requireAdmin(ctx); err != nil {
+		log.Warn("access denied", sl.Err(err))
+		return model.Webhook{}, fmt.Errorf("%s %w", op, err)
+	}
+
+	if err := validate.Struct(webhookInput{URL: url, EventTypes: eventTypes, Secret: secret}); err != nil {
+		log.Warn("data is invalid", sl.Err(err))
+		return model.Webhook{}, fmt.Errorf("%s %w", op, err)
+	}
+
+	if err := validateEventTypes(eventTypes); err != nil {
+		log.Warn("data is invalid", sl.Err(err))
+		return model.Webhook{}, fmt.Errorf("%s %w", op, err)
+	}
+
+	if err := s.checkURL(ctx, url); err != nil {
+		log.Warn("url is not allowed", sl.Err(err))
+		return model.Webhook{}, fmt.Errorf("%s %w", op, err)
+	}
+
+	if secret == "" {
+		generated, err := webhook.NewSecret()
+		if err != nil {
+			log.Error("error generate secret", sl.Err(err))
+			return model.Webhook{}, fmt.Errorf("%s %w", op, ErrWebhookSecret)
+		}
+		secret = generated
+	}
+
+	created, err := s.saver.CreateWebhook(ctx, model.Webhook{
+		UserID:     userID,
+		URL:        url,
+		EventTypes: eventTypes,
+		Secret:     secret,
+	})
+	if err != nil {
+		log.Error("webhook didnt created", sl.Err(err))
+		return model.Webhook{}, fmt.Errorf("%s %w", op, err)
+	}
+
+	log.Info("webhook is created")
+
+	return created, nil
+}
+
+func (s *WebhookService) ListWebhooks(ctx context.Context, userID int64) ([]model.Webhook, error) {
+	const op = "webhook.ListWebhooks"
+	ctx, span := tracer.Start(ctx, "WebhookService.ListWebhooks")
+	defer span.End()
+
+	if err := requireAdmin(ctx); err != nil {
+		return nil, fmt.Errorf("%s %w", op, err)
+	}
+
+	webhooks, err := s.provider.ListWebhooks(ctx, userID)
+	if err != nil {
+		s.log.Error("webhooks didnt get", slog.String("op", op), sl.Err(err))
+		return nil, fmt.Errorf("%s %w", op, err)
+	}
+
+	for i := range webhooks {
+		webhooks[i].Secret = ""
+	}
+
+	return webhooks, nil
+}
+
+func (s *WebhookService) GetWebhook(ctx context.Context, userID, id int64) (model.Webhook, error) {
+	const op = "webhook.GetWebhook"
+	ctx, span := tracer.Start(ctx, "WebhookService.GetWebhook")
+	defer span.End()
+
+	if err := requireAdmin(ctx); err != nil {
+		return model.Webhook{}, fmt.Errorf("%s %w", op, err)
+	}
+
+	found, err := s.provider.GetWebhook(ctx, userID, id)
+	if err != nil {
+		return model.Webhook{}, fmt.Errorf("%s %w", op, s.webhookError(op, err))
+	}
+
+	found.Secret = ""
+
+	return found, nil
+}
+
+// UpdateWebhook меняет заданные поля подписки. Ненулевая версия должна совпадать с текущей.
+func (s *WebhookService) UpdateWebhook(
+	ctx context.Context,
+	userID, id int64,
+	update model.WebhookUpdate,
+	version int64,
+) (model.Webhook, error) {
+	const op = "webhook.UpdateWebhook"
+	ctx, span := tracer.Start(ctx, "WebhookService.UpdateWebhook")
+	defer span.End()
+
+	log := logctx.From(ctx, s.log).With(
+		slog.String("op", op),
+		slog.Int64("id", id),
+	)
+
+	log.Info("update webhook")
+
+	if err := requireAdmin(ctx); err != nil {
+		log.Warn("access denied", sl.Err(err))
+		return model.Webhook{}, fmt.Errorf("%s %w", op, err)
+	}
+
+	if len(update.EventTypes) == 0 {
+		update.EventTypes = nil
+	}
+
+	if update.URL == nil && update.EventTypes == nil && update.Secret == nil && update.Active == nil {
+		return model.Webhook{}, fmt.Errorf("%s %w", op, ErrWebhookUpdateEmpty)
+	}
+
+	if err := validate.Struct(webhookUpdateInput{URL: update.URL, EventTypes: update.EventTypes, Secret: update.Secret}); err != nil {
+		log.Warn("data is invalid", sl.Err(err))
+		return model.Webhook{}, fmt.Errorf("%s %w", op, err)
+	}
+
+	if err := validateEventTypes(update.EventTypes); err != nil {
+		log.Warn("data is invalid", sl.Err(err))
+		return model.Webhook{}, fmt.Errorf("%s %w", op, err)
+	}
+
+	if update.URL != nil {
+		if err := s.checkURL(ctx, *update.URL); err != nil {
+			log.Warn("url is not allowed", sl.Err(err))
+			return model.Webhook{}, fmt.Errorf("%s %w", op, err)
+		}
+	}
+
+	updated, err := s.updater.UpdateWebhook(ctx, userID, id, update, version)
+	if err != nil {
+		return model.Webhook{}, fmt.Errorf("%s %w", op, s.webhookError(op, err))
+	}
+
+	updated.Secret = ""
+
+	log.Info("webhook is updated")
+
+	return updated, nil
+}
+
+func (s *WebhookService) DeleteWebhook(ctx context.Context, userID, id int64, version int64) error {
+	const op = "webhook.DeleteWebhook"
+	ctx, span := tracer.Start(ctx, "WebhookService.DeleteWebhook")
+	defer span.End()
+
+	if err := requireAdmin(ctx); err != nil {
+		return fmt.Errorf("%s %w", op, err)
+	}
+
+	if err := s.updater.DeleteWebhook(ctx, userID, id, version); err != nil {
+		return fmt.Errorf("%s %w", op, s.webhookError(op, err))
+	}
+
+	s.log.Info("webhook is deleted", slog.String("op", op), slog.Int64("id", id))
+
+	return nil
+}
+
+// ListWebhookDeliveries возвращает журнал последних доставок подписки
+func (s *WebhookService) ListWebhookDeliveries(ctx context.Context, userID, webhookID int64, limit int) ([]model.WebhookDelivery, error) {
+	const op = "webhook.ListWebhookDeliveries"
+	ctx, span := tracer.Start(ctx, "WebhookService.ListWebhookDeliveries")
+	defer span.End()
+
+	if err := requireAdmin(ctx); err != nil {
+		return nil, fmt.Errorf("%s %w", op, err)
+	}
+
+	if limit <= 0 {
+		limit = DefaultWebhookDeliveries
+	}
+	limit = min(limit, MaxWebhookDeliveries)
+
+	if _, err := s.provider.GetWebhook(ctx, userID, webhookID); err != nil {
+		return nil, fmt.Errorf("%s %w", op, s.webhookError(op, err))
+	}
+
+	deliveries, err := s.provider.ListWebhookDeliveries(ctx, userID, webhookID, limit)
+	if err != nil {
+		s.log.Error("webhook deliveries didnt get", slog.String("op", op), sl.Err(err))
+		return nil, fmt.Errorf("%s %w", op, err)
+	}
+
+	return deliveries, nil
+}
+
+// GetWebhookDelivery возвращает доставку с журналом попыток и кодами ответов
+func (s *WebhookService) GetWebhookDelivery(ctx context.Context, userID, webhookID, id int64) (model.WebhookDelivery, error) {
+	const op = "webhook.GetWebhookDelivery"
+	ctx, span := tracer.Start(ctx, "WebhookService.GetWebhookDelivery")
+	defer span.End()
+
+	if err := requireAdmin(ctx); err != nil {
+		return model.WebhookDelivery{}, fmt.Errorf("%s %w", op, err)
+	}
+
+	delivery, err := s.provider.GetWebhookDelivery(ctx, userID, webhookID, id)
+	if err != nil {
+		return model.WebhookDelivery{}, fmt.Errorf("%s %w", op, s.webhookError(op, err))
+	}
+
+	return delivery, nil
+}
+
+// RedeliverWebhook ставит доставку в очередь на повторную отправку
+func (s *WebhookService) RedeliverWebhook(ctx context.Context, userID, webhookID, id int64) error {
+	const op = "webhook.RedeliverWebhook"
+	ctx, span := tracer.Start(ctx, "WebhookService.RedeliverWebhook")
+	defer span.End()
+
+	if err := requireAdmin(ctx); err != nil {
+		return fmt.Errorf("%s %w", op, err)
+	}
+
+	if err := s.updater.RedeliverWebhook(ctx, userID, webhookID, id); err != nil {
+		return fmt.Errorf("%s %w", op, s.webhookError(op, err))
+	}
+
+	s.log.Info("webhook delivery is queued", slog.String("op", op), slog.Int64("id", id))
+
+	return nil
+}
+
+// webhookError переводит ошибку хранилища в доменную ошибку
+func (s *WebhookService) webhookError(op string, err error) error {
+	log := s.log.With(
+		slog.String("op", op),
+	)
+
+	switch {
+	case errors.Is(err, repository.ErrWebhookNotFound):
+		log.Warn("webhook not found", sl.Err(err))
+		return ErrWebhookNotFound
+	case errors.Is(err, repository.ErrWebhookDeliveryNotFound):
+		log.Warn("webhook delivery not found", sl.Err(err))
+		return ErrWebhookDeliveryNotFound
+	case errors.Is(err, repository.ErrVersionMismatch):
+		log.Warn("webhook version mismatch", sl.Err(err))
+		return ErrWebhookVersionMismatch
+	default:
+		log.Error("webhook storage error", sl.Err(err))
+		return err
+	}
+}
+
+// checkURL проверяет адреса хоста получателя. Доставщик проверяет адрес еще раз
+// при каждом соединении, поэтому эта проверка только сообщает об ошибке заранее.
+func (s *WebhookService) checkURL(ctx context.Context, url string) error {
+	err := s.checker.CheckURL(ctx, url)
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, webhook.ErrForbiddenAddress):
+		return ErrWebhookURLForbidden
+	default:
+		return ErrWebhookURLUnresolved
+	}
+}
+
+// validateEventTypes проверяет, что подписка оформлена на известные типы событий
+func validateEventTypes(eventTypes []string) error {
+	for _, eventType := range eventTypes {
+		if eventType != model.WebhookAllEvents && !slices.Contains(model.EventTypes, model.EventType(eventType)) {
+			return ErrWebhookUnknownEvent
+		}
+	}
+
+	return nil
+}
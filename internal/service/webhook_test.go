@@ -0,0 +1,217 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"goapi/internal/lib/apperr"
+	"goapi/internal/lib/webhook"
+	"goapi/internal/model"
+	"goapi/internal/repository"
+	mock_service "goapi/internal/service/mock"
+	"log/slog"
+	"os"
+)
+
+func TestCreateWebhook(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockWebhookSaver)
+
+	tests := []struct {
+		name          string
+		url           string
+		eventTypes    []string
+		secret        string
+		urlErr        error
+		mockBehavior  mockBehavior
+		expectedError error
+		expectedKind  apperr.Kind
+	}{
+		{
+			name:       "OK",
+			url:        "https://example.com/hook",
+			eventTypes: []string{string(model.EventProductCreated)},
+			secret:     "0123456789abcdef",
+			mockBehavior: func(s *mock_service.MockWebhookSaver) {
+				s.EXPECT().CreateWebhook(gomock.Any(), model.Webhook{
+					UserID:     1,
+					URL:        "https://example.com/hook",
+					EventTypes: []string{string(model.EventProductCreated)},
+					Secret:     "0123456789abcdef",
+				}).Return(model.Webhook{ID: 1, Secret: "0123456789abcdef"}, nil)
+			},
+		},
+		{
+			name:       "Generated Secret",
+			url:        "https://example.com/hook",
+			eventTypes: []string{model.WebhookAllEvents},
+			mockBehavior: func(s *mock_service.MockWebhookSaver) {
+				s.EXPECT().CreateWebhook(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, w model.Webhook) (model.Webhook, error) {
+						assert.True(t, strings.HasPrefix(w.Secret, "whsec_"))
+						return w, nil
+					})
+			},
+		},
+		{
+			name:          "Unknown Event",
+			url:           "https://example.com/hook",
+			eventTypes:    []string{"product.exploded"},
+			mockBehavior:  func(s *mock_service.MockWebhookSaver) {},
+			expectedError: ErrWebhookUnknownEvent,
+		},
+		{
+			name:          "Private URL",
+			url:           "http://10.0.0.5/hook",
+			eventTypes:    []string{model.WebhookAllEvents},
+			urlErr:        fmt.Errorf("%w: 10.0.0.5", webhook.ErrForbiddenAddress),
+			mockBehavior:  func(s *mock_service.MockWebhookSaver) {},
+			expectedError: ErrWebhookURLForbidden,
+		},
+		{
+			name:          "Unresolved URL",
+			url:           "https://unknown.invalid/hook",
+			eventTypes:    []string{model.WebhookAllEvents},
+			urlErr:        errors.New("no such host"),
+			mockBehavior:  func(s *mock_service.MockWebhookSaver) {},
+			expectedError: ErrWebhookURLUnresolved,
+		},
+		{
+			name:         "Invalid URL",
+			url:          "ftp://example.com",
+			eventTypes:   []string{model.WebhookAllEvents},
+			mockBehavior: func(s *mock_service.MockWebhookSaver) {},
+			expectedKind: apperr.KindValidation,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			saver := mock_service.NewMockWebhookSaver(ctrl)
+			test.mockBehavior(saver)
+
+			checker := mock_service.NewMockWebhookURLChecker(ctrl)
+			checker.EXPECT().CheckURL(gomock.Any(), test.url).Return(test.urlErr).AnyTimes()
+
+			logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
+			s := NewWebhookService(saver, nil, nil, checker, logger)
+
+			_, err := s.CreateWebhook(adminCtx, 1, test.url, test.eventTypes, test.secret)
+			switch {
+			case test.expectedError != nil:
+				assert.ErrorIs(t, err, test.expectedError)
+			case test.expectedKind != "":
+				assert.Equal(t, test.expectedKind, apperr.KindOf(err))
+			default:
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestUpdateWebhook(t *testing.T) {
+	type mockBehavior func(u *mock_service.MockWebhookUpdater)
+
+	active := false
+	privateURL := "http://169.254.169.254/latest/meta-data"
+
+	tests := []struct {
+		name          string
+		update        model.WebhookUpdate
+		mockBehavior  mockBehavior
+		expectedError error
+	}{
+		{
+			name:   "OK",
+			update: model.WebhookUpdate{Active: &active},
+			mockBehavior: func(u *mock_service.MockWebhookUpdater) {
+				u.EXPECT().UpdateWebhook(gomock.Any(), int64(1), int64(2), model.WebhookUpdate{Active: &active}, int64(3)).
+					Return(model.Webhook{ID: 2, Secret: "secret", Version: 4}, nil)
+			},
+		},
+		{
+			name:          "Empty",
+			update:        model.WebhookUpdate{EventTypes: []string{}},
+			mockBehavior:  func(u *mock_service.MockWebhookUpdater) {},
+			expectedError: ErrWebhookUpdateEmpty,
+		},
+		{
+			name:          "Private URL",
+			update:        model.WebhookUpdate{URL: &privateURL},
+			mockBehavior:  func(u *mock_service.MockWebhookUpdater) {},
+			expectedError: ErrWebhookURLForbidden,
+		},
+		{
+			name:   "Version Mismatch",
+			update: model.WebhookUpdate{Active: &active},
+			mockBehavior: func(u *mock_service.MockWebhookUpdater) {
+				u.EXPECT().UpdateWebhook(gomock.Any(), int64(1), int64(2), gomock.Any(), int64(3)).
+					Return(model.Webhook{}, repository.ErrVersionMismatch)
+			},
+			expectedError: ErrWebhookVersionMismatch,
+		},
+		{
+			name:   "Not Found",
+			update: model.WebhookUpdate{Active: &active},
+			mockBehavior: func(u *mock_service.MockWebhookUpdater) {
+				u.EXPECT().UpdateWebhook(gomock.Any(), int64(1), int64(2), gomock.Any(), int64(3)).
+					Return(model.Webhook{}, repository.ErrWebhookNotFound)
+			},
+			expectedError: ErrWebhookNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			updater := mock_service.NewMockWebhookUpdater(ctrl)
+			test.mockBehavior(updater)
+
+			checker := mock_service.NewMockWebhookURLChecker(ctrl)
+			checker.EXPECT().CheckURL(gomock.Any(), privateURL).Return(webhook.ErrForbiddenAddress).AnyTimes()
+
+			logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
+			s := NewWebhookService(nil, nil, updater, checker, logger)
+
+			updated, err := s.UpdateWebhook(adminCtx, 1, 2, test.update, 3)
+			if test.expectedError != nil {
+				assert.ErrorIs(t, err, test.expectedError)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Empty(t, updated.Secret)
+			assert.Equal(t, int64(4), updated.Version)
+		})
+	}
+}
+
+func TestListWebhookDeliveries(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	provider := mock_service.NewMockWebhookProvider(ctrl)
+	provider.EXPECT().GetWebhook(gomock.Any(), int64(1), int64(2)).Return(model.Webhook{ID: 2}, nil)
+	provider.EXPECT().ListWebhookDeliveries(gomock.Any(), int64(1), int64(2), MaxWebhookDeliveries).
+		Return([]model.WebhookDelivery{{ID: 5}}, nil)
+	provider.EXPECT().GetWebhook(gomock.Any(), int64(1), int64(3)).Return(model.Webhook{}, repository.ErrWebhookNotFound)
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	s := NewWebhookService(nil, provider, nil, nil, logger)
+
+	deliveries, err := s.ListWebhookDeliveries(adminCtx, 1, 2, 1000)
+	assert.NoError(t, err)
+	assert.Len(t, deliveries, 1)
+
+	_, err = s.ListWebhookDeliveries(adminCtx, 1, 3, 0)
+	assert.ErrorIs(t, err, ErrWebhookNotFound)
+}
@@ -1,6 +1,6 @@
 DROP TABLE IF EXISTS product_category;
 
-DROP TABLE IF EXISTS categories;
+DROP TABLE IF EXISTS categoryies;
 
 DROP TABLE IF EXISTS products;
 
@@ -0,0 +1 @@
+ALTER TABLE products ADD COLUMN sku VARCHAR(64) UNIQUE;
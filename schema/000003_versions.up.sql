@@ -0,0 +1,3 @@
+ALTER TABLE products ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+
+ALTER TABLE categoryies ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
@@ -0,0 +1,12 @@
+CREATE TABLE outbox (
+                        id BIGSERIAL PRIMARY KEY,
+                        type VARCHAR(64) NOT NULL,
+                        aggregate_id BIGINT NOT NULL,
+                        payload JSONB NOT NULL,
+                        created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+                        attempts INTEGER NOT NULL DEFAULT 0,
+                        next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+                        delivered_at TIMESTAMPTZ
+);
+
+CREATE INDEX outbox_pending_idx ON outbox (next_attempt_at, id) WHERE delivered_at IS NULL;
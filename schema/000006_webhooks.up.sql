@@ -0,0 +1,38 @@
+CREATE TABLE webhooks (
+                          id SERIAL PRIMARY KEY,
+                          user_id INTEGER NOT NULL,
+                          url VARCHAR(2048) NOT NULL,
+                          event_types TEXT[] NOT NULL,
+                          secret VARCHAR(255) NOT NULL,
+                          active BOOLEAN NOT NULL DEFAULT TRUE,
+                          version INTEGER NOT NULL DEFAULT 1,
+                          created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+                          FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TABLE webhook_deliveries (
+                                    id BIGSERIAL PRIMARY KEY,
+                                    webhook_id INTEGER NOT NULL,
+                                    event_id BIGINT NOT NULL,
+                                    event_type VARCHAR(64) NOT NULL,
+                                    event JSONB NOT NULL,
+                                    status VARCHAR(16) NOT NULL DEFAULT 'pending',
+                                    attempts INTEGER NOT NULL DEFAULT 0,
+                                    response_code INTEGER,
+                                    next_attempt_at TIMESTAMPTZ DEFAULT now(),
+                                    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+                                    FOREIGN KEY (webhook_id) REFERENCES webhooks(id) ON DELETE CASCADE,
+                                    UNIQUE (webhook_id, event_id)
+);
+
+CREATE INDEX webhook_deliveries_due_idx ON webhook_deliveries (next_attempt_at) WHERE status = 'pending';
+
+CREATE TABLE webhook_delivery_attempts (
+                                           id BIGSERIAL PRIMARY KEY,
+                                           delivery_id BIGINT NOT NULL,
+                                           response_code INTEGER,
+                                           error TEXT NOT NULL DEFAULT '',
+                                           duration_ms BIGINT NOT NULL,
+                                           attempted_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+                                           FOREIGN KEY (delivery_id) REFERENCES webhook_deliveries(id) ON DELETE CASCADE
+);
@@ -0,0 +1,5 @@
+CREATE TABLE leases (
+                        name VARCHAR(255) PRIMARY KEY,
+                        holder VARCHAR(255) NOT NULL,
+                        expires_at TIMESTAMPTZ NOT NULL
+);
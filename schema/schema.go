@@ -0,0 +1,14 @@
+// Package schema встраивает миграции базы в бинарник приложения
+package schema
+
+import "embed"
+
+// Postgres - миграции для Postgres, в формате утилиты migrate
+//
+//go:embed *.sql
+var Postgres embed.FS
+
+// SQLite - те же миграции для SQLite
+//
+//go:embed sqlite/*.sql
+var SQLite embed.FS
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS product_category;
+
+DROP TABLE IF EXISTS categoryies;
+
+DROP TABLE IF EXISTS products;
+
+DROP TABLE IF EXISTS users;
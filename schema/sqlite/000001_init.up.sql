@@ -0,0 +1,24 @@
+CREATE TABLE users (
+                       id INTEGER PRIMARY KEY AUTOINCREMENT,
+                       email VARCHAR(255) NOT NULL UNIQUE,
+                       passhash BLOB NOT NULL
+);
+
+CREATE TABLE products (
+                          id INTEGER PRIMARY KEY AUTOINCREMENT,
+                          name VARCHAR(255) NOT NULL
+);
+
+CREATE TABLE categoryies (
+                            id INTEGER PRIMARY KEY AUTOINCREMENT,
+                            name VARCHAR(255) NOT NULL UNIQUE
+);
+
+CREATE TABLE product_category (
+                                  id INTEGER PRIMARY KEY AUTOINCREMENT,
+                                  product_id INTEGER NOT NULL,
+                                  category_id INTEGER NOT NULL,
+                                  FOREIGN KEY (product_id) REFERENCES products(id),
+                                  FOREIGN KEY (category_id) REFERENCES categoryies(id),
+                                  UNIQUE (product_id, category_id)
+);
@@ -0,0 +1,3 @@
+ALTER TABLE products ADD COLUMN sku VARCHAR(64);
+
+CREATE UNIQUE INDEX products_sku_key ON products (sku);
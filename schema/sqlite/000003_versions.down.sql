@@ -0,0 +1,3 @@
+ALTER TABLE categoryies DROP COLUMN version;
+
+ALTER TABLE products DROP COLUMN version;
@@ -0,0 +1,13 @@
+CREATE TABLE idempotency_keys (
+                                  user_id INTEGER NOT NULL,
+                                  key VARCHAR(255) NOT NULL,
+                                  fingerprint CHAR(64) NOT NULL,
+                                  status INTEGER,
+                                  headers BLOB,
+                                  body BLOB,
+                                  expires_at TIMESTAMP NOT NULL,
+                                  FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+                                  PRIMARY KEY (user_id, key)
+);
+
+CREATE INDEX idempotency_keys_expires_at_idx ON idempotency_keys (expires_at);